@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	cacheTTL   time.Duration
+	cachedOnly bool
+
+	// chainCache is the on-disk chain cache (nil unless --cache-ttl or --cached-only is set).
+	chainCache *fetcher.ChainCache
+)
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 0, "Cache fetched chains on disk and reuse those fetched within `duration` (0 = no cache)")
+	rootCmd.PersistentFlags().BoolVar(&cachedOnly, "cached-only", false, "Use cached chains of any age and never connect to endpoints")
+}
+
+// loadChainCache checks the cache flags and locates the chain cache.
+func loadChainCache() error {
+	if cacheTTL < 0 {
+		return fmt.Errorf("invalid --cache-ttl: must not be negative")
+	}
+	if cacheTTL == 0 && !cachedOnly {
+		return nil
+	}
+	dir, err := fetcher.DefaultCacheDir()
+	if err != nil {
+		return fmt.Errorf("chain cache: %w", err)
+	}
+	chainCache = &fetcher.ChainCache{Dir: dir}
+	return nil
+}
+
+// chainSource returns the source endpoint chains are fetched from: a TLS connection with
+// timeout, through the chain cache if enabled.
+func chainSource(timeout time.Duration) fetcher.ChainSource {
+	var src fetcher.ChainSource = fetcher.TLSSource{Timeout: timeout}
+	if chainCache != nil {
+		src = fetcher.CachedSource{Source: src, Cache: *chainCache, TTL: cacheTTL, CachedOnly: cachedOnly}
+	}
+	return src
+}
+
+// cacheChain updates the cached chain of endpoint after it was annotated, such as by
+// --probe-tls. It does nothing if the cache is disabled.
+func cacheChain(endpoint string, chain *truststore.CertChain) {
+	if chainCache != nil {
+		_ = chainCache.Save(endpoint, chain)
+	}
+}
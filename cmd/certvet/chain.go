@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/endpoints"
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+var (
+	chainJSON    bool
+	chainFilter  []string
+	chainTimeout time.Duration
+	chainNoAIA   bool
+)
+
+var chainCmd = &cobra.Command{
+	Use:   "chain <endpoint>",
+	Short: "Show the verified path each platform version builds",
+	Long: `Validate an endpoint and print the path each platform version verified, from the leaf
+through intermediates to the matched root. Platform versions that built the same path are grouped.
+
+Each certificate is marked as served by the endpoint, fetched via AIA, or taken from the root
+store, and intermediates carrying another root's subject and key are marked as cross-signs. This
+shows why platforms anchor at different roots.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet chain example.com
+  certvet chain -f 'android' -j example.com`,
+	RunE: runChain,
+}
+
+func init() {
+	chainCmd.Flags().BoolVarP(&chainJSON, "json", "j", false, "Output in JSON format")
+	chainCmd.Flags().StringArrayVarP(&chainFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	chainCmd.Flags().DurationVar(&chainTimeout, "timeout", 10*time.Second, "Connection timeout")
+	chainCmd.Flags().BoolVar(&chainNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+}
+
+func runChain(cmd *cobra.Command, args []string) error {
+	targets, err := endpoints.FromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := filter.ParseAll(chainFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	report, err := validateEndpoint(targets[0].Endpoint, stores, chainTimeout, chainNoAIA)
+	if err != nil {
+		return err
+	}
+
+	format := output.FormatText
+	if chainJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewPathsOutput(report, truststore.CrossSigns), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
+
+// validateEndpoint fetches an endpoint's chain and validates it against stores, chasing AIA
+// issuers unless noAIA is set.
+func validateEndpoint(endpoint string, stores []truststore.Store, timeout time.Duration, noAIA bool) (*truststore.ValidationReport, error) {
+	chain, err := chainSource(timeout).FetchChain(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	v := validator.New(stores)
+	if !noAIA {
+		v = v.WithIssuerFetcher(fetcher.NewIssuerCache(timeout).Fetch)
+	}
+	return &truststore.ValidationReport{
+		Endpoint: endpoint,
+		Chain:    *chain,
+		Results:  v.Validate(chain),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/endpoints"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	compareJSON    bool
+	compareFilter  []string
+	compareTimeout time.Duration
+	compareNoAIA   bool
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <old-endpoint> <new-endpoint>",
+	Short: "Show platforms where two endpoints' trust results differ",
+	Long: `Validate two endpoints and list only the platform versions where one is trusted and the
+other isn't, e.g. to check a CDN or CA migration before switching traffic.
+
+Exit code is 1 if any platform version differs.`,
+	Args: cobra.ExactArgs(2),
+	Example: `  certvet compare old.example.com new.example.com
+  certvet compare -f 'android' example.com:443 staging.example.com:8443`,
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().BoolVarP(&compareJSON, "json", "j", false, "Output in JSON format")
+	compareCmd.Flags().StringArrayVarP(&compareFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	compareCmd.Flags().DurationVar(&compareTimeout, "timeout", 10*time.Second, "Connection timeout")
+	compareCmd.Flags().BoolVar(&compareNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	targets, err := endpoints.FromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := filter.ParseAll(compareFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	oldReport, err := validateEndpoint(targets[0].Endpoint, stores, compareTimeout, compareNoAIA)
+	if err != nil {
+		return err
+	}
+	newReport, err := validateEndpoint(targets[1].Endpoint, stores, compareTimeout, compareNoAIA)
+	if err != nil {
+		return err
+	}
+
+	format := output.FormatText
+	if compareJSON {
+		format = output.FormatJSON
+	}
+	c := output.NewCompareOutput(oldReport, newReport)
+	result, err := output.FormatOutput(c, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	if len(c.Diffs) > 0 {
+		os.Exit(ExitTrustFail)
+	}
+	return nil
+}
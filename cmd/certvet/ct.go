@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/ctsearch"
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+var (
+	ctJSON       bool
+	ctIssuers    []string
+	ctSubdomains bool
+	ctExpired    bool
+	ctValidate   bool
+	ctFilter     []string
+	ctLimit      int
+	ctURL        string
+	ctTimeout    time.Duration
+)
+
+var ctCmd = &cobra.Command{
+	Use:   "ct <domain>",
+	Short: "List certificates logged to Certificate Transparency for a domain",
+	Long: `Query crt.sh for certificates logged to CT for a domain, newest first. Precertificates
+and final certificates of the same issuance are listed once.
+
+With --issuer, certificates from any other CA are flagged as unexpected, e.g. to catch
+issuance outside the CAs your CAA records allow. With --validate, each certificate is
+downloaded, completed with intermediates from its AIA URLs and validated against the trust
+stores, as if a server presented it.
+
+Exit code is 1 if any certificate is from an unexpected issuer or, with --validate, isn't
+trusted by every selected platform version.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet ct example.com
+  certvet ct --subdomains --issuer "Let's Encrypt" --issuer DigiCert example.com
+  certvet ct --validate -f 'android>=10' -j example.com`,
+	RunE: runCT,
+}
+
+func init() {
+	ctCmd.Flags().BoolVarP(&ctJSON, "json", "j", false, "Output in JSON format")
+	ctCmd.Flags().StringArrayVar(&ctIssuers, "issuer", nil, "Expected issuer `name` substring, e.g. \"Let's Encrypt\" (repeatable)")
+	ctCmd.Flags().BoolVar(&ctSubdomains, "subdomains", false, "Include certificates for subdomains")
+	ctCmd.Flags().BoolVar(&ctExpired, "expired", false, "Include expired certificates")
+	ctCmd.Flags().BoolVar(&ctValidate, "validate", false, "Validate each certificate against the trust stores")
+	ctCmd.Flags().StringArrayVarP(&ctFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	ctCmd.Flags().IntVar(&ctLimit, "limit", 50, "Show at most `n` newest certificates (0 for all)")
+	ctCmd.Flags().StringVar(&ctURL, "url", ctsearch.DefaultURL, "crt.sh compatible search service `url`")
+	ctCmd.Flags().DurationVar(&ctTimeout, "timeout", 60*time.Second, "Request timeout (crt.sh can be slow for popular domains)")
+}
+
+func runCT(cmd *cobra.Command, args []string) error {
+	f, err := filter.ParseAll(ctFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	client := ctsearch.NewClient(ctURL, ctTimeout)
+	entries, err := client.Search(args[0], ctsearch.Options{Subdomains: ctSubdomains, IncludeExpired: ctExpired})
+	if err != nil {
+		return err
+	}
+	if ctLimit > 0 && len(entries) > ctLimit {
+		entries = entries[:ctLimit]
+	}
+
+	o := output.NewCTOutput(args[0], entries, ctIssuers)
+	if ctValidate {
+		o.Validated = true
+		issuers := fetcher.NewIssuerCache(ctTimeout)
+		v := validator.New(stores).WithIssuerFetcher(issuers.Fetch)
+		for i := range o.Certs {
+			cert, err := client.Certificate(o.Certs[i].Entry.ID)
+			if err != nil {
+				o.Certs[i].Error = err.Error()
+				continue
+			}
+			o.Certs[i].Results = v.Validate(&truststore.CertChain{
+				Endpoint:      args[0],
+				ServerCert:    cert,
+				Intermediates: issuers.Intermediates(cert),
+			})
+		}
+	}
+
+	format := output.FormatText
+	if ctJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(o, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	if o.Unexpected() > 0 || o.Untrusted() > 0 {
+		os.Exit(ExitTrustFail)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	changelogJSON  bool
+	changelogSince string
+	statsJSON      bool
+	statsFilter    []string
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Inspect the embedded trust store data",
+}
+
+var dataChangelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Show trust store changes between data snapshots",
+	Long: `Display roots added, removed and constrained per platform version in each data refresh.
+
+The changelog is generated when trust store data is refreshed, embedded in the binary, and attached
+to every release as data-changelog.json. JSON output uses the same format.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet data changelog
+  certvet data changelog -j --since 2026-01-01`,
+	RunE: runDataChangelog,
+}
+
+var dataStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show trust store data statistics",
+	Long: `Print what the trust data in use contains: its source and snapshot date, certificate and CT log
+counts, roots per platform and per platform version, roots included by a single platform only,
+and how many roots carry each kind of date constraint.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet data stats
+  certvet data stats -f 'ios>=17' -j`,
+	RunE: runDataStats,
+}
+
+func init() {
+	dataChangelogCmd.Flags().BoolVarP(&changelogJSON, "json", "j", false, "Output in JSON format")
+	dataChangelogCmd.Flags().StringVar(&changelogSince, "since", "", "Only show snapshots dated on or after `date` (YYYY-MM-DD)")
+	dataCmd.AddCommand(dataChangelogCmd)
+
+	dataStatsCmd.Flags().BoolVarP(&statsJSON, "json", "j", false, "Output in JSON format")
+	dataStatsCmd.Flags().StringArrayVarP(&statsFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	dataCmd.AddCommand(dataStatsCmd)
+}
+
+func runDataChangelog(cmd *cobra.Command, args []string) error {
+	c, err := changelog.Embedded()
+	if err != nil {
+		return err
+	}
+
+	if changelogSince != "" {
+		if _, err := time.Parse(truststore.DateFormat, changelogSince); err != nil {
+			return fmt.Errorf("invalid --since %q: expected YYYY-MM-DD", changelogSince)
+		}
+		c = c.Since(changelogSince)
+	}
+
+	format := output.FormatText
+	if changelogJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewChangelogOutput(c), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
+
+func runDataStats(cmd *cobra.Command, args []string) error {
+	f, err := filter.ParseAll(statsFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	c, err := changelog.Embedded()
+	if err != nil {
+		return err
+	}
+	data := output.DataStats{
+		Source:       dataSource(),
+		Certificates: truststore.Certs.Len(),
+		CrossSigns:   truststore.CrossSigns.Len(),
+		CTLogs:       len(truststore.CTLogs),
+	}
+	if len(c.Snapshots) > 0 {
+		data.Snapshot = c.Snapshots[0].Date
+	}
+
+	format := output.FormatText
+	if statsJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewStatsOutput(stores, data, rootName), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/testutil"
+)
+
+func TestDataChangelogCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantExit   int
+		wantStdout string
+	}{
+		{"json output", []string{"data", "changelog", "-j"}, ExitSuccess, `"snapshots":`},
+		{"since filter", []string{"data", "changelog", "-j", "--since", "2099-01-01"}, ExitSuccess, `"snapshots": []`},
+		{"invalid since", []string{"data", "changelog", "--since", "yesterday"}, ExitInputError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := testutil.RunCLI(t, tt.args...)
+
+			if result.ExitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", result.ExitCode, tt.wantExit, result.Stdout, result.Stderr)
+			}
+			if !strings.Contains(result.Stdout, tt.wantStdout) {
+				t.Errorf("stdout should contain %q, got:\n%s", tt.wantStdout, result.Stdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var diffJSON bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <platform=version> <platform=version>",
+	Short: "Compare the roots of two trust stores",
+	Long: `List roots added, removed and constrained differently in the second store relative to the first.
+
+Stores are selected with filter syntax that must match exactly one platform version. Stores of
+different platforms can be compared, e.g. to see which roots Chrome trusts that Windows doesn't.`,
+	Args: cobra.ExactArgs(2),
+	Example: `  certvet diff ios=17 ios=18
+  certvet diff -j chrome=current windows=current`,
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "j", false, "Output in JSON format")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	from, err := selectStore(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := selectStore(args[1])
+	if err != nil {
+		return err
+	}
+
+	name := func(fp truststore.Fingerprint) string {
+		if cert := truststore.Certs.Get(fp); cert != nil {
+			return truststore.CertName(cert)
+		}
+		return ""
+	}
+
+	format := output.FormatText
+	if diffJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewStoreDiffOutput(from, to, name), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
+
+// selectStore returns the single store matched by a filter expression.
+func selectStore(expr string) (truststore.Store, error) {
+	f, err := filter.Parse(expr)
+	if err != nil {
+		return truststore.Store{}, fmt.Errorf("invalid store %q: %w", expr, err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) != 1 {
+		return truststore.Store{}, fmt.Errorf("store %q matches %d platform versions, expected exactly 1 (e.g., ios=18)", expr, len(stores))
+	}
+	return stores[0], nil
+}
@@ -0,0 +1,40 @@
+//go:build integration
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/testutil"
+)
+
+func TestDiffCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantExit   int
+		wantStdout string
+	}{
+		{"same store", []string{"diff", "ios=18", "ios=18"}, ExitSuccess, "0 added, 0 removed, 0 constrained"},
+		{"json output", []string{"diff", "-j", "chrome=current", "windows=current"}, ExitSuccess, `"constrained":`},
+		{"ambiguous store", []string{"diff", "ios", "ios=18"}, ExitInputError, ""},
+		{"invalid store", []string{"diff", "nokia=1", "ios=18"}, ExitInputError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := testutil.RunCLI(t, tt.args...)
+
+			if result.ExitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", result.ExitCode, tt.wantExit, result.Stdout, result.Stderr)
+			}
+			if !strings.Contains(result.Stdout, tt.wantStdout) {
+				t.Errorf("stdout should contain %q, got:\n%s", tt.wantStdout, result.Stdout)
+			}
+		})
+	}
+}
@@ -1,8 +1,111 @@
 package main
 
+import (
+	"fmt"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
 // Exit codes
 const (
-	ExitSuccess    = 0
-	ExitTrustFail  = 1
-	ExitInputError = 2
+	ExitSuccess         = 0
+	ExitTrustFail       = 1 // Chain is untrusted on some platform versions
+	ExitInputError      = 2 // Invalid arguments, unreadable files or other runtime errors
+	ExitConnectionError = 3 // Endpoint could not be reached (DNS, connect, TLS handshake)
+	ExitConstraintFail  = 4 // Chain reaches a trusted root, but only root constraints reject it
+)
+
+// exitCodesHelp documents the validate exit codes in --help.
+const exitCodesHelp = `Exit codes:
+  0  all endpoints are trusted on every selected platform version
+  1  some chain is not trusted (missing root, expired, hostname mismatch, ...)
+  2  invalid input or runtime error
+  3  some endpoint could not be reached and no chain failed
+  4  every failure is a root constraint (distrust date, notBefore or name constraint)
+     on a chain that otherwise verifies to a trusted root
+
+With --fail-on warn, warnings and --lookahead forecasts on passing results also exit 1;
+with --fail-on never, only errors (2, 3) affect the exit code.`
+
+// Validation failure policies (validate --fail-on).
+const (
+	failOnError = "error" // Only trust failures affect the exit code
+	failOnWarn  = "warn"  // Warnings and forecasts also fail
+	failOnNever = "never" // Trust results never affect the exit code
 )
+
+// parseFailOn checks a --fail-on policy.
+func parseFailOn(s string) (string, error) {
+	switch s {
+	case failOnError, failOnWarn, failOnNever:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown policy %q (want %s, %s or %s)", s, failOnError, failOnWarn, failOnNever)
+}
+
+// exitError makes main exit with code instead of ExitInputError.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// validationExitCode returns the exit code for validation reports under a --fail-on policy.
+// Trust failures take precedence over constraint-only failures, which take precedence
+// over connection errors.
+func validationExitCode(failOn string, reports ...*truststore.ValidationReport) int {
+	code := ExitSuccess
+	for _, r := range reports {
+		switch {
+		case r.Error != "":
+			if code == ExitSuccess {
+				code = ExitConnectionError
+			}
+		case failOn == failOnNever:
+		case !r.AllPassed && constraintOnly(r):
+			code = ExitConstraintFail
+		case !r.AllPassed:
+			return ExitTrustFail
+		case failOn == failOnWarn && hasWarnings(r):
+			return ExitTrustFail
+		}
+	}
+	return code
+}
+
+// hasWarnings reports whether any result of r has warnings, a lookahead forecast, or a
+// root pending removal.
+func hasWarnings(r *truststore.ValidationReport) bool {
+	for _, res := range r.Results {
+		if len(res.Warnings) > 0 || res.Forecast != nil || res.PendingRemoval {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintFailures are the failure codes of chains that reach a store root which the
+// store's constraints on it then reject.
+var constraintFailures = map[truststore.FailureCode]bool{
+	truststore.FailureDistrustDate:    true,
+	truststore.FailureNotBeforeCutoff: true,
+	truststore.FailureSCTDeadline:     true,
+	truststore.FailureNameConstraints: true,
+}
+
+// constraintOnly reports whether every failing result of r failed only on root
+// constraints (see constraintFailures). Other failures, such as a blocked certificate
+// in a chain that verified, are trust failures.
+func constraintOnly(r *truststore.ValidationReport) bool {
+	if r.Hostname != nil && !r.Hostname.Valid {
+		return false
+	}
+	for _, res := range r.Results {
+		if !res.Trusted && !constraintFailures[res.FailureCode] {
+			return false
+		}
+	}
+	return true
+}
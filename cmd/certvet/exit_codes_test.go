@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestValidationExitCodeFailureCodes(t *testing.T) {
+	t.Parallel()
+
+	verified := []*x509.Certificate{{}, {}}
+	apple := truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}
+	tests := []struct {
+		name string
+		code truststore.FailureCode
+		want int
+	}{
+		{"distrusted root", truststore.FailureDistrustDate, ExitConstraintFail},
+		{"not before cutoff", truststore.FailureNotBeforeCutoff, ExitConstraintFail},
+		{"SCT deadline", truststore.FailureSCTDeadline, ExitConstraintFail},
+		{"name constraint", truststore.FailureNameConstraints, ExitConstraintFail},
+		{"Apple blocked", truststore.FailureBlocked, ExitTrustFail},
+		{"Apple always ask", truststore.FailureAlwaysAsk, ExitTrustFail},
+		{"unknown authority", truststore.FailureUnknownAuthority, ExitTrustFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			report := &truststore.ValidationReport{
+				Results: []truststore.TrustResult{
+					{Platform: apple, Trusted: true, VerifiedChain: verified},
+					// Blocked and always ask results have a verified chain too
+					{Platform: apple, FailureCode: tt.code, VerifiedChain: verified},
+				},
+			}
+			if got := validationExitCode(failOnError, report); got != tt.want {
+				t.Errorf("validationExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+var (
+	expiryJSON       bool
+	expiryFilter     []string
+	expiryTimeout    time.Duration
+	expiryNoAIA      bool
+	expiryDays       int
+	expiryAnchorDays int
+)
+
+var expiryCmd = &cobra.Command{
+	Use:   "expiry <endpoint|file>",
+	Short: "Show days remaining for each certificate and trust anchor",
+	Long: `Print when the leaf and each intermediate expire, plus the roots platforms anchor the chain
+at, with whole days remaining.
+
+The chain is fetched from a TLS endpoint, or read from a PEM file if the argument names an
+existing file. Anchors are found by validating the chain against the selected trust stores.
+
+Exit code is 1 if any served certificate expires within --days, or any anchor within
+--anchor-days.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet expiry example.com
+  certvet expiry --days 14 --anchor-days 365 -j chain.pem`,
+	RunE: runExpiry,
+}
+
+func init() {
+	expiryCmd.Flags().BoolVarP(&expiryJSON, "json", "j", false, "Output in JSON format")
+	expiryCmd.Flags().StringArrayVarP(&expiryFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	expiryCmd.Flags().DurationVar(&expiryTimeout, "timeout", 10*time.Second, "Connection timeout")
+	expiryCmd.Flags().BoolVar(&expiryNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+	expiryCmd.Flags().IntVar(&expiryDays, "days", 30, "Fail if the leaf or an intermediate expires within `n` days")
+	expiryCmd.Flags().IntVar(&expiryAnchorDays, "anchor-days", 180, "Fail if a trust anchor expires within `n` days")
+}
+
+func runExpiry(cmd *cobra.Command, args []string) error {
+	f, err := filter.ParseAll(expiryFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	chain, err := loadInspectChain(args[0], expiryTimeout)
+	if err != nil {
+		return err
+	}
+
+	v := validator.New(stores)
+	if !expiryNoAIA {
+		v = v.WithIssuerFetcher(fetcher.NewIssuerCache(expiryTimeout).Fetch)
+	}
+	e := output.NewExpiryOutput(args[0], chain, v.Validate(chain), truststore.Certs, time.Now(), expiryDays, expiryAnchorDays)
+
+	format := output.FormatText
+	if expiryJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(e, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	if e.Expiring() {
+		os.Exit(ExitTrustFail)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/bundle"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	exportFilter         []string
+	exportFormat         string
+	exportStorePass      string
+	exportSkipDistrusted bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export trust store roots as a PEM bundle or Java keystore",
+	Long: `Write the roots of the stores selected by --filter to stdout, so the embedded data can
+feed other tooling and test rigs. Roots shared by several selected stores are written once.
+
+Formats:
+  pem   Concatenated PEM certificates, each preceded by a "# name" comment
+  jks   Java KeyStore of trusted certificate entries (password set by --storepass)
+
+Bundles carry no trust constraints: roots with a NotBeforeMax, DistrustDate or SCTNotAfter
+constraint are exported as fully trusted unless --skip-distrusted drops those already distrusted.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet export -f 'android=7' > android7.pem
+  certvet export -f 'windows' --skip-distrusted --format jks > windows.jks`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringArrayVarP(&exportFilter, "filter", "f", nil, "Filter expression selecting stores (e.g., android=7); repeat for alternatives")
+	exportCmd.Flags().StringVar(&exportFormat, "format", bundle.FormatPEM, "Bundle `format` ("+strings.Join(bundle.Formats, ", ")+")")
+	exportCmd.Flags().StringVar(&exportStorePass, "storepass", "changeit", "Keystore `password` for the jks format")
+	exportCmd.Flags().BoolVar(&exportSkipDistrusted, "skip-distrusted", false, "Omit roots whose DistrustDate has passed")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, err := bundle.ParseFormat(exportFormat)
+	if err != nil {
+		return err
+	}
+
+	f, err := filter.ParseAll(exportFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	entries, err := exportEntries(stores, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := bundle.Write(os.Stdout, format, entries, bundle.Options{Password: exportStorePass, Created: time.Now()}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d roots from %d stores\n", len(entries), len(stores))
+	return nil
+}
+
+// exportEntries collects the roots of stores in store order, each once, skipping roots
+// distrusted by now in every store that includes them when --skip-distrusted is set.
+func exportEntries(stores []truststore.Store, now time.Time) ([]bundle.Entry, error) {
+	var fps []truststore.Fingerprint
+	keep := make(map[truststore.Fingerprint]bool)
+	for _, s := range stores {
+		for _, fp := range s.Fingerprints {
+			if _, seen := keep[fp]; !seen {
+				fps = append(fps, fp)
+				keep[fp] = false
+			}
+			distrust := s.ConstraintFor(fp).DistrustDate
+			if !exportSkipDistrusted || distrust == nil || distrust.After(now) {
+				keep[fp] = true
+			}
+		}
+	}
+
+	var entries []bundle.Entry
+	for _, fp := range fps {
+		if !keep[fp] {
+			continue
+		}
+		cert, err := truststore.Certs.Load(fp)
+		if err != nil {
+			return nil, fmt.Errorf("load root %s: %w", fp.Truncate(4), err)
+		}
+		if cert == nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping root %s: certificate not in embedded data\n", fp.Truncate(4))
+			continue
+		}
+		name := truststore.CertName(cert)
+		if name == "" {
+			name = fp.String()
+		}
+		entries = append(entries, bundle.Entry{Name: name, Cert: cert})
+	}
+	return entries, nil
+}
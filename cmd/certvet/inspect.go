@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/endpoints"
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	inspectJSON    bool
+	inspectTimeout time.Duration
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <endpoint|file>",
+	Short: "Show certificate chain details",
+	Long: `Print details of each certificate in a chain: subject, issuer, SANs, validity, key,
+signature algorithm, fingerprints, AIA/CRL URLs and SCTs.
+
+The chain is fetched from a TLS endpoint, or read from a PEM file if the argument names an
+existing file. No trust store validation is performed.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet inspect example.com
+  certvet inspect chain.pem -j`,
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().BoolVarP(&inspectJSON, "json", "j", false, "Output in JSON format")
+	inspectCmd.Flags().DurationVar(&inspectTimeout, "timeout", 10*time.Second, "Connection timeout")
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	chain, err := loadInspectChain(args[0], inspectTimeout)
+	if err != nil {
+		return err
+	}
+
+	format := output.FormatText
+	if inspectJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(&output.InspectOutput{
+		Chain:  chain,
+		Logs:   truststore.CTLogs,
+		Source: args[0],
+	}, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
+
+// loadInspectChain reads a PEM chain if arg is an existing file, otherwise fetches it over TLS.
+func loadInspectChain(arg string, timeout time.Duration) (*truststore.CertChain, error) {
+	if info, err := os.Stat(arg); err == nil && info.Mode().IsRegular() {
+		data, err := os.ReadFile(arg) //nolint:gosec // G304: Path is user-specified certificate file
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", arg, err)
+		}
+		return fetcher.ParsePEMChain(arg, data)
+	}
+
+	targets, err := endpoints.FromArgs([]string{arg})
+	if err != nil {
+		return nil, err
+	}
+	return chainSource(timeout).FetchChain(targets[0].Endpoint)
+}
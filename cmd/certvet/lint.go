@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/lint"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	lintJSON    bool
+	lintTimeout time.Duration
+	lintFailOn  string
+	lintRules   bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <endpoint|file>",
+	Short: "Check a chain for deployment best practices",
+	Long: `Check the chain an endpoint presents, or a PEM file, for ordering, included roots, duplicate
+certificates, missing intermediates, oversized chains, and CA/Browser Forum and root program
+policy violations. Each finding has a rule ID and a severity (error, warning or info).
+
+Missing intermediates are detected against all embedded roots; no trust store validation is
+performed. Use --rules to list all rules.
+
+Exit code is 1 if any finding is at least as severe as --fail-on.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if lintRules {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Example: `  certvet lint example.com
+  certvet lint --fail-on warning -j chain.pem
+  certvet lint --rules`,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVarP(&lintJSON, "json", "j", false, "Output in JSON format")
+	lintCmd.Flags().DurationVar(&lintTimeout, "timeout", 10*time.Second, "Connection timeout")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "error", "Exit with code 1 on findings of at least this `severity` (error, warning, info)")
+	lintCmd.Flags().BoolVar(&lintRules, "rules", false, "List all rules and exit")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if lintRules {
+		tw := output.NewTableWriter()
+		tw.Header("RULE", "SEVERITY", "DESCRIPTION")
+		for _, r := range lint.Rules {
+			tw.Row(r.ID, r.Severity.String(), r.Description)
+		}
+		fmt.Println(tw.String())
+		return nil
+	}
+
+	failOn, err := lint.ParseSeverity(lintFailOn)
+	if err != nil {
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+
+	chain, err := loadInspectChain(args[0], lintTimeout)
+	if err != nil {
+		return err
+	}
+	roots, err := truststore.Certs.LoadAll()
+	if err != nil {
+		return err
+	}
+	findings := lint.Lint(chain, lint.Options{Now: time.Now(), Roots: roots})
+
+	format := output.FormatText
+	if lintJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewLintOutput(args[0], chain, findings), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	for _, f := range findings {
+		if f.Rule.Severity >= failOn {
+			os.Exit(ExitTrustFail)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"crypto/x509"
 	"fmt"
+	"iter"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,9 +17,13 @@ import (
 )
 
 var (
-	listJSON   bool
-	listFilter string
-	listWide   bool
+	listJSON     bool
+	listOutput   string
+	listFilter   []string
+	listWide     bool
+	listColumns  string
+	listOperator string
+	listIssuer   string
 )
 
 var listCmd = &cobra.Command{
@@ -24,44 +33,81 @@ var listCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	Example: `  certvet list
   certvet list -j
-  certvet list -f 'ios>=17'`,
+  certvet list -o csv > roots.csv
+  certvet list --columns platform,version,issuer,expiry
+  certvet list -o go-template='{{range .}}{{.fingerprint}}{{"\n"}}{{end}}'
+  certvet list -f 'ios>=17'
+  certvet list -f 'android=14' --operator government --columns issuer,operator,country
+  certvet list -f 'ios=latest' --issuer DigiCert -j
+  certvet list --issuer '/^(GTS|GlobalSign) Root R[0-9]$/'`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVarP(&listJSON, "json", "j", false, "Output in JSON format")
-	listCmd.Flags().StringVarP(&listFilter, "filter", "f", "", "Filter expression (e.g., ios>=15,android>=10)")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", "text", "Output `format`: text, json, csv, go-template=TEMPLATE or go-template-file=PATH")
+	listCmd.Flags().StringArrayVarP(&listFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
 	listCmd.Flags().BoolVarP(&listWide, "wide", "w", false, "Display full fingerprints without truncation")
+	listCmd.Flags().StringVar(&listColumns, "columns", "", "Comma-separated `columns` for text and CSV output ("+strings.Join(output.ListColumnNames, ", ")+")")
+	listCmd.Flags().StringVar(&listOperator, "operator", "", "Only list roots whose CA operator contains `text` (case-insensitive)")
+	listCmd.Flags().StringVar(&listIssuer, "issuer", "", "Only list roots whose subject CN or O contains `text` (case-insensitive), or matches /regexp/")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	// Parse filter
-	var f *filter.Filter
-	if listFilter != "" {
-		var err error
-		f, err = filter.Parse(listFilter)
-		if err != nil {
-			return fmt.Errorf("invalid filter: %w", err)
+	out, err := parseOutput(listJSON, listOutput)
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	if listColumns != "" {
+		if columns, err = output.ParseListColumns(listColumns); err != nil {
+			return err
 		}
 	}
 
+	// Parse filter
+	f, err := filter.ParseAll(listFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+
 	// Get and filter stores
 	stores := filter.FilterStores(truststore.Stores, f)
+	if listIssuer != "" {
+		match, err := issuerMatcher(listIssuer)
+		if err != nil {
+			return fmt.Errorf("invalid --issuer: %w", err)
+		}
+		stores = issuerStores(stores, match)
+	}
 
-	// Build entries
-	entries := buildListEntries(stores, listJSON)
+	// Only the text table truncates fingerprints
+	truncate := out.isText() && !listWide
 
+	// Stream entries unless a template needs the whole document
+	if out.template == nil {
+		stream := &output.ListStream{
+			Entries:     operatorEntries(output.ListEntrySeq(stores, truststore.Certs, truststore.CAInfos, truncate), listOperator),
+			Columns:     columns,
+			DataVersion: dataVersion(),
+			DataDate:    truststore.Sources.Generated,
+		}
+		return stream.Write(os.Stdout, out.format)
+	}
+
+	entries := slices.Collect(operatorEntries(slices.Values(output.ListEntries(stores, truststore.Certs, truststore.CAInfos, truncate)), listOperator))
 	if len(entries) == 0 {
 		return nil // Empty result is not an error
 	}
 
-	// Output
-	list := &output.StoreList{Entries: entries}
-	format := output.FormatText
-	if listJSON {
-		format = output.FormatJSON
+	list := &output.StoreList{
+		Entries:     entries,
+		Columns:     columns,
+		DataVersion: dataVersion(),
+		DataDate:    truststore.Sources.Generated,
 	}
-	result, err := output.FormatOutput(list, format)
+	result, err := out.render(list)
 	if err != nil {
 		return err
 	}
@@ -70,65 +116,52 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// buildListEntries converts trust stores to list entries for output.
-// When jsonMode is true, fingerprints are kept full; otherwise truncated to 4 octets.
-func buildListEntries(stores []truststore.Store, jsonMode bool) []output.ListEntry {
-	var entries []output.ListEntry
-
-	for _, store := range stores {
-		for _, fp := range store.Fingerprints {
-			// Lookup certificate to get issuer
-			issuer := "-"
-			if cert := truststore.Certs[fp]; cert != nil {
-				// Prefer CommonName, fallback to Organization
-				if cert.Subject.CommonName != "" {
-					issuer = cert.Subject.CommonName
-				} else if len(cert.Subject.Organization) > 0 {
-					issuer = cert.Subject.Organization[0]
-				}
-			}
-
-			// Truncate fingerprint for text mode (unless wide mode)
-			var displayFP string
-			if !jsonMode && !listWide {
-				displayFP = fp.Truncate(4)
-			} else {
-				displayFP = fp.String()
-			}
-
-			// Format constraints
-			constraints := formatConstraints(store.ConstraintFor(fp))
-
-			entries = append(entries, output.ListEntry{
-				Platform:    string(store.Platform),
-				Version:     store.Version,
-				Fingerprint: displayFP,
-				Issuer:      issuer,
-				Constraints: constraints,
-			})
+// issuerMatcher returns a matcher of certificates whose subject CommonName or an
+// Organization contains issuer, ignoring case, or matches the regular expression
+// between slashes of "/regexp/".
+func issuerMatcher(issuer string) (func(*x509.Certificate) bool, error) {
+	var match func(string) bool
+	if expr, ok := strings.CutPrefix(issuer, "/"); ok && len(expr) > 0 && strings.HasSuffix(expr, "/") {
+		re, err := regexp.Compile(strings.TrimSuffix(expr, "/"))
+		if err != nil {
+			return nil, err
 		}
+		match = re.MatchString
+	} else {
+		needle := strings.ToLower(issuer)
+		match = func(s string) bool { return strings.Contains(strings.ToLower(s), needle) }
 	}
-
-	return entries
+	return func(cert *x509.Certificate) bool {
+		return match(cert.Subject.CommonName) || slices.ContainsFunc(cert.Subject.Organization, match)
+	}, nil
 }
 
-// formatConstraints returns a short string representation of constraints.
-// Empty string if no constraints set.
-// Format: NB:YYYY-MM-DD (NotBeforeMax), DT:YYYY-MM-DD (DistrustDate), SCT:YYYY-MM-DD (SCTNotAfter)
-func formatConstraints(c truststore.Constraints) string {
-	if c.IsEmpty() {
-		return ""
+// issuerStores returns stores holding only the roots whose certificate matches. Roots
+// without an embedded certificate have no subject to match and are left out.
+func issuerStores(stores []truststore.Store, match func(*x509.Certificate) bool) []truststore.Store {
+	result := make([]truststore.Store, 0, len(stores))
+	for _, s := range stores {
+		s.Fingerprints = slices.DeleteFunc(slices.Clone(s.Fingerprints), func(fp truststore.Fingerprint) bool {
+			cert := truststore.Certs.Get(fp)
+			return cert == nil || !match(cert)
+		})
+		result = append(result, s)
 	}
+	return result
+}
 
-	var parts []string
-	if c.NotBeforeMax != nil {
-		parts = append(parts, "NB:"+c.NotBeforeMax.Format(truststore.DateFormat))
+// operatorEntries yields the entries whose CA operator contains operator, ignoring case;
+// all entries if operator is empty.
+func operatorEntries(entries iter.Seq[output.ListEntry], operator string) iter.Seq[output.ListEntry] {
+	if operator == "" {
+		return entries
 	}
-	if c.DistrustDate != nil {
-		parts = append(parts, "DT:"+c.DistrustDate.Format(truststore.DateFormat))
-	}
-	if c.SCTNotAfter != nil {
-		parts = append(parts, "SCT:"+c.SCTNotAfter.Format(truststore.DateFormat))
+	needle := strings.ToLower(operator)
+	return func(yield func(output.ListEntry) bool) {
+		for e := range entries {
+			if strings.Contains(strings.ToLower(e.Operator), needle) && !yield(e) {
+				return
+			}
+		}
 	}
-	return strings.Join(parts, ",")
 }
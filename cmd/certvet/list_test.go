@@ -62,6 +62,29 @@ func TestListCommand(t *testing.T) {
 			},
 			wantExitCode: ExitSuccess,
 		},
+		{
+			name:         "repeated filters",
+			args:         []string{"list", "-f", "ios=18", "-f", "android=14"},
+			wantSubstrs:  []string{"ios", "android"},
+			wantExitCode: ExitSuccess,
+		},
+		{
+			name:         "issuer substring",
+			args:         []string{"list", "-f", "chrome=current", "--issuer", "digicert"},
+			wantSubstrs:  []string{"DigiCert Global Root G2"},
+			wantExitCode: ExitSuccess,
+		},
+		{
+			name:         "issuer regexp",
+			args:         []string{"list", "-f", "chrome=current", "--issuer", "/^GTS Root R[0-9]$/"},
+			wantSubstrs:  []string{"GTS Root R1"},
+			wantExitCode: ExitSuccess,
+		},
+		{
+			name:         "invalid issuer regexp",
+			args:         []string{"list", "--issuer", "/[/"},
+			wantExitCode: ExitInputError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,6 +133,12 @@ func TestListCommandFiltering(t *testing.T) {
 			wantPlatform:   "macos",
 			unwantPlatform: "android",
 		},
+		{
+			name:           "apple group",
+			filter:         "apple>=26",
+			wantPlatform:   "watchos",
+			unwantPlatform: "android",
+		},
 	}
 
 	for _, tt := range tests {
@@ -3,30 +3,139 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/progress"
+	"github.com/ivoronin/certvet/internal/truststore"
 )
 
 // Version is set via ldflags at build time.
 var Version = "dev"
 
+var (
+	noColor       bool
+	quiet         bool
+	customStores  []string
+	storesCSV     string
+	certsCSV      string
+	replaceStores bool
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "certvet",
 	Short: "Check SSL certificate trust across platforms",
 	CompletionOptions: cobra.CompletionOptions{
 		DisableDefaultCmd: true,
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		output.Colors = useColors()
+		if err := loadInstalledData(cmd, args); err != nil {
+			return err
+		}
+		if err := loadDataOverlay(); err != nil {
+			return err
+		}
+		if err := loadChainCache(); err != nil {
+			return err
+		}
+		return loadCustomStores()
+	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors and status icons in table output")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Don't report progress of long runs on stderr")
+	rootCmd.PersistentFlags().StringVar(&storesCSV, "stores-csv", "", "Merge trust stores from a stores.csv `file` into the trust data (same format as the embedded data)")
+	rootCmd.PersistentFlags().StringVar(&certsCSV, "certs-csv", "", "Add root certificates from a certificates.csv `file` to the trust data")
+	rootCmd.PersistentFlags().BoolVar(&replaceStores, "replace-stores", false, "Use only the --stores-csv stores instead of merging them")
+	rootCmd.PersistentFlags().StringArrayVar(&customStores, "custom-store", nil, "Add a trust store platform `name=dir` with the roots in a directory of PEM files (repeatable)")
+
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(testchainsCmd)
+	rootCmd.AddCommand(dataCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(chainCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(whoTrustsCmd)
+	rootCmd.AddCommand(matrixCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(expiryCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(ctCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// loadDataOverlay applies the --stores-csv and --certs-csv fragments to the trust data in use.
+func loadDataOverlay() error {
+	if replaceStores && storesCSV == "" {
+		return fmt.Errorf("--replace-stores requires --stores-csv")
+	}
+	if storesCSV == "" && certsCSV == "" {
+		return nil
+	}
+	overlay, err := truststore.LoadDataOverlay(storesCSV, certsCSV)
+	if err != nil {
+		return err
+	}
+	overlay.Replace = replaceStores
+	overlay.Apply()
+	return nil
+}
+
+// loadCustomStores adds the --custom-store platforms to the trust data in use.
+func loadCustomStores() error {
+	for _, spec := range customStores {
+		store, err := truststore.LoadCustomStore(spec)
+		if err != nil {
+			return err
+		}
+		store.Add()
+	}
+	return nil
+}
+
+// useColors reports whether tables should be colored: only when stdout is a terminal,
+// and never with --no-color, NO_COLOR (https://no-color.org) or TERM=dumb.
+func useColors() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// newProgress returns a progress bar on stderr, or nil (reporting nothing) with --quiet
+// or when stderr is not a terminal.
+func newProgress(label string) *progress.Bar {
+	if quiet || !isTerminal(os.Stderr) {
+		return nil
+	}
+	return progress.New(os.Stderr, label)
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(ExitInputError)
 	}
 }
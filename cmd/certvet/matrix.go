@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/endpoints"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	matrixJSON    bool
+	matrixFilter  []string
+	matrixTimeout time.Duration
+	matrixNoAIA   bool
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix <endpoint>",
+	Short: "Show pass/fail per platform version as a grid",
+	Long: `Validate an endpoint and render the results as a platform by version grid: one row per
+platform, with each version and whether it trusts the endpoint, so the compatibility picture
+is visible at a glance. Use validate for failure reasons.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet matrix example.com
+  certvet matrix -f 'ios>=15,android' example.com`,
+	RunE: runMatrix,
+}
+
+func init() {
+	matrixCmd.Flags().BoolVarP(&matrixJSON, "json", "j", false, "Output in JSON format")
+	matrixCmd.Flags().StringArrayVarP(&matrixFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	matrixCmd.Flags().DurationVar(&matrixTimeout, "timeout", 10*time.Second, "Connection timeout")
+	matrixCmd.Flags().BoolVar(&matrixNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+}
+
+func runMatrix(cmd *cobra.Command, args []string) error {
+	targets, err := endpoints.FromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := filter.ParseAll(matrixFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	report, err := validateEndpoint(targets[0].Endpoint, stores, matrixTimeout, matrixNoAIA)
+	if err != nil {
+		return err
+	}
+
+	format := output.FormatText
+	if matrixJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewMatrixOutput(report), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	planJSON   bool
+	planFilter []string
+	planSince  string
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "List upcoming trust store dates grouped by CA",
+	Long: `List future dates in the embedded trust store data, grouped by the CA owning each root:
+distrust dates, cutoffs for certificates issued after a date (NotBeforeMax) and cutoffs for
+certificates logged to CT after a date (Chrome SCTNotAfter).
+
+Each date is shown with the platform versions applying it, so migrations off affected CAs can
+be planned before endpoints start failing.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet plan
+  certvet plan -f chrome -j
+  certvet plan --since 2024-01-01`,
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().BoolVarP(&planJSON, "json", "j", false, "Output in JSON format")
+	planCmd.Flags().StringArrayVarP(&planFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+	planCmd.Flags().StringVar(&planSince, "since", "", "Show dates on or after `date` (YYYY-MM-DD) instead of today")
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	since := time.Now().UTC().Truncate(24 * time.Hour)
+	if planSince != "" {
+		var err error
+		if since, err = time.Parse(truststore.DateFormat, planSince); err != nil {
+			return fmt.Errorf("invalid --since %q: expected YYYY-MM-DD", planSince)
+		}
+	}
+
+	f, err := filter.ParseAll(planFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+	if len(stores) == 0 {
+		return fmt.Errorf("no trust stores match filter")
+	}
+
+	format := output.FormatText
+	if planJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewPlanOutput(stores, truststore.Certs, since), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	return nil
+}
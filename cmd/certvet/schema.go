@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/output"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <validate|list>",
+	Short: "Print the JSON Schema of a command's JSON output",
+	Long: `Print the JSON Schema (draft 2020-12) describing the JSON output of a command,
+generated from the structs certvet marshals. Use it to validate output or generate
+client types in downstream tools.
+
+The validate schema accepts both the single-endpoint report and the multi-endpoint
+{"endpoints": [...]} document.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: output.SchemaNames,
+	Example: `  certvet schema validate > certvet-validate.schema.json
+  certvet schema list`,
+	RunE: runSchema,
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	data, err := output.Schema(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
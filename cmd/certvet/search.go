@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	searchJSON   bool
+	searchFilter []string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search embedded root certificates",
+	Long: `Search the embedded root certificates and print the matches with the platforms that
+include them.
+
+The query is matched as a case-insensitive substring of the subject CommonName, Organization,
+full subject or CA operator (CCADB's CA owner), or exactly as a SHA-256 certificate fingerprint or SPKI hash (hex or base64, as in
+pin-sha256). Use who-trusts for per-version detail on a match.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet search digicert
+  certvet search 'cGuxAXyFXFkWm61cF4HPWX8S0srS9j0aSqN0k4AP+4A='
+  certvet search -j -f 'android' 'Let''s Encrypt'`,
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().BoolVarP(&searchJSON, "json", "j", false, "Output in JSON format")
+	searchCmd.Flags().StringArrayVarP(&searchFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	f, err := filter.ParseAll(searchFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+
+	format := output.FormatText
+	if searchJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewSearchOutput(args[0], searchRoots(args[0]), truststore.Certs, truststore.CAInfos, stores), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// searchRoots returns the fingerprints of embedded roots matching query (see searchCmd).
+func searchRoots(query string) []truststore.Fingerprint {
+	var hash *truststore.Fingerprint
+	if fp, err := truststore.ParseFingerprint(query); err == nil {
+		hash = &fp
+	} else if raw, err := base64.StdEncoding.DecodeString(query); err == nil && len(raw) == sha256.Size {
+		fp := truststore.FingerprintFromBytes(raw)
+		hash = &fp
+	}
+	needle := strings.ToLower(query)
+
+	var matches []truststore.Fingerprint
+	for _, fp := range truststore.Certs.Fingerprints() {
+		cert := truststore.Certs.Get(fp)
+		if cert == nil {
+			continue
+		}
+		var match bool
+		if hash != nil {
+			match = fp == *hash || truststore.SPKIFingerprint(cert) == *hash
+		} else {
+			fields := append([]string{cert.Subject.CommonName, cert.Subject.String(), truststore.CAInfos[fp].Operator}, cert.Subject.Organization...)
+			for _, field := range fields {
+				if strings.Contains(strings.ToLower(field), needle) {
+					match = true
+					break
+				}
+			}
+		}
+		if match {
+			matches = append(matches, fp)
+		}
+	}
+	return matches
+}
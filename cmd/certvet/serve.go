@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/dataupdate"
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/server"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+var (
+	serveListen     string
+	serveTimeout    time.Duration
+	serveNoAIA      bool
+	serveNoValidate bool
+	serveRefresh    time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve validation and trust store data over HTTP",
+	Long: `Run an HTTP API so other services can use certvet without shelling out.
+
+Endpoints:
+  GET /validate?endpoint=example.com[&filter=ios>=15][&verify_hostname=true]
+                                               Validation report (validate -j format)
+  GET /stores[?filter=ios>=15]                 Store entries (list -j format)
+  GET /data/stores[?platform=ios&version=18]   Store records with roots and constraints (JSON)
+  GET /data/certs/{fingerprint}[?format=pem]   Root or cross-signed intermediate (JSON or PEM)
+
+The server listens on localhost only by default. /validate connects to any endpoint a
+client names; use --no-validate when the listener is reachable by untrusted clients.
+
+With --data-refresh, the --data source is reloaded at that interval and requests are served
+from the new data once it loads; a failed reload keeps the current data.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet serve --listen 127.0.0.1:8080
+  certvet serve --listen :8080 --no-validate
+  curl 'localhost:8080/validate?endpoint=example.com&filter=ios>=15'
+  curl 'localhost:8080/data/stores?platform=ios&version=18'
+  certvet serve --data https://github.com/ivoronin/certvet/releases/latest/download/data-bundle.tar.gz --data-refresh 24h`,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Listen `address` (e.g., :8080 for all interfaces)")
+	serveCmd.Flags().DurationVar(&serveTimeout, "timeout", 10*time.Second, "Connection timeout for validated endpoints")
+	serveCmd.Flags().BoolVar(&serveNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+	serveCmd.Flags().BoolVar(&serveNoValidate, "no-validate", false, "Disable the /validate endpoint")
+	serveCmd.Flags().DurationVar(&serveRefresh, "data-refresh", 0, "Reload the --data source at this `interval` (0 disables)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if serveRefresh > 0 {
+		if dataFrom == "" {
+			return fmt.Errorf("--data-refresh requires --data")
+		}
+		if storesCSV != "" || certsCSV != "" || len(customStores) > 0 {
+			return fmt.Errorf("--data-refresh cannot be combined with --stores-csv, --certs-csv or --custom-store")
+		}
+	}
+
+	var current atomic.Pointer[server.Server]
+	current.Store(configureServer(server.New(truststore.Stores, truststore.Certs, truststore.CrossSigns).WithDataVersion(dataVersion())))
+	if serveRefresh > 0 {
+		go refreshData(&current, serveRefresh)
+	}
+
+	srv := &http.Server{
+		Addr:              serveListen,
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { current.Load().ServeHTTP(w, r) }),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", serveListen)
+	return srv.ListenAndServe()
+}
+
+// configureServer enables validation on s as set by the serve flags.
+func configureServer(s *server.Server) *server.Server {
+	if serveNoValidate {
+		return s
+	}
+	validation := server.Validation{Source: fetcher.TLSSource{Timeout: serveTimeout}, ToolVersion: Version}
+	if !serveNoAIA {
+		// A cache per request, so failed downloads are retried and memory stays bounded
+		validation.Issuers = func() validator.IssuerFetcher { return fetcher.NewIssuerCache(serveTimeout).Fetch }
+	}
+	return s.WithValidation(validation)
+}
+
+// refreshData reloads the --data source every interval and swaps in a server for the new
+// data. Requests in flight finish on the data they started with.
+func refreshData(current *atomic.Pointer[server.Server], interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ds, meta, err := dataupdate.Open(dataFrom, dataupdate.PublicKey, dataTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: keeping current trust data, cannot reload %s: %v\n", dataFrom, err)
+			continue
+		}
+		current.Store(configureServer(server.NewFromDataset(ds).WithDataVersion(meta.Version)))
+		fmt.Fprintf(os.Stderr, "Loaded trust data %s (created %s)\n", meta.Version, meta.Created.Format(truststore.DateFormat))
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/testchains"
+)
+
+var testchainsJSON bool
+
+var testchainsCmd = &cobra.Command{
+	Use:   "testchains",
+	Short: "Validate built-in broken chains and compare rule codes",
+	Long: `Generate deliberately broken certificate chains (expired, wrong order, SHA-1, constrained CA, ...)
+and run them through the validation pipeline against a synthetic trust store.
+
+Prints the expected and actual rule code for each fixture, so automation built on certvet can be
+checked against every failure class. Exits with code 1 if any fixture doesn't match.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet testchains
+  certvet testchains -j`,
+	RunE: runTestchains,
+}
+
+func init() {
+	testchainsCmd.Flags().BoolVarP(&testchainsJSON, "json", "j", false, "Output in JSON format")
+}
+
+func runTestchains(cmd *cobra.Command, args []string) error {
+	outcomes, err := testchains.Run()
+	if err != nil {
+		return err
+	}
+
+	format := output.FormatText
+	if testchainsJSON {
+		format = output.FormatJSON
+	}
+
+	to := output.NewTestChainsOutput(outcomes)
+	result, err := output.FormatOutput(to, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+
+	if !to.AllOK() {
+		os.Exit(ExitTrustFail)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/testutil"
+)
+
+func TestTestchainsCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantSubstrs []string
+	}{
+		{
+			name:        "text output",
+			args:        []string{"testchains"},
+			wantSubstrs: []string{"FIXTURE", "EXPECTED", "ACTUAL", "insecure_algorithm", "OK"},
+		},
+		{
+			name:        "json output",
+			args:        []string{"testchains", "-j"},
+			wantSubstrs: []string{`"fixtures":`, `"all_ok": true`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := testutil.RunCLI(t, tt.args...)
+
+			if result.ExitCode != ExitSuccess {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", result.ExitCode, ExitSuccess, result.Stdout, result.Stderr)
+			}
+			for _, want := range tt.wantSubstrs {
+				if !strings.Contains(result.Stdout, want) {
+					t.Errorf("stdout should contain %q, got:\n%s", want, result.Stdout)
+				}
+			}
+			if strings.Contains(result.Stdout, "MISMATCH") {
+				t.Errorf("unexpected fixture mismatch:\n%s", result.Stdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/dataupdate"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	updateURL     string
+	updateTimeout time.Duration
+	embeddedData  bool
+	dataFrom      string
+
+	// installedData describes the data loaded by loadInstalledData (nil for embedded data).
+	installedData *dataupdate.Metadata
+)
+
+// dataTimeout bounds downloading a --data bundle.
+const dataTimeout = 60 * time.Second
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download the latest trust store data without upgrading certvet",
+	Long: `Download the signed trust data bundle published with the latest release and install it
+in the user cache directory. Other commands use the installed data instead of the data embedded
+in the binary while it is newer; pass --embedded-data to ignore it.
+
+The bundle signature is verified against the key built into release binaries before anything
+is installed, so dev builds cannot install bundles.`,
+	Args: cobra.NoArgs,
+	Example: `  certvet update
+  certvet validate example.com --embedded-data`,
+	RunE: runUpdate,
+}
+
+func init() {
+	updateCmd.Flags().StringVar(&updateURL, "url", dataupdate.DefaultURL, "Data bundle URL (signature is fetched from `url`.sig)")
+	updateCmd.Flags().DurationVar(&updateTimeout, "timeout", 60*time.Second, "Download timeout")
+	rootCmd.PersistentFlags().BoolVar(&embeddedData, "embedded-data", false, "Use trust data embedded in the binary, ignoring data installed by update")
+	rootCmd.PersistentFlags().StringVar(&dataFrom, "data", "", "Load trust data from a bundle `path|url` or an unpacked data directory instead of installed data")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	dir, err := dataupdate.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	bundle, sig, err := dataupdate.Download(updateURL, updateTimeout)
+	if err != nil {
+		return err
+	}
+	if err := dataupdate.Verify(dataupdate.PublicKey, bundle, sig); err != nil {
+		return err
+	}
+	meta, err := dataupdate.Install(dir, bundle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed trust data %s (created %s) to %s\n", meta.Version, meta.Created.Format(truststore.DateFormat), dir)
+	if !meta.NewerThan(Version) {
+		fmt.Printf("Note: certvet %s embeds data at least as new; installed data is used once it is newer.\n", Version)
+	}
+	return nil
+}
+
+// loadInstalledData switches to trust data installed by update when it is newer than the
+// embedded data, or to the --data source regardless of its age. Unusable data is reported
+// and the embedded data is used.
+func loadInstalledData(cmd *cobra.Command, args []string) error {
+	if dataFrom != "" && embeddedData {
+		return fmt.Errorf("--data and --embedded-data are mutually exclusive")
+	}
+	if embeddedData || cmd == updateCmd {
+		return nil
+	}
+	if dataFrom != "" {
+		ds, meta, err := dataupdate.Open(dataFrom, dataupdate.PublicKey, dataTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: using embedded trust data, cannot load %s: %v\n", dataFrom, err)
+			return nil
+		}
+		truststore.Use(ds)
+		installedData = &meta
+		return nil
+	}
+	dir, err := dataupdate.DefaultDir()
+	if err != nil {
+		return nil //nolint:nilerr // No cache directory means nothing can be installed
+	}
+
+	ds, meta, err := dataupdate.Load(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: ignoring installed trust data in %s: %v\n", dir, err)
+		return nil
+	}
+	if meta.NewerThan(Version) {
+		truststore.Use(ds)
+		installedData = &meta
+	}
+	return nil
+}
+
+// dataVersion returns the version of the trust data in use. Embedded data has the version
+// of the binary.
+func dataVersion() string {
+	if installedData == nil {
+		return Version
+	}
+	return installedData.Version
+}
+
+// dataSource describes where the trust data in use was loaded from.
+func dataSource() string {
+	if installedData == nil {
+		return "embedded"
+	}
+	origin := "installed"
+	if dataFrom != "" {
+		origin = dataFrom
+	}
+	return fmt.Sprintf("%s %s (created %s)", origin, installedData.Version, installedData.Created.Format(truststore.DateFormat))
+}
@@ -3,58 +3,222 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ivoronin/certvet/internal/advisory"
+	"github.com/ivoronin/certvet/internal/endpoints"
 	"github.com/ivoronin/certvet/internal/fetcher"
 	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/issues"
 	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/progress"
 	"github.com/ivoronin/certvet/internal/truststore"
 	"github.com/ivoronin/certvet/internal/validator"
 )
 
 var (
-	validateJSON    bool
-	validateFilter  string
-	validateTimeout time.Duration
+	validateJSON      bool
+	validateOutput    string
+	validateFilter    []string
+	validateTimeout   time.Duration
+	validateAdvise    bool
+	validateFeed      string
+	validateFailFast  bool
+	validateFirstFail bool
+	validateStream    bool
+	validateSaveDir   string
+	validateHostname  bool
+	validateStdin     bool
+	validateRedact    output.Redaction
+	validateSummary   bool
+	validateAtTime    string
+	validateProbeTLS  bool
+	validateLookahead string
+	validateSuggest   bool
+	validateExtra     []string
+	validateReplace   string
+	validateNoAIA     bool
+	validatePreloaded []string
+	validateUntil     bool
+	validateEV        bool
+	validateShowChain bool
+	validateICS       string
+	validateIssue     string
+	validateFailOn    string
+	validateWorkers   int
+	validateRate      float64
+	validateJitter    time.Duration
 )
 
 var validateCmd = &cobra.Command{
-	Use:   "validate <endpoint>",
-	Short: "Check certificate trust for an endpoint",
-	Long:  `Fetch SSL certificate chain from endpoint and validate against mobile trust stores.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "validate [endpoint...]",
+	Short: "Check certificate trust for one or more endpoints",
+	Long: `Fetch SSL certificate chain from each endpoint and validate against mobile trust stores.
+
+With multiple endpoints, results are combined into a single table (or JSON, CSV, SARIF or TAP document)
+and connection errors are reported per endpoint instead of aborting the run. Endpoints are fetched
+--concurrency at a time; --rate-limit and --jitter pace connections to avoid tripping rate limits.
+
+With --progressive, each result is printed as soon as its platform is validated (a table row, or
+an NDJSON line with -o json) instead of in a final table or document. --first-failure stops
+validating an endpoint at its first failing platform, skipping slower checks of the rest.
+
+With --stdin, endpoints are also read from standard input, one per line. Lines may be
+plain endpoints, URLs, or NDJSON objects with per-endpoint options:
+
+  {"endpoint": "api.example.com:8443", "timeout": "30s", "verify_hostname": true}
+
+` + exitCodesHelp,
+	Args: cobra.ArbitraryArgs,
 	Example: `  certvet validate example.com
   certvet validate -j example.com
-  certvet validate -f 'ios>=15' example.com`,
+  certvet validate -o csv --stdin < endpoints.txt > results.csv
+  certvet validate -o sarif --lookahead 90d --stdin < endpoints.txt > certvet.sarif
+  certvet validate -o tap -f 'ios>=16' example.com
+  certvet validate -o go-template='{{range .results}}{{if not .trusted}}{{.platform}} {{.version}}{{"\n"}}{{end}}{{end}}' example.com
+  certvet validate -f 'ios>=15' example.com
+  certvet validate --advisories example.com
+  certvet validate --verify-hostname example.com
+  certvet validate --fail-fast api.example.com www.example.com
+  certvet validate --progressive --first-failure -o json --stdin < endpoints.txt
+  certvet validate --fail-on warn --lookahead 30d example.com
+  certvet validate --stdin --summary < endpoints.txt
+  certvet validate --save-chain chains/ example.com
+  certvet validate --stdin --ics deadlines.ics < endpoints.txt
+  GITHUB_TOKEN=... certvet validate --stdin --create-issue github:acme/ops < endpoints.txt
+  certvet validate --at-time 2026-06-01 example.com
+  certvet validate --lookahead 90d example.com
+  certvet validate --trusted-until example.com
+  certvet validate --show-chain -f 'android' example.com
+  certvet validate --suggest-chains example.com
+  certvet validate --replace-leaf new-cert.pem example.com
+  certvet validate --extra-roots corp-root.pem:ios intranet.example.com
+  certvet validate --redact-endpoints --truncate-fingerprints 4 internal.example.com
+  certvet validate --stdin --concurrency 32 --rate-limit 2 --jitter 500ms < endpoints.txt
+  subfinder -d example.com | certvet validate --stdin`,
 	RunE: runValidate,
 }
 
 func init() {
 	validateCmd.Flags().BoolVarP(&validateJSON, "json", "j", false, "Output in JSON format")
-	validateCmd.Flags().StringVarP(&validateFilter, "filter", "f", "", "Filter expression (e.g., ios>=15,android>=10)")
+	validateCmd.Flags().StringVarP(&validateOutput, "output", "o", "text", "Output `format`: text, json, csv, sarif, tap, go-template=TEMPLATE or go-template-file=PATH")
+	validateCmd.Flags().StringArrayVarP(&validateFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
 	validateCmd.Flags().DurationVar(&validateTimeout, "timeout", 10*time.Second, "Connection timeout")
+	validateCmd.Flags().BoolVar(&validateAdvise, "advisories", false, "Annotate results with known CA incident advisories")
+	validateCmd.Flags().StringVar(&validateFeed, "advisory-feed", advisory.DefaultFeedURL, "Advisory feed URL or file path")
+	validateCmd.Flags().BoolVar(&validateFailFast, "fail-fast", false, "Stop at the first endpoint that fails trust validation (fetches endpoints one at a time)")
+	validateCmd.Flags().BoolVar(&validateFirstFail, "first-failure", false, "Stop validating an endpoint at its first failing platform (later platforms are not reported)")
+	validateCmd.Flags().BoolVar(&validateStream, "progressive", false, "Print each result as soon as it is validated (text rows, or NDJSON with -o json)")
+	validateCmd.Flags().IntVar(&validateWorkers, "concurrency", 8, "Maximum endpoints fetched at once")
+	validateCmd.Flags().Float64Var(&validateRate, "rate-limit", 0, "Maximum connections per second to each host (0 = unlimited)")
+	validateCmd.Flags().DurationVar(&validateJitter, "jitter", 0, "Random delay of up to `duration` before each connection")
+	validateCmd.Flags().StringVar(&validateFailOn, "fail-on", failOnError, "Results that fail the exit code: `policy` error (trust failures), warn (also warnings and forecasts) or never")
+	validateCmd.Flags().BoolVar(&validateHostname, "verify-hostname", false, "Also verify the certificate covers the endpoint hostname")
+	validateCmd.Flags().BoolVar(&validateProbeTLS, "probe-tls", false, "Probe the lowest TLS version accepted and note platforms it excludes")
+	validateCmd.Flags().BoolVar(&validateStdin, "stdin", false, "Read additional endpoints from stdin (plain or NDJSON lines)")
+	validateCmd.Flags().StringVar(&validateSaveDir, "save-chain", "", "Save fetched and verified chains as PEM files under `dir`")
+	validateCmd.Flags().StringVar(&validateICS, "ics", "", "Write upcoming certificate expiry and root distrust dates to `file` as an iCalendar calendar")
+	validateCmd.Flags().StringVar(&validateIssue, "create-issue", "", "File, update and close deduplicated issues for failing endpoints in `tracker` (github:owner/repo or jira:PROJECT)")
+	validateCmd.Flags().StringVar(&validateAtTime, "at-time", "", "Evaluate validity and distrust dates as of `time` (YYYY-MM-DD or RFC 3339)")
+	validateCmd.Flags().StringVar(&validateLookahead, "lookahead", "", "Report passing results that will fail within `window` (e.g., 90d, 720h)")
+	validateCmd.Flags().BoolVar(&validateUntil, "trusted-until", false, "Add a TRUSTED UNTIL column with the last date each passing platform stays trusted")
+	validateCmd.Flags().BoolVar(&validateEV, "ev", false, "Check that passing platforms recognize the certificate as Extended Validation (warn if not)")
+	validateCmd.Flags().StringArrayVar(&validateExtra, "extra-roots", nil, "Also validate with roots from `file.pem[:platform]` installed (repeatable)")
+	validateCmd.Flags().StringVar(&validateReplace, "replace-leaf", "", "Compare trust with a candidate leaf (and intermediates) from `file.pem` substituted")
+	validateCmd.Flags().BoolVar(&validateNoAIA, "no-aia", false, "Don't fetch missing intermediates from AIA URLs, even for platforms whose clients do")
+	validateCmd.Flags().StringSliceVar(&validatePreloaded, "preloaded-intermediates", nil, "Complete chains missing intermediates from the preloaded set (Firefox, Windows auto-fetch) for `platforms`")
+	validateCmd.Flags().BoolVar(&validateSuggest, "suggest-chains", false, "For failing platforms, suggest cross-signed intermediates that would fix trust")
+	validateCmd.Flags().BoolVar(&validateShowChain, "show-chain", false, "Show each platform's verified path (or where it broke), anchoring root and root constraints")
+	validateCmd.Flags().BoolVar(&validateSummary, "summary", false, "With multiple endpoints, group failures and count anchoring roots")
+	validateCmd.Flags().BoolVar(&validateRedact.Endpoints, "redact-endpoints", false, "Replace endpoint hostnames with placeholders and drop leaf names and fingerprint in output")
+	validateCmd.Flags().IntVar(&validateRedact.NameLength, "truncate-names", 0, "Truncate CA names to `n` characters in output (0 = full)")
+	validateCmd.Flags().IntVar(&validateRedact.FingerprintOctets, "truncate-fingerprints", 0, "Truncate fingerprints to `n` octets in output (0 = full)")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	endpoint := args[0]
-
-	// Parse filter
-	var f *filter.Filter
-	if validateFilter != "" {
-		var err error
-		f, err = filter.Parse(validateFilter)
+	targets, err := endpoints.FromArgs(args)
+	if err != nil {
+		return err
+	}
+	if validateStdin {
+		stdinTargets, err := endpoints.Parse(cmd.InOrStdin())
 		if err != nil {
-			return fmt.Errorf("invalid filter: %w", err)
+			return err
 		}
+		targets = append(targets, stdinTargets...)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("requires at least 1 endpoint")
+	}
+	if validateReplace != "" && len(targets) > 1 {
+		return fmt.Errorf("--replace-leaf requires exactly 1 endpoint")
+	}
+
+	// Parse filter
+	f, err := filter.ParseAll(validateFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
 	}
 
-	// Fetch chain
-	chain, err := fetcher.FetchCertChain(endpoint, validateTimeout)
+	out, err := parseOutput(validateJSON, validateOutput)
+	if err != nil {
+		return err
+	}
+	failOn, err := parseFailOn(validateFailOn)
+	if err != nil {
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	batch, err := newBatch(validateWorkers, validateRate, validateJitter)
 	if err != nil {
 		return err
 	}
+	if validateReplace != "" {
+		// A what-if comparison leaves the served chain's artifacts to a plain run
+		switch {
+		case out.format != output.FormatText && out.format != output.FormatJSON:
+			return fmt.Errorf("--replace-leaf supports only text, JSON and template output")
+		case validateSaveDir != "":
+			return fmt.Errorf("--replace-leaf conflicts with --save-chain")
+		case validateICS != "":
+			return fmt.Errorf("--replace-leaf conflicts with --ics")
+		case validateIssue != "":
+			return fmt.Errorf("--replace-leaf conflicts with --create-issue")
+		}
+	}
+	if validateStream {
+		switch {
+		case out.template != nil || out.format != output.FormatText && out.format != output.FormatJSON:
+			return fmt.Errorf("--progressive supports only text and JSON output")
+		case validateReplace != "":
+			return fmt.Errorf("--progressive conflicts with --replace-leaf")
+		case validateSummary:
+			return fmt.Errorf("--progressive conflicts with --summary")
+		case validateShowChain:
+			return fmt.Errorf("--progressive conflicts with --show-chain")
+		}
+	}
+
+	var evaluatedAt time.Time
+	if validateAtTime != "" {
+		evaluatedAt, err = parseAtTime(validateAtTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	var lookahead time.Duration
+	if validateLookahead != "" {
+		lookahead, err = parseLookahead(validateLookahead)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Get and filter stores
 	stores := filter.FilterStores(truststore.Stores, f)
@@ -63,8 +227,239 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no trust stores match filter")
 	}
 
-	// Validate
-	results := validator.ValidateChain(chain, stores)
+	// Stores with user-supplied roots are validated alongside the stock ones
+	if len(validateExtra) > 0 {
+		extras := make([]truststore.ExtraRoots, len(validateExtra))
+		for i, spec := range validateExtra {
+			if extras[i], err = truststore.LoadExtraRoots(spec); err != nil {
+				return err
+			}
+			extras[i].Register()
+		}
+		stores = truststore.WithExtraRoots(stores, extras)
+	}
+
+	// Tracker credentials are checked before any endpoint is fetched
+	var tracker issues.Tracker
+	if validateIssue != "" {
+		if tracker, err = issues.NewTracker(validateIssue, validateTimeout); err != nil {
+			return err
+		}
+	}
+
+	// Fetch advisory feed once for all endpoints
+	var feed *advisory.Feed
+	if validateAdvise {
+		var err error
+		feed, err = advisory.Fetch(validateFeed, validateTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Root pools are prepared once and shared by all endpoints
+	v := validator.New(stores).WithTime(evaluatedAt).WithLookahead(lookahead).WithTrustedUntil(validateUntil).
+		WithEV(validateEV).WithFirstFailure(validateFirstFail)
+	if !validateNoAIA {
+		v = v.WithIssuerFetcher(fetcher.NewIssuerCache(validateTimeout).Fetch)
+	}
+	if len(validatePreloaded) > 0 {
+		if v, err = withPreloaded(v, validatePreloaded); err != nil {
+			return err
+		}
+	}
+	if validateSuggest {
+		crossSigns, err := truststore.CrossSigns.LoadAll()
+		if err != nil {
+			return fmt.Errorf("load cross-signed intermediates: %w", err)
+		}
+		v = v.WithCrossSigns(crossSigns)
+	}
+
+	// Results are printed as they are validated with --progressive, which replaces progress bars
+	var stream *progressiveResults
+	if validateStream {
+		if stream, err = newProgressiveResults(out.format, targets); err != nil {
+			return err
+		}
+		v = stream.validator(v)
+	}
+	newBar := func(label string) *progress.Bar {
+		if stream != nil {
+			return nil
+		}
+		return newProgress(label)
+	}
+
+	// Single endpoint: connection errors abort the run
+	if len(targets) == 1 {
+		chain, err := fetchTarget(targets[0])
+		if err != nil {
+			return &exitError{code: ExitConnectionError, err: err}
+		}
+		stream.add(targets[0].Endpoint, chain)
+		bar := newBar("Validating")
+		report := buildReport(targets[0], chain, v.WithProgress(bar.Update).Validate(chain), feed, evaluatedAt)
+		bar.Finish()
+		stream.report(report)
+		if validateReplace != "" {
+			candidate, err := candidateChain(chain, validateReplace)
+			if err != nil {
+				return err
+			}
+			candidateReport := buildReport(targets[0], candidate, v.Validate(candidate), feed, evaluatedAt)
+			ro := output.NewReplacementOutput(report, candidateReport)
+			ro.Redaction = validateRedact
+			return printValidation(ro, out, validationExitCode(failOn, candidateReport))
+		}
+		if err := saveChains(report); err != nil {
+			return err
+		}
+		if err := saveCalendar(stores, report); err != nil {
+			return err
+		}
+		if err := syncIssues(tracker, report); err != nil {
+			return err
+		}
+		if stream != nil {
+			return exitWith(validationExitCode(failOn, report))
+		}
+		vo := output.NewValidationOutput(report)
+		vo.Redaction = validateRedact
+		vo.ShowChain = validateShowChain
+		return printValidation(vo, out, validationExitCode(failOn, report))
+	}
+
+	// Bulk: record per-endpoint errors and keep going
+	var reports []*truststore.ValidationReport
+	if validateFailFast {
+		// Validate each endpoint as soon as it's fetched so the run can stop early
+		bar := newBar("Validating")
+		for i, t := range targets {
+			batch.Wait(t.Endpoint)
+			chain, err := fetchTarget(t)
+			bar.Update(i+1, len(targets))
+			if err != nil {
+				reports = append(reports, errorReport(t.Endpoint, err))
+				stream.report(reports[len(reports)-1])
+				continue
+			}
+			stream.add(t.Endpoint, chain)
+			report := buildReport(t, chain, v.Validate(chain), feed, evaluatedAt)
+			stream.report(report)
+			reports = append(reports, report)
+			if !report.AllPassed {
+				break
+			}
+		}
+		bar.Finish()
+	} else {
+		// Fetch everything, then validate all (endpoint, store) pairs in one batch
+		reports = make([]*truststore.ValidationReport, len(targets))
+		fetched := make([]*truststore.CertChain, len(targets))
+		names := make([]string, len(targets))
+		for i, t := range targets {
+			names[i] = t.Endpoint
+		}
+		var done atomic.Int64
+		bar := newBar("Fetching")
+		batch.Run(names, func(i int) {
+			chain, err := fetchTarget(targets[i])
+			bar.Update(int(done.Add(1)), len(targets))
+			if err != nil {
+				reports[i] = errorReport(targets[i].Endpoint, err)
+				stream.report(reports[i])
+				return
+			}
+			fetched[i] = chain
+		})
+		bar.Finish()
+		var chains []*truststore.CertChain
+		var chainIdx []int
+		for i, chain := range fetched {
+			if chain != nil {
+				chains = append(chains, chain)
+				chainIdx = append(chainIdx, i)
+				stream.add(targets[i].Endpoint, chain)
+			}
+		}
+		bar = newBar("Validating")
+		for j, results := range v.WithProgress(bar.Update).ValidateAll(chains) {
+			i := chainIdx[j]
+			reports[i] = buildReport(targets[i], chains[j], results, feed, evaluatedAt)
+			stream.report(reports[i])
+		}
+		bar.Finish()
+	}
+
+	if err := saveChains(reports...); err != nil {
+		return err
+	}
+	if err := saveCalendar(stores, reports...); err != nil {
+		return err
+	}
+	if err := syncIssues(tracker, reports...); err != nil {
+		return err
+	}
+
+	if stream != nil {
+		return exitWith(validationExitCode(failOn, reports...))
+	}
+	bo := output.NewBulkValidationOutput(reports)
+	bo.Redaction = validateRedact
+	bo.Summary = validateSummary
+	bo.ShowChain = validateShowChain
+	return printValidation(bo, out, validationExitCode(failOn, reports...))
+}
+
+// newBatch paces bulk fetches as set by --concurrency, --rate-limit and --jitter.
+func newBatch(workers int, rate float64, jitter time.Duration) (*fetcher.Batch, error) {
+	switch {
+	case workers < 1:
+		return nil, fmt.Errorf("invalid --concurrency: must be at least 1")
+	case rate < 0:
+		return nil, fmt.Errorf("invalid --rate-limit: must not be negative")
+	case jitter < 0:
+		return nil, fmt.Errorf("invalid --jitter: must not be negative")
+	}
+	b := &fetcher.Batch{Concurrency: workers, Jitter: jitter}
+	if rate > 0 {
+		b.HostInterval = time.Duration(float64(time.Second) / rate)
+	}
+	return b, nil
+}
+
+// fetchTarget fetches a target's chain (through the chain cache if enabled), honoring its timeout override,
+// and probes the minimum TLS version if --probe-tls is set.
+func fetchTarget(t endpoints.Target) (*truststore.CertChain, error) {
+	timeout := validateTimeout
+	if t.Timeout > 0 {
+		timeout = t.Timeout
+	}
+	chain, err := chainSource(timeout).FetchChain(t.Endpoint)
+	if err != nil || !validateProbeTLS || chain.TLS != nil && chain.TLS.MinVersion != 0 {
+		return chain, err // Cached chains may already be probed
+	}
+	if cachedOnly {
+		return nil, fmt.Errorf("%s: cached chain was fetched without --probe-tls", t.Endpoint)
+	}
+	if err := fetcher.ProbeMinVersion(chain, t.Endpoint, timeout); err != nil {
+		return nil, err
+	}
+	cacheChain(t.Endpoint, chain)
+	return chain, nil
+}
+
+// buildReport assembles a validation report, annotating advisories if a feed is given
+// and checking the hostname if requested by --verify-hostname or the target.
+// evaluatedAt is the --at-time value (zero if validated now).
+func buildReport(t endpoints.Target, chain *truststore.CertChain, results []truststore.TrustResult, feed *advisory.Feed, evaluatedAt time.Time) *truststore.ValidationReport {
+	// Match known CA incidents
+	var advisories []truststore.AdvisoryMatch
+	if feed != nil {
+		advisories = feed.Match(chain, results)
+		advisory.Annotate(chain, results, advisories)
+	}
 
 	// Check all passed
 	allPassed := true
@@ -75,32 +470,254 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Build report
-	report := &truststore.ValidationReport{
-		Endpoint:    endpoint,
+	// Hostname mismatch fails validation when explicitly requested
+	verifyHostname := validateHostname
+	if t.VerifyHostname != nil {
+		verifyHostname = *t.VerifyHostname
+	}
+
+	var hostname *truststore.HostnameCheck
+	if verifyHostname {
+		check := validator.VerifyHostname(chain)
+		hostname = &check
+		if !check.Valid {
+			allPassed = false
+		}
+	}
+
+	return &truststore.ValidationReport{
+		Endpoint:    t.Endpoint,
 		Timestamp:   time.Now(),
 		ToolVersion: Version,
+		DataVersion: dataVersion(),
+		DataDate:    truststore.Sources.Generated,
 		Chain:       *chain,
 		Results:     results,
 		AllPassed:   allPassed,
+		Advisories:  advisories,
+		Hostname:    hostname,
+		EvaluatedAt: evaluatedAt,
+	}
+}
+
+// candidateChain substitutes the leaf from a PEM file into a fetched chain for what-if validation.
+// Further certificates in the file replace the served intermediates; otherwise those are kept.
+// The stapled OCSP response and TLS-delivered SCTs belong to the served leaf and are dropped.
+func candidateChain(served *truststore.CertChain, path string) (*truststore.CertChain, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Path is user-specified candidate certificate
+	if err != nil {
+		return nil, fmt.Errorf("read candidate certificate: %w", err)
+	}
+	candidate, err := fetcher.ParsePEMChain(served.Endpoint, data)
+	if err != nil {
+		return nil, fmt.Errorf("candidate certificate %s: %w", path, err)
+	}
+	if len(candidate.Intermediates) == 0 {
+		candidate.Intermediates = served.Intermediates
+	}
+	candidate.TLS = served.TLS
+	return candidate, nil
+}
+
+// parseLookahead parses a --lookahead window: a number of days ("90d") or a Go duration.
+func parseLookahead(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid --lookahead %q: expected days (e.g., 90d) or a duration (e.g., 720h)", value)
+}
+
+// parseAtTime parses an --at-time value as a date (midnight UTC) or RFC 3339 timestamp.
+func parseAtTime(value string) (time.Time, error) {
+	if t, err := time.Parse(truststore.DateFormat, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --at-time %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
+
+// saveChains writes chain artifacts for each report if --save-chain is set.
+func saveChains(reports ...*truststore.ValidationReport) error {
+	if validateSaveDir == "" {
+		return nil
+	}
+	for _, report := range reports {
+		if err := output.SaveChain(validateSaveDir, report); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// saveCalendar writes upcoming trust-impacting dates from reports if --ics is set.
+func saveCalendar(stores []truststore.Store, reports ...*truststore.ValidationReport) error {
+	if validateICS == "" {
+		return nil
+	}
+	return output.SaveCalendar(validateICS, reports, stores)
+}
 
-	// Output
-	format := output.FormatText
-	if validateJSON {
+// syncIssues files failing reports in the --create-issue tracker (if set) and closes issues
+// of endpoints that pass again. Changes are listed on stderr.
+func syncIssues(tracker issues.Tracker, reports ...*truststore.ValidationReport) error {
+	if tracker == nil {
+		return nil
+	}
+	failing, passing := issues.FromReports(reports)
+	actions, err := issues.Sync(tracker, failing, passing)
+	for _, a := range actions {
+		fmt.Fprintf(os.Stderr, "Issue %s: %s\n", a.Kind, a.URL)
+	}
+	return err
+}
+
+// errorReport records an endpoint that could not be fetched in a bulk run.
+func errorReport(endpoint string, err error) *truststore.ValidationReport {
+	return &truststore.ValidationReport{
+		Endpoint:    endpoint,
+		Timestamp:   time.Now(),
+		ToolVersion: Version,
+		DataVersion: dataVersion(),
+		DataDate:    truststore.Sources.Generated,
+		Error:       err.Error(),
+	}
+}
+
+// outputSpec is a resolved -o/--output flag.
+type outputSpec struct {
+	format   output.Format
+	template *template.Template // Set for go-template and go-template-file
+}
+
+// parseOutput resolves the -o/--output flag; -j/--json is shorthand for -o json.
+func parseOutput(jsonFlag bool, spec string) (outputSpec, error) {
+	tmpl, err := output.ParseTemplate(spec)
+	if err != nil {
+		return outputSpec{}, err
+	}
+	if tmpl != nil {
+		if jsonFlag {
+			return outputSpec{}, fmt.Errorf("--json conflicts with --output go-template")
+		}
+		return outputSpec{template: tmpl}, nil
+	}
+
+	format, err := output.ParseFormat(spec)
+	if err != nil {
+		return outputSpec{}, err
+	}
+	if jsonFlag {
+		if format != output.FormatText && format != output.FormatJSON {
+			return outputSpec{}, fmt.Errorf("--json conflicts with --output %s", spec)
+		}
 		format = output.FormatJSON
 	}
-	vo := output.NewValidationOutput(report)
-	result, err := output.FormatOutput(vo, format)
+	return outputSpec{format: format}, nil
+}
+
+// isText reports whether output is the human-readable table.
+func (o outputSpec) isText() bool {
+	return o.template == nil && o.format == output.FormatText
+}
+
+// render formats f as selected, executing templates over its JSON document.
+func (o outputSpec) render(f output.Formatter) (string, error) {
+	if o.template != nil {
+		return output.ExecuteTemplate(f, o.template)
+	}
+	return output.FormatOutput(f, o.format)
+}
+
+// printValidation writes formatted output and exits with code unless it's ExitSuccess.
+func printValidation(f output.Formatter, out outputSpec, code int) error {
+	result, err := out.render(f)
 	if err != nil {
 		return err
 	}
 
 	fmt.Println(result)
+	return exitWith(code)
+}
 
-	// Exit with trust fail code if not all passed
-	if !allPassed {
-		os.Exit(ExitTrustFail)
+// exitWith exits with code unless it's ExitSuccess.
+func exitWith(code int) error {
+	if code != ExitSuccess {
+		os.Exit(code)
 	}
 	return nil
 }
+
+// progressiveResults prints results on stdout as they are validated with --progressive.
+// Methods of a nil *progressiveResults do nothing.
+type progressiveResults struct {
+	out       *output.ResultStream
+	endpoints map[*truststore.CertChain]string // Requested endpoint of each chain
+}
+
+func newProgressiveResults(format output.Format, targets []endpoints.Target) (*progressiveResults, error) {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.Endpoint
+	}
+	out, err := output.NewResultStream(os.Stdout, format, names)
+	if err != nil {
+		return nil, err
+	}
+	out.Redaction = validateRedact
+	return &progressiveResults{out: out, endpoints: make(map[*truststore.CertChain]string)}, nil
+}
+
+// validator returns v printing each result once it is complete.
+// Chains must be added before they are validated.
+func (p *progressiveResults) validator(v *validator.Validator) *validator.Validator {
+	return v.WithOptions(validator.Options{
+		OnResult: func(chain *truststore.CertChain, r *truststore.TrustResult) {
+			_ = p.out.Result(p.endpoints[chain], chain, *r)
+		},
+	})
+}
+
+// add records the endpoint chain was fetched from.
+func (p *progressiveResults) add(endpoint string, chain *truststore.CertChain) {
+	if p != nil {
+		p.endpoints[chain] = endpoint
+	}
+}
+
+// report prints endpoint-level failures of a finished report: fetch errors and hostname
+// mismatches, which aren't part of any result.
+func (p *progressiveResults) report(report *truststore.ValidationReport) {
+	switch {
+	case p == nil:
+	case report.Error != "":
+		_ = p.out.Error(report.Endpoint, report.Error)
+	case report.Hostname != nil && !report.Hostname.Valid:
+		_ = p.out.Error(report.Endpoint, report.Hostname.Error)
+	}
+}
+
+// withPreloaded returns v completing chains with the preloaded intermediates for the named
+// platforms.
+func withPreloaded(v *validator.Validator, names []string) (*validator.Validator, error) {
+	platforms := make([]truststore.Platform, 0, len(names))
+	for _, name := range names {
+		platform := truststore.Platform(strings.TrimSpace(name))
+		if !truststore.KnownPlatform(platform) {
+			return nil, fmt.Errorf("--preloaded-intermediates: unknown platform %q", name)
+		}
+		platforms = append(platforms, platform)
+	}
+	certs, err := truststore.Preloaded.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("load preloaded intermediates: %w", err)
+	}
+	return v.WithPreloaded(certs, platforms...), nil
+}
@@ -41,8 +41,118 @@ func TestValidateCommandInvalidEndpoint(t *testing.T) {
 	// Invalid hostname that won't resolve
 	result := testutil.RunCLI(t, "validate", "this-host-does-not-exist-12345.invalid")
 
-	if result.ExitCode == ExitSuccess {
-		t.Errorf("expected non-zero exit code for invalid endpoint")
+	if result.ExitCode != ExitConnectionError {
+		t.Errorf("exit code = %d, want %d for invalid endpoint", result.ExitCode, ExitConnectionError)
 	}
 }
 
+func TestValidateCommandInvalidFailOn(t *testing.T) {
+	t.Parallel()
+
+	result := testutil.RunCLI(t, "validate", "--fail-on", "sometimes", "example.com")
+
+	if result.ExitCode != ExitInputError {
+		t.Errorf("exit code = %d, want %d for invalid --fail-on", result.ExitCode, ExitInputError)
+	}
+	if !strings.Contains(result.Stderr, "invalid --fail-on") {
+		t.Errorf("stderr should mention invalid --fail-on, got:\n%s", result.Stderr)
+	}
+}
+
+func TestValidateCommandInvalidPacing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--concurrency", "0"}, "invalid --concurrency"},
+		{[]string{"--rate-limit", "-1"}, "invalid --rate-limit"},
+		{[]string{"--jitter", "-1s"}, "invalid --jitter"},
+	}
+	for _, tt := range tests {
+		result := testutil.RunCLI(t, append([]string{"validate"}, append(tt.args, "example.com")...)...)
+		if result.ExitCode != ExitInputError {
+			t.Errorf("%v: exit code = %d, want %d", tt.args, result.ExitCode, ExitInputError)
+		}
+		if !strings.Contains(result.Stderr, tt.want) {
+			t.Errorf("%v: stderr should mention %s, got:\n%s", tt.args, tt.want, result.Stderr)
+		}
+	}
+}
+
+func TestValidateCommandProgressiveConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-o", "csv"}, "supports only text and JSON"},
+		{[]string{"-o", "go-template={{.endpoint}}"}, "supports only text and JSON"},
+		{[]string{"--summary"}, "conflicts with --summary"},
+		{[]string{"--show-chain"}, "conflicts with --show-chain"},
+	}
+	for _, tt := range tests {
+		result := testutil.RunCLI(t, append([]string{"validate", "--progressive"}, append(tt.args, "example.com")...)...)
+		if result.ExitCode != ExitInputError {
+			t.Errorf("%v: exit code = %d, want %d", tt.args, result.ExitCode, ExitInputError)
+		}
+		if !strings.Contains(result.Stderr, tt.want) {
+			t.Errorf("%v: stderr should mention %s, got:\n%s", tt.args, tt.want, result.Stderr)
+		}
+	}
+}
+
+func TestValidateCommandReplaceLeafSingleEndpoint(t *testing.T) {
+	t.Parallel()
+
+	result := testutil.RunCLI(t, "validate", "--replace-leaf", "new-cert.pem", "a.example.com", "b.example.com")
+
+	if result.ExitCode != ExitInputError {
+		t.Errorf("exit code = %d, want %d for --replace-leaf with several endpoints", result.ExitCode, ExitInputError)
+	}
+	if !strings.Contains(result.Stderr, "exactly 1 endpoint") {
+		t.Errorf("stderr should explain the endpoint limit, got:\n%s", result.Stderr)
+	}
+}
+
+func TestValidateCommandReplaceLeafConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--save-chain", "chains"}, "conflicts with --save-chain"},
+		{[]string{"--ics", "expiry.ics"}, "conflicts with --ics"},
+		{[]string{"--create-issue", "github:owner/repo"}, "conflicts with --create-issue"},
+	}
+	for _, tt := range tests {
+		result := testutil.RunCLI(t, append([]string{"validate", "--replace-leaf", "new-cert.pem"}, append(tt.args, "example.com")...)...)
+		if result.ExitCode != ExitInputError {
+			t.Errorf("%v: exit code = %d, want %d", tt.args, result.ExitCode, ExitInputError)
+		}
+		if !strings.Contains(result.Stderr, tt.want) {
+			t.Errorf("%v: stderr should mention %s, got:\n%s", tt.args, tt.want, result.Stderr)
+		}
+	}
+}
+
+func TestValidateCommandBulkErrors(t *testing.T) {
+	t.Parallel()
+
+	result := testutil.RunCLI(t, "validate", "--fail-fast",
+		"this-host-does-not-exist-12345.invalid", "another-host-does-not-exist-12345.invalid")
+
+	if result.ExitCode != ExitConnectionError {
+		t.Errorf("exit code = %d, want %d for unreachable endpoints", result.ExitCode, ExitConnectionError)
+	}
+
+	// Connection errors don't trigger fail-fast; both endpoints are reported
+	for _, host := range []string{"this-host-does-not-exist-12345.invalid", "another-host-does-not-exist-12345.invalid"} {
+		if !strings.Contains(result.Stdout, host) {
+			t.Errorf("stdout should mention %s, got:\n%s", host, result.Stdout)
+		}
+	}
+}
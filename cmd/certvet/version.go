@@ -3,31 +3,72 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/release"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	versionJSON      bool
+	versionCheckData bool
 )
 
-var versionJSON bool
+// versionCheckTimeout bounds the GitHub API requests made by --check-data.
+const versionCheckTimeout = 10 * time.Second
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version and trust store update date",
-	Long:  `Display certvet version and when the embedded trust stores were last updated.`,
-	Args:  cobra.NoArgs,
-	RunE:  runVersion,
+	Long: `Display certvet version and when the embedded trust stores were last updated.
+
+With --check-data, also query GitHub for the latest release and trust store data
+and exit with code 1 if either is newer than this build, or than the trust data
+installed by certvet update when it is in use. Nothing is downloaded.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
 }
 
 func init() {
 	versionCmd.Flags().BoolVarP(&versionJSON, "json", "j", false, "Output in JSON format")
+	versionCmd.Flags().BoolVar(&versionCheckData, "check-data", false, "Check whether a newer release or data snapshot is published")
 }
 
 func runVersion(cmd *cobra.Command, args []string) error {
+	var status *release.Status
+	if versionCheckData {
+		var dataCreated time.Time
+		if installedData != nil {
+			dataCreated = installedData.Created
+		}
+		var err error
+		status, err = release.Check(release.DefaultAPIURL, Version, dataCreated, versionCheckTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
 	if versionJSON {
 		info := struct {
-			Version string `json:"version"`
+			Version        string `json:"version"`
+			LatestRelease  string `json:"latest_release,omitempty"`
+			DataSource     string `json:"data_source,omitempty"`
+			DataUpdated    string `json:"data_updated,omitempty"`
+			BinaryOutdated *bool  `json:"binary_outdated,omitempty"`
+			DataOutdated   *bool  `json:"data_outdated,omitempty"`
 		}{
 			Version: Version,
 		}
+		if status != nil {
+			info.LatestRelease = status.LatestRelease
+			info.DataSource = dataSource()
+			info.DataUpdated = status.DataUpdated.Format(truststore.DateFormat)
+			info.BinaryOutdated = &status.BinaryOutdated
+			info.DataOutdated = &status.DataOutdated
+		}
 		out, err := json.Marshal(info)
 		if err != nil {
 			return err
@@ -35,6 +76,27 @@ func runVersion(cmd *cobra.Command, args []string) error {
 		fmt.Println(string(out))
 	} else {
 		fmt.Printf("certvet %s\n", Version)
+		if status != nil {
+			fmt.Printf("Latest release: %s%s\n", status.LatestRelease, outdatedSuffix(status.Known, status.BinaryOutdated))
+			fmt.Printf("Data in use:    %s\n", dataSource())
+			fmt.Printf("Data updated:   %s%s\n", status.DataUpdated.Format(truststore.DateFormat), outdatedSuffix(status.DataKnown, status.DataOutdated))
+		}
+	}
+
+	if status != nil && status.Outdated() {
+		os.Exit(ExitTrustFail)
 	}
 	return nil
 }
+
+// outdatedSuffix annotates a --check-data line.
+func outdatedSuffix(known, outdated bool) string {
+	switch {
+	case !known:
+		return " (unknown for dev build)"
+	case outdated:
+		return " (outdated)"
+	default:
+		return " (up to date)"
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var (
+	whoTrustsJSON   bool
+	whoTrustsFilter []string
+)
+
+var whoTrustsCmd = &cobra.Command{
+	Use:   "who-trusts <fingerprint|name>",
+	Short: "Show which platform versions trust a root CA",
+	Long: `Report every platform version whose trust store includes a root, with its constraints.
+
+The root is selected as in search: by SHA-256 fingerprint or SPKI hash, or by a
+case-insensitive substring of its name, organization or subject matching any number of roots.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  certvet who-trusts 'Entrust Root'
+  certvet who-trusts 43:DF:57:74:B0:3E:7F:EF:5F:E4:0D:93:1A:7B:ED:F1:BB:2E:6B:42:73:8C:4E:6D:38:41:10:3D:3A:A7:F3:39
+  certvet who-trusts -j -f 'ios>=17' DigiCert`,
+	RunE: runWhoTrusts,
+}
+
+func init() {
+	whoTrustsCmd.Flags().BoolVarP(&whoTrustsJSON, "json", "j", false, "Output in JSON format")
+	whoTrustsCmd.Flags().StringArrayVarP(&whoTrustsFilter, "filter", "f", nil, "Filter expression (e.g., ios>=15,android>=10); repeat for alternatives")
+}
+
+func runWhoTrusts(cmd *cobra.Command, args []string) error {
+	f, err := filter.ParseAll(whoTrustsFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	stores := filter.FilterStores(truststore.Stores, f)
+
+	fps := matchRoots(args[0])
+	format := output.FormatText
+	if whoTrustsJSON {
+		format = output.FormatJSON
+	}
+	result, err := output.FormatOutput(output.NewWhoTrustsOutput(args[0], fps, stores, rootName), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// matchRoots resolves a query to roots as search does, falling back to a fingerprint
+// that no embedded certificate has, so stores listing only the fingerprint are still found.
+func matchRoots(query string) []truststore.Fingerprint {
+	if fps := searchRoots(query); len(fps) > 0 {
+		return fps
+	}
+	if fp, err := truststore.ParseFingerprint(query); err == nil {
+		return []truststore.Fingerprint{fp}
+	}
+	return nil
+}
+
+// rootName resolves a root's display name from the embedded certificates.
+func rootName(fp truststore.Fingerprint) string {
+	if cert := truststore.Certs.Get(fp); cert != nil {
+		return truststore.CertName(cert)
+	}
+	return "-"
+}
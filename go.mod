@@ -9,6 +9,7 @@ require (
 	github.com/bufbuild/protocompile v0.14.1
 	github.com/google/go-cabfile v0.0.0-20220815135208-f9ac3a87fd26
 	github.com/hashicorp/go-retryablehttp v0.7.8
+	github.com/ivoronin/certvet/trustdata v0.20261016.0
 	github.com/spf13/cobra v1.10.2
 	go.mozilla.org/pkcs7 v0.9.0
 	google.golang.org/protobuf v1.36.11
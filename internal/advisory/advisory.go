@@ -0,0 +1,165 @@
+// Package advisory provides the known CA incident advisory feed.
+//
+// The feed is a JSON document maintained in the certvet repository (advisories.json)
+// that maps root and intermediate fingerprints to incidents and distrust timelines.
+package advisory
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// DefaultFeedURL is the advisory feed published from the certvet repository.
+const DefaultFeedURL = "https://raw.githubusercontent.com/ivoronin/certvet/main/advisories.json"
+
+// Feed is a parsed advisory feed.
+type Feed struct {
+	Updated    string     `json:"updated"`
+	Advisories []Advisory `json:"advisories"`
+}
+
+// Advisory describes a CA incident affecting one or more certificates.
+type Advisory struct {
+	ID           string                   `json:"id"`
+	Title        string                   `json:"title"`
+	URL          string                   `json:"url,omitempty"`
+	Fingerprints []truststore.Fingerprint `json:"fingerprints"`
+	Timeline     []Event                  `json:"timeline,omitempty"`
+}
+
+// Event is a dated timeline entry; Date uses truststore.DateFormat (YYYY-MM-DD).
+type Event struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+// Fetch loads a feed from an http(s) URL or a local file path.
+func Fetch(source string, timeout time.Duration) (*Feed, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source) //nolint:gosec // G304: User-specified feed path
+		if err != nil {
+			return nil, fmt.Errorf("open advisory feed: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		return Parse(f)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("fetch advisory feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch advisory feed: status %d", resp.StatusCode)
+	}
+
+	return Parse(resp.Body)
+}
+
+// Parse decodes and validates a feed.
+func Parse(r io.Reader) (*Feed, error) {
+	var feed Feed
+	if err := json.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode advisory feed: %w", err)
+	}
+
+	for _, a := range feed.Advisories {
+		if a.ID == "" {
+			return nil, fmt.Errorf("advisory %q: missing id", a.Title)
+		}
+		for _, e := range a.Timeline {
+			if _, err := time.Parse(truststore.DateFormat, e.Date); err != nil {
+				return nil, fmt.Errorf("advisory %s: invalid date %q: %w", a.ID, e.Date, err)
+			}
+		}
+	}
+
+	return &feed, nil
+}
+
+// Match returns advisories affecting any certificate in the presented chain
+// or any root that anchored a verified chain in results.
+func (f *Feed) Match(chain *truststore.CertChain, results []truststore.TrustResult) []truststore.AdvisoryMatch {
+	certs := chainCertificates(chain, results)
+
+	var matches []truststore.AdvisoryMatch
+	for _, a := range f.Advisories {
+		for _, fp := range a.Fingerprints {
+			cert, ok := certs[fp]
+			if !ok {
+				continue
+			}
+			matches = append(matches, truststore.AdvisoryMatch{
+				ID:          a.ID,
+				Title:       a.Title,
+				URL:         a.URL,
+				Fingerprint: fp,
+				Subject:     truststore.CertName(cert),
+				Timeline:    a.timeline(),
+			})
+		}
+	}
+	return matches
+}
+
+// timeline converts events to domain type. Dates were validated by Parse.
+func (a Advisory) timeline() []truststore.AdvisoryEvent {
+	events := make([]truststore.AdvisoryEvent, 0, len(a.Timeline))
+	for _, e := range a.Timeline {
+		date, _ := time.Parse(truststore.DateFormat, e.Date)
+		events = append(events, truststore.AdvisoryEvent{Date: date, Description: e.Description})
+	}
+	return events
+}
+
+// Annotate records matched advisory IDs on each result that relies on a matched certificate.
+// Presented certificates affect every result; roots only affect results they anchored.
+func Annotate(chain *truststore.CertChain, results []truststore.TrustResult, matches []truststore.AdvisoryMatch) {
+	presented := chainCertificates(chain, nil)
+
+	for i := range results {
+		used := make(map[truststore.Fingerprint]bool)
+		for _, cert := range results[i].VerifiedChain {
+			used[truststore.FingerprintFromCert(cert)] = true
+		}
+
+		seen := make(map[string]bool)
+		for _, m := range matches {
+			if seen[m.ID] {
+				continue
+			}
+			if _, ok := presented[m.Fingerprint]; ok || used[m.Fingerprint] {
+				results[i].Advisories = append(results[i].Advisories, m.ID)
+				seen[m.ID] = true
+			}
+		}
+	}
+}
+
+// chainCertificates indexes presented certificates and verified chain roots by fingerprint.
+func chainCertificates(chain *truststore.CertChain, results []truststore.TrustResult) map[truststore.Fingerprint]*x509.Certificate {
+	certs := make(map[truststore.Fingerprint]*x509.Certificate)
+	if chain.ServerCert != nil {
+		certs[truststore.FingerprintFromCert(chain.ServerCert)] = chain.ServerCert
+	}
+	for _, cert := range chain.Intermediates {
+		certs[truststore.FingerprintFromCert(cert)] = cert
+	}
+	for _, r := range results {
+		if n := len(r.VerifiedChain); n > 0 {
+			root := r.VerifiedChain[n-1]
+			certs[truststore.FingerprintFromCert(root)] = root
+		}
+	}
+	return certs
+}
@@ -0,0 +1,156 @@
+package advisory
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func generateCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			input: `{"updated":"2025-01-01","advisories":[{"id":"a","title":"A","fingerprints":["` + strings.Repeat("AB", 32) + `"],"timeline":[{"date":"2024-11-11","description":"x"}]}]}`,
+		},
+		{name: "invalid json", input: `{`, wantErr: "decode advisory feed"},
+		{name: "bad fingerprint", input: `{"advisories":[{"id":"a","fingerprints":["zz"]}]}`, wantErr: "decode advisory feed"},
+		{name: "missing id", input: `{"advisories":[{"title":"A"}]}`, wantErr: "missing id"},
+		{name: "bad date", input: `{"advisories":[{"id":"a","timeline":[{"date":"11/11/2024"}]}]}`, wantErr: "invalid date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchAndAnnotate(t *testing.T) {
+	leaf := generateCert(t, "leaf")
+	inter := generateCert(t, "Bad Intermediate")
+	rootA := generateCert(t, "Bad Root")
+	rootB := generateCert(t, "Good Root")
+
+	feed := &Feed{Advisories: []Advisory{
+		{
+			ID:           "inter",
+			Fingerprints: []truststore.Fingerprint{truststore.FingerprintFromCert(inter)},
+			Timeline:     []Event{{Date: "2024-11-11", Description: "distrust"}},
+		},
+		{ID: "root", Fingerprints: []truststore.Fingerprint{truststore.FingerprintFromCert(rootA)}},
+		{ID: "unrelated", Fingerprints: []truststore.Fingerprint{truststore.FingerprintFromCert(generateCert(t, "x"))}},
+	}}
+
+	chain := &truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{inter}}
+	results := []truststore.TrustResult{
+		{Trusted: true, VerifiedChain: []*x509.Certificate{leaf, inter, rootA}},
+		{Trusted: true, VerifiedChain: []*x509.Certificate{leaf, inter, rootB}},
+		{Trusted: false},
+	}
+
+	matches := feed.Match(chain, results)
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+	if matches[0].ID != "inter" || matches[0].Subject != "Bad Intermediate" {
+		t.Errorf("matches[0] = %+v", matches[0])
+	}
+	if len(matches[0].Timeline) != 1 || matches[0].Timeline[0].Date.Format(truststore.DateFormat) != "2024-11-11" {
+		t.Errorf("timeline = %+v", matches[0].Timeline)
+	}
+
+	Annotate(chain, results, matches)
+
+	want := [][]string{{"inter", "root"}, {"inter"}, {"inter"}}
+	for i, w := range want {
+		if strings.Join(results[i].Advisories, ",") != strings.Join(w, ",") {
+			t.Errorf("results[%d].Advisories = %v, want %v", i, results[i].Advisories, w)
+		}
+	}
+}
+
+func TestFetchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.json")
+	if err := os.WriteFile(path, []byte(`{"updated":"2025-01-01","advisories":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := Fetch(path, time.Second)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if feed.Updated != "2025-01-01" {
+		t.Errorf("Updated = %q", feed.Updated)
+	}
+
+	if _, err := Fetch(filepath.Join(t.TempDir(), "missing.json"), time.Second); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+// TestBundledFeed ensures the repository feed parses and references known certificates.
+func TestBundledFeed(t *testing.T) {
+	f, err := os.Open("../../advisories.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	feed, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for _, a := range feed.Advisories {
+		for _, fp := range a.Fingerprints {
+			if !truststore.Certs.Has(fp) {
+				t.Errorf("advisory %s: unknown fingerprint %s", a.ID, fp)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package bundle encodes root certificates as trust bundles for other tooling (certvet export).
+package bundle
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Bundle formats.
+const (
+	FormatPEM = "pem" // Concatenated PEM certificates, each preceded by a "# name" comment
+	FormatJKS = "jks" // Java KeyStore with one trusted certificate entry per root
+)
+
+// Formats lists the supported bundle formats.
+var Formats = []string{FormatPEM, FormatJKS}
+
+// Entry is a certificate with the name it is exported under.
+type Entry struct {
+	Name string
+	Cert *x509.Certificate
+}
+
+// Options configures bundle encoding.
+type Options struct {
+	Password string    // JKS integrity password
+	Created  time.Time // JKS entry creation date
+}
+
+// ParseFormat validates a bundle format name (case-insensitive).
+func ParseFormat(s string) (string, error) {
+	f := strings.ToLower(s)
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown bundle format %q (want %s)", s, strings.Join(Formats, ", "))
+}
+
+// Write encodes entries in format to w.
+func Write(w io.Writer, format string, entries []Entry, opts Options) error {
+	switch format {
+	case FormatPEM:
+		return writePEM(w, entries)
+	case FormatJKS:
+		return writeJKS(w, entries, opts)
+	}
+	return fmt.Errorf("unknown bundle format %q", format)
+}
+
+// writePEM writes entries as a ca-certificates style PEM bundle.
+func writePEM(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "# %s\n", e.Name); err != nil {
+			return err
+		}
+		if err := pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: e.Cert.Raw}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // G505: Verifies the JKS integrity check
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func testCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"pem", FormatPEM, false},
+		{"JKS", FormatJKS, false},
+		{"nss", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestWritePEM(t *testing.T) {
+	t.Parallel()
+
+	a, b := testCert(t, "Root A"), testCert(t, "Root B")
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatPEM, []Entry{{"Root A", a}, {"Root B", b}}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "# Root A\n-----BEGIN CERTIFICATE-----") {
+		t.Errorf("bundle = %q", buf.String())
+	}
+	rest := buf.Bytes()
+	var got [][]byte
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		got = append(got, block.Bytes)
+	}
+	if len(got) != 2 || !bytes.Equal(got[0], a.Raw) || !bytes.Equal(got[1], b.Raw) {
+		t.Errorf("decoded %d certificates, want Root A and Root B", len(got))
+	}
+}
+
+func TestWriteJKS(t *testing.T) {
+	t.Parallel()
+
+	a, b := testCert(t, "Root A"), testCert(t, "Root B")
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	entries := []Entry{{"Root A", a}, {"Root B", b}, {"ROOT A", b}}
+	if err := Write(&buf, FormatJKS, entries, Options{Password: "changeit", Created: created}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	body, sum := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+	digest := sha1.New() //nolint:gosec // G401: See import
+	for _, c := range utf16.Encode([]rune("changeit")) {
+		_ = binary.Write(digest, binary.BigEndian, c)
+	}
+	digest.Write([]byte(jksWhitener))
+	digest.Write(body)
+	if !bytes.Equal(digest.Sum(nil), sum) {
+		t.Fatal("integrity digest mismatch")
+	}
+
+	r := bytes.NewReader(body)
+	var header [3]uint32
+	_ = binary.Read(r, binary.BigEndian, &header)
+	if header != [3]uint32{jksMagic, jksVersion, 3} {
+		t.Fatalf("header = %x", header)
+	}
+	readUTF := func() string {
+		var n uint16
+		_ = binary.Read(r, binary.BigEndian, &n)
+		s := make([]byte, n)
+		_, _ = r.Read(s)
+		return string(s)
+	}
+	var aliases []string
+	for range 3 {
+		var tag uint32
+		var date uint64
+		_ = binary.Read(r, binary.BigEndian, &tag)
+		alias := readUTF()
+		_ = binary.Read(r, binary.BigEndian, &date)
+		certType := readUTF()
+		var n uint32
+		_ = binary.Read(r, binary.BigEndian, &n)
+		der := make([]byte, n)
+		_, _ = r.Read(der)
+		if tag != jksTrustedCertTag || certType != "X.509" || int64(date) != created.UnixMilli() {
+			t.Errorf("entry %q: tag %d, type %q, date %d", alias, tag, certType, date)
+		}
+		if _, err := x509.ParseCertificate(der); err != nil {
+			t.Errorf("entry %q: %v", alias, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if strings.Join(aliases, ",") != "root a,root b,root a 2" {
+		t.Errorf("aliases = %q", aliases)
+	}
+	if r.Len() != 0 {
+		t.Errorf("%d trailing bytes", r.Len())
+	}
+}
+
+func TestWriteJavaUTF(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := writeJavaUTF(&buf, "a\x00é"); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0, 5, 'a', 0xC0, 0x80, 0xC3, 0xA9}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("writeJavaUTF = %x, want %x", buf.Bytes(), want)
+	}
+}
@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // G505: SHA-1 is mandated by the JKS integrity check
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// JKS file layout constants (see sun.security.provider.JavaKeyStore).
+const (
+	jksMagic          = 0xFEEDFEED
+	jksVersion        = 2
+	jksTrustedCertTag = 2
+	jksWhitener       = "Mighty Aphrodite" // Salt appended to the password in the integrity digest
+)
+
+// writeJKS writes entries as trusted certificate entries of a version 2 Java KeyStore.
+// Aliases are the lowercased entry names, deduplicated with a numeric suffix, as keytool
+// treats aliases case-insensitively.
+func writeJKS(w io.Writer, entries []Entry, opts Options) error {
+	var buf bytes.Buffer
+	be := func(v any) { _ = binary.Write(&buf, binary.BigEndian, v) }
+
+	be(uint32(jksMagic))
+	be(uint32(jksVersion))
+	be(uint32(len(entries)))
+
+	seen := make(map[string]int)
+	for _, e := range entries {
+		alias := strings.ToLower(e.Name)
+		seen[alias]++
+		if n := seen[alias]; n > 1 {
+			alias = fmt.Sprintf("%s %d", alias, n)
+		}
+
+		be(uint32(jksTrustedCertTag))
+		if err := writeJavaUTF(&buf, alias); err != nil {
+			return err
+		}
+		be(uint64(opts.Created.UnixMilli()))
+		if err := writeJavaUTF(&buf, "X.509"); err != nil {
+			return err
+		}
+		be(uint32(len(e.Cert.Raw)))
+		buf.Write(e.Cert.Raw)
+	}
+
+	digest := sha1.New() //nolint:gosec // G401: See import
+	for _, c := range utf16.Encode([]rune(opts.Password)) {
+		_ = binary.Write(digest, binary.BigEndian, c)
+	}
+	digest.Write([]byte(jksWhitener))
+	digest.Write(buf.Bytes())
+	buf.Write(digest.Sum(nil))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeJavaUTF writes s in Java's DataOutput.writeUTF encoding: a 2-byte length followed by
+// modified UTF-8 (NUL as two bytes, supplementary characters as surrogate pairs).
+func writeJavaUTF(buf *bytes.Buffer, s string) error {
+	var enc []byte
+	for _, c := range utf16.Encode([]rune(s)) {
+		switch {
+		case c >= 0x01 && c <= 0x7F:
+			enc = append(enc, byte(c))
+		case c <= 0x7FF:
+			enc = append(enc, byte(0xC0|c>>6), byte(0x80|c&0x3F))
+		default:
+			enc = append(enc, byte(0xE0|c>>12), byte(0x80|(c>>6)&0x3F), byte(0x80|c&0x3F))
+		}
+	}
+	if len(enc) > 0xFFFF {
+		return fmt.Errorf("string too long for keystore: %d bytes", len(enc))
+	}
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(enc)))
+	buf.Write(enc)
+	return nil
+}
@@ -0,0 +1,195 @@
+// Package changelog describes trust store changes between data snapshots.
+//
+// On each data refresh the generator diffs the new stores against the previous snapshot and
+// prepends a Snapshot to trustdata/data/changelog.json. The file is embedded in the
+// binary and attached to releases, so subscribers can automate impact analysis of each update.
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// Changelog lists data snapshots, newest first.
+type Changelog struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// Snapshot records the store changes of one data refresh.
+type Snapshot struct {
+	Date    string   `json:"date"` // Refresh date (YYYY-MM-DD)
+	Changes []Change `json:"changes"`
+}
+
+// Change describes how one platform version's store differs from the previous snapshot.
+// A new version is compared against the closest older version of the same platform.
+type Change struct {
+	Platform       string `json:"platform"`
+	Version        string `json:"version"`
+	NewVersion     bool   `json:"new_version,omitempty"`
+	RemovedVersion bool   `json:"removed_version,omitempty"`
+	Added          []Root `json:"added,omitempty"`
+	Removed        []Root `json:"removed,omitempty"`
+	Constrained    []Root `json:"constrained,omitempty"` // Constraints set, changed or lifted
+}
+
+// Root identifies a root CA and its constraints in the store that changed.
+type Root struct {
+	Fingerprint  string `json:"fingerprint"`
+	Name         string `json:"name,omitempty"`
+	NotBeforeMax string `json:"not_before_max,omitempty"`
+	DistrustDate string `json:"distrust_date,omitempty"`
+	SCTNotAfter  string `json:"sct_not_after,omitempty"`
+}
+
+// Parse decodes a JSON changelog.
+func Parse(data []byte) (*Changelog, error) {
+	var c Changelog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse changelog: %w", err)
+	}
+	return &c, nil
+}
+
+// Embedded returns the changelog embedded with the trust store data.
+func Embedded() (*Changelog, error) {
+	return Parse(truststore.ChangelogData)
+}
+
+// Add prepends a snapshot dated date. Empty change sets are not recorded.
+func (c *Changelog) Add(date string, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	c.Snapshots = append([]Snapshot{{Date: date, Changes: changes}}, c.Snapshots...)
+}
+
+// Since returns a changelog with the snapshots dated on or after date (YYYY-MM-DD).
+func (c *Changelog) Since(date string) *Changelog {
+	out := &Changelog{Snapshots: []Snapshot{}}
+	for _, s := range c.Snapshots {
+		if s.Date >= date {
+			out.Snapshots = append(out.Snapshots, s)
+		}
+	}
+	return out
+}
+
+// Diff compares two store snapshots. name resolves display names for root fingerprints
+// and may return an empty string. Changes are sorted by platform, then version.
+func Diff(old, new []truststore.Store, name func(truststore.Fingerprint) string) []Change {
+	type key struct {
+		platform truststore.Platform
+		version  string
+	}
+	oldStores := make(map[key]truststore.Store, len(old))
+	for _, s := range old {
+		oldStores[key{s.Platform, s.Version}] = s
+	}
+	newStores := make(map[key]bool, len(new))
+
+	var changes []Change
+	for _, s := range new {
+		newStores[key{s.Platform, s.Version}] = true
+		c := Change{Platform: string(s.Platform), Version: s.Version}
+
+		base, ok := oldStores[key{s.Platform, s.Version}]
+		if !ok {
+			c.NewVersion = true
+			base = previousVersion(old, s)
+		}
+		c.Added, c.Removed, c.Constrained = DiffRoots(base, s, name)
+
+		if c.NewVersion || len(c.Added) > 0 || len(c.Removed) > 0 || len(c.Constrained) > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	for _, s := range old {
+		if !newStores[key{s.Platform, s.Version}] {
+			changes = append(changes, Change{Platform: string(s.Platform), Version: s.Version, RemovedVersion: true})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Platform != changes[j].Platform {
+			return changes[i].Platform < changes[j].Platform
+		}
+		return version.CompareAsc(changes[i].Version, changes[j].Version)
+	})
+	return changes
+}
+
+// previousVersion returns the newest store of s's platform older than s (empty if none).
+func previousVersion(stores []truststore.Store, s truststore.Store) truststore.Store {
+	var prev truststore.Store
+	for _, o := range stores {
+		if o.Platform != s.Platform || !version.LessThan(o.Version, s.Version) {
+			continue
+		}
+		if prev.Version == "" || version.LessThan(prev.Version, o.Version) {
+			prev = o
+		}
+	}
+	return prev
+}
+
+// DiffRoots lists roots added to, removed from, and re-constrained in cur relative to base.
+func DiffRoots(base, cur truststore.Store, name func(truststore.Fingerprint) string) (added, removed, constrained []Root) {
+	inBase := make(map[truststore.Fingerprint]bool, len(base.Fingerprints))
+	for _, fp := range base.Fingerprints {
+		inBase[fp] = true
+	}
+	inCur := make(map[truststore.Fingerprint]bool, len(cur.Fingerprints))
+
+	for _, fp := range cur.Fingerprints {
+		inCur[fp] = true
+		c := cur.ConstraintFor(fp)
+		switch {
+		case !inBase[fp]:
+			added = append(added, NewRoot(fp, c, name))
+		case !sameConstraints(base.ConstraintFor(fp), c):
+			constrained = append(constrained, NewRoot(fp, c, name))
+		}
+	}
+	for _, fp := range base.Fingerprints {
+		if !inCur[fp] {
+			removed = append(removed, NewRoot(fp, base.ConstraintFor(fp), name))
+		}
+	}
+
+	for _, roots := range [][]Root{added, removed, constrained} {
+		sort.Slice(roots, func(i, j int) bool { return roots[i].Fingerprint < roots[j].Fingerprint })
+	}
+	return added, removed, constrained
+}
+
+// NewRoot describes the root fp with constraints c. name resolves its display name.
+func NewRoot(fp truststore.Fingerprint, c truststore.Constraints, name func(truststore.Fingerprint) string) Root {
+	return Root{
+		Fingerprint:  fp.String(),
+		Name:         name(fp),
+		NotBeforeMax: formatTime(c.NotBeforeMax),
+		DistrustDate: formatTime(c.DistrustDate),
+		SCTNotAfter:  formatTime(c.SCTNotAfter),
+	}
+}
+
+func sameConstraints(a, b truststore.Constraints) bool {
+	return formatTime(a.NotBeforeMax) == formatTime(b.NotBeforeMax) &&
+		formatTime(a.DistrustDate) == formatTime(b.DistrustDate) &&
+		formatTime(a.SCTNotAfter) == formatTime(b.SCTNotAfter)
+}
+
+// formatTime renders a constraint time as RFC 3339 (empty if unset), matching stores.csv.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
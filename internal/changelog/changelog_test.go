@@ -0,0 +1,103 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	fpA := truststore.Fingerprint{0xAA}
+	fpB := truststore.Fingerprint{0xBB}
+	fpC := truststore.Fingerprint{0xCC}
+	distrust := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	old := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{fpA, fpB}},
+		{Platform: truststore.PlatformAndroid, Version: "13", Fingerprints: []truststore.Fingerprint{fpA}},
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fpA}},
+	}
+	current := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{fpA, fpB}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fpA, fpC}},
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fpA},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{fpA: {DistrustDate: &distrust}}},
+	}
+	names := map[truststore.Fingerprint]string{fpA: "Root A", fpB: "Root B", fpC: "Root C"}
+
+	got := Diff(old, current, func(fp truststore.Fingerprint) string { return names[fp] })
+
+	if len(got) != 3 {
+		t.Fatalf("Diff() returned %d changes, want 3: %+v", len(got), got)
+	}
+
+	android := got[0]
+	if android.Platform != "android" || !android.RemovedVersion {
+		t.Errorf("changes[0] = %+v, want removed android 13", android)
+	}
+
+	ios := got[1]
+	if ios.Version != "18" || !ios.NewVersion {
+		t.Errorf("changes[1] = %+v, want new ios 18", ios)
+	}
+	if len(ios.Added) != 1 || ios.Added[0].Name != "Root C" {
+		t.Errorf("ios 18 added = %+v, want Root C (compared to ios 17)", ios.Added)
+	}
+	if len(ios.Removed) != 1 || ios.Removed[0].Name != "Root B" {
+		t.Errorf("ios 18 removed = %+v, want Root B (compared to ios 17)", ios.Removed)
+	}
+
+	windows := got[2]
+	if len(windows.Constrained) != 1 || windows.Constrained[0].DistrustDate != "2026-06-01T00:00:00Z" {
+		t.Errorf("windows constrained = %+v, want Root A with distrust date", windows.Constrained)
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	t.Parallel()
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{{0x01}}},
+	}
+	if got := Diff(stores, stores, func(truststore.Fingerprint) string { return "" }); len(got) != 0 {
+		t.Errorf("Diff() of identical snapshots = %+v, want none", got)
+	}
+}
+
+func TestAddAndSince(t *testing.T) {
+	t.Parallel()
+
+	c := &Changelog{}
+	c.Add("2026-01-10", []Change{{Platform: "ios", Version: "17", NewVersion: true}})
+	c.Add("2026-02-01", nil) // empty refreshes are not recorded
+	c.Add("2026-03-05", []Change{{Platform: "android", Version: "16", NewVersion: true}})
+
+	if len(c.Snapshots) != 2 || c.Snapshots[0].Date != "2026-03-05" {
+		t.Fatalf("Snapshots = %+v, want 2 newest first", c.Snapshots)
+	}
+
+	tests := []struct {
+		since string
+		want  int
+	}{
+		{"2026-01-01", 2},
+		{"2026-03-05", 1},
+		{"2026-04-01", 0},
+	}
+	for _, tt := range tests {
+		if got := len(c.Since(tt.since).Snapshots); got != tt.want {
+			t.Errorf("Since(%s) = %d snapshots, want %d", tt.since, got, tt.want)
+		}
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Embedded(); err != nil {
+		t.Fatalf("Embedded() error = %v", err)
+	}
+}
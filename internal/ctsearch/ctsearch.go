@@ -0,0 +1,183 @@
+// Package ctsearch finds certificates logged to Certificate Transparency for a domain
+// using the crt.sh search service.
+package ctsearch
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the crt.sh service queried by default.
+const DefaultURL = "https://crt.sh"
+
+// maxCertSize bounds downloaded certificates.
+const maxCertSize = 1 << 20
+
+// Entry is a logged certificate. Precertificates and final certificates with the same
+// issuer and serial number are reported once.
+type Entry struct {
+	ID         int64    // crt.sh certificate ID
+	IssuerName string   // Issuer distinguished name
+	CommonName string   // Subject common name
+	Names      []string // Subject common name and SANs
+	Serial     string   // Serial number (hex)
+	NotBefore  time.Time
+	NotAfter   time.Time
+	LoggedAt   time.Time // First CT log entry
+}
+
+// Expired reports whether the certificate expired before now.
+func (e Entry) Expired(now time.Time) bool {
+	return now.After(e.NotAfter)
+}
+
+// IssuedBy reports whether the issuer name contains any of patterns, case-insensitively.
+func (e Entry) IssuedBy(patterns []string) bool {
+	issuer := strings.ToLower(e.IssuerName)
+	for _, p := range patterns {
+		if strings.Contains(issuer, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Client queries a crt.sh compatible service.
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client for baseURL (e.g., DefaultURL).
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+// Options narrows a search.
+type Options struct {
+	Subdomains     bool // Also match certificates for subdomains (%.domain)
+	IncludeExpired bool // Include certificates that have expired
+}
+
+// crtshTimeFormat is the timestamp layout of crt.sh JSON output (UTC, no zone).
+const crtshTimeFormat = "2006-01-02T15:04:05.999999999"
+
+// jsonEntry is a crt.sh JSON search result.
+type jsonEntry struct {
+	ID             int64  `json:"id"`
+	IssuerName     string `json:"issuer_name"`
+	CommonName     string `json:"common_name"`
+	NameValue      string `json:"name_value"` // Newline-separated names
+	SerialNumber   string `json:"serial_number"`
+	NotBefore      string `json:"not_before"`
+	NotAfter       string `json:"not_after"`
+	EntryTimestamp string `json:"entry_timestamp"`
+}
+
+// Search returns certificates logged for domain, newest first.
+func (c *Client) Search(domain string, opts Options) ([]Entry, error) {
+	q := domain
+	if opts.Subdomains {
+		q = "%." + domain
+	}
+	params := url.Values{"q": {q}, "output": {"json"}}
+	if !opts.IncludeExpired {
+		params.Set("exclude", "expired")
+	}
+
+	var raw []jsonEntry
+	if err := c.get("/?"+params.Encode(), func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&raw)
+	}); err != nil {
+		return nil, fmt.Errorf("search %s: %w", domain, err)
+	}
+
+	seen := make(map[string]bool)
+	var entries []Entry
+	for _, r := range raw {
+		key := r.IssuerName + "|" + r.SerialNumber
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		e := Entry{ID: r.ID, IssuerName: r.IssuerName, CommonName: r.CommonName, Serial: r.SerialNumber}
+		for _, name := range strings.Split(r.NameValue, "\n") {
+			if name = strings.TrimSpace(name); name != "" {
+				e.Names = append(e.Names, name)
+			}
+		}
+		var err error
+		if e.NotBefore, err = parseTime(r.NotBefore); err != nil {
+			return nil, err
+		}
+		if e.NotAfter, err = parseTime(r.NotAfter); err != nil {
+			return nil, err
+		}
+		if e.LoggedAt, err = parseTime(r.EntryTimestamp); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].NotBefore.After(entries[j].NotBefore)
+	})
+	return entries, nil
+}
+
+// parseTime parses a crt.sh timestamp, allowing empty values.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(crtshTimeFormat, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse timestamp %q: %w", s, err)
+	}
+	return t.UTC(), nil
+}
+
+// Certificate downloads a logged certificate by crt.sh ID.
+func (c *Client) Certificate(id int64) (*x509.Certificate, error) {
+	var cert *x509.Certificate
+	err := c.get("/?d="+strconv.FormatInt(id, 10), func(body io.Reader) error {
+		data, err := io.ReadAll(io.LimitReader(body, maxCertSize))
+		if err != nil {
+			return err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return fmt.Errorf("no PEM certificate in response")
+		}
+		cert, err = x509.ParseCertificate(block.Bytes)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("download certificate %d: %w", id, err)
+	}
+	return cert, nil
+}
+
+// get requests path and passes a successful response body to read.
+func (c *Client) get(path string, read func(io.Reader) error) error {
+	resp, err := c.client.Get(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return read(resp.Body)
+}
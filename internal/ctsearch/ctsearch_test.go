@@ -0,0 +1,144 @@
+package ctsearch
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// searchResponse holds a precertificate and final certificate for one issuance and an older one.
+const searchResponse = `[
+  {"id": 12, "issuer_name": "C=US, O=Let's Encrypt, CN=R11", "common_name": "example.com",
+   "name_value": "example.com\nwww.example.com", "serial_number": "04aa",
+   "not_before": "2026-09-01T00:00:00", "not_after": "2026-11-30T00:00:00", "entry_timestamp": "2026-09-01T01:02:03.456"},
+  {"id": 11, "issuer_name": "C=US, O=Let's Encrypt, CN=R11", "common_name": "example.com",
+   "name_value": "example.com\nwww.example.com", "serial_number": "04aa",
+   "not_before": "2026-09-01T00:00:00", "not_after": "2026-11-30T00:00:00", "entry_timestamp": "2026-09-01T01:02:03"},
+  {"id": 5, "issuer_name": "C=US, O=DigiCert Inc, CN=DigiCert Global G2 TLS RSA SHA256 2020 CA1", "common_name": "example.com",
+   "name_value": "example.com", "serial_number": "0bcd",
+   "not_before": "2026-03-01T00:00:00", "not_after": "2027-03-01T00:00:00", "entry_timestamp": "2026-03-01T00:00:00"}
+]`
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(searchResponse))
+	}))
+	defer srv.Close()
+
+	entries, err := NewClient(srv.URL, time.Second).Search("example.com", Options{Subdomains: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if want := "exclude=expired&output=json&q=%25.example.com"; gotQuery != want {
+		t.Errorf("query = %q, want %q", gotQuery, want)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want precertificate and certificate merged into 2", len(entries))
+	}
+	e := entries[0]
+	if e.ID != 12 || len(e.Names) != 2 || e.Names[1] != "www.example.com" {
+		t.Errorf("entries[0] = %+v", e)
+	}
+	if want := time.Date(2026, 9, 1, 1, 2, 3, 456e6, time.UTC); !e.LoggedAt.Equal(want) {
+		t.Errorf("LoggedAt = %v, want %v", e.LoggedAt, want)
+	}
+	if !entries[1].NotBefore.Before(e.NotBefore) {
+		t.Error("entries not sorted newest first")
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusBadGateway, ""},
+		{"invalid json", http.StatusOK, "<html>"},
+		{"invalid timestamp", http.StatusOK, `[{"id": 1, "not_before": "yesterday"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			if _, err := NewClient(srv.URL, time.Second).Search("example.com", Options{}); err == nil {
+				t.Error("Search: expected error")
+			}
+		})
+	}
+}
+
+func TestEntryIssuedBy(t *testing.T) {
+	t.Parallel()
+	e := Entry{IssuerName: "C=US, O=Let's Encrypt, CN=R11"}
+	tests := []struct {
+		patterns []string
+		want     bool
+	}{
+		{[]string{"let's encrypt"}, true},
+		{[]string{"DigiCert", "CN=R11"}, true},
+		{[]string{"DigiCert"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := e.IssuedBy(tt.patterns); got != tt.want {
+			t.Errorf("IssuedBy(%q) = %v, want %v", tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("d") != "12" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, time.Second)
+	cert, err := c.Certificate(12)
+	if err != nil {
+		t.Fatalf("Certificate: %v", err)
+	}
+	if cert.Subject.CommonName != "example.com" {
+		t.Errorf("CommonName = %q", cert.Subject.CommonName)
+	}
+	if _, err := c.Certificate(13); err == nil {
+		t.Error("Certificate of unknown ID: expected error")
+	}
+}
@@ -0,0 +1,301 @@
+// Package dataupdate installs trust data bundles published with releases in a local cache,
+// which certvet prefers over its embedded data when newer (certvet update), and loads
+// bundles from other locations (--data).
+//
+// A bundle is a gzipped tar of the trustdata.DataFiles plus metadata.json, with a detached
+// base64 ed25519 signature published alongside it as <bundle>.sig.
+package dataupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/version"
+	"github.com/ivoronin/certvet/trustdata"
+)
+
+// DefaultURL is the data bundle attached to the latest release.
+const DefaultURL = "https://github.com/ivoronin/certvet/releases/latest/download/data-bundle.tar.gz"
+
+// PublicKey is the base64 ed25519 key bundles are signed with. Release builds set it via
+// ldflags; without it bundles cannot be verified and update refuses to install them.
+var PublicKey = ""
+
+// metadataFile describes the bundle inside it and in the installed directory.
+const metadataFile = "metadata.json"
+
+// maxFileSize bounds each unpacked bundle file, guarding against decompression bombs.
+const maxFileSize = 256 << 20
+
+// calverFormat is the layout of release versions (without "v" prefix).
+const calverFormat = "2006.01.02"
+
+// Metadata describes a data bundle.
+type Metadata struct {
+	Version string    `json:"version"` // Release the bundle was published with (vYYYY.MM.DD)
+	Created time.Time `json:"created"`
+}
+
+// NewerThan reports whether the bundle's data is newer than a binary's embedded data.
+// Release builds embed data committed up to their CalVer date, so a bundle is newer if its
+// release is; dev builds have no known data date and always prefer an installed bundle.
+func (m Metadata) NewerThan(binary string) bool {
+	if _, err := time.Parse(calverFormat, strings.TrimPrefix(binary, "v")); err != nil {
+		return true
+	}
+	return version.LessThan(strings.TrimPrefix(binary, "v"), strings.TrimPrefix(m.Version, "v"))
+}
+
+// DefaultDir returns the per-user directory installed data lives in.
+func DefaultDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "certvet", "data"), nil
+}
+
+// Download fetches a bundle and its detached signature from url and url + ".sig".
+func Download(url string, timeout time.Duration) (bundle, sig []byte, err error) {
+	client := &http.Client{Timeout: timeout}
+	if bundle, err = get(client, url); err != nil {
+		return nil, nil, err
+	}
+	if sig, err = get(client, url+".sig"); err != nil {
+		return nil, nil, err
+	}
+	return bundle, sig, nil
+}
+
+// get downloads url, failing on non-200 responses.
+func get(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url) //nolint:gosec // G107: URL is the configured bundle location
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// Verify checks a bundle's base64 signature against a base64 ed25519 public key.
+func Verify(publicKey string, bundle, sig []byte) error {
+	if publicKey == "" {
+		return errors.New("this build has no data signing key; use a release build to install data bundles")
+	}
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid data signing key")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("decode bundle signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), bundle, raw) {
+		return errors.New("data bundle signature verification failed")
+	}
+	return nil
+}
+
+// Sign returns the base64 signature of a bundle for publishing as <bundle>.sig.
+func Sign(key ed25519.PrivateKey, bundle []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, bundle)) + "\n")
+}
+
+// Pack builds a bundle from the data files in dir and meta.
+func Pack(dir string, meta Metadata) ([]byte, error) {
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: meta.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := add(metadataFile, append(metaJSON, '\n')); err != nil {
+		return nil, err
+	}
+	for _, name := range trustdata.DataFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: Known data file names
+		if err != nil {
+			return nil, err
+		}
+		if err := add(name, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Install unpacks a verified bundle into dir. The previous installation is replaced only
+// after the new data loads successfully.
+func Install(dir string, bundle []byte) (Metadata, error) {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil { //nolint:gosec // G301: Cache directory is not secret
+		return Metadata{}, err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".data-")
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := unpack(tmp, bundle); err != nil {
+		return Metadata{}, fmt.Errorf("unpack data bundle: %w", err)
+	}
+	_, meta, err := load(tmp, truststore.Read)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("invalid data bundle: %w", err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return Metadata{}, err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return Metadata{}, err
+	}
+	return meta, nil
+}
+
+// unpack extracts the expected bundle files into dir, rejecting anything else.
+func unpack(dir string, bundle []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || (hdr.Name != metadataFile && !slices.Contains(trustdata.DataFiles, hdr.Name)) {
+			return fmt.Errorf("unexpected entry %q", hdr.Name)
+		}
+		if hdr.Size > maxFileSize {
+			return fmt.Errorf("entry %q too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, hdr.Name), data, 0o644); err != nil { //nolint:gosec // G306: Data files are world-readable like the embedded ones
+			return err
+		}
+	}
+}
+
+// LoadBundle reads the data in a bundle without installing it. The bundle is unpacked to a
+// temporary directory that is removed once the data is loaded.
+func LoadBundle(bundle []byte) (*truststore.Dataset, Metadata, error) {
+	tmp, err := os.MkdirTemp("", "certvet-data-")
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := unpack(tmp, bundle); err != nil {
+		return nil, Metadata{}, fmt.Errorf("unpack data bundle: %w", err)
+	}
+	ds, meta, err := load(tmp, truststore.Read)
+	if err != nil {
+		return nil, Metadata{}, fmt.Errorf("invalid data bundle: %w", err)
+	}
+	return ds, meta, nil
+}
+
+// Open loads trust data from source: an http(s) URL of a bundle, a bundle file, or a
+// directory with unpacked data as written by Install. Bundles downloaded from a URL must
+// carry a valid signature for publicKey at <url>.sig; local files are trusted like other
+// local configuration. The data is read into memory, so sources can be reloaded
+// repeatedly and directories rewritten while in use.
+func Open(source, publicKey string, timeout time.Duration) (*truststore.Dataset, Metadata, error) {
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		bundle, sig, err := Download(source, timeout)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		if err := Verify(publicKey, bundle, sig); err != nil {
+			return nil, Metadata{}, err
+		}
+		return LoadBundle(bundle)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	if info.IsDir() {
+		return load(source, truststore.Read)
+	}
+	bundle, err := os.ReadFile(source) //nolint:gosec // G304: Data source is configured by the user
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return LoadBundle(bundle)
+}
+
+// Load reads the data installed in dir, memory-mapping its certificate files (see
+// truststore.Open): Install replaces rather than rewrites them. The error wraps
+// fs.ErrNotExist if nothing is installed.
+func Load(dir string) (*truststore.Dataset, Metadata, error) {
+	return load(dir, truststore.Open)
+}
+
+// load reads the data in dir, loading the dataset with open.
+func load(dir string, open func(dir string) (*truststore.Dataset, error)) (*truststore.Dataset, Metadata, error) {
+	var meta Metadata
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile)) //nolint:gosec // G304: Data directory is configured by the user
+	if err != nil {
+		return nil, meta, err
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, meta, fmt.Errorf("parse %s: %w", metadataFile, err)
+	}
+	ds, err := open(dir)
+	if err != nil {
+		return nil, meta, err
+	}
+	if len(ds.Stores) == 0 {
+		return nil, meta, errors.New("no trust stores")
+	}
+	return ds, meta, nil
+}
@@ -0,0 +1,281 @@
+package dataupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// dataDir is the repository's trust data, packed into test bundles.
+const dataDir = "../../trustdata/data"
+
+func testBundle(t *testing.T) ([]byte, Metadata) {
+	t.Helper()
+	meta := Metadata{Version: "v2026.10.01", Created: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	bundle, err := Pack(dataDir, meta)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	return bundle, meta
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := []byte("bundle")
+	sig := Sign(priv, bundle)
+
+	tests := []struct {
+		name    string
+		key     string
+		bundle  []byte
+		sig     []byte
+		wantErr bool
+	}{
+		{"valid", base64.StdEncoding.EncodeToString(pub), bundle, sig, false},
+		{"no key", "", bundle, sig, true},
+		{"invalid key", "bm90IGEga2V5", bundle, sig, true},
+		{"wrong key", base64.StdEncoding.EncodeToString(otherPub), bundle, sig, true},
+		{"tampered bundle", base64.StdEncoding.EncodeToString(pub), []byte("bundle!"), sig, true},
+		{"malformed signature", base64.StdEncoding.EncodeToString(pub), bundle, []byte("!!"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := Verify(tt.key, tt.bundle, tt.sig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallAndLoad(t *testing.T) {
+	t.Parallel()
+	bundle, meta := testBundle(t)
+	dir := filepath.Join(t.TempDir(), "data")
+
+	if _, _, err := Load(dir); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Load before install: got %v, want fs.ErrNotExist", err)
+	}
+
+	// Install twice to cover replacing an existing installation
+	for range 2 {
+		got, err := Install(dir, bundle)
+		if err != nil {
+			t.Fatalf("Install: %v", err)
+		}
+		if got.Version != meta.Version || !got.Created.Equal(meta.Created) {
+			t.Errorf("Install metadata = %+v, want %+v", got, meta)
+		}
+	}
+
+	ds, got, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.Version != meta.Version {
+		t.Errorf("Load version = %q, want %q", got.Version, meta.Version)
+	}
+	if len(ds.Stores) == 0 || ds.Certs.Len() == 0 {
+		t.Error("Load returned empty dataset")
+	}
+}
+
+func TestInstallRejectsInvalidBundles(t *testing.T) {
+	t.Parallel()
+	pack := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, data := range files {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))})
+			_, _ = tw.Write([]byte(data))
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name   string
+		bundle []byte
+	}{
+		{"not gzip", []byte("garbage")},
+		{"path traversal", pack(map[string]string{"../stores.csv": ""})},
+		{"unexpected file", pack(map[string]string{"extra.txt": ""})},
+		{"missing data files", pack(map[string]string{metadataFile: `{"version":"v2026.10.01"}`})},
+		{"empty stores", pack(map[string]string{
+			metadataFile:        `{"version":"v2026.10.01"}`,
+			"certificates.csv":  "fingerprint,pem\n",
+			"intermediates.csv": "fingerprint,pem\n",
+			"stores.csv":        "platform,version,fingerprint\n",
+			"ctlogs.csv":        "log_id,operator,description,state,state_since,mmd\n",
+			"changelog.json":    "[]",
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := filepath.Join(t.TempDir(), "data")
+			if _, err := Install(dir, tt.bundle); err == nil {
+				t.Error("Install: expected error")
+			}
+			if _, _, err := Load(dir); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("failed Install left data behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+	bundle, meta := testBundle(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data-bundle.tar.gz":
+			_, _ = w.Write(bundle)
+		case "/data-bundle.tar.gz.sig":
+			_, _ = w.Write(Sign(priv, bundle))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "data-bundle.tar.gz")
+	if err := os.WriteFile(file, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(tmp, "data")
+	if _, err := Install(dir, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, source, key string
+		wantErr           bool
+	}{
+		{"url", srv.URL + "/data-bundle.tar.gz", key, false},
+		{"url without key", srv.URL + "/data-bundle.tar.gz", "", true},
+		{"bundle file", file, "", false},
+		{"directory", dir, "", false},
+		{"missing path", filepath.Join(tmp, "nope"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ds, got, err := Open(tt.source, tt.key, time.Second)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Open: expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if got.Version != meta.Version || len(ds.Stores) == 0 {
+				t.Errorf("Open = %d stores, version %q; want data of %q", len(ds.Stores), got.Version, meta.Version)
+			}
+		})
+	}
+}
+
+func TestOpenDirectoryRewritten(t *testing.T) {
+	t.Parallel()
+	bundle, _ := testBundle(t)
+	dir := filepath.Join(t.TempDir(), "data")
+	if _, err := Install(dir, bundle); err != nil {
+		t.Fatal(err)
+	}
+	ds, _, err := Open(dir, "", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncating a mapped file would make the lookups below fault
+	if err := os.Truncate(filepath.Join(dir, "certificates.csv"), 0); err != nil {
+		t.Fatal(err)
+	}
+	fps := ds.Certs.Fingerprints()
+	if len(fps) == 0 {
+		t.Fatal("no certificates")
+	}
+	for _, fp := range fps[:min(10, len(fps))] {
+		if ds.Certs.Get(fp) == nil {
+			t.Errorf("certificate %s unreadable after its file was rewritten", fp)
+		}
+	}
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data-bundle.tar.gz":
+			_, _ = w.Write([]byte("bundle"))
+		case "/data-bundle.tar.gz.sig":
+			_, _ = w.Write([]byte("sig"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	bundle, sig, err := Download(srv.URL+"/data-bundle.tar.gz", time.Second)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if string(bundle) != "bundle" || string(sig) != "sig" {
+		t.Errorf("Download = %q, %q", bundle, sig)
+	}
+
+	if _, _, err := Download(srv.URL+"/missing.tar.gz", time.Second); err == nil {
+		t.Error("Download of missing bundle: expected error")
+	}
+}
+
+func TestMetadataNewerThan(t *testing.T) {
+	t.Parallel()
+	meta := Metadata{Version: "v2026.10.01"}
+	tests := []struct {
+		binary string
+		want   bool
+	}{
+		{"dev", true},
+		{"v2026.09.15", true},
+		{"2026.09.15", true},
+		{"v2026.10.01", false},
+		{"v2026.11.01", false},
+	}
+	for _, tt := range tests {
+		if got := meta.NewerThan(tt.binary); got != tt.want {
+			t.Errorf("NewerThan(%q) = %v, want %v", tt.binary, got, tt.want)
+		}
+	}
+}
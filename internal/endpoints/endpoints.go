@@ -0,0 +1,122 @@
+// Package endpoints parses endpoint lists for bulk validation.
+//
+// Input is line-oriented: each line is either a plain endpoint ("host", "host:port",
+// or a URL such as "https://host:8443/path") or an NDJSON object with per-endpoint options.
+// Blank lines and lines starting with '#' are ignored.
+package endpoints
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Target is an endpoint with optional per-endpoint overrides.
+type Target struct {
+	Endpoint       string
+	Timeout        time.Duration // Zero means use the default
+	VerifyHostname *bool         // Nil means use the default
+}
+
+// jsonTarget is the NDJSON input format.
+type jsonTarget struct {
+	Endpoint       string `json:"endpoint"`
+	Timeout        string `json:"timeout,omitempty"` // Go duration (e.g., "5s")
+	VerifyHostname *bool  `json:"verify_hostname,omitempty"`
+}
+
+// Parse reads targets from r.
+func Parse(r io.Reader) ([]Target, error) {
+	var targets []Target
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		t, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		targets = append(targets, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read endpoints: %w", err)
+	}
+
+	return targets, nil
+}
+
+// FromArgs converts plain endpoint arguments to targets.
+func FromArgs(args []string) ([]Target, error) {
+	targets := make([]Target, 0, len(args))
+	for _, arg := range args {
+		t, err := parseLine(arg)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// parseLine parses a single plain or NDJSON line.
+func parseLine(line string) (Target, error) {
+	if !strings.HasPrefix(line, "{") {
+		endpoint, err := normalize(line)
+		if err != nil {
+			return Target{}, err
+		}
+		return Target{Endpoint: endpoint}, nil
+	}
+
+	var jt jsonTarget
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&jt); err != nil {
+		return Target{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	endpoint, err := normalize(jt.Endpoint)
+	if err != nil {
+		return Target{}, err
+	}
+	t := Target{Endpoint: endpoint, VerifyHostname: jt.VerifyHostname}
+
+	if jt.Timeout != "" {
+		t.Timeout, err = time.ParseDuration(jt.Timeout)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid timeout %q: %w", jt.Timeout, err)
+		}
+	}
+
+	return t, nil
+}
+
+// normalize reduces URLs to "host[:port]" and rejects empty endpoints.
+func normalize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("empty endpoint")
+	}
+	if !strings.Contains(s, "://") {
+		return s, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid URL %q: missing host", s)
+	}
+	return u.Host, nil
+}
@@ -0,0 +1,76 @@
+package endpoints
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := `# discovered hosts
+example.com
+
+api.example.com:8443
+https://www.example.com/login
+{"endpoint": "slow.example.com", "timeout": "30s", "verify_hostname": false}
+`
+	targets, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []string{"example.com", "api.example.com:8443", "www.example.com", "slow.example.com"}
+	if len(targets) != len(want) {
+		t.Fatalf("got %d targets, want %d", len(targets), len(want))
+	}
+	for i, w := range want {
+		if targets[i].Endpoint != w {
+			t.Errorf("targets[%d].Endpoint = %q, want %q", i, targets[i].Endpoint, w)
+		}
+	}
+
+	slow := targets[3]
+	if slow.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", slow.Timeout)
+	}
+	if slow.VerifyHostname == nil || *slow.VerifyHostname {
+		t.Errorf("VerifyHostname = %v, want false", slow.VerifyHostname)
+	}
+	if targets[0].Timeout != 0 || targets[0].VerifyHostname != nil {
+		t.Error("plain lines should not set overrides")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		errSubstr string
+	}{
+		{"invalid json", `{"endpoint":`, "line 1: invalid JSON"},
+		{"unknown field", `{"endpoint":"a.com","port":443}`, "invalid JSON"},
+		{"missing endpoint", `{"timeout":"5s"}`, "empty endpoint"},
+		{"bad timeout", `{"endpoint":"a.com","timeout":"soon"}`, "invalid timeout"},
+		{"url without host", "https:///path", "missing host"},
+		{"line number", "ok.com\n{bad", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("error = %v, want containing %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	targets, err := FromArgs([]string{"example.com", "https://example.org:8443"})
+	if err != nil {
+		t.Fatalf("FromArgs: %v", err)
+	}
+	if len(targets) != 2 || targets[1].Endpoint != "example.org:8443" {
+		t.Errorf("unexpected targets: %+v", targets)
+	}
+}
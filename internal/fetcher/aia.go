@@ -0,0 +1,113 @@
+package fetcher
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIssuerSize bounds AIA responses; CA certificates are a few KB.
+const maxIssuerSize = 1 << 20
+
+// IssuerCache fetches CA certificates from AIA caIssuers URLs (RFC 5280 section 4.2.2.1).
+// Each URL is requested at most once, so validating a chain against many stores
+// doesn't repeat downloads. It is safe for concurrent use.
+type IssuerCache struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	entries map[string]*issuerEntry
+}
+
+// issuerEntry is the outcome of one caIssuers download.
+type issuerEntry struct {
+	once sync.Once
+	cert *x509.Certificate
+	err  error
+}
+
+// NewIssuerCache creates a cache whose downloads time out after timeout.
+func NewIssuerCache(timeout time.Duration) *IssuerCache {
+	return &IssuerCache{
+		client:  &http.Client{Timeout: timeout},
+		entries: make(map[string]*issuerEntry),
+	}
+}
+
+// Fetch returns the certificate published at a caIssuers URL.
+func (c *IssuerCache) Fetch(url string) (*x509.Certificate, error) {
+	c.mu.Lock()
+	e, ok := c.entries[url]
+	if !ok {
+		e = &issuerEntry{}
+		c.entries[url] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() { e.cert, e.err = c.download(url) })
+	return e.cert, e.err
+}
+
+// download retrieves and parses one caIssuers certificate.
+func (c *IssuerCache) download(url string) (*x509.Certificate, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("fetch issuer %s: unsupported scheme", url)
+	}
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch issuer: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch issuer %s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIssuerSize))
+	if err != nil {
+		return nil, fmt.Errorf("fetch issuer %s: %w", url, err)
+	}
+	return parseIssuerCert(data)
+}
+
+// parseIssuerCert decodes a caIssuers response. RFC 5280 specifies DER, but some CAs
+// publish PEM. PKCS#7 bundles (.p7c) are not supported.
+func parseIssuerCert(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil && block.Type == "CERTIFICATE" {
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse issuer certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// maxAIADepth bounds the intermediates Intermediates follows above a certificate.
+const maxAIADepth = 4
+
+// Intermediates follows caIssuers URLs up from cert and returns the CA certificates found
+// below the root, as a server presenting cert should send them. It stops at a self-signed
+// certificate, a certificate without caIssuers URLs, or the first failed download.
+func (c *IssuerCache) Intermediates(cert *x509.Certificate) []*x509.Certificate {
+	var chain []*x509.Certificate
+	for range maxAIADepth {
+		if len(cert.IssuingCertificateURL) == 0 {
+			break
+		}
+		issuer, err := c.Fetch(cert.IssuingCertificateURL[0])
+		if err != nil || bytes.Equal(issuer.RawSubject, issuer.RawIssuer) {
+			break
+		}
+		chain = append(chain, issuer)
+		cert = issuer
+	}
+	return chain
+}
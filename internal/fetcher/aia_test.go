@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIssuerCacheFetch(t *testing.T) {
+	t.Parallel()
+
+	cert := testServerCert(t)
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/issuer.der":
+			_, _ = w.Write(cert.Raw)
+		case "/issuer.pem":
+			_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		case "/garbage":
+			_, _ = w.Write([]byte("not a certificate"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache := NewIssuerCache(5 * time.Second)
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"/issuer.der", false},
+		{"/issuer.pem", false},
+		{"/garbage", true},
+		{"/missing", true},
+	}
+	for _, tt := range tests {
+		got, err := cache.Fetch(srv.URL + tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Fetch(%s) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(cert) {
+			t.Errorf("Fetch(%s) returned a different certificate", tt.path)
+		}
+	}
+
+	// Repeated lookups are served from the cache, including failures
+	before := requests.Load()
+	_, _ = cache.Fetch(srv.URL + "/issuer.der")
+	_, _ = cache.Fetch(srv.URL + "/missing")
+	if got := requests.Load(); got != before {
+		t.Errorf("cached lookups made %d requests, want 0", got-before)
+	}
+
+	if _, err := cache.Fetch("ldap://ldap.example.com/cn=CA"); err == nil {
+		t.Error("Fetch(ldap URL) should fail")
+	}
+}
+
+func TestIssuerCacheIntermediates(t *testing.T) {
+	t.Parallel()
+
+	published := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if der, ok := published[r.URL.Path]; ok {
+			_, _ = w.Write(der)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(subject, issuer, aiaPath string) *x509.Certificate {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: subject},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		if aiaPath != "" {
+			template.IssuingCertificateURL = []string{srv.URL + aiaPath}
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, &x509.Certificate{Subject: pkix.Name{CommonName: issuer}}, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		published["/"+subject] = der
+		return cert
+	}
+	issue("root", "root", "")
+	intermediate := issue("intermediate", "root", "/root")
+	leaf := issue("leaf", "intermediate", "/intermediate")
+	orphan := issue("orphan", "intermediate", "/missing")
+
+	cache := NewIssuerCache(5 * time.Second)
+	if got := cache.Intermediates(leaf); len(got) != 1 || !got[0].Equal(intermediate) {
+		t.Errorf("Intermediates(leaf) = %d certificates, want the intermediate without the root", len(got))
+	}
+	if got := cache.Intermediates(orphan); len(got) != 0 {
+		t.Errorf("Intermediates(orphan) = %d certificates, want none", len(got))
+	}
+}
@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batch paces fetches from many endpoints: at most Concurrency run at once, connections
+// to the same host are spaced by HostInterval, and each waits a random delay of up to
+// Jitter first, so large scans neither trip WAFs nor exhaust file descriptors.
+// A Batch is safe for concurrent use and must not be copied after first use.
+type Batch struct {
+	Concurrency  int           // Simultaneous fetches in Run (values below 1 mean 1)
+	HostInterval time.Duration // Minimum time between connections to one host (0 = unlimited)
+	Jitter       time.Duration // Maximum random delay before each connection (0 = none)
+
+	mu   sync.Mutex
+	next map[string]time.Time // Earliest time of the next connection to each host
+}
+
+// Wait blocks until a connection to endpoint is allowed and reserves it.
+func (b *Batch) Wait(endpoint string) {
+	if b.Jitter > 0 {
+		time.Sleep(rand.N(b.Jitter)) //nolint:gosec // Jitter doesn't need a secure source
+	}
+	if b.HostInterval <= 0 {
+		return
+	}
+
+	host, _ := splitEndpoint(endpoint)
+	host = strings.ToLower(host)
+	b.mu.Lock()
+	if b.next == nil {
+		b.next = make(map[string]time.Time)
+	}
+	now := time.Now()
+	at := b.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	b.next[host] = at.Add(b.HostInterval)
+	b.mu.Unlock()
+
+	time.Sleep(at.Sub(now))
+}
+
+// Run calls fn for each endpoint index on up to Concurrency workers, in order of index,
+// after Wait allows a connection to the endpoint. It returns when all calls are done.
+func (b *Batch) Run(endpoints []string, fn func(i int)) {
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(b.Concurrency, len(endpoints))) {
+		wg.Go(func() {
+			for i := range next {
+				b.Wait(endpoints[i])
+				fn(i)
+			}
+		})
+	}
+
+	for i := range endpoints {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
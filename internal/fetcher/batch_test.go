@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatchRun(t *testing.T) {
+	t.Parallel()
+
+	endpoints := make([]string, 20)
+	for i := range endpoints {
+		endpoints[i] = "host" + string(rune('a'+i)) + ".example.com"
+	}
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	b := &Batch{Concurrency: 4}
+	b.Run(endpoints, func(i int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		mu.Lock()
+		seen[i] = true
+		mu.Unlock()
+	})
+
+	if len(seen) != len(endpoints) {
+		t.Errorf("Run called fn for %d endpoints, want %d", len(seen), len(endpoints))
+	}
+	if p := peak.Load(); p > 4 || p < 2 {
+		t.Errorf("peak concurrency = %d, want 2..4", p)
+	}
+}
+
+func TestBatchHostInterval(t *testing.T) {
+	t.Parallel()
+
+	b := &Batch{Concurrency: 8, HostInterval: 30 * time.Millisecond}
+	var mu sync.Mutex
+	started := make(map[string][]time.Time)
+	endpoints := []string{"a.example.com", "A.example.com:8443", "a.example.com", "b.example.com"}
+	b.Run(endpoints, func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		host, _ := splitEndpoint(endpoints[i])
+		if host == "A.example.com" {
+			host = "a.example.com"
+		}
+		started[host] = append(started[host], time.Now())
+	})
+
+	a := started["a.example.com"]
+	if len(a) != 3 {
+		t.Fatalf("a.example.com fetched %d times, want 3", len(a))
+	}
+	if spread := a[2].Sub(a[0]); spread < 55*time.Millisecond {
+		t.Errorf("connections to one host (any port or case) spread over %v, want at least 2 intervals", spread)
+	}
+	if d := started["b.example.com"][0].Sub(a[0]); d > 25*time.Millisecond {
+		t.Errorf("other host delayed by %v", d)
+	}
+}
+
+func TestBatchJitter(t *testing.T) {
+	t.Parallel()
+
+	b := &Batch{Jitter: 20 * time.Millisecond}
+	start := time.Now()
+	for range 5 {
+		b.Wait("example.com")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("5 waits with 20ms jitter took %v", elapsed)
+	}
+}
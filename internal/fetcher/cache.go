@@ -0,0 +1,158 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// ErrNotCached is returned by a CachedSource with CachedOnly set for endpoints without
+// a cached chain.
+var ErrNotCached = errors.New("no cached chain")
+
+// ChainCache stores fetched chains on disk, one file per endpoint, so repeated runs can
+// reuse them without a handshake.
+type ChainCache struct {
+	Dir string
+}
+
+// DefaultCacheDir returns the per-user directory chains are cached in.
+func DefaultCacheDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "certvet", "chains"), nil
+}
+
+// cachedChain is the on-disk form of a chain.
+type cachedChain struct {
+	Endpoint   string                 `json:"endpoint"` // Requested endpoint the chain is cached for
+	Host       string                 `json:"host"`     // CertChain.Endpoint
+	Fetched    time.Time              `json:"fetched"`
+	Certs      [][]byte               `json:"certs"` // DER, leaf first
+	SCTs       []truststore.SCT       `json:"scts,omitempty"`
+	OCSPStaple *truststore.OCSPStaple `json:"ocsp_staple,omitempty"`
+	MustStaple bool                   `json:"must_staple,omitempty"`
+	TLS        *truststore.TLSInfo    `json:"tls,omitempty"`
+}
+
+// path returns the cache file of endpoint. Endpoints are case-insensitive.
+func (c ChainCache) path(endpoint string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(endpoint)))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Load returns the cached chain of endpoint and when it was fetched.
+// The error wraps fs.ErrNotExist if the endpoint is not cached.
+func (c ChainCache) Load(endpoint string) (*truststore.CertChain, time.Time, error) {
+	data, err := os.ReadFile(c.path(endpoint))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var cc cachedChain
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return nil, time.Time{}, fmt.Errorf("cached chain of %s: %w", endpoint, err)
+	}
+	if !strings.EqualFold(cc.Endpoint, endpoint) || len(cc.Certs) == 0 {
+		return nil, time.Time{}, fmt.Errorf("cached chain of %s: %w", endpoint, fs.ErrNotExist)
+	}
+	certs := make([]*x509.Certificate, len(cc.Certs))
+	for i, der := range cc.Certs {
+		if certs[i], err = x509.ParseCertificate(der); err != nil {
+			return nil, time.Time{}, fmt.Errorf("cached chain of %s: %w", endpoint, err)
+		}
+	}
+
+	return &truststore.CertChain{
+		Endpoint:      cc.Host,
+		ServerCert:    certs[0],
+		Intermediates: certs[1:],
+		SCTs:          cc.SCTs,
+		OCSPStaple:    cc.OCSPStaple,
+		MustStaple:    cc.MustStaple,
+		TLS:           cc.TLS,
+	}, cc.Fetched, nil
+}
+
+// Save caches the chain fetched from endpoint as fetched now, replacing any cached chain
+// of the endpoint.
+func (c ChainCache) Save(endpoint string, chain *truststore.CertChain) error {
+	cc := cachedChain{
+		Endpoint:   endpoint,
+		Host:       chain.Endpoint,
+		Fetched:    time.Now().UTC(),
+		Certs:      [][]byte{chain.ServerCert.Raw},
+		SCTs:       chain.SCTs,
+		OCSPStaple: chain.OCSPStaple,
+		MustStaple: chain.MustStaple,
+		TLS:        chain.TLS,
+	}
+	for _, cert := range chain.Intermediates {
+		cc.Certs = append(cc.Certs, cert.Raw)
+	}
+	data, err := json.Marshal(cc)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return err
+	}
+	// Write and rename so concurrent runs never read a partial file
+	f, err := os.CreateTemp(c.Dir, "chain-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), c.path(endpoint))
+}
+
+// CachedSource serves chains from Cache while they are younger than TTL, fetching and
+// caching others from Source. With CachedOnly, cached chains of any age are used and
+// nothing is fetched, for offline re-analysis.
+type CachedSource struct {
+	Source     ChainSource
+	Cache      ChainCache
+	TTL        time.Duration
+	CachedOnly bool
+}
+
+// FetchChain returns the cached chain of endpoint or fetches it.
+// Unreadable cache entries are refetched; failures to cache a fetched chain are ignored.
+func (s CachedSource) FetchChain(endpoint string) (*truststore.CertChain, error) {
+	chain, fetched, err := s.Cache.Load(endpoint)
+	switch {
+	case s.CachedOnly && errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("%s: %w", endpoint, ErrNotCached)
+	case s.CachedOnly && err != nil:
+		return nil, err
+	case s.CachedOnly || err == nil && time.Since(fetched) < s.TTL:
+		return chain, nil
+	}
+
+	if chain, err = s.Source.FetchChain(endpoint); err != nil {
+		return nil, err
+	}
+	_ = s.Cache.Save(endpoint, chain)
+	return chain, nil
+}
@@ -0,0 +1,82 @@
+package fetcher
+
+import (
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCachedSource(t *testing.T) {
+	t.Parallel()
+
+	cert := testServerCert(t)
+	var fetches int
+	source := ChainSourceFunc(func(endpoint string) (*truststore.CertChain, error) {
+		fetches++
+		chain, err := NewCertChain("example.com", []*x509.Certificate{cert})
+		if err != nil {
+			return nil, err
+		}
+		chain.TLS = &truststore.TLSInfo{Version: 0x0304}
+		chain.SCTs = append(chain.SCTs, truststore.SCT{Timestamp: time.Unix(1700000000, 0).UTC(), LogID: [32]byte{1}, Source: truststore.SCTSourceTLS})
+		return chain, nil
+	})
+	cache := ChainCache{Dir: t.TempDir()}
+	cached := CachedSource{Source: source, Cache: cache, TTL: time.Hour}
+
+	// The first fetch fills the cache and the second is served from it
+	for range 2 {
+		chain, err := cached.FetchChain("Example.com:8443")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !chain.ServerCert.Equal(cert) || chain.Endpoint != "example.com" || chain.TLS == nil || len(chain.SCTs) != 1 {
+			t.Errorf("chain = %+v, want the fetched chain", chain)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("source fetched %d times, want 1", fetches)
+	}
+
+	// Endpoints are case-insensitive, and ports are part of the key
+	if _, _, err := cache.Load("example.com:8443"); err != nil {
+		t.Errorf("Load() with other case: %v", err)
+	}
+	if _, _, err := cache.Load("example.com"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() of another endpoint: err = %v, want not exist", err)
+	}
+
+	// Expired chains are refetched
+	expired := CachedSource{Source: source, Cache: cache, TTL: time.Nanosecond}
+	if _, err := expired.FetchChain("example.com:8443"); err != nil || fetches != 2 {
+		t.Errorf("expired: err = %v, fetches = %d, want 2", err, fetches)
+	}
+
+	// Cached-only uses chains of any age and never fetches
+	offline := CachedSource{Source: source, Cache: cache, CachedOnly: true}
+	if _, err := offline.FetchChain("example.com:8443"); err != nil {
+		t.Errorf("cached-only hit: %v", err)
+	}
+	if _, err := offline.FetchChain("other.example.com"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("cached-only miss: err = %v, want ErrNotCached", err)
+	}
+	if fetches != 2 {
+		t.Errorf("cached-only fetched from the source")
+	}
+
+	// Corrupt entries are refetched
+	matches, _ := filepath.Glob(filepath.Join(cache.Dir, "*.json"))
+	for _, m := range matches {
+		if err := os.WriteFile(m, []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cached.FetchChain("example.com:8443"); err != nil || fetches != 3 {
+		t.Errorf("corrupt: err = %v, fetches = %d, want 3", err, fetches)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"time"
@@ -35,22 +37,13 @@ const (
 
 // FetchCertChain connects to endpoint via TLS and returns the certificate chain.
 // Endpoint can be "host" or "host:port" (default port 443).
-// Also extracts Signed Certificate Timestamps (SCTs) from TLS extension and embedded in certificate.
+// Also extracts Signed Certificate Timestamps (SCTs) from TLS extension and embedded in certificate,
+// and the stapled OCSP response if the server provided one.
 func FetchCertChain(endpoint string, timeout time.Duration) (*truststore.CertChain, error) {
-	// Normalize endpoint
-	host := endpoint
-	addr := endpoint
-	if !strings.Contains(endpoint, ":") {
-		addr = endpoint + ":" + defaultTLSPort
-	} else {
-		host = endpoint[:strings.LastIndex(endpoint, ":")]
-	}
+	host, addr := splitEndpoint(endpoint)
 
 	// Connect with timeout
-	dialer := &net.Dialer{Timeout: timeout}
-	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
-		InsecureSkipVerify: true, //nolint:gosec // G402: Intentional - we validate against custom trust stores
-	})
+	conn, err := dial(addr, timeout, 0)
 	if err != nil {
 		return nil, fmt.Errorf("TLS connection failed: %w", err)
 	}
@@ -64,14 +57,12 @@ func FetchCertChain(endpoint string, timeout time.Duration) (*truststore.CertCha
 		return nil, fmt.Errorf("no certificates received from %s", endpoint)
 	}
 
-	chain := &truststore.CertChain{
-		Endpoint:   host,
-		ServerCert: certs[0],
-	}
-
-	if len(certs) > 1 {
-		chain.Intermediates = certs[1:]
+	// Embedded SCTs and must-staple come from the certificates themselves
+	chain, err := NewCertChain(host, certs)
+	if err != nil {
+		return nil, err
 	}
+	chain.TLS = &truststore.TLSInfo{Version: state.Version, CipherSuite: state.CipherSuite}
 
 	// Extract SCTs from TLS extension
 	for _, sctBytes := range state.SignedCertificateTimestamps {
@@ -80,13 +71,72 @@ func FetchCertChain(endpoint string, timeout time.Duration) (*truststore.CertCha
 		}
 	}
 
-	// Extract embedded SCTs from server certificate
-	embeddedSCTs := extractEmbeddedSCTs(certs[0])
-	chain.SCTs = append(chain.SCTs, embeddedSCTs...)
+	// Stapled OCSP response
+	if len(state.OCSPResponse) > 0 {
+		staple, err := parseOCSPStaple(state.OCSPResponse, certs[0].SerialNumber)
+		if err != nil {
+			staple = &truststore.OCSPStaple{Status: truststore.OCSPStatusInvalid}
+		}
+		chain.OCSPStaple = staple
+	}
 
 	return chain, nil
 }
 
+// ProbeMinVersion records in chain.TLS the lowest protocol version the server accepts,
+// reconnecting with TLS 1.2 as the maximum if TLS 1.3 was negotiated.
+// Versions below TLS 1.2 are not probed.
+func ProbeMinVersion(chain *truststore.CertChain, endpoint string, timeout time.Duration) error {
+	if chain.TLS == nil {
+		return fmt.Errorf("no handshake recorded for %s", endpoint)
+	}
+	if chain.TLS.Version < tls.VersionTLS13 {
+		chain.TLS.MinVersion = chain.TLS.Version
+		return nil
+	}
+
+	_, addr := splitEndpoint(endpoint)
+	conn, err := dial(addr, timeout, tls.VersionTLS12)
+	if err != nil {
+		if !isHandshakeRefusal(err) {
+			return fmt.Errorf("TLS 1.2 probe failed: %w", err)
+		}
+		chain.TLS.MinVersion = tls.VersionTLS13
+		return nil
+	}
+	_ = conn.Close()
+	chain.TLS.MinVersion = tls.VersionTLS12
+	return nil
+}
+
+// isHandshakeRefusal reports whether err means the server rejected the handshake
+// (an alert or a closed connection) rather than being unreachable.
+func isHandshakeRefusal(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "remote error" {
+		return true
+	}
+	return errors.Is(err, io.EOF)
+}
+
+// splitEndpoint returns the host and dial address ("host:port", default port 443) of an endpoint.
+func splitEndpoint(endpoint string) (host, addr string) {
+	if !strings.Contains(endpoint, ":") {
+		return endpoint, endpoint + ":" + defaultTLSPort
+	}
+	return endpoint[:strings.LastIndex(endpoint, ":")], endpoint
+}
+
+// dial opens a TLS connection without certificate verification.
+// maxVersion limits the protocol version (0 for the default).
+func dial(addr string, timeout time.Duration, maxVersion uint16) (*tls.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	return tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // G402: Intentional - we validate against custom trust stores
+		MaxVersion:         maxVersion,
+	})
+}
+
 // parseSCT parses an SCT from raw bytes (RFC 6962 format).
 // Returns the SCT with timestamp and log ID extracted.
 func parseSCT(data []byte, source truststore.SCTSource) (truststore.SCT, error) {
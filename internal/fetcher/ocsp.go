@@ -0,0 +1,133 @@
+package fetcher
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// OIDs for OCSP stapling (RFC 6960, RFC 7633)
+var (
+	oidOCSPBasic  = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+	oidTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+)
+
+// tlsFeatureStatusRequest is the TLS status_request extension number (must-staple).
+const tlsFeatureStatusRequest = 5
+
+// ocspSuccessful is the OCSPResponseStatus for a successful response.
+const ocspSuccessful = 0
+
+// ASN.1 structures for OCSP response parsing (RFC 6960 section 4.2.1)
+type ocspResponse struct {
+	Status   asn1.Enumerated
+	Response ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type ocspBasicResponse struct {
+	TBSResponseData    ocspResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseData struct {
+	Version            int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID     asn1.RawValue
+	ProducedAt         time.Time `asn1:"generalized"`
+	Responses          []ocspSingleResponse
+	ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspSingleResponse struct {
+	CertID           ocspCertID
+	Good             asn1.Flag        `asn1:"tag:0,optional"`
+	Revoked          ocspRevokedInfo  `asn1:"tag:1,optional"`
+	Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspCertID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRevokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
+
+// parseOCSPStaple extracts status and timestamps for the certificate with the given serial.
+func parseOCSPStaple(data []byte, serial *big.Int) (*truststore.OCSPStaple, error) {
+	var resp ocspResponse
+	if _, err := asn1.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse OCSP response: %w", err)
+	}
+	if resp.Status != ocspSuccessful {
+		return nil, fmt.Errorf("OCSP response status: %d", resp.Status)
+	}
+	if !resp.Response.ResponseType.Equal(oidOCSPBasic) {
+		return nil, fmt.Errorf("unsupported OCSP response type: %s", resp.Response.ResponseType)
+	}
+
+	var basic ocspBasicResponse
+	if _, err := asn1.Unmarshal(resp.Response.Response, &basic); err != nil {
+		return nil, fmt.Errorf("parse basic OCSP response: %w", err)
+	}
+
+	for _, r := range basic.TBSResponseData.Responses {
+		if r.CertID.SerialNumber == nil || r.CertID.SerialNumber.Cmp(serial) != 0 {
+			continue
+		}
+
+		staple := &truststore.OCSPStaple{
+			Status:     truststore.OCSPStatusUnknown,
+			ProducedAt: basic.TBSResponseData.ProducedAt,
+			ThisUpdate: r.ThisUpdate,
+			NextUpdate: r.NextUpdate,
+		}
+		switch {
+		case bool(r.Good):
+			staple.Status = truststore.OCSPStatusGood
+		case !r.Revoked.RevocationTime.IsZero():
+			staple.Status = truststore.OCSPStatusRevoked
+			staple.RevokedAt = r.Revoked.RevocationTime
+		}
+		return staple, nil
+	}
+
+	return nil, fmt.Errorf("OCSP response does not cover serial %s", serial)
+}
+
+// hasMustStaple reports whether the certificate requests OCSP stapling via TLS Feature (RFC 7633).
+func hasMustStaple(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidTLSFeature) {
+			continue
+		}
+		var features []int
+		if _, err := asn1.Unmarshal(ext.Value, &features); err != nil {
+			return false
+		}
+		for _, f := range features {
+			if f == tlsFeatureStatusRequest {
+				return true
+			}
+		}
+	}
+	return false
+}
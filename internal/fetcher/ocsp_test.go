@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// buildOCSPResponse marshals a minimal successful basic OCSP response for one serial.
+// status is the CertStatus CHOICE tag: 0 good, 1 revoked, 2 unknown.
+func buildOCSPResponse(t *testing.T, serial int64, status int, produced time.Time) []byte {
+	t.Helper()
+
+	type singleResponse struct {
+		CertID     ocspCertID
+		Status     asn1.RawValue
+		ThisUpdate time.Time `asn1:"generalized"`
+		NextUpdate time.Time `asn1:"generalized,explicit,tag:0,optional"`
+	}
+	type responseData struct {
+		ResponderID asn1.RawValue
+		ProducedAt  time.Time `asn1:"generalized"`
+		Responses   []singleResponse
+	}
+	type basicResponse struct {
+		TBSResponseData    responseData
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          asn1.BitString
+	}
+	type responseBytes struct {
+		ResponseType asn1.ObjectIdentifier
+		Response     []byte
+	}
+	type response struct {
+		Status   asn1.Enumerated
+		Response responseBytes `asn1:"explicit,tag:0"`
+	}
+
+	statusValue := asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: status}
+	if status == 1 {
+		revokedAt, err := asn1.MarshalWithParams(produced.Add(-time.Hour), "generalized")
+		if err != nil {
+			t.Fatal(err)
+		}
+		statusValue.IsCompound = true
+		statusValue.Bytes = revokedAt
+	}
+
+	basic, err := asn1.Marshal(basicResponse{
+		TBSResponseData: responseData{
+			ResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: []byte{0x04, 0x01, 0x00}},
+			ProducedAt:  produced,
+			Responses: []singleResponse{{
+				CertID: ocspCertID{
+					HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}},
+					NameHash:      []byte{1},
+					IssuerKeyHash: []byte{2},
+					SerialNumber:  big.NewInt(serial),
+				},
+				Status:     statusValue,
+				ThisUpdate: produced,
+				NextUpdate: produced.Add(7 * 24 * time.Hour),
+			}},
+		},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}},
+		Signature:          asn1.BitString{Bytes: []byte{0}, BitLength: 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := asn1.Marshal(response{Response: responseBytes{ResponseType: oidOCSPBasic, Response: basic}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseOCSPStaple(t *testing.T) {
+	produced := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		serial     int64
+		wantStatus string
+		errSubstr  string
+	}{
+		{name: "good", data: buildOCSPResponse(t, 42, 0, produced), serial: 42, wantStatus: truststore.OCSPStatusGood},
+		{name: "revoked", data: buildOCSPResponse(t, 42, 1, produced), serial: 42, wantStatus: truststore.OCSPStatusRevoked},
+		{name: "unknown", data: buildOCSPResponse(t, 42, 2, produced), serial: 42, wantStatus: truststore.OCSPStatusUnknown},
+		{name: "other serial", data: buildOCSPResponse(t, 42, 0, produced), serial: 7, errSubstr: "does not cover"},
+		{name: "garbage", data: []byte{0x01, 0x02}, serial: 42, errSubstr: "parse OCSP response"},
+		{name: "unsuccessful", data: []byte{0x30, 0x03, 0x0a, 0x01, 0x06}, serial: 42, errSubstr: "status: 6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staple, err := parseOCSPStaple(tt.data, big.NewInt(tt.serial))
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if staple.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", staple.Status, tt.wantStatus)
+			}
+			if !staple.ProducedAt.Equal(produced) {
+				t.Errorf("ProducedAt = %v, want %v", staple.ProducedAt, produced)
+			}
+			if staple.NextUpdate.IsZero() {
+				t.Error("NextUpdate not parsed")
+			}
+			if tt.wantStatus == truststore.OCSPStatusRevoked && staple.RevokedAt.IsZero() {
+				t.Error("RevokedAt not parsed")
+			}
+		})
+	}
+}
+
+func TestHasMustStaple(t *testing.T) {
+	features, err := asn1.Marshal([]int{tlsFeatureStatusRequest})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withFeature := &x509.Certificate{Extensions: []pkix.Extension{{Id: oidTLSFeature, Value: features}}}
+	if !hasMustStaple(withFeature) {
+		t.Error("expected must-staple for certificate with TLS Feature status_request")
+	}
+	if hasMustStaple(&x509.Certificate{}) {
+		t.Error("expected no must-staple for certificate without extensions")
+	}
+}
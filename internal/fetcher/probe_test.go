@@ -0,0 +1,46 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeMinVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion uint16
+		want       uint16
+	}{
+		{"accepts TLS 1.2", tls.VersionTLS12, tls.VersionTLS12},
+		{"requires TLS 1.3", tls.VersionTLS13, tls.VersionTLS13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+			srv.TLS = &tls.Config{MinVersion: tt.minVersion}
+			srv.StartTLS()
+			defer srv.Close()
+
+			endpoint := strings.TrimPrefix(srv.URL, "https://")
+			chain, err := FetchCertChain(endpoint, 5*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if chain.TLS == nil || chain.TLS.Version != tls.VersionTLS13 {
+				t.Fatalf("expected TLS 1.3 handshake, got %+v", chain.TLS)
+			}
+
+			if err := ProbeMinVersion(chain, endpoint, 5*time.Second); err != nil {
+				t.Fatal(err)
+			}
+			if chain.TLS.MinVersion != tt.want {
+				t.Errorf("MinVersion = %s, want %s", tls.VersionName(chain.TLS.MinVersion), tls.VersionName(tt.want))
+			}
+		})
+	}
+}
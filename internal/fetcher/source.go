@@ -0,0 +1,82 @@
+package fetcher
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// ChainSource retrieves the certificate chain an endpoint serves.
+// Embedders implement it to validate chains from sources other than a live handshake
+// (service-mesh admin APIs, proxy config dumps, stored chains) while reusing
+// validation, filtering and output unchanged.
+type ChainSource interface {
+	FetchChain(endpoint string) (*truststore.CertChain, error)
+}
+
+// ChainSourceFunc adapts an ordinary function to a ChainSource.
+type ChainSourceFunc func(endpoint string) (*truststore.CertChain, error)
+
+// FetchChain calls f(endpoint).
+func (f ChainSourceFunc) FetchChain(endpoint string) (*truststore.CertChain, error) {
+	return f(endpoint)
+}
+
+// TLSSource fetches chains over a live TLS connection (see FetchCertChain).
+type TLSSource struct {
+	Timeout time.Duration
+}
+
+// FetchChain connects to endpoint and returns the chain it serves.
+func (s TLSSource) FetchChain(endpoint string) (*truststore.CertChain, error) {
+	return FetchCertChain(endpoint, s.Timeout)
+}
+
+// NewCertChain builds a chain from certificates in served order (leaf first), extracting
+// embedded SCTs and the must-staple flag like FetchCertChain does. Handshake data
+// (TLS SCTs, OCSP staple, protocol version) is left empty.
+func NewCertChain(endpoint string, certs []*x509.Certificate) (*truststore.CertChain, error) {
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates for %s", endpoint)
+	}
+
+	chain := &truststore.CertChain{
+		Endpoint:   endpoint,
+		ServerCert: certs[0],
+		SCTs:       extractEmbeddedSCTs(certs[0]),
+		MustStaple: hasMustStaple(certs[0]),
+	}
+	if len(certs) > 1 {
+		chain.Intermediates = certs[1:]
+	}
+	return chain, nil
+}
+
+// ParsePEMChain builds a chain from PEM-encoded certificates in served order (leaf first),
+// such as a stored chain.pem. Non-certificate PEM blocks are skipped.
+func ParsePEMChain(endpoint string, data []byte) (*truststore.CertChain, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate for %s: %w", endpoint, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificates found")
+	}
+	return NewCertChain(endpoint, certs)
+}
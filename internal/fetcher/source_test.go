@@ -0,0 +1,98 @@
+package fetcher
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// testServerCert returns the self-signed certificate of a throwaway httptest TLS server.
+func testServerCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	return srv.Certificate()
+}
+
+func TestTLSSource(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	var src ChainSource = TLSSource{Timeout: 5 * time.Second}
+	chain, err := src.FetchChain(strings.TrimPrefix(srv.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chain.ServerCert.Equal(srv.Certificate()) || chain.TLS == nil {
+		t.Errorf("FetchChain() = %+v, want server certificate with handshake info", chain)
+	}
+}
+
+func TestChainSourceFunc(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string][]byte{}
+	cert := testServerCert(t)
+	stored["db.example.com"] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	var src ChainSource = ChainSourceFunc(func(endpoint string) (*truststore.CertChain, error) {
+		data, ok := stored[endpoint]
+		if !ok {
+			return nil, errors.New("not stored")
+		}
+		return ParsePEMChain(endpoint, data)
+	})
+
+	chain, err := src.FetchChain("db.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.Endpoint != "db.example.com" || !chain.ServerCert.Equal(cert) || chain.TLS != nil {
+		t.Errorf("FetchChain() = %+v, want stored leaf without handshake info", chain)
+	}
+
+	if _, err := src.FetchChain("missing.example.com"); err == nil {
+		t.Error("expected error for unknown endpoint")
+	}
+}
+
+func TestParsePEMChain(t *testing.T) {
+	t.Parallel()
+
+	cert := testServerCert(t)
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{0x01}})
+
+	tests := []struct {
+		name              string
+		data              []byte
+		wantIntermediates int
+		wantErr           bool
+	}{
+		{"leaf only", block, 0, false},
+		{"leaf and intermediate", append(append([]byte{}, block...), block...), 1, false},
+		{"skips other blocks", append(append([]byte{}, key...), block...), 0, false},
+		{"empty", nil, 0, true},
+		{"garbage certificate", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0x30}}), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chain, err := ParsePEMChain("example.com", tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePEMChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(chain.Intermediates) != tt.wantIntermediates {
+				t.Errorf("intermediates = %d, want %d", len(chain.Intermediates), tt.wantIntermediates)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package filter
 
 import (
+	"slices"
+
 	"github.com/Masterminds/semver/v3"
 	"github.com/ivoronin/certvet/internal/truststore"
 	"github.com/ivoronin/certvet/internal/version"
@@ -59,11 +61,24 @@ func (lessEqualStrategy) MatchCurrentVersion() bool                      { retur
 func (lessEqualStrategy) MatchSemver(cmp int) bool                       { return cmp <= 0 }
 
 // Match checks if a PlatformVersion satisfies the filter.
-// Logic: AND within same platform, OR across platforms.
+// Logic: AND within same platform, OR across platforms and across Or groups.
 func (f *Filter) Match(pv truststore.PlatformVersion) bool {
 	if f == nil || len(f.Constraints) == 0 {
 		return true
 	}
+	if f.matchGroup(pv) {
+		return true
+	}
+	for _, g := range f.Or {
+		if g.Match(pv) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGroup checks pv against the constraints of f, ignoring its Or groups.
+func (f *Filter) matchGroup(pv truststore.PlatformVersion) bool {
 
 	// Group constraints by platform
 	byPlatform := make(map[truststore.Platform][]Constraint)
@@ -88,6 +103,11 @@ func (f *Filter) Match(pv truststore.PlatformVersion) bool {
 
 // matchConstraint compares a constraint against a version string using operator strategies.
 func matchConstraint(c Constraint, ver string) bool {
+	// Unresolved "latest" (no versions of the platform in the data) matches nothing
+	if c.IsLatest {
+		return false
+	}
+
 	// Bare platform (nil Version and not IsCurrent) matches any version
 	if c.Version == nil && !c.IsCurrent {
 		return true
@@ -119,11 +139,63 @@ func matchConstraint(c Constraint, ver string) bool {
 	return strategy.MatchSemver(v.Compare(c.Version))
 }
 
-// FilterStores returns stores that match the filter.
+// Resolve returns the filter with "latest" and "latest-N" replaced by the newest and the
+// Nth newest version of the platform among stores ("current" aside). N beyond the oldest
+// version resolves to the oldest. Constraints of platforms without stores stay unresolved
+// and match nothing.
+func (f *Filter) Resolve(stores []truststore.Store) *Filter {
+	if f == nil || !f.hasLatest() {
+		return f
+	}
+
+	// Distinct versions per platform, newest first
+	versions := make(map[truststore.Platform][]*semver.Version)
+	for _, s := range stores {
+		v, err := semver.NewVersion(s.Version)
+		if err != nil {
+			continue // "current"
+		}
+		if !slices.ContainsFunc(versions[s.Platform], v.Equal) {
+			versions[s.Platform] = append(versions[s.Platform], v)
+		}
+	}
+	for _, vs := range versions {
+		slices.SortFunc(vs, func(a, b *semver.Version) int { return b.Compare(a) })
+	}
+
+	return f.resolve(versions)
+}
+
+// hasLatest reports whether f or any of its Or groups uses "latest".
+func (f *Filter) hasLatest() bool {
+	return slices.ContainsFunc(f.Constraints, func(c Constraint) bool { return c.IsLatest }) ||
+		slices.ContainsFunc(f.Or, (*Filter).hasLatest)
+}
+
+// resolve returns a copy of f with "latest" replaced by versions, newest first per platform.
+func (f *Filter) resolve(versions map[truststore.Platform][]*semver.Version) *Filter {
+	resolved := &Filter{Constraints: slices.Clone(f.Constraints)}
+	for i, c := range resolved.Constraints {
+		vs := versions[c.Platform]
+		if !c.IsLatest || len(vs) == 0 {
+			continue
+		}
+		c.Version = vs[min(c.Latest, len(vs)-1)]
+		c.IsLatest, c.Latest = false, 0
+		resolved.Constraints[i] = c
+	}
+	for _, g := range f.Or {
+		resolved.Or = append(resolved.Or, g.resolve(versions))
+	}
+	return resolved
+}
+
+// FilterStores returns stores that match the filter, resolving "latest" against them.
 func FilterStores(stores []truststore.Store, f *Filter) []truststore.Store {
 	if f == nil {
 		return stores
 	}
+	f = f.Resolve(stores)
 
 	var result []truststore.Store
 	for _, s := range stores {
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ivoronin/certvet/internal/truststore"
@@ -134,3 +135,84 @@ func TestFilterStoresNilFilter(t *testing.T) {
 		t.Errorf("nil filter should return all stores, got %d", len(filtered))
 	}
 }
+
+func TestFilterMatchOrGroups(t *testing.T) {
+	// Within a group constraints of a platform are ANDed, so this range is impossible
+	// without groups
+	f, err := ParseAll([]string{"ios>=18", "ios<=15", "android=14"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pv   truststore.PlatformVersion
+		want bool
+	}{
+		{truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, true},
+		{truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "15"}, true},
+		{truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "16"}, false},
+		{truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "14"}, true},
+		{truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "13"}, false},
+		{truststore.PlatformVersion{Platform: truststore.PlatformMacOS, Version: "14"}, false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.pv); got != tt.want {
+			t.Errorf("Match(%v) = %v, want %v", tt.pv, got, tt.want)
+		}
+	}
+
+	// "latest" is resolved in every group
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17"},
+		{Platform: truststore.PlatformIOS, Version: "18"},
+	}
+	if f, err = ParseAll([]string{"android", "ios=latest"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := FilterStores(stores, f); len(got) != 1 || got[0].Version != "18" {
+		t.Errorf("FilterStores() = %+v, want iOS 18", got)
+	}
+}
+
+func TestFilterStoresLatest(t *testing.T) {
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17"},
+		{Platform: truststore.PlatformIOS, Version: "18"},
+		{Platform: truststore.PlatformIOS, Version: "17.4"},
+		{Platform: truststore.PlatformAndroid, Version: "14"},
+		{Platform: truststore.PlatformAndroid, Version: "15"},
+		{Platform: truststore.PlatformAndroid, Version: "16"},
+		{Platform: truststore.PlatformChrome, Version: "139"},
+		{Platform: truststore.PlatformChrome, Version: "current"},
+	}
+
+	tests := []struct {
+		expr string
+		want []string // platform/version of matched stores, in input order
+	}{
+		{"ios=latest", []string{"ios/18"}},
+		{"ios=latest-1", []string{"ios/17.4"}},
+		{"android>=latest-1", []string{"android/15", "android/16"}},
+		{"android<latest-1", []string{"android/14"}},
+		{"android>=latest-10", []string{"android/14", "android/15", "android/16"}},
+		{"chrome=latest", []string{"chrome/139"}},
+		{"ios=latest,windows=latest", []string{"ios/18"}}, // No windows stores: matches nothing
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, s := range FilterStores(stores, f) {
+				got = append(got, string(s.Platform)+"/"+s.Version)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("FilterStores(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+			if !f.Constraints[0].IsLatest {
+				t.Error("FilterStores resolved the filter in place")
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package filter
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
@@ -25,13 +27,15 @@ type constraintExpr struct {
 }
 
 // Build the lexer
-// IMPORTANT: Platform pattern uses word boundaries (\b) to prevent "ios" matching inside "visionos" or "ipados"
+// IMPORTANT: Version comes first so "current" and "latest" aren't taken for platform names. Platform
+// names are any identifier, as custom stores (see truststore.CustomStore) add platforms;
+// Parse checks them against the known stores.
 var filterLexer = lexer.MustSimple([]lexer.SimpleRule{
 	{Name: "Whitespace", Pattern: `\s+`},
 	{Name: "Comma", Pattern: `,`},
 	{Name: "Operator", Pattern: `>=|<=|>|<|=`},
-	{Name: "Platform", Pattern: `(?i)\bios\b|\bipados\b|\bmacos\b|\btvos\b|\bvisionos\b|\bwatchos\b|\bandroid\b|\bchrome\b|\bwindows\b`},
-	{Name: "Version", Pattern: `\d+(\.\d+)*|current`}, // Semver: 17, 17.4, 17.4.1, or "current"
+	{Name: "Version", Pattern: `\d+(\.\d+)*|\bcurrent\b|\blatest(-\d+)?\b`}, // Semver: 17, 17.4, 17.4.1, "current" or "latest[-N]"
+	{Name: "Platform", Pattern: `[A-Za-z][A-Za-z0-9_-]*`},
 })
 
 // Build the parser
@@ -41,8 +45,15 @@ var filterParser = participle.MustBuild[filterExpr](
 	participle.Elide("Whitespace"),
 )
 
-// Parse parses a filter expression like "ios>=17.4,android>=10" or "android".
+// Parse parses a filter expression like "ios>=17.4,android>=10", "ios=latest", "apple>=26" or
+// "android", checking platform names against truststore.Stores.
 func Parse(expr string) (*Filter, error) {
+	return ParseFor(expr, truststore.Stores)
+}
+
+// ParseFor is Parse with platform names checked against stores, for callers that serve
+// a dataset other than truststore.Stores.
+func ParseFor(expr string, stores []truststore.Store) (*Filter, error) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return nil, fmt.Errorf("empty filter expression")
@@ -55,21 +66,64 @@ func Parse(expr string) (*Filter, error) {
 
 	constraints := make([]Constraint, 0, len(ast.Constraints))
 	for _, c := range ast.Constraints {
-		constraint, err := convertConstraint(c)
+		platforms, err := expandPlatform(c.Platform, stores)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		for _, p := range platforms {
+			constraint, err := convertConstraint(p, c)
+			if err != nil {
+				return nil, err
+			}
+			constraints = append(constraints, constraint)
 		}
-		constraints = append(constraints, constraint)
 	}
 
 	return &Filter{Constraints: constraints}, nil
 }
 
-// convertConstraint converts AST constraint to domain Constraint
-func convertConstraint(c *constraintExpr) (Constraint, error) {
-	// Platform is already validated by lexer, just convert to type
-	p := truststore.Platform(strings.ToLower(c.Platform))
+// ParseAll parses filter expressions given as separate flags, each a group of its own:
+// a version matches if it matches any of them. Empty expressions are ignored, and nil
+// (match all) is returned if there are no others.
+func ParseAll(exprs []string) (*Filter, error) {
+	return ParseAllFor(exprs, truststore.Stores)
+}
 
+// ParseAllFor is ParseAll with platform names checked against stores (see ParseFor).
+func ParseAllFor(exprs []string, stores []truststore.Store) (*Filter, error) {
+	var f *Filter
+	for _, expr := range exprs {
+		if expr == "" {
+			continue
+		}
+		group, err := ParseFor(expr, stores)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			f = group
+		} else {
+			f.Or = append(f.Or, group)
+		}
+	}
+	return f, nil
+}
+
+// expandPlatform returns the platform named in a filter, or the platforms of a meta-platform
+// such as "apple" (see platformGroups). Platforms of stores take precedence over groups.
+func expandPlatform(name string, stores []truststore.Store) ([]truststore.Platform, error) {
+	p := truststore.Platform(strings.ToLower(name))
+	if slices.ContainsFunc(stores, func(s truststore.Store) bool { return s.Platform == p }) {
+		return []truststore.Platform{p}, nil
+	}
+	if group, ok := platformGroups[string(p)]; ok {
+		return group, nil
+	}
+	return nil, fmt.Errorf("unknown platform %q", name)
+}
+
+// convertConstraint converts AST constraint to domain Constraint for platform p
+func convertConstraint(p truststore.Platform, c *constraintExpr) (Constraint, error) {
 	// Handle bare platform (no operator/version)
 	if c.Operator == "" && c.Version == "" {
 		return Constraint{
@@ -97,6 +151,23 @@ func convertConstraint(c *constraintExpr) (Constraint, error) {
 		}, nil
 	}
 
+	// "latest" and "latest-N" are resolved against the data (see Filter.Resolve)
+	if rest, ok := strings.CutPrefix(c.Version, "latest"); ok {
+		n := 0
+		if rest != "" {
+			var err error
+			if n, err = strconv.Atoi(strings.TrimPrefix(rest, "-")); err != nil {
+				return Constraint{}, fmt.Errorf("invalid version %q: %w", c.Version, err)
+			}
+		}
+		return Constraint{
+			Platform: p,
+			Operator: Operator(c.Operator),
+			IsLatest: true,
+			Latest:   n,
+		}, nil
+	}
+
 	// Parse semver
 	ver, err := semver.NewVersion(c.Version)
 	if err != nil {
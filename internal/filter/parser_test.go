@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
@@ -32,6 +33,13 @@ func TestParse(t *testing.T) {
 		{"bare platform windows", "windows", 1, ""},
 		{"windows constraint", "windows>=10", 1, ""},
 		{"windows current", "windows=current", 1, ""},
+		{"latest", "ios=latest", 1, ""},
+		{"apple group", "apple>=26", 6, ""},
+		{"apple group and platform", "Apple>=17,android", 7, ""},
+		{"latest minus", "android>=latest-2", 1, ""},
+		{"latest without operator", "ios latest", 0, "invalid filter"},
+		{"bare platform wincontainer", "wincontainer", 1, ""},
+		{"windows and wincontainer", "windows,wincontainer", 2, ""},
 		{"mixed bare and constraint", "ios,android>=10", 2, ""},
 		{"unknown platform", "linux>=10", 0, "invalid filter"},
 		{"invalid operator", "ios>>15", 0, "invalid filter"},
@@ -66,6 +74,8 @@ func TestParse(t *testing.T) {
 
 		// Invalid platform name
 		{"invalid platform osx", "osx>=10", 0, "invalid filter"},
+		{"platform without operator", "ios17", 0, "invalid filter"},
+		{"version without platform", "current", 0, "invalid filter"},
 	}
 
 	for _, tt := range tests {
@@ -110,6 +120,76 @@ func TestParseConstraintValues(t *testing.T) {
 	}
 }
 
+func TestParseAll(t *testing.T) {
+	f, err := ParseAll([]string{"ios>=17", "", "android=14,ios<15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Constraints) != 1 || len(f.Or) != 1 || len(f.Or[0].Constraints) != 2 {
+		t.Errorf("ParseAll() = %+v, want 1 constraint and 1 Or group of 2", f)
+	}
+
+	if f, err := ParseAll(nil); f != nil || err != nil {
+		t.Errorf("ParseAll(nil) = %v, %v; want nil filter", f, err)
+	}
+	if _, err := ParseAll([]string{"ios", "linux"}); err == nil {
+		t.Error("ParseAll() accepted an invalid group")
+	}
+}
+
+func TestParseAllFor(t *testing.T) {
+	stores := []truststore.Store{{Platform: "linux", Version: "current"}}
+
+	f, err := ParseAllFor([]string{"Linux=current"}, stores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Constraints) != 1 || f.Constraints[0].Platform != "linux" {
+		t.Errorf("ParseAllFor() = %+v, want a linux constraint", f)
+	}
+	if _, err := ParseAllFor([]string{"ios"}, stores); err == nil || !strings.Contains(err.Error(), "unknown platform") {
+		t.Errorf("ParseAllFor(ios) error = %v, want unknown platform", err)
+	}
+}
+
+func TestParsePlatformGroup(t *testing.T) {
+	f, err := Parse("apple>=17,apple<26")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[truststore.Platform]int)
+	for _, c := range f.Constraints {
+		counts[c.Platform]++
+	}
+	for _, p := range platformGroups["apple"] {
+		if counts[p] != 2 {
+			t.Errorf("%s has %d constraints, want 2", p, counts[p])
+		}
+	}
+	if len(counts) != 6 {
+		t.Errorf("constraints on %d platforms, want the 6 Apple platforms", len(counts))
+	}
+
+	// Like a platform, the group matches each member's versions
+	pv := truststore.PlatformVersion{Platform: truststore.PlatformWatchOS, Version: "18"}
+	if !f.Match(pv) {
+		t.Errorf("Match(%v) = false, want true", pv)
+	}
+}
+
+func TestParseLatest(t *testing.T) {
+	f, err := Parse("ios=latest,android>=latest-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{0, 2} {
+		c := f.Constraints[i]
+		if !c.IsLatest || c.Latest != want || c.Version != nil {
+			t.Errorf("constraint %d = %+v, want latest-%d", i, c, want)
+		}
+	}
+}
+
 func TestParseSemverConstraint(t *testing.T) {
 	f, err := Parse("ios>=17.4")
 	if err != nil {
@@ -23,9 +23,25 @@ type Constraint struct {
 	Operator  Operator
 	Version   *semver.Version // nil means "match any version" (bare platform)
 	IsCurrent bool            // true when version is "current" (Chrome only)
+	IsLatest  bool            // true when version is "latest" or "latest-N", until resolved
+	Latest    int             // N of "latest-N": versions back from the newest in the data
 }
 
 // Filter represents parsed filter expression.
 type Filter struct {
 	Constraints []Constraint
+	Or          []*Filter // Alternative groups, from further expressions given to ParseAll
+}
+
+// platformGroups maps meta-platforms to the platforms they stand for in filters. Apple's
+// platforms share one root store, so filters usually want all of them.
+var platformGroups = map[string][]truststore.Platform{
+	"apple": {
+		truststore.PlatformIOS,
+		truststore.PlatformIPadOS,
+		truststore.PlatformMacOS,
+		truststore.PlatformTVOS,
+		truststore.PlatformVisionOS,
+		truststore.PlatformWatchOS,
+	},
 }
@@ -0,0 +1,60 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody limits how much of an error response is included in errors.
+const maxErrorBody = 512
+
+// apiClient sends JSON requests to a tracker REST API.
+type apiClient struct {
+	client *http.Client
+	header http.Header // Authentication and content negotiation headers
+}
+
+// do sends in (if non-nil) as JSON and decodes a JSON response into out (if non-nil).
+func (c *apiClient) do(method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if text := strings.TrimSpace(string(msg)); text != "" {
+			return fmt.Errorf("%s %s: status %d: %s", method, url, resp.StatusCode, text)
+		}
+		return fmt.Errorf("%s %s: status %d", method, url, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,101 @@
+package issues
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the public GitHub REST API (override with GITHUB_API_URL for GitHub Enterprise).
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// githubMarker tags issue bodies with their deduplication key; GitHub renders it invisibly.
+var githubMarker = regexp.MustCompile(`\n?<!-- certvet:([0-9a-f]+) -->\s*$`)
+
+// GitHub files issues in a GitHub repository.
+type GitHub struct {
+	api     apiClient
+	baseURL string // API URL of the repository
+}
+
+// NewGitHub creates a tracker for repo ("owner/name") at apiURL, authenticating with token.
+func NewGitHub(apiURL, repo, token string, timeout time.Duration) *GitHub {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return &GitHub{
+		api:     apiClient{client: &http.Client{Timeout: timeout}, header: header},
+		baseURL: strings.TrimSuffix(apiURL, "/") + "/repos/" + repo,
+	}
+}
+
+type githubIssue struct {
+	Number      int       `json:"number"`
+	HTMLURL     string    `json:"html_url"`
+	Body        string    `json:"body"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// Open lists open issues labeled certvet and indexes them by the key in their body marker.
+func (g *GitHub) Open() (map[string]Ticket, error) {
+	open := make(map[string]Ticket)
+	for page := 1; ; page++ {
+		var batch []githubIssue
+		url := fmt.Sprintf("%s/issues?state=open&labels=%s&per_page=100&page=%d", g.baseURL, Label, page)
+		if err := g.api.do(http.MethodGet, url, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, issue := range batch {
+			m := githubMarker.FindStringSubmatchIndex(issue.Body)
+			if issue.PullRequest != nil || m == nil {
+				continue
+			}
+			open[issue.Body[m[2]:m[3]]] = Ticket{
+				ID:   strconv.Itoa(issue.Number),
+				URL:  issue.HTMLURL,
+				Body: issue.Body[:m[0]],
+			}
+		}
+		if len(batch) < 100 {
+			return open, nil
+		}
+	}
+}
+
+// Create opens a labeled issue.
+func (g *GitHub) Create(issue Issue) (Ticket, error) {
+	req := map[string]any{
+		"title":  issue.Title,
+		"body":   githubBody(issue),
+		"labels": []string{Label},
+	}
+	var created githubIssue
+	if err := g.api.do(http.MethodPost, g.baseURL+"/issues", req, &created); err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{ID: strconv.Itoa(created.Number), URL: created.HTMLURL, Body: issue.Body}, nil
+}
+
+// Update replaces the title and body of an open issue.
+func (g *GitHub) Update(ticket Ticket, issue Issue) error {
+	req := map[string]any{"title": issue.Title, "body": githubBody(issue)}
+	return g.api.do(http.MethodPatch, g.baseURL+"/issues/"+ticket.ID, req, nil)
+}
+
+// Close comments on an issue and closes it as completed.
+func (g *GitHub) Close(ticket Ticket, comment string) error {
+	if err := g.api.do(http.MethodPost, g.baseURL+"/issues/"+ticket.ID+"/comments", map[string]any{"body": comment}, nil); err != nil {
+		return err
+	}
+	req := map[string]any{"state": "closed", "state_reason": "completed"}
+	return g.api.do(http.MethodPatch, g.baseURL+"/issues/"+ticket.ID, req, nil)
+}
+
+// githubBody appends the deduplication marker to an issue body.
+func githubBody(issue Issue) string {
+	return strings.TrimRight(issue.Body, "\n") + "\n\n<!-- certvet:" + issue.Key + " -->\n"
+}
@@ -0,0 +1,83 @@
+package issues
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGitHub(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/web/issues":
+			if r.URL.Query().Get("labels") != Label {
+				t.Errorf("issues listed without %s label filter", Label)
+			}
+			_, _ = w.Write([]byte(`[
+				{"number": 7, "html_url": "https://github.com/acme/web/issues/7", "body": "failures\n\n<!-- certvet:abc123 -->\n"},
+				{"number": 8, "html_url": "https://github.com/acme/web/pull/8", "body": "<!-- certvet:def456 -->", "pull_request": {}},
+				{"number": 9, "html_url": "https://github.com/acme/web/issues/9", "body": "filed by hand"}
+			]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/web/issues":
+			if !strings.HasSuffix(body["body"].(string), "<!-- certvet:new1 -->\n") {
+				t.Errorf("created body lacks marker: %q", body["body"])
+			}
+			_, _ = w.Write([]byte(`{"number": 10, "html_url": "https://github.com/acme/web/issues/10"}`))
+		case r.URL.Path == "/repos/acme/web/issues/7" || r.URL.Path == "/repos/acme/web/issues/7/comments":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gh := NewGitHub(srv.URL, "acme/web", "token", time.Second)
+
+	open, err := gh.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open["abc123"].ID != "7" || open["abc123"].Body != "failures\n" {
+		t.Errorf("Open() = %+v, want only issue 7 without marker", open)
+	}
+
+	created, err := gh.Create(Issue{Key: "new1", Title: "certvet: trust failures for a.example.com", Body: "failures\n"})
+	if err != nil || created.URL != "https://github.com/acme/web/issues/10" {
+		t.Errorf("Create() = %+v, %v", created, err)
+	}
+	if err := gh.Close(open["abc123"], "passed"); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	want := []string{
+		"GET /repos/acme/web/issues",
+		"POST /repos/acme/web/issues",
+		"POST /repos/acme/web/issues/7/comments",
+		"PATCH /repos/acme/web/issues/7",
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	if _, err := NewGitHub(srv.URL, "acme/web", "wrong", time.Second).Open(); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Open() with bad token error = %v, want status 401", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package issues files trust failures as issues in external trackers (GitHub, Jira).
+//
+// Each endpoint with failures maps to one issue identified by a key derived from the endpoint,
+// so repeated runs update the existing issue instead of filing duplicates, and issues are
+// closed once their endpoint passes again.
+package issues
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Label marks issues filed by certvet.
+const Label = "certvet"
+
+// Issue is the desired state of an issue for one failing endpoint.
+type Issue struct {
+	Key   string // Deduplication key (see KeyFor)
+	Title string
+	Body  string
+}
+
+// Ticket is an open issue found in a tracker.
+type Ticket struct {
+	ID   string // Tracker-specific identifier (issue number or key)
+	URL  string
+	Body string
+}
+
+// Tracker creates, updates and closes issues in an issue tracker.
+type Tracker interface {
+	// Open returns open certvet issues by deduplication key.
+	Open() (map[string]Ticket, error)
+	Create(issue Issue) (Ticket, error)
+	Update(ticket Ticket, issue Issue) error
+	Close(ticket Ticket, comment string) error
+}
+
+// Action is a change made to a tracker by Sync.
+type Action struct {
+	Kind string // "created", "updated" or "closed"
+	Key  string
+	URL  string
+}
+
+// KeyFor returns the deduplication key for an endpoint.
+func KeyFor(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return hex.EncodeToString(sum[:6])
+}
+
+// FromReports builds issues for endpoints that failed validation and lists keys of endpoints
+// that passed. Endpoints that could not be fetched are neither filed nor resolved.
+func FromReports(reports []*truststore.ValidationReport) (failing []Issue, passing []string) {
+	for _, report := range reports {
+		switch {
+		case report.Error != "":
+			continue
+		case report.AllPassed:
+			passing = append(passing, KeyFor(report.Endpoint))
+		default:
+			failing = append(failing, newIssue(report))
+		}
+	}
+	return failing, passing
+}
+
+// newIssue describes a failing report. The body only changes when the failures do,
+// so unchanged failures don't update the issue on every run.
+func newIssue(report *truststore.ValidationReport) Issue {
+	var b strings.Builder
+	fmt.Fprintf(&b, "certvet found trust failures for `%s`:\n\n", report.Endpoint)
+	for _, g := range output.SummarizeFailures([]*truststore.ValidationReport{report}) {
+		fmt.Fprintf(&b, "- %s: %s\n", g.Platforms, g.Reason)
+	}
+	if h := report.Hostname; h != nil && !h.Valid {
+		fmt.Fprintf(&b, "- hostname: %s\n", h.Error)
+	}
+	fmt.Fprintf(&b, "\nReproduce with `certvet validate %s`.\n", report.Endpoint)
+
+	return Issue{
+		Key:   KeyFor(report.Endpoint),
+		Title: "certvet: trust failures for " + report.Endpoint,
+		Body:  b.String(),
+	}
+}
+
+// Sync files new issues, updates open issues whose failures changed and closes open issues
+// whose endpoint now passes. It stops at the first tracker error.
+func Sync(t Tracker, failing []Issue, passing []string) ([]Action, error) {
+	open, err := t.Open()
+	if err != nil {
+		return nil, fmt.Errorf("list open issues: %w", err)
+	}
+
+	var actions []Action
+	for _, issue := range failing {
+		ticket, ok := open[issue.Key]
+		switch {
+		case !ok:
+			created, err := t.Create(issue)
+			if err != nil {
+				return actions, fmt.Errorf("create issue for %s: %w", issue.Title, err)
+			}
+			actions = append(actions, Action{Kind: "created", Key: issue.Key, URL: created.URL})
+		case strings.TrimSpace(ticket.Body) != strings.TrimSpace(issue.Body):
+			if err := t.Update(ticket, issue); err != nil {
+				return actions, fmt.Errorf("update issue %s: %w", ticket.URL, err)
+			}
+			actions = append(actions, Action{Kind: "updated", Key: issue.Key, URL: ticket.URL})
+		}
+	}
+
+	for _, key := range passing {
+		ticket, ok := open[key]
+		if !ok {
+			continue
+		}
+		comment := "certvet validation passed on " + time.Now().UTC().Format(truststore.DateFormat) + "."
+		if err := t.Close(ticket, comment); err != nil {
+			return actions, fmt.Errorf("close issue %s: %w", ticket.URL, err)
+		}
+		actions = append(actions, Action{Kind: "closed", Key: key, URL: ticket.URL})
+	}
+	return actions, nil
+}
+
+// NewTracker creates a tracker from a spec: "github:owner/repo" (token in GITHUB_TOKEN)
+// or "jira:PROJECT" (site in JIRA_URL; JIRA_USER and JIRA_TOKEN, or JIRA_TOKEN alone as a
+// personal access token).
+func NewTracker(spec string, timeout time.Duration) (Tracker, error) {
+	kind, target, ok := strings.Cut(spec, ":")
+	if !ok || target == "" {
+		return nil, fmt.Errorf("invalid issue tracker %q: expected github:owner/repo or jira:PROJECT", spec)
+	}
+
+	switch kind {
+	case "github":
+		owner, repo, ok := strings.Cut(target, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid GitHub repository %q: expected owner/repo", target)
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITHUB_TOKEN is required for %s", spec)
+		}
+		apiURL := os.Getenv("GITHUB_API_URL")
+		if apiURL == "" {
+			apiURL = DefaultGitHubAPIURL
+		}
+		return NewGitHub(apiURL, owner+"/"+repo, token, timeout), nil
+	case "jira":
+		site, token := os.Getenv("JIRA_URL"), os.Getenv("JIRA_TOKEN")
+		if site == "" || token == "" {
+			return nil, fmt.Errorf("JIRA_URL and JIRA_TOKEN are required for %s", spec)
+		}
+		return NewJira(site, target, os.Getenv("JIRA_USER"), token, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported issue tracker %q: expected github or jira", kind)
+	}
+}
@@ -0,0 +1,126 @@
+package issues
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// fakeTracker records calls against an in-memory set of open tickets.
+type fakeTracker struct {
+	open    map[string]Ticket
+	calls   []string
+	failing string // Method that returns an error
+}
+
+func (f *fakeTracker) Open() (map[string]Ticket, error) { return f.open, nil }
+
+func (f *fakeTracker) Create(issue Issue) (Ticket, error) {
+	f.calls = append(f.calls, "create "+issue.Key)
+	if f.failing == "create" {
+		return Ticket{}, errors.New("forbidden")
+	}
+	return Ticket{ID: "1", URL: "https://tracker/" + issue.Key}, nil
+}
+
+func (f *fakeTracker) Update(ticket Ticket, issue Issue) error {
+	f.calls = append(f.calls, "update "+ticket.ID)
+	return nil
+}
+
+func (f *fakeTracker) Close(ticket Ticket, comment string) error {
+	f.calls = append(f.calls, "close "+ticket.ID)
+	return nil
+}
+
+func TestFromReports(t *testing.T) {
+	t.Parallel()
+
+	reports := []*truststore.ValidationReport{
+		{
+			Endpoint: "bad.example.com",
+			Results: []truststore.TrustResult{
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "Root"},
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"}, FailureReason: "certificate signed by unknown authority"},
+			},
+			Hostname: &truststore.HostnameCheck{Host: "bad.example.com", Error: "certificate is not valid for bad.example.com"},
+		},
+		{Endpoint: "good.example.com", AllPassed: true},
+		{Endpoint: "down.example.com", Error: "connection refused"},
+	}
+
+	failing, passing := FromReports(reports)
+	if len(failing) != 1 || len(passing) != 1 {
+		t.Fatalf("got %d failing and %d passing, want 1 and 1", len(failing), len(passing))
+	}
+	issue := failing[0]
+	if issue.Key != KeyFor("bad.example.com") || passing[0] != KeyFor("good.example.com") {
+		t.Errorf("keys = %s, %s, want keys derived from endpoints", issue.Key, passing[0])
+	}
+	for _, want := range []string{"`bad.example.com`", "- android: missing Root", "- hostname: certificate is not valid"} {
+		if !strings.Contains(issue.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, issue.Body)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	body := "android: missing Root\n"
+	tracker := &fakeTracker{open: map[string]Ticket{
+		"same":    {ID: "10", Body: body},
+		"changed": {ID: "11", Body: "old failures\n"},
+		"fixed":   {ID: "12"},
+	}}
+	failing := []Issue{
+		{Key: "same", Body: body},
+		{Key: "changed", Body: body},
+		{Key: "new", Body: body},
+	}
+
+	actions, err := Sync(tracker, failing, []string{"fixed", "never-filed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"update 11", "create new", "close 12"}
+	if strings.Join(tracker.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", tracker.calls, want)
+	}
+	if len(actions) != 3 || actions[1].Kind != "created" || actions[1].URL != "https://tracker/new" {
+		t.Errorf("actions = %+v", actions)
+	}
+
+	tracker = &fakeTracker{open: map[string]Ticket{}, failing: "create"}
+	if _, err := Sync(tracker, failing, nil); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("Sync() error = %v, want create failure", err)
+	}
+}
+
+func TestNewTracker(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("JIRA_URL", "https://jira.example.com")
+	t.Setenv("JIRA_TOKEN", "secret")
+
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"github", "expected github:owner/repo"},
+		{"github:owner", "expected owner/repo"},
+		{"github:owner/repo", "GITHUB_TOKEN is required"},
+		{"gitlab:owner/repo", "unsupported issue tracker"},
+		{"jira:OPS", ""},
+	}
+	for _, tt := range tests {
+		_, err := NewTracker(tt.spec, 0)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("NewTracker(%q) error = %v", tt.spec, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("NewTracker(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+		}
+	}
+}
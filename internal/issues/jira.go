@@ -0,0 +1,151 @@
+package issues
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// jiraIssueType is the type of issues filed in Jira (available in all default schemes).
+const jiraIssueType = "Task"
+
+// jiraKeyLabel prefixes the label holding an issue's deduplication key.
+const jiraKeyLabel = Label + "-"
+
+// Inline code is `code` in issue bodies and {{code}} in Jira wiki markup.
+var (
+	markdownCode = regexp.MustCompile("`([^`]*)`")
+	jiraCode     = regexp.MustCompile(`\{\{([^}]*)\}\}`)
+)
+
+// Jira files issues in a Jira project.
+type Jira struct {
+	api     apiClient
+	site    string
+	project string
+}
+
+// NewJira creates a tracker for project at site. With user set, token is an API token for basic
+// authentication (Jira Cloud); otherwise it is sent as a bearer personal access token (Data Center).
+func NewJira(site, project, user, token string, timeout time.Duration) *Jira {
+	header := http.Header{}
+	if user != "" {
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+token)))
+	} else {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	header.Set("Accept", "application/json")
+	return &Jira{
+		api:     apiClient{client: &http.Client{Timeout: timeout}, header: header},
+		site:    strings.TrimSuffix(site, "/"),
+		project: project,
+	}
+}
+
+type jiraSearch struct {
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Labels      []string `json:"labels"`
+			Description string   `json:"description"`
+		} `json:"fields"`
+	} `json:"issues"`
+	Total int `json:"total"`
+}
+
+// Open searches the project for unresolved issues labeled certvet and indexes them by key label.
+func (j *Jira) Open() (map[string]Ticket, error) {
+	jql := fmt.Sprintf("project = %q AND labels = %s AND statusCategory != Done", j.project, Label)
+	open := make(map[string]Ticket)
+	for start := 0; ; {
+		query := url.Values{
+			"jql":        {jql},
+			"fields":     {"labels,description"},
+			"maxResults": {"100"},
+			"startAt":    {fmt.Sprint(start)},
+		}
+		var result jiraSearch
+		if err := j.api.do(http.MethodGet, j.site+"/rest/api/2/search?"+query.Encode(), nil, &result); err != nil {
+			return nil, err
+		}
+		for _, issue := range result.Issues {
+			for _, label := range issue.Fields.Labels {
+				if key, ok := strings.CutPrefix(label, jiraKeyLabel); ok {
+					open[key] = Ticket{
+						ID:   issue.Key,
+						URL:  j.site + "/browse/" + issue.Key,
+						Body: jiraCode.ReplaceAllString(issue.Fields.Description, "`$1`"),
+					}
+				}
+			}
+		}
+		start += len(result.Issues)
+		if len(result.Issues) == 0 || start >= result.Total {
+			return open, nil
+		}
+	}
+}
+
+// Create files a task labeled with certvet and its key.
+func (j *Jira) Create(issue Issue) (Ticket, error) {
+	req := map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": jiraIssueType},
+		"summary":     issue.Title,
+		"description": jiraText(issue.Body),
+		"labels":      []string{Label, jiraKeyLabel + issue.Key},
+	}}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.api.do(http.MethodPost, j.site+"/rest/api/2/issue", req, &created); err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{ID: created.Key, URL: j.site + "/browse/" + created.Key, Body: issue.Body}, nil
+}
+
+// Update replaces the summary and description of an issue.
+func (j *Jira) Update(ticket Ticket, issue Issue) error {
+	req := map[string]any{"fields": map[string]any{
+		"summary":     issue.Title,
+		"description": jiraText(issue.Body),
+	}}
+	return j.api.do(http.MethodPut, j.site+"/rest/api/2/issue/"+ticket.ID, req, nil)
+}
+
+// Close comments on an issue and moves it to the first status in the Done category.
+func (j *Jira) Close(ticket Ticket, comment string) error {
+	issueURL := j.site + "/rest/api/2/issue/" + ticket.ID
+	if err := j.api.do(http.MethodPost, issueURL+"/comment", map[string]any{"body": comment}, nil); err != nil {
+		return err
+	}
+
+	var result struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.api.do(http.MethodGet, issueURL+"/transitions", nil, &result); err != nil {
+		return err
+	}
+	for _, t := range result.Transitions {
+		if t.To.StatusCategory.Key == "done" {
+			return j.api.do(http.MethodPost, issueURL+"/transitions", map[string]any{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("no transition to a done status for %s", ticket.ID)
+}
+
+// jiraText converts inline code in an issue body to Jira wiki markup.
+func jiraText(body string) string {
+	return markdownCode.ReplaceAllString(body, "{{$1}}")
+}
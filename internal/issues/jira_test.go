@@ -0,0 +1,77 @@
+package issues
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJira(t *testing.T) {
+	t.Parallel()
+
+	var transitioned string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "ops@example.com" || token != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/search":
+			if jql := r.URL.Query().Get("jql"); !strings.Contains(jql, `project = "OPS"`) {
+				t.Errorf("unexpected JQL %q", jql)
+			}
+			_, _ = w.Write([]byte(`{"total": 2, "issues": [
+				{"key": "OPS-1", "fields": {"labels": ["certvet", "certvet-abc123"], "description": "failures for {{a.example.com}}\n"}},
+				{"key": "OPS-2", "fields": {"labels": ["certvet"], "description": "no key"}}
+			]}`))
+		case "POST /rest/api/2/issue":
+			fields := body["fields"].(map[string]any)
+			if fields["description"] != "failures for {{b.example.com}}\n" {
+				t.Errorf("description not converted to wiki markup: %q", fields["description"])
+			}
+			_, _ = w.Write([]byte(`{"key": "OPS-3"}`))
+		case "POST /rest/api/2/issue/OPS-1/comment":
+			w.WriteHeader(http.StatusCreated)
+		case "GET /rest/api/2/issue/OPS-1/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [
+				{"id": "11", "to": {"statusCategory": {"key": "indeterminate"}}},
+				{"id": "31", "to": {"statusCategory": {"key": "done"}}}
+			]}`))
+		case "POST /rest/api/2/issue/OPS-1/transitions":
+			transitioned = body["transition"].(map[string]any)["id"].(string)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	jira := NewJira(srv.URL, "OPS", "ops@example.com", "token", time.Second)
+
+	open, err := jira.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket, ok := open["abc123"]
+	if len(open) != 1 || !ok || ticket.Body != "failures for `a.example.com`\n" || ticket.URL != srv.URL+"/browse/OPS-1" {
+		t.Errorf("Open() = %+v, want OPS-1 with markdown body", open)
+	}
+
+	created, err := jira.Create(Issue{Key: "def456", Title: "certvet: trust failures for b.example.com", Body: "failures for `b.example.com`\n"})
+	if err != nil || created.ID != "OPS-3" {
+		t.Errorf("Create() = %+v, %v", created, err)
+	}
+
+	if err := jira.Close(ticket, "passed"); err != nil {
+		t.Fatal(err)
+	}
+	if transitioned != "31" {
+		t.Errorf("transitioned with %q, want the done transition 31", transitioned)
+	}
+}
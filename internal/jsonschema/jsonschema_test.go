@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFingerprint [2]byte
+
+func (f testFingerprint) MarshalText() ([]byte, error) { return []byte("AA:BB"), nil }
+
+type testEmbedded struct {
+	Note string `json:"note,omitempty"`
+}
+
+type testReport struct {
+	testEmbedded
+	Name     string            `json:"name"`
+	Count    int               `json:"count,omitempty"`
+	Ratio    float64           `json:"ratio"`
+	Passed   bool              `json:"passed"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]int    `json:"labels"`
+	Child    *testReport       `json:"-"`
+	At       time.Time         `json:"at"`
+	FP       testFingerprint   `json:"fp"`
+	Nested   *struct{ X bool } `json:"nested,omitempty"`
+	Untagged string
+	hidden   string
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	s := For(reflect.TypeFor[testReport]())
+
+	tests := []struct {
+		property string
+		want     string // JSON encoding of the property schema
+	}{
+		{"note", `{"type":"string"}`},
+		{"name", `{"type":"string"}`},
+		{"count", `{"type":"integer"}`},
+		{"ratio", `{"type":"number"}`},
+		{"passed", `{"type":"boolean"}`},
+		{"tags", `{"type":"array","items":{"type":"string"}}`},
+		{"labels", `{"type":"object","additionalProperties":{"type":"integer"}}`},
+		{"at", `{"type":"string","format":"date-time"}`},
+		{"fp", `{"type":"string"}`},
+		{"nested", `{"type":"object","properties":{"X":{"type":"boolean"}},"required":["X"]}`},
+		{"Untagged", `{"type":"string"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(s.Properties[tt.property])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("properties[%q] = %s, want %s", tt.property, data, tt.want)
+		}
+	}
+	if len(s.Properties) != len(tests) {
+		t.Errorf("got %d properties, want %d (Child and hidden excluded)", len(s.Properties), len(tests))
+	}
+	if got, want := strings.Join(s.Required, ","), "name,ratio,passed,labels,at,fp,Untagged"; got != want {
+		t.Errorf("required = %s, want %s", got, want)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	one := Document("One", reflect.TypeFor[[]string]())
+	if one.Schema != Draft || one.Title != "One" || one.Type != "array" {
+		t.Errorf("Document(one type) = %+v", one)
+	}
+
+	either := Document("Either", reflect.TypeFor[string](), reflect.TypeFor[int]())
+	if either.Type != "" || len(either.OneOf) != 2 || either.OneOf[1].Type != "integer" {
+		t.Errorf("Document(two types) = %+v", either)
+	}
+}
@@ -0,0 +1,299 @@
+// Package lint checks presented certificate chains against deployment best practices:
+// chain order and completeness, redundant certificates, size, and CA/Browser Forum and
+// root program policies that some clients enforce.
+package lint
+
+import (
+	"bytes"
+	"crypto/dsa" //nolint:staticcheck // SA1019: Only used to detect deprecated DSA keys
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Severity ranks findings.
+type Severity int
+
+// Severities, in increasing order.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lowercase severity name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid severity %q: expected info, warning or error", name)
+}
+
+// Rule describes a check.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Rule IDs.
+const (
+	RuleChainOrder          = "chain-order"
+	RuleDuplicateCert       = "duplicate-cert"
+	RuleRootIncluded        = "root-included"
+	RuleMissingIntermediate = "missing-intermediate"
+	RuleUnrelatedCert       = "unrelated-cert"
+	RuleChainSize           = "chain-size"
+	RuleExpired             = "expired"
+	RuleNotYetValid         = "not-yet-valid"
+	RuleLeafValidity        = "leaf-validity-period"
+	RuleLeafNoSAN           = "leaf-no-san"
+	RuleLeafIsCA            = "leaf-is-ca"
+	RuleLeafServerAuth      = "leaf-server-auth"
+	RuleIntermediateNotCA   = "intermediate-not-ca"
+	RuleWeakSignature       = "weak-signature"
+	RuleWeakKey             = "weak-key"
+)
+
+// Rules lists all checks.
+var Rules = []Rule{
+	{RuleChainOrder, SeverityError, "Each certificate must be followed by its issuer"},
+	{RuleDuplicateCert, SeverityWarning, "A certificate is sent more than once"},
+	{RuleRootIncluded, SeverityInfo, "A self-signed root is sent; clients use their own copy"},
+	{RuleMissingIntermediate, SeverityError, "The last certificate sent is not issued by a known root"},
+	{RuleUnrelatedCert, SeverityWarning, "A certificate sent is not part of the path from the leaf"},
+	{RuleChainSize, SeverityWarning, "The chain is too long or too large for a fast handshake"},
+	{RuleExpired, SeverityError, "A certificate sent has expired"},
+	{RuleNotYetValid, SeverityError, "A certificate sent is not yet valid"},
+	{RuleLeafValidity, SeverityError, "The leaf is valid for more than 398 days (CA/B Forum BR 6.3.2, from 2020-09-01)"},
+	{RuleLeafNoSAN, SeverityError, "The leaf has no subjectAltName; clients ignore the common name"},
+	{RuleLeafIsCA, SeverityError, "The leaf is a CA certificate"},
+	{RuleLeafServerAuth, SeverityWarning, "The leaf lacks the serverAuth extended key usage Apple requires"},
+	{RuleIntermediateNotCA, SeverityError, "An intermediate lacks CA basic constraints"},
+	{RuleWeakSignature, SeverityError, "A certificate is signed with MD5 or SHA-1"},
+	{RuleWeakKey, SeverityError, "A certificate has an RSA key below 2048 bits, a P-224 key or a DSA key"},
+}
+
+// Size limits for RuleChainSize. Chains over maxChainBytes don't fit the QUIC
+// anti-amplification budget and cost an extra round trip.
+const (
+	maxChainCerts = 4
+	maxChainBytes = 4096
+)
+
+// maxLeafValidity is the longest leaf validity allowed for leaves issued from
+// leafValidityStart (CA/B Forum BR 6.3.2).
+const maxLeafValidity = 398 * 24 * time.Hour
+
+var leafValidityStart = time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// Finding is a rule violation.
+type Finding struct {
+	Rule    Rule
+	Cert    int // Index in the presented chain (leaf is 0), -1 for the chain as a whole
+	Message string
+}
+
+// Options configures Lint.
+type Options struct {
+	Now   time.Time
+	Roots []*x509.Certificate // Known roots, for RuleMissingIntermediate (nil skips the rule)
+}
+
+// rules indexes Rules by ID.
+var rules = func() map[string]Rule {
+	m := make(map[string]Rule, len(Rules))
+	for _, r := range Rules {
+		m[r.ID] = r
+	}
+	return m
+}()
+
+// Lint checks a presented chain and returns findings sorted by severity (highest first),
+// then certificate.
+func Lint(chain *truststore.CertChain, opts Options) []Finding {
+	certs := append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...)
+	var findings []Finding
+	add := func(id string, cert int, format string, args ...any) {
+		findings = append(findings, Finding{Rule: rules[id], Cert: cert, Message: fmt.Sprintf(format, args...)})
+	}
+
+	checkPath(certs, opts.Roots, add)
+	checkSize(certs, add)
+	for i, cert := range certs {
+		checkValidity(i, cert, opts.Now, add)
+		checkCrypto(i, cert, add)
+		if i == 0 {
+			checkLeaf(cert, add)
+		} else if !cert.BasicConstraintsValid || !cert.IsCA {
+			add(RuleIntermediateNotCA, i, "%q is sent as an intermediate but is not a CA", truststore.CertName(cert))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Rule.Severity != findings[j].Rule.Severity {
+			return findings[i].Rule.Severity > findings[j].Rule.Severity
+		}
+		return findings[i].Cert < findings[j].Cert
+	})
+	return findings
+}
+
+// issues reports whether issuer's subject matches cert's issuer and its key signed cert.
+// Weak signatures count, since they are reported separately.
+func issues(issuer, cert *x509.Certificate) bool {
+	if !bytes.Equal(issuer.RawSubject, cert.RawIssuer) {
+		return false
+	}
+	err := issuer.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+	var insecure x509.InsecureAlgorithmError
+	return err == nil || errors.As(err, &insecure)
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer)
+}
+
+// checkPath walks the path from the leaf through the presented certificates, reporting
+// duplicates, misordering, certificates off the path, included roots and missing intermediates.
+func checkPath(certs []*x509.Certificate, roots []*x509.Certificate, add func(string, int, string, ...any)) {
+	seen := make(map[truststore.Fingerprint]int)
+	for i, cert := range certs {
+		fp := truststore.FingerprintFromCert(cert)
+		if first, ok := seen[fp]; ok {
+			add(RuleDuplicateCert, i, "%q is a duplicate of certificate #%d", truststore.CertName(cert), first)
+			continue
+		}
+		seen[fp] = i
+	}
+
+	// Follow issuers from the leaf; each should be the next certificate sent
+	onPath := map[int]bool{0: true}
+	path := []int{0}
+	current := 0
+	for {
+		cert := certs[current]
+		if isSelfSigned(cert) {
+			if current > 0 {
+				add(RuleRootIncluded, current, "root %q is sent", truststore.CertName(cert))
+			}
+			break
+		}
+		next := slices.IndexFunc(certs, func(c *x509.Certificate) bool { return issues(c, cert) })
+		if next < 0 || onPath[next] {
+			if roots != nil && !slices.ContainsFunc(roots, func(r *x509.Certificate) bool { return issues(r, cert) }) {
+				add(RuleMissingIntermediate, current, "issuer %q of %q is neither sent nor a known root",
+					cert.Issuer.CommonName, truststore.CertName(cert))
+			}
+			break
+		}
+		onPath[next] = true
+		path = append(path, next)
+		current = next
+	}
+	for i, idx := range path {
+		if idx != i {
+			order := make([]string, len(path))
+			for j, idx := range path {
+				order[j] = fmt.Sprintf("#%d", idx)
+			}
+			add(RuleChainOrder, -1, "certificates are sent out of order; send them as %s", strings.Join(order, ", "))
+			break
+		}
+	}
+
+	for i, cert := range certs {
+		if !onPath[i] && seen[truststore.FingerprintFromCert(cert)] == i {
+			add(RuleUnrelatedCert, i, "%q is not on the path from the leaf", truststore.CertName(cert))
+		}
+	}
+}
+
+// checkSize reports chains with too many certificates or bytes.
+func checkSize(certs []*x509.Certificate, add func(string, int, string, ...any)) {
+	size := 0
+	for _, cert := range certs {
+		size += len(cert.Raw)
+	}
+	if len(certs) > maxChainCerts {
+		add(RuleChainSize, -1, "%d certificates sent, expected at most %d", len(certs), maxChainCerts)
+	}
+	if size > maxChainBytes {
+		add(RuleChainSize, -1, "%d bytes of certificates sent, expected at most %d", size, maxChainBytes)
+	}
+}
+
+// checkValidity reports certificates outside their validity period at now.
+func checkValidity(i int, cert *x509.Certificate, now time.Time, add func(string, int, string, ...any)) {
+	switch {
+	case now.After(cert.NotAfter):
+		add(RuleExpired, i, "%q expired on %s", truststore.CertName(cert), cert.NotAfter.UTC().Format(truststore.DateFormat))
+	case now.Before(cert.NotBefore):
+		add(RuleNotYetValid, i, "%q is valid from %s", truststore.CertName(cert), cert.NotBefore.UTC().Format(truststore.DateFormat))
+	}
+}
+
+// checkCrypto reports weak signatures (except on self-signed roots, whose signatures
+// clients don't check) and weak keys.
+func checkCrypto(i int, cert *x509.Certificate, add func(string, int, string, ...any)) {
+	name := truststore.CertName(cert)
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		if !isSelfSigned(cert) {
+			add(RuleWeakSignature, i, "%q is signed with %s", name, cert.SignatureAlgorithm)
+		}
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if bits := key.N.BitLen(); bits < 2048 {
+			add(RuleWeakKey, i, "%q has an RSA-%d key", name, bits)
+		}
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P224() {
+			add(RuleWeakKey, i, "%q has a P-224 key", name)
+		}
+	case *dsa.PublicKey:
+		add(RuleWeakKey, i, "%q has a DSA key", name)
+	}
+}
+
+// checkLeaf reports leaf policy violations.
+func checkLeaf(cert *x509.Certificate, add func(string, int, string, ...any)) {
+	name := truststore.CertName(cert)
+	if validity := cert.NotAfter.Sub(cert.NotBefore); validity > maxLeafValidity && !cert.NotBefore.Before(leafValidityStart) {
+		add(RuleLeafValidity, 0, "%q is valid for %d days", name, int(validity.Hours()/24))
+	}
+	if len(cert.DNSNames) == 0 && len(cert.IPAddresses) == 0 {
+		add(RuleLeafNoSAN, 0, "%q has no DNS or IP subjectAltName", name)
+	}
+	if cert.BasicConstraintsValid && cert.IsCA {
+		add(RuleLeafIsCA, 0, "%q has CA basic constraints", name)
+	}
+	if !slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		add(RuleLeafServerAuth, 0, "%q lacks serverAuth extended key usage", name)
+	}
+}
@@ -0,0 +1,186 @@
+package lint
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+var now = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// testCA is a CA certificate with its key.
+type testCA struct {
+	cert *x509.Certificate
+	key  any
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// issue signs template with issuer (self-signed if nil) and returns the parsed certificate.
+func issue(t *testing.T, template *x509.Certificate, pub, key any, issuer *testCA) *x509.Certificate {
+	t.Helper()
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if template.NotBefore.IsZero() {
+		template.NotBefore = now.Add(-24 * time.Hour)
+		template.NotAfter = now.Add(90 * 24 * time.Hour)
+	}
+	parent, signer := template, key
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func newCA(t *testing.T, name string, issuer *testCA) *testCA {
+	t.Helper()
+	key := newKey(t)
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	return &testCA{cert: issue(t, template, &key.PublicKey, key, issuer), key: key}
+}
+
+func leafTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "example.com"},
+		DNSNames:    []string{"example.com"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	root := newCA(t, "Root", nil)
+	intermediate := newCA(t, "Intermediate", root)
+	subIntermediate := newCA(t, "Sub Intermediate", intermediate)
+	otherRoot := newCA(t, "Other Root", nil)
+	roots := []*x509.Certificate{root.cert}
+
+	leafKey := newKey(t)
+	leafFrom := func(template *x509.Certificate, issuer *testCA) *x509.Certificate {
+		return issue(t, template, &leafKey.PublicKey, leafKey, issuer)
+	}
+	leaf := leafFrom(leafTemplate(), intermediate)
+	deepLeaf := leafFrom(leafTemplate(), subIntermediate)
+
+	expired := leafTemplate()
+	expired.NotBefore, expired.NotAfter = now.Add(-100*24*time.Hour), now.Add(-time.Hour)
+	longLived := leafTemplate()
+	longLived.NotBefore, longLived.NotAfter = now.Add(-time.Hour), now.Add(400*24*time.Hour)
+	noSAN := leafTemplate()
+	noSAN.DNSNames = nil
+	noEKU := leafTemplate()
+	noEKU.ExtKeyUsage = nil
+	isCA := leafTemplate()
+	isCA.BasicConstraintsValid, isCA.IsCA = true, true
+
+	notCAKey := newKey(t)
+	notCA := &testCA{key: notCAKey, cert: issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Not A CA"}},
+		&notCAKey.PublicKey, notCAKey, root)}
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		certs        []*x509.Certificate
+		want         []string // Rule IDs in reported order
+		wantCerts    []int
+		withoutRoots bool
+	}{
+		{name: "valid", certs: []*x509.Certificate{leaf, intermediate.cert}},
+		{name: "misordered", certs: []*x509.Certificate{deepLeaf, intermediate.cert, subIntermediate.cert},
+			want: []string{RuleChainOrder}, wantCerts: []int{-1}},
+		{name: "root included", certs: []*x509.Certificate{leaf, intermediate.cert, root.cert},
+			want: []string{RuleRootIncluded}, wantCerts: []int{2}},
+		{name: "duplicate", certs: []*x509.Certificate{leaf, intermediate.cert, intermediate.cert},
+			want: []string{RuleDuplicateCert}, wantCerts: []int{2}},
+		{name: "missing intermediate", certs: []*x509.Certificate{deepLeaf, intermediate.cert},
+			want: []string{RuleMissingIntermediate, RuleUnrelatedCert}, wantCerts: []int{0, 1}},
+		{name: "missing intermediate without known roots", certs: []*x509.Certificate{deepLeaf, intermediate.cert},
+			withoutRoots: true, want: []string{RuleUnrelatedCert}, wantCerts: []int{1}},
+		{name: "unrelated certificate", certs: []*x509.Certificate{leaf, intermediate.cert, otherRoot.cert},
+			want: []string{RuleUnrelatedCert}, wantCerts: []int{2}},
+		{name: "expired", certs: []*x509.Certificate{leafFrom(expired, intermediate), intermediate.cert},
+			want: []string{RuleExpired}, wantCerts: []int{0}},
+		{name: "long-lived leaf", certs: []*x509.Certificate{leafFrom(longLived, intermediate), intermediate.cert},
+			want: []string{RuleLeafValidity}, wantCerts: []int{0}},
+		{name: "leaf without SAN", certs: []*x509.Certificate{leafFrom(noSAN, intermediate), intermediate.cert},
+			want: []string{RuleLeafNoSAN}, wantCerts: []int{0}},
+		{name: "leaf without serverAuth", certs: []*x509.Certificate{leafFrom(noEKU, intermediate), intermediate.cert},
+			want: []string{RuleLeafServerAuth}, wantCerts: []int{0}},
+		{name: "leaf is CA", certs: []*x509.Certificate{leafFrom(isCA, intermediate), intermediate.cert},
+			want: []string{RuleLeafIsCA}, wantCerts: []int{0}},
+		{name: "intermediate not CA", certs: []*x509.Certificate{leafFrom(leafTemplate(), notCA), notCA.cert},
+			want: []string{RuleIntermediateNotCA}, wantCerts: []int{1}},
+		{name: "weak key", certs: []*x509.Certificate{issue(t, leafTemplate(), &weakKey.PublicKey, weakKey, intermediate), intermediate.cert},
+			want: []string{RuleWeakKey}, wantCerts: []int{0}},
+		{name: "too many certificates", certs: []*x509.Certificate{leaf, intermediate.cert, intermediate.cert, intermediate.cert, intermediate.cert},
+			want: []string{RuleChainSize, RuleDuplicateCert, RuleDuplicateCert, RuleDuplicateCert}, wantCerts: []int{-1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chain := &truststore.CertChain{ServerCert: tt.certs[0], Intermediates: tt.certs[1:]}
+			opts := Options{Now: now, Roots: roots}
+			if tt.withoutRoots {
+				opts.Roots = nil
+			}
+
+			var got []string
+			var gotCerts []int
+			for _, f := range Lint(chain, opts) {
+				got = append(got, f.Rule.ID)
+				gotCerts = append(gotCerts, f.Cert)
+			}
+			if !slices.Equal(got, tt.want) || !slices.Equal(gotCerts, tt.wantCerts) {
+				t.Errorf("Lint() = %v at %v, want %v at %v", got, gotCerts, tt.want, tt.wantCerts)
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		got, err := ParseSeverity(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal): expected error")
+	}
+}
@@ -0,0 +1,238 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// BulkValidationOutput implements Formatter for validation reports of multiple endpoints.
+type BulkValidationOutput struct {
+	Reports   []*truststore.ValidationReport
+	Redaction Redaction // Applied when formatting; Reports themselves are not modified
+	Summary   bool      // Group failures and count anchoring roots instead of listing rows
+	ShowChain bool      // Append each result's path and the root constraints applied
+}
+
+// NewBulkValidationOutput creates a new BulkValidationOutput formatter.
+// Endpoint order is preserved; results within each endpoint are sorted as in NewValidationOutput.
+func NewBulkValidationOutput(reports []*truststore.ValidationReport) *BulkValidationOutput {
+	for _, r := range reports {
+		sortResults(r.Results)
+	}
+	return &BulkValidationOutput{Reports: reports}
+}
+
+// AllPassed reports whether every validated endpoint passed. Endpoints with errors are ignored.
+func (b *BulkValidationOutput) AllPassed() bool {
+	for _, r := range b.Reports {
+		if r.Error == "" && !r.AllPassed {
+			return false
+		}
+	}
+	return true
+}
+
+// HasErrors reports whether any endpoint could not be validated.
+func (b *BulkValidationOutput) HasErrors() bool {
+	for _, r := range b.Reports {
+		if r.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatText formats all reports as a single table with an ENDPOINT column,
+// or as grouped failures if Summary is set.
+func (b *BulkValidationOutput) FormatText() string {
+	if b.Summary {
+		return b.formatSummaryText()
+	}
+
+	hostnames, until := false, false
+	for _, report := range b.Reports {
+		if report.Hostname != nil {
+			hostnames = true
+		}
+		if hasTrustedUntil(report.Results) {
+			until = true
+		}
+	}
+
+	header := []string{"ENDPOINT", "PLATFORM", "VERSION", "VALIDATION"}
+	if hostnames {
+		header = append(header, "HOSTNAME")
+	}
+	if until {
+		header = append(header, "TRUSTED UNTIL")
+	}
+	tw := NewTableWriter()
+	tw.Header(append(header, "STATUS")...)
+
+	var advisories []truststore.AdvisoryMatch
+	var rollups []string
+	seen := make(map[string]bool)
+
+	for _, report := range b.Reports {
+		report = b.Redaction.apply(report)
+		if report.Error != "" {
+			row := []string{report.Endpoint, "-", "-", "ERROR"}
+			for range len(header) - len(row) {
+				row = append(row, "-")
+			}
+			tw.Row(append(row, report.Error)...)
+			continue
+		}
+		if len(report.Results) > 0 {
+			rollups = append(rollups, report.Endpoint+": "+formatRollup(Rollup(report.Results)))
+		}
+		for _, r := range report.Results {
+			validation, status := resultColumns(r)
+			row := []string{report.Endpoint, string(r.Platform.Platform), r.Platform.Label(), validation}
+			if hostnames {
+				hostname := "-"
+				if h := report.Hostname; h != nil {
+					hostname = hostnameColumn(h)
+					if !h.Valid {
+						status = h.Error + "; " + status
+					}
+				}
+				row = append(row, hostname)
+			}
+			if until {
+				row = append(row, trustedUntilColumn(r))
+			}
+			tw.Row(append(row, status)...)
+		}
+		for _, a := range report.Advisories {
+			key := a.ID + "/" + a.Fingerprint.String()
+			if !seen[key] {
+				seen[key] = true
+				advisories = append(advisories, a)
+			}
+		}
+	}
+
+	table := tw.String()
+	if len(rollups) > 0 {
+		table += "\n\n" + strings.Join(rollups, "\n")
+	}
+
+	out := formatEvaluatedAt(b.evaluatedAt()) + table + formatAdvisoryTable(advisories)
+	if b.ShowChain {
+		var details strings.Builder
+		for _, report := range b.redactedReports() {
+			if report.Error == "" {
+				details.WriteString(formatChainDetails(report, b.Redaction, report.Endpoint))
+			}
+		}
+		out = strings.TrimSuffix(out, "\n") + "\n" + strings.TrimSuffix(details.String(), "\n")
+	}
+	return out
+}
+
+// evaluatedAt returns the simulated validation time shared by all reports (zero if none).
+func (b *BulkValidationOutput) evaluatedAt() time.Time {
+	for _, r := range b.Reports {
+		if !r.EvaluatedAt.IsZero() {
+			return r.EvaluatedAt
+		}
+	}
+	return time.Time{}
+}
+
+// formatSummaryText renders endpoint counts, failure groups and advisories.
+func (b *BulkValidationOutput) formatSummaryText() string {
+	reports := b.redactedReports()
+	var advisories []truststore.AdvisoryMatch
+	seen := make(map[string]bool)
+	for _, report := range reports {
+		for _, a := range report.Advisories {
+			key := a.ID + "/" + a.Fingerprint.String()
+			if !seen[key] {
+				seen[key] = true
+				advisories = append(advisories, a)
+			}
+		}
+	}
+	return formatEvaluatedAt(b.evaluatedAt()) + formatSummaryTable(reports, SummarizeFailures(reports)) +
+		formatRootTable(SummarizeRoots(reports), b.Redaction) + formatAdvisoryTable(advisories)
+}
+
+// redactedReports returns the reports with Redaction applied.
+func (b *BulkValidationOutput) redactedReports() []*truststore.ValidationReport {
+	reports := make([]*truststore.ValidationReport, len(b.Reports))
+	for i, r := range b.Reports {
+		reports[i] = b.Redaction.apply(r)
+	}
+	return reports
+}
+
+// FormatJSON formats all reports as JSON. Summary adds grouped failures and root usage.
+func (b *BulkValidationOutput) FormatJSON() ([]byte, error) {
+	reports := b.redactedReports()
+	jb := jsonBulkReport{
+		Endpoints: make([]jsonReport, len(reports)),
+		AllPassed: b.AllPassed() && !b.HasErrors(),
+	}
+	for i, r := range reports {
+		jb.Endpoints[i] = newJSONReport(r, b.Redaction)
+		if b.ShowChain {
+			addJSONChains(&jb.Endpoints[i], r, b.Redaction)
+		}
+	}
+	if b.Summary {
+		for _, g := range SummarizeFailures(reports) {
+			jb.Summary = append(jb.Summary, jsonFailureGroup{
+				Platforms: g.Platforms,
+				Reason:    g.Reason,
+				Count:     len(g.Endpoints),
+				Endpoints: g.Endpoints,
+			})
+		}
+		for _, u := range SummarizeRoots(reports) {
+			jb.Roots = append(jb.Roots, jsonRootUsage{
+				Name:        b.Redaction.name(u.Name),
+				Fingerprint: b.Redaction.fingerprint(u.Fingerprint),
+				Endpoints:   u.Endpoints,
+				Platforms:   u.Platforms,
+			})
+		}
+	}
+	return json.MarshalIndent(jb, "", "  ")
+}
+
+// FormatCSV formats all reports as CSV in the same columns as ValidationOutput.
+// Summary is ignored: CSV always lists one row per endpoint and platform version.
+func (b *BulkValidationOutput) FormatCSV() ([]byte, error) {
+	var rows [][]string
+	for _, r := range b.redactedReports() {
+		rows = append(rows, csvResultRows(r, b.Redaction)...)
+	}
+	return writeCSV(csvResultHeader, rows)
+}
+
+// jsonBulkReport is the JSON output structure for multiple endpoints.
+type jsonBulkReport struct {
+	Endpoints []jsonReport       `json:"endpoints"`
+	AllPassed bool               `json:"all_passed"`
+	Summary   []jsonFailureGroup `json:"summary,omitempty"`
+	Roots     []jsonRootUsage    `json:"roots,omitempty"`
+}
+
+type jsonRootUsage struct {
+	Name        string                `json:"name"`
+	Fingerprint string                `json:"fingerprint_sha256"`
+	Endpoints   int                   `json:"endpoints"`
+	Platforms   []truststore.Platform `json:"platforms"`
+}
+
+type jsonFailureGroup struct {
+	Platforms string   `json:"platforms"`
+	Reason    string   `json:"reason"`
+	Count     int      `json:"count"`
+	Endpoints []string `json:"endpoints"`
+}
@@ -0,0 +1,133 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func testBulkReports() []*truststore.ValidationReport {
+	return []*truststore.ValidationReport{
+		{
+			Endpoint:  "a.example.com",
+			AllPassed: true,
+			Results: []truststore.TrustResult{
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "Root A"},
+			},
+		},
+		{
+			Endpoint:  "b.example.com",
+			AllPassed: false,
+			Results: []truststore.TrustResult{
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, FailureReason: "no root"},
+			},
+		},
+		{Endpoint: "c.example.com", Error: "connection refused"},
+	}
+}
+
+func TestBulkValidationOutputText(t *testing.T) {
+	out := NewBulkValidationOutput(testBulkReports()).FormatText()
+
+	for _, want := range []string{"ENDPOINT", "a.example.com", "Root A", "b.example.com", "no root", "ERROR", "connection refused"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBulkValidationOutputJSON(t *testing.T) {
+	data, err := NewBulkValidationOutput(testBulkReports()).FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed struct {
+		Endpoints []struct {
+			Endpoint string `json:"endpoint"`
+			Error    string `json:"error"`
+		} `json:"endpoints"`
+		AllPassed bool `json:"all_passed"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed.Endpoints) != 3 {
+		t.Fatalf("len(endpoints) = %d, want 3", len(parsed.Endpoints))
+	}
+	if parsed.Endpoints[2].Error != "connection refused" {
+		t.Errorf("endpoints[2].error = %q", parsed.Endpoints[2].Error)
+	}
+	if parsed.AllPassed {
+		t.Error("all_passed should be false")
+	}
+}
+
+func TestBulkValidationOutputCSV(t *testing.T) {
+	reports := testBulkReports()
+	reports[1].Results[0].FailureReason = `no root, "quoted"`
+	data, err := NewBulkValidationOutput(reports).FormatCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("len(records) = %d, want 4 (header + 3 rows)", len(records))
+	}
+	if got := strings.Join(records[0], ","); got != strings.Join(csvResultHeader, ",") {
+		t.Errorf("header = %s", got)
+	}
+
+	tests := []struct {
+		row, col int
+		want     string
+	}{
+		{1, 0, "a.example.com"},
+		{1, 4, "true"},
+		{1, 5, "Root A"},
+		{2, 4, "false"},
+		{2, 7, `no root, "quoted"`},
+		{3, 0, "c.example.com"},
+		{3, 1, ""},
+		{3, 11, "connection refused"},
+	}
+	for _, tt := range tests {
+		if got := records[tt.row][tt.col]; got != tt.want {
+			t.Errorf("records[%d][%s] = %q, want %q", tt.row, records[0][tt.col], got, tt.want)
+		}
+	}
+}
+
+func TestBulkValidationOutputStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		reports       []*truststore.ValidationReport
+		wantAllPassed bool
+		wantErrors    bool
+	}{
+		{"mixed", testBulkReports(), false, true},
+		{"passed with error", []*truststore.ValidationReport{{AllPassed: true}, {Error: "timeout"}}, true, true},
+		{"all passed", []*truststore.ValidationReport{{AllPassed: true}}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bo := NewBulkValidationOutput(tt.reports)
+			if got := bo.AllPassed(); got != tt.wantAllPassed {
+				t.Errorf("AllPassed() = %v, want %v", got, tt.wantAllPassed)
+			}
+			if got := bo.HasErrors(); got != tt.wantErrors {
+				t.Errorf("HasErrors() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package output
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// icsLineLimit is the maximum line length in octets before folding (RFC 5545 section 3.1).
+const icsLineLimit = 75
+
+// calendarEvent is an all-day calendar entry for a date that affects trust.
+// Occurrences of the same event across endpoints and platforms are merged.
+type calendarEvent struct {
+	key       string // Identity for merging and the event UID
+	Date      time.Time
+	Summary   string
+	Endpoints []string
+	Platforms []string // Affected platform versions (empty for certificate expiry)
+}
+
+// SaveCalendar writes upcoming trust-impacting dates from reports to path as an iCalendar file:
+// expiry of presented certificates and anchoring roots, root constraint dates from stores
+// (distrust, issuance and SCT cutoffs) for each matched root, and advisory milestones.
+// Dates before each report's evaluation time are skipped.
+func SaveCalendar(path string, reports []*truststore.ValidationReport, stores []truststore.Store) error {
+	data := formatICS(calendarEvents(reports, stores), time.Now())
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil { //nolint:gosec // G306: Calendar is meant to be shared
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// calendarEvents collects upcoming events from reports, sorted by date.
+func calendarEvents(reports []*truststore.ValidationReport, stores []truststore.Store) []*calendarEvent {
+	events := make(map[string]*calendarEvent)
+	add := func(report *truststore.ValidationReport, date time.Time, summary string, platform string) {
+		key := date.UTC().Format(truststore.DateFormat) + "|" + summary
+		e, ok := events[key]
+		if !ok {
+			e = &calendarEvent{key: key, Date: date.UTC(), Summary: summary}
+			events[key] = e
+		}
+		if !slices.Contains(e.Endpoints, report.Endpoint) {
+			e.Endpoints = append(e.Endpoints, report.Endpoint)
+		}
+		if platform != "" && !slices.Contains(e.Platforms, platform) {
+			e.Platforms = append(e.Platforms, platform)
+		}
+	}
+
+	for _, report := range reports {
+		chain := report.Chain
+		if chain.ServerCert == nil {
+			continue
+		}
+		now := report.Timestamp
+		if !report.EvaluatedAt.IsZero() {
+			now = report.EvaluatedAt
+		}
+		upcoming := func(t *time.Time) bool { return t != nil && t.After(now) }
+
+		for _, cert := range append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...) {
+			if upcoming(&cert.NotAfter) {
+				add(report, cert.NotAfter, "Certificate expires: "+truststore.CertName(cert), "")
+			}
+		}
+
+		for _, r := range report.Results {
+			if !r.Trusted || len(r.VerifiedChain) == 0 {
+				continue
+			}
+			platform := fmt.Sprintf("%s %s", r.Platform.Platform, r.Platform.Label())
+			root := r.VerifiedChain[len(r.VerifiedChain)-1]
+			if upcoming(&root.NotAfter) {
+				add(report, root.NotAfter, "Root expires: "+r.MatchedCA, platform)
+			}
+
+			c := storeFor(stores, r.Platform).ConstraintFor(r.MatchedFingerprint)
+			if upcoming(c.DistrustDate) {
+				add(report, *c.DistrustDate, "Root distrusted: "+r.MatchedCA, platform)
+			}
+			if upcoming(c.NotBeforeMax) {
+				add(report, *c.NotBeforeMax, "Root stops trusting new certificates: "+r.MatchedCA, platform)
+			}
+			if upcoming(c.SCTNotAfter) {
+				add(report, *c.SCTNotAfter, "Root stops trusting newly logged certificates: "+r.MatchedCA, platform)
+			}
+		}
+
+		for _, a := range report.Advisories {
+			for _, ev := range a.Timeline {
+				if upcoming(&ev.Date) {
+					add(report, ev.Date, fmt.Sprintf("%s: %s", a.Title, ev.Description), "")
+				}
+			}
+		}
+	}
+
+	out := make([]*calendarEvent, 0, len(events))
+	for _, e := range events {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Date.Equal(out[j].Date) {
+			return out[i].Date.Before(out[j].Date)
+		}
+		return out[i].Summary < out[j].Summary
+	})
+	return out
+}
+
+// storeFor returns the store a result was validated against (empty if not found).
+func storeFor(stores []truststore.Store, pv truststore.PlatformVersion) truststore.Store {
+	for _, s := range stores {
+		if s.Platform == pv.Platform && s.Version == pv.Version && s.HasExtraRoots() == pv.ExtraRoots {
+			return s
+		}
+	}
+	return truststore.Store{}
+}
+
+// formatICS renders events as an iCalendar document with all-day events.
+func formatICS(events []*calendarEvent, stamp time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//certvet//certvet//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		uid := sha256.Sum256([]byte(e.key))
+
+		description := "Endpoints: " + strings.Join(e.Endpoints, ", ")
+		if len(e.Platforms) > 0 {
+			description += "\nPlatforms: " + strings.Join(e.Platforms, ", ")
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:" + hex.EncodeToString(uid[:16]) + "@certvet")
+		line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICSText(e.Summary))
+		line("DESCRIPTION:" + escapeICSText(description))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICSText escapes a TEXT property value (RFC 5545 section 3.3.11).
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICSLine splits a content line longer than icsLineLimit octets into continuation lines,
+// without breaking UTF-8 sequences.
+func foldICSLine(s string) string {
+	var b strings.Builder
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = icsLineLimit - 1 // Continuation lines start with a space
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCalendarEvents(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	distrust := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	past := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	root := &x509.Certificate{Subject: pkix.Name{CommonName: "Old Root"}, NotAfter: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fp := truststore.Fingerprint{1}
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformWindows, Version: "current", Constraints: map[truststore.Fingerprint]truststore.Constraints{
+			fp: {DistrustDate: &distrust, NotBeforeMax: &past},
+		}},
+		{Platform: truststore.PlatformIOS, Version: "18"},
+	}
+
+	report := func(endpoint string, leafExpiry time.Time) *truststore.ValidationReport {
+		leaf := &x509.Certificate{Subject: pkix.Name{CommonName: endpoint}, NotAfter: leafExpiry}
+		result := func(pv truststore.PlatformVersion) truststore.TrustResult {
+			return truststore.TrustResult{
+				Platform: pv, Trusted: true, MatchedCA: "Old Root", MatchedFingerprint: fp,
+				VerifiedChain: []*x509.Certificate{leaf, root},
+			}
+		}
+		return &truststore.ValidationReport{
+			Endpoint:  endpoint,
+			Timestamp: now,
+			Chain:     truststore.CertChain{ServerCert: leaf},
+			Results: []truststore.TrustResult{
+				result(truststore.PlatformVersion{Platform: truststore.PlatformWindows, Version: "current"}),
+				result(truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}),
+			},
+		}
+	}
+
+	reports := []*truststore.ValidationReport{
+		report("a.example.com", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+		report("b.example.com", past),
+		{Endpoint: "down.example.com", Error: "connection refused"},
+	}
+
+	var got []string
+	for _, e := range calendarEvents(reports, stores) {
+		got = append(got, e.Date.Format(truststore.DateFormat)+" "+e.Summary+" ["+strings.Join(e.Endpoints, ",")+"] ["+strings.Join(e.Platforms, ",")+"]")
+	}
+	want := []string{
+		"2026-03-01 Certificate expires: a.example.com [a.example.com] []",
+		"2026-06-01 Root distrusted: Old Root [a.example.com,b.example.com] [windows current]",
+		"2030-01-01 Root expires: Old Root [a.example.com,b.example.com] [windows current,ios 18]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFormatICS(t *testing.T) {
+	t.Parallel()
+
+	events := []*calendarEvent{{
+		key:       "2026-06-01|Root distrusted: Example, Inc. Root",
+		Date:      time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		Summary:   "Root distrusted: Example, Inc. Root",
+		Endpoints: []string{strings.Repeat("long-subdomain.", 6) + "example.com"},
+	}}
+	out := formatICS(events, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20260101T120000Z\r\n",
+		"DTSTART;VALUE=DATE:20260601\r\n",
+		"DTEND;VALUE=DATE:20260602\r\n",
+		`SUMMARY:Root distrusted: Example\, Inc. Root` + "\r\n",
+		"long-su\r\n bdomain",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line exceeds %d octets: %q", icsLineLimit, line)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package output
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// chainDetail is the path behind one validation outcome, shared by the platform
+// versions that reached it: the verified path to the anchoring root, or for chains
+// that found no root, the served path up to the certificate whose issuer is missing.
+type chainDetail struct {
+	certs       []*x509.Certificate
+	anchored    bool   // certs end at a store root
+	constraints string // Constraints applied to the root (see formatConstraints)
+	reason      string // Failure reason ("" if trusted)
+	results     []truststore.TrustResult
+}
+
+// chainDetails groups a report's results by path, constraints and outcome, in result order.
+func chainDetails(report *truststore.ValidationReport) []*chainDetail {
+	served := servedPath(&report.Chain)
+
+	var out []*chainDetail
+	byKey := make(map[string]*chainDetail)
+	for _, r := range report.Results {
+		d := &chainDetail{certs: r.VerifiedChain, anchored: len(r.VerifiedChain) > 0, reason: r.FailureReason}
+		if d.anchored {
+			d.constraints = formatConstraints(r.Constraints)
+		} else {
+			d.certs = served
+		}
+
+		var key strings.Builder
+		for _, cert := range d.certs {
+			fp := truststore.FingerprintFromCert(cert)
+			key.Write(fp[:])
+		}
+		fmt.Fprintf(&key, "|%t|%s|%s", r.Trusted, d.constraints, d.reason)
+
+		if existing, ok := byKey[key.String()]; ok {
+			d = existing
+		} else {
+			byKey[key.String()] = d
+			out = append(out, d)
+		}
+		d.results = append(d.results, r)
+	}
+	return out
+}
+
+// servedPath orders the served certificates from the leaf up by following issuer
+// names, stopping at the first certificate whose issuer was not served.
+func servedPath(chain *truststore.CertChain) []*x509.Certificate {
+	if chain.ServerCert == nil {
+		return nil
+	}
+	path := []*x509.Certificate{chain.ServerCert}
+	for len(path) <= len(chain.Intermediates) {
+		cur := path[len(path)-1]
+		if bytes.Equal(cur.RawSubject, cur.RawIssuer) {
+			break
+		}
+		var next *x509.Certificate
+		for _, c := range chain.Intermediates {
+			if bytes.Equal(c.RawSubject, cur.RawIssuer) && !c.Equal(cur) {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		path = append(path, next)
+	}
+	return path
+}
+
+// formatChainDetails renders one block per chain detail. With prefix set (bulk output),
+// block headings start with it, e.g. the endpoint.
+func formatChainDetails(report *truststore.ValidationReport, r Redaction, prefix string) string {
+	var b strings.Builder
+	for _, d := range chainDetails(report) {
+		status := "PASS"
+		if d.reason != "" {
+			status = "FAIL " + d.reason
+		}
+		b.WriteString("\nCHAIN ")
+		if prefix != "" {
+			b.WriteString(prefix + " ")
+		}
+		b.WriteString(platformsLabel(d.results, report.Results) + ": " + status + "\n")
+
+		tw := NewTableWriter()
+		for i, cert := range d.certs {
+			note := pathSourceServed
+			if d.anchored {
+				note = pathSource(&report.Chain, d.certs, i)
+				if i == len(d.certs)-1 {
+					note += " " + r.certFingerprint(cert)
+				}
+			} else if i == len(d.certs)-1 {
+				note += fmt.Sprintf(", issuer %q not found", r.name(cert.Issuer.CommonName))
+			}
+			tw.Row("  "+strconv.Itoa(i), truststore.CertName(cert), note)
+		}
+		if out := tw.String(); out != "" {
+			b.WriteString(out + "\n")
+		}
+		if d.anchored {
+			constraints := d.constraints
+			if constraints == "" {
+				constraints = "none"
+			}
+			b.WriteString("  constraints: " + constraints + "\n")
+		}
+	}
+	return b.String()
+}
+
+// addJSONChains adds each result's path and applied constraints to a JSON report
+// built from the same (redacted) report.
+func addJSONChains(jr *jsonReport, report *truststore.ValidationReport, r Redaction) {
+	served := servedPath(&report.Chain)
+	for i, res := range report.Results {
+		certs := res.VerifiedChain
+		if len(certs) == 0 {
+			certs = served
+		}
+		for j, cert := range certs {
+			source := pathSourceServed
+			if len(res.VerifiedChain) > 0 {
+				source = pathSource(&report.Chain, certs, j)
+			}
+			jr.Results[i].Chain = append(jr.Results[i].Chain, jsonPathCert{
+				Subject:     cert.Subject.String(),
+				Issuer:      cert.Issuer.String(),
+				Fingerprint: r.certFingerprint(cert),
+				Source:      source,
+			})
+		}
+		if len(res.VerifiedChain) > 0 {
+			jr.Results[i].Constraints = newJSONRootConstraints(res.Constraints)
+		}
+	}
+}
+
+// newJSONRootConstraints converts constraints to their JSON form.
+func newJSONRootConstraints(c truststore.Constraints) *jsonRootConstraints {
+	jc := &jsonRootConstraints{}
+	if c.NotBeforeMax != nil {
+		jc.NotBeforeMax = c.NotBeforeMax.Format(truststore.DateFormat)
+	}
+	if c.DistrustDate != nil {
+		jc.DistrustDate = c.DistrustDate.Format(truststore.DateFormat)
+	}
+	if c.SCTNotAfter != nil {
+		jc.SCTNotAfter = c.SCTNotAfter.Format(truststore.DateFormat)
+	}
+	return jc
+}
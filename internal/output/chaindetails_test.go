@@ -0,0 +1,104 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestShowChain(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	rootKey, intKey, leafKey := keys[0], keys[1], keys[2]
+
+	root := pathTestCert(t, "Test Root", "Test Root", rootKey, rootKey)
+	intermediate := pathTestCert(t, "Test CA", "Test Root", intKey, rootKey)
+	leaf := pathTestCert(t, "example.com", "Test CA", leafKey, intKey)
+	distrust := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rootFP := truststore.FingerprintFromCert(root)
+
+	pv := func(p truststore.Platform, version string) truststore.PlatformVersion {
+		return truststore.PlatformVersion{Platform: p, Version: version}
+	}
+	newReport := func() *truststore.ValidationReport {
+		verified := []*x509.Certificate{leaf, intermediate, root}
+		return &truststore.ValidationReport{
+			Endpoint: "example.com",
+			// Intermediates out of order: the served path still starts at the leaf
+			Chain: truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{intermediate}},
+			Results: []truststore.TrustResult{
+				{Platform: pv(truststore.PlatformAndroid, "7"), FailureReason: "certificate signed by unknown authority"},
+				{Platform: pv(truststore.PlatformAndroid, "15"), Trusted: true, MatchedCA: "Test Root",
+					MatchedFingerprint: rootFP, VerifiedChain: verified},
+				{Platform: pv(truststore.PlatformChrome, "current"), MatchedFingerprint: rootFP, VerifiedChain: verified,
+					Constraints: truststore.Constraints{DistrustDate: &distrust}, FailureReason: "root distrusted since 2025-01-01"},
+			},
+		}
+	}
+
+	vo := NewValidationOutput(newReport())
+	vo.ShowChain = true
+	text := vo.FormatText()
+	for _, want := range []string{
+		"CHAIN android<=7: FAIL certificate signed by unknown authority\n",
+		`served, issuer "Test Root" not found`,
+		"CHAIN android>=15: PASS\n",
+		"store " + rootFP.String(),
+		"constraints: none",
+		"CHAIN chrome: FAIL root distrusted since 2025-01-01\n",
+		"constraints: DT:2025-01-01",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+	if vo := NewValidationOutput(newReport()); strings.Contains(vo.FormatText(), "CHAIN") {
+		t.Error("chain details shown without ShowChain")
+	}
+
+	data, err := vo.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	failed, passed, constrained := got.Results[0], got.Results[1], got.Results[2]
+	if len(failed.Chain) != 2 || failed.Constraints != nil {
+		t.Errorf("unanchored result: chain = %+v, constraints = %+v", failed.Chain, failed.Constraints)
+	}
+	if len(passed.Chain) != 3 || passed.Chain[2].Source != pathSourceStore || passed.Chain[2].Fingerprint != rootFP.String() {
+		t.Errorf("anchored result chain = %+v", passed.Chain)
+	}
+	if constrained.Constraints == nil || constrained.Constraints.DistrustDate != "2025-01-01" {
+		t.Errorf("constraints = %+v, want distrust_date 2025-01-01", constrained.Constraints)
+	}
+
+	// Bulk output labels blocks with the endpoint and redacts fingerprints
+	bo := NewBulkValidationOutput([]*truststore.ValidationReport{newReport(), {Endpoint: "down.example.com", Error: "refused"}})
+	bo.ShowChain = true
+	bo.Redaction = Redaction{FingerprintOctets: 4}
+	text = bo.FormatText()
+	if !strings.Contains(text, "CHAIN example.com android>=15: PASS") || !strings.Contains(text, "store "+rootFP.Truncate(4)) {
+		t.Errorf("bulk text output:\n%s", text)
+	}
+	if strings.Contains(text, "CHAIN down.example.com") {
+		t.Errorf("failed endpoint has chain details:\n%s", text)
+	}
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// ChangelogOutput implements Formatter for the trust store data changelog.
+type ChangelogOutput struct {
+	Changelog *changelog.Changelog
+}
+
+// NewChangelogOutput creates a new ChangelogOutput formatter.
+func NewChangelogOutput(c *changelog.Changelog) *ChangelogOutput {
+	return &ChangelogOutput{Changelog: c}
+}
+
+// FormatText formats one row per root change (or per added/removed platform version).
+// Header: DATE, PLATFORM, VERSION, CHANGE, FINGERPRINT, ROOT
+func (c *ChangelogOutput) FormatText() string {
+	if len(c.Changelog.Snapshots) == 0 {
+		return "No trust store changes recorded"
+	}
+
+	tw := NewTableWriter()
+	tw.Header("DATE", "PLATFORM", "VERSION", "CHANGE", "FINGERPRINT", "ROOT")
+	for _, s := range c.Changelog.Snapshots {
+		for _, ch := range s.Changes {
+			row := func(change string, r changelog.Root) {
+				tw.Row(s.Date, ch.Platform, ch.Version, change, shortFingerprint(r.Fingerprint), rootLabel(r))
+			}
+			switch {
+			case ch.RemovedVersion:
+				tw.Row(s.Date, ch.Platform, ch.Version, "removed version", "-", "-")
+			case ch.NewVersion:
+				tw.Row(s.Date, ch.Platform, ch.Version, "new version", "-", "-")
+			}
+			for _, r := range ch.Added {
+				row("added", r)
+			}
+			for _, r := range ch.Removed {
+				row("removed", r)
+			}
+			for _, r := range ch.Constrained {
+				row("constrained", r)
+			}
+		}
+	}
+	return tw.String()
+}
+
+// FormatJSON returns the changelog in its published JSON form.
+func (c *ChangelogOutput) FormatJSON() ([]byte, error) {
+	return json.MarshalIndent(c.Changelog, "", "  ")
+}
+
+// shortFingerprint truncates a formatted fingerprint to 4 octets for table display.
+func shortFingerprint(s string) string {
+	fp, err := truststore.ParseFingerprint(s)
+	if err != nil {
+		return s
+	}
+	return fp.Truncate(4)
+}
+
+// rootLabel returns the root name followed by its constraints, in list's NB/DT/SCT notation.
+func rootLabel(r changelog.Root) string {
+	label := r.Name
+	if label == "" {
+		label = "-"
+	}
+	if c := rootConstraints(r); c != "" {
+		label += " (" + c + ")"
+	}
+	return label
+}
+
+// rootConstraints returns a root's constraint dates in list's NB/DT/SCT notation (empty if none).
+func rootConstraints(r changelog.Root) string {
+	var parts []string
+	for _, c := range []struct{ prefix, value string }{
+		{"NB:", r.NotBeforeMax},
+		{"DT:", r.DistrustDate},
+		{"SCT:", r.SCTNotAfter},
+	} {
+		if c.value != "" {
+			parts = append(parts, c.prefix+strings.SplitN(c.value, "T", 2)[0])
+		}
+	}
+	return strings.Join(parts, ",")
+}
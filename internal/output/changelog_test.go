@@ -0,0 +1,47 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+)
+
+func TestChangelogOutput(t *testing.T) {
+	fp := "AA:BB:CC:DD:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00"
+	c := &changelog.Changelog{Snapshots: []changelog.Snapshot{{
+		Date: "2026-03-05",
+		Changes: []changelog.Change{
+			{Platform: "ios", Version: "18", NewVersion: true, Added: []changelog.Root{{Fingerprint: fp, Name: "Root A"}}},
+			{Platform: "windows", Version: "current", Constrained: []changelog.Root{
+				{Fingerprint: fp, Name: "Root A", DistrustDate: "2026-06-01T00:00:00Z"},
+			}},
+		},
+	}}}
+	out := NewChangelogOutput(c)
+
+	text := out.FormatText()
+	for _, want := range []string{"new version", "added", "AA:BB:CC:DD", "Root A (DT:2026-06-01)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := out.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded changelog.Changelog
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("FormatJSON() is not a changelog: %v", err)
+	}
+	if len(decoded.Snapshots) != 1 || len(decoded.Snapshots[0].Changes) != 2 {
+		t.Errorf("FormatJSON() round trip = %+v", decoded)
+	}
+
+	empty := NewChangelogOutput(&changelog.Changelog{}).FormatText()
+	if !strings.Contains(empty, "No trust store changes") {
+		t.Errorf("empty FormatText() = %q", empty)
+	}
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// CompareOutput implements Formatter for the platform versions where two endpoints'
+// validation results diverge.
+type CompareOutput struct {
+	Old, New *truststore.ValidationReport
+	Diffs    []ResultDiff
+	Compared int // Platform versions validated for both endpoints
+}
+
+// ResultDiff pairs one platform version's diverging results.
+type ResultDiff struct {
+	Platform truststore.PlatformVersion
+	Old, New truststore.TrustResult
+}
+
+// NewCompareOutput matches results by platform version and keeps those whose trust
+// outcome differs, sorted by platform then version.
+func NewCompareOutput(oldReport, newReport *truststore.ValidationReport) *CompareOutput {
+	sortResults(newReport.Results)
+	byPlatform := make(map[truststore.PlatformVersion]truststore.TrustResult, len(oldReport.Results))
+	for _, r := range oldReport.Results {
+		byPlatform[r.Platform] = r
+	}
+
+	c := &CompareOutput{Old: oldReport, New: newReport}
+	for _, r := range newReport.Results {
+		o, ok := byPlatform[r.Platform]
+		if !ok {
+			continue
+		}
+		c.Compared++
+		if o.Trusted != r.Trusted {
+			c.Diffs = append(c.Diffs, ResultDiff{Platform: r.Platform, Old: o, New: r})
+		}
+	}
+	return c
+}
+
+// compareCell describes one side of a diff: the anchoring root or the failure reason.
+func compareCell(r truststore.TrustResult) string {
+	if r.Trusted {
+		return "PASS " + r.MatchedCA
+	}
+	return "FAIL " + r.FailureReason
+}
+
+// FormatText formats a table of diverging platform versions, or a one-line summary if none.
+func (c *CompareOutput) FormatText() string {
+	if len(c.Diffs) == 0 {
+		return fmt.Sprintf("No differences between %s and %s across %d platform versions",
+			c.Old.Endpoint, c.New.Endpoint, c.Compared)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "OLD: %s\nNEW: %s\n\n", c.Old.Endpoint, c.New.Endpoint)
+
+	tw := NewTableWriter()
+	tw.Header("PLATFORM", "VERSION", "OLD", "NEW")
+	for _, d := range c.Diffs {
+		tw.Row(string(d.Platform.Platform), d.Platform.Label(), compareCell(d.Old), compareCell(d.New))
+	}
+	sb.WriteString(tw.String())
+	fmt.Fprintf(&sb, "\n\n%d of %d platform versions differ", len(c.Diffs), c.Compared)
+	return sb.String()
+}
+
+// FormatJSON formats the diverging platform versions with both endpoints' results.
+func (c *CompareOutput) FormatJSON() ([]byte, error) {
+	out := jsonCompare{Old: c.Old.Endpoint, New: c.New.Endpoint, Compared: c.Compared, Differences: []jsonResultDiff{}}
+	for _, d := range c.Diffs {
+		out.Differences = append(out.Differences, jsonResultDiff{
+			Platform:   string(d.Platform.Platform),
+			Version:    d.Platform.Version,
+			ExtraRoots: d.Platform.ExtraRoots,
+			Old:        jsonCompareSide(d.Old),
+			New:        jsonCompareSide(d.New),
+		})
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+func jsonCompareSide(r truststore.TrustResult) jsonCompareResult {
+	return jsonCompareResult{Trusted: r.Trusted, MatchedCA: r.MatchedCA, FailureCode: string(r.FailureCode), FailureReason: r.FailureReason}
+}
+
+type jsonCompare struct {
+	Old         string           `json:"old"`
+	New         string           `json:"new"`
+	Compared    int              `json:"compared"`
+	Differences []jsonResultDiff `json:"differences"`
+}
+
+type jsonResultDiff struct {
+	Platform   string            `json:"platform"`
+	Version    string            `json:"version"`
+	ExtraRoots bool              `json:"extra_roots,omitempty"`
+	Old        jsonCompareResult `json:"old"`
+	New        jsonCompareResult `json:"new"`
+}
+
+type jsonCompareResult struct {
+	Trusted       bool   `json:"trusted"`
+	MatchedCA     string `json:"matched_ca,omitempty"`
+	FailureCode   string `json:"failure_code,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
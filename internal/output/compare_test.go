@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCompareOutput(t *testing.T) {
+	t.Parallel()
+
+	pass := func(p truststore.Platform, v, ca string) truststore.TrustResult {
+		return truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: p, Version: v}, Trusted: true, MatchedCA: ca}
+	}
+	fail := func(p truststore.Platform, v, reason string) truststore.TrustResult {
+		return truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: p, Version: v}, FailureReason: reason}
+	}
+	oldReport := &truststore.ValidationReport{
+		Endpoint: "old.example.com",
+		Results: []truststore.TrustResult{
+			pass(truststore.PlatformAndroid, "7", "Old Root"),
+			pass(truststore.PlatformAndroid, "10", "Old Root"),
+			pass(truststore.PlatformIOS, "18", "Old Root"),
+			pass(truststore.PlatformIOS, "17", "Old Root"),
+		},
+	}
+	newReport := &truststore.ValidationReport{
+		Endpoint: "new.example.com",
+		Results: []truststore.TrustResult{
+			pass(truststore.PlatformIOS, "18", "New Root"), // Different anchor, same outcome
+			fail(truststore.PlatformIOS, "17", "root not trusted"),
+			pass(truststore.PlatformAndroid, "10", "New Root"),
+			fail(truststore.PlatformAndroid, "7", "root not trusted"),
+		},
+	}
+
+	c := NewCompareOutput(oldReport, newReport)
+	if c.Compared != 4 || len(c.Diffs) != 2 {
+		t.Fatalf("compared %d, %d diffs; want 4, 2", c.Compared, len(c.Diffs))
+	}
+	if c.Diffs[0].Platform.Platform != truststore.PlatformAndroid || c.Diffs[1].Platform.Version != "17" {
+		t.Errorf("diffs not sorted by platform: %+v", c.Diffs)
+	}
+
+	text := c.FormatText()
+	for _, want := range []string{"OLD: old.example.com", "PASS Old Root", "FAIL root not trusted", "2 of 4 platform versions differ"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "New Root") {
+		t.Errorf("text lists platform versions with the same outcome:\n%s", text)
+	}
+
+	data, err := c.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonCompare
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Old != "old.example.com" || len(got.Differences) != 2 || !got.Differences[0].Old.Trusted ||
+		got.Differences[0].New.FailureReason != "root not trusted" {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+func TestCompareOutputNoDifferences(t *testing.T) {
+	t.Parallel()
+
+	report := func(endpoint string) *truststore.ValidationReport {
+		return &truststore.ValidationReport{
+			Endpoint: endpoint,
+			Results: []truststore.TrustResult{
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true},
+			},
+		}
+	}
+	c := NewCompareOutput(report("a.example.com"), report("b.example.com"))
+	if got, want := c.FormatText(), "No differences between a.example.com and b.example.com across 1 platform versions"; got != want {
+		t.Errorf("FormatText() = %q, want %q", got, want)
+	}
+
+	data, err := c.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"differences": []`) {
+		t.Errorf("json should have empty differences: %s", data)
+	}
+}
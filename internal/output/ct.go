@@ -0,0 +1,200 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/ctsearch"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// CTCert is a certificate logged to CT, with its validation results if requested.
+type CTCert struct {
+	Entry      ctsearch.Entry
+	Unexpected bool                     // Issuer matches none of the expected issuers
+	Results    []truststore.TrustResult // Nil unless validated
+	Error      string                   // Why validation could not run
+}
+
+// CTOutput implements Formatter for certificates logged to CT for a domain.
+type CTOutput struct {
+	Domain    string
+	Issuers   []string // Expected issuer patterns (empty disables the check)
+	Validated bool
+	Certs     []CTCert
+}
+
+// NewCTOutput flags entries whose issuer matches none of issuers, if any are given.
+func NewCTOutput(domain string, entries []ctsearch.Entry, issuers []string) *CTOutput {
+	o := &CTOutput{Domain: domain, Issuers: issuers}
+	for _, e := range entries {
+		o.Certs = append(o.Certs, CTCert{Entry: e, Unexpected: len(issuers) > 0 && !e.IssuedBy(issuers)})
+	}
+	return o
+}
+
+// Unexpected counts certificates from unexpected issuers.
+func (o *CTOutput) Unexpected() int {
+	n := 0
+	for _, c := range o.Certs {
+		if c.Unexpected {
+			n++
+		}
+	}
+	return n
+}
+
+// Untrusted counts validated certificates failing on at least one platform version.
+func (o *CTOutput) Untrusted() int {
+	n := 0
+	for _, c := range o.Certs {
+		if c.Error != "" || ctTrusted(c.Results) < len(c.Results) {
+			n++
+		}
+	}
+	return n
+}
+
+// ctTrusted counts trusted results.
+func ctTrusted(results []truststore.TrustResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Trusted {
+			n++
+		}
+	}
+	return n
+}
+
+// issuerCN extracts the common name from a crt.sh issuer DN, or returns the DN unchanged.
+func issuerCN(dn string) string {
+	for _, part := range strings.Split(dn, ", ") {
+		if cn, ok := strings.CutPrefix(part, "CN="); ok {
+			return cn
+		}
+	}
+	return dn
+}
+
+// FormatText formats a table of logged certificates, newest first, followed by a summary.
+// Header: NOT BEFORE, NOT AFTER, ISSUER, NAMES, plus ISSUER CHECK and TRUSTED when enabled
+func (o *CTOutput) FormatText() string {
+	if len(o.Certs) == 0 {
+		return fmt.Sprintf("No certificates logged for %s", o.Domain)
+	}
+
+	header := []string{"NOT BEFORE", "NOT AFTER", "ISSUER", "NAMES"}
+	if len(o.Issuers) > 0 {
+		header = append(header, "ISSUER CHECK")
+	}
+	if o.Validated {
+		header = append(header, "TRUSTED")
+	}
+
+	tw := NewTableWriter()
+	tw.Header(header...)
+	for _, c := range o.Certs {
+		names := strings.Join(c.Entry.Names, ", ")
+		if len(c.Entry.Names) > 2 {
+			names = fmt.Sprintf("%s (+%d)", strings.Join(c.Entry.Names[:2], ", "), len(c.Entry.Names)-2)
+		}
+		row := []string{
+			c.Entry.NotBefore.Format(truststore.DateFormat),
+			c.Entry.NotAfter.Format(truststore.DateFormat),
+			issuerCN(c.Entry.IssuerName),
+			names,
+		}
+		if len(o.Issuers) > 0 {
+			check := "ok"
+			if c.Unexpected {
+				check = "UNEXPECTED"
+			}
+			row = append(row, check)
+		}
+		if o.Validated {
+			trusted := fmt.Sprintf("%d/%d", ctTrusted(c.Results), len(c.Results))
+			if c.Error != "" {
+				trusted = "error: " + c.Error
+			}
+			row = append(row, trusted)
+		}
+		tw.Row(row...)
+	}
+
+	summary := fmt.Sprintf("%d certificates logged for %s", len(o.Certs), o.Domain)
+	if len(o.Issuers) > 0 {
+		summary += fmt.Sprintf(", %d from unexpected issuers", o.Unexpected())
+	}
+	if o.Validated {
+		summary += fmt.Sprintf(", %d not trusted everywhere", o.Untrusted())
+	}
+	return tw.String() + "\n\n" + summary
+}
+
+// FormatJSON formats the logged certificates with issuer checks and failing platform versions.
+func (o *CTOutput) FormatJSON() ([]byte, error) {
+	out := jsonCT{Domain: o.Domain, ExpectedIssuers: o.Issuers, Certificates: []jsonCTCert{}}
+	for _, c := range o.Certs {
+		jc := jsonCTCert{
+			ID:         c.Entry.ID,
+			Issuer:     c.Entry.IssuerName,
+			CommonName: c.Entry.CommonName,
+			Names:      c.Entry.Names,
+			Serial:     c.Entry.Serial,
+			NotBefore:  c.Entry.NotBefore.Format(jsonTimeFormat),
+			NotAfter:   c.Entry.NotAfter.Format(jsonTimeFormat),
+			LoggedAt:   c.Entry.LoggedAt.Format(jsonTimeFormat),
+			Unexpected: c.Unexpected,
+			Error:      c.Error,
+		}
+		if o.Validated && c.Error == "" {
+			jt := &jsonCTTrust{Trusted: ctTrusted(c.Results), Total: len(c.Results), Failing: []jsonCTFailure{}}
+			sortResults(c.Results)
+			for _, r := range c.Results {
+				if !r.Trusted {
+					jt.Failing = append(jt.Failing, jsonCTFailure{
+						Platform: string(r.Platform.Platform),
+						Version:  r.Platform.Version,
+						Reason:   r.FailureReason,
+					})
+				}
+			}
+			jc.Trust = jt
+		}
+		out.Certificates = append(out.Certificates, jc)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonCT struct {
+	Domain          string       `json:"domain"`
+	ExpectedIssuers []string     `json:"expected_issuers,omitempty"`
+	Certificates    []jsonCTCert `json:"certificates"`
+}
+
+type jsonCTCert struct {
+	ID         int64        `json:"crtsh_id"`
+	Issuer     string       `json:"issuer"`
+	CommonName string       `json:"common_name"`
+	Names      []string     `json:"names"`
+	Serial     string       `json:"serial"`
+	NotBefore  string       `json:"not_before"`
+	NotAfter   string       `json:"not_after"`
+	LoggedAt   string       `json:"logged_at"`
+	Unexpected bool         `json:"unexpected_issuer,omitempty"`
+	Trust      *jsonCTTrust `json:"trust,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+type jsonCTTrust struct {
+	Trusted int             `json:"trusted"`
+	Total   int             `json:"total"`
+	Failing []jsonCTFailure `json:"failing"`
+}
+
+type jsonCTFailure struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+	Reason   string `json:"reason"`
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/ctsearch"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCTOutput(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2026, 9, d, 0, 0, 0, 0, time.UTC) }
+	entries := []ctsearch.Entry{
+		{ID: 2, IssuerName: "C=US, O=Let's Encrypt, CN=R11", Names: []string{"example.com", "www.example.com", "a.example.com"},
+			NotBefore: day(2), NotAfter: day(30)},
+		{ID: 1, IssuerName: "C=XX, O=Rogue CA, CN=Rogue Issuing CA", Names: []string{"example.com"},
+			NotBefore: day(1), NotAfter: day(30)},
+	}
+	o := NewCTOutput("example.com", entries, []string{"Let's Encrypt"})
+	o.Validated = true
+	pv := truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}
+	o.Certs[0].Results = []truststore.TrustResult{{Platform: pv, Trusted: true}}
+	o.Certs[1].Results = []truststore.TrustResult{{Platform: pv, FailureReason: "unknown authority"}}
+
+	if o.Unexpected() != 1 || !o.Certs[1].Unexpected {
+		t.Errorf("Unexpected() = %d, want the rogue issuance only", o.Unexpected())
+	}
+	if o.Untrusted() != 1 {
+		t.Errorf("Untrusted() = %d, want 1", o.Untrusted())
+	}
+
+	text := o.FormatText()
+	for _, want := range []string{"R11", "example.com, www.example.com (+1)", "UNEXPECTED", "Rogue Issuing CA", "0/1",
+		"2 certificates logged for example.com, 1 from unexpected issuers, 1 not trusted everywhere"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonCT
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Certificates) != 2 || got.Certificates[0].Unexpected || !got.Certificates[1].Unexpected ||
+		got.Certificates[1].Trust == nil || len(got.Certificates[1].Trust.Failing) != 1 {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+func TestCTOutputWithoutChecks(t *testing.T) {
+	t.Parallel()
+
+	o := NewCTOutput("example.com", []ctsearch.Entry{{IssuerName: "CN=Any CA", Names: []string{"example.com"}}}, nil)
+	if o.Unexpected() != 0 {
+		t.Error("issuances flagged without expected issuers")
+	}
+	text := o.FormatText()
+	if strings.Contains(text, "ISSUER CHECK") || strings.Contains(text, "TRUSTED") {
+		t.Errorf("text output has disabled columns:\n%s", text)
+	}
+
+	if got := NewCTOutput("example.com", nil, nil).FormatText(); got != "No certificates logged for example.com" {
+		t.Errorf("FormatText() without certificates = %q", got)
+	}
+}
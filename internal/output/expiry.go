@@ -0,0 +1,176 @@
+package output
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Expiry statuses, by certificate lifetime left relative to its threshold.
+const (
+	ExpiryOK       = "ok"
+	ExpiryExpiring = "expiring"
+	ExpiryExpired  = "expired"
+)
+
+// ExpiryOutput implements Formatter for the remaining lifetime of a chain's certificates
+// and the roots anchoring it.
+type ExpiryOutput struct {
+	Source     string
+	Now        time.Time
+	Days       int // Served certificates expiring within Days are "expiring"
+	AnchorDays int // Trust anchors expiring within AnchorDays are "expiring"
+	Entries    []ExpiryEntry
+}
+
+// ExpiryEntry is one certificate's lifetime.
+type ExpiryEntry struct {
+	Role      string // leaf, intermediate, root (self-signed, served) or anchor
+	Cert      *x509.Certificate
+	AnchorFor []truststore.Platform // Platforms anchoring the chain at this root
+}
+
+// NewExpiryOutput lists the served certificates followed by the distinct roots the
+// validation results anchored at. Anchors missing from certs are skipped.
+func NewExpiryOutput(source string, chain *truststore.CertChain, results []truststore.TrustResult,
+	certs *truststore.CertIndex, now time.Time, days, anchorDays int,
+) *ExpiryOutput {
+	e := &ExpiryOutput{Source: source, Now: now, Days: days, AnchorDays: anchorDays}
+	for i, cert := range append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...) {
+		role := "leaf"
+		if i > 0 {
+			role = "intermediate"
+			if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+				role = "root"
+			}
+		}
+		e.Entries = append(e.Entries, ExpiryEntry{Role: role, Cert: cert})
+	}
+
+	for _, r := range results {
+		if r.MatchedFingerprint.IsZero() {
+			continue
+		}
+		idx := slices.IndexFunc(e.Entries, func(entry ExpiryEntry) bool {
+			return truststore.FingerprintFromCert(entry.Cert) == r.MatchedFingerprint
+		})
+		if idx < 0 {
+			cert := certs.Get(r.MatchedFingerprint)
+			if cert == nil {
+				continue
+			}
+			e.Entries = append(e.Entries, ExpiryEntry{Cert: cert})
+			idx = len(e.Entries) - 1
+		}
+		entry := &e.Entries[idx]
+		entry.Role = "anchor"
+		if !slices.Contains(entry.AnchorFor, r.Platform.Platform) {
+			entry.AnchorFor = append(entry.AnchorFor, r.Platform.Platform)
+		}
+	}
+	for i := range e.Entries {
+		slices.Sort(e.Entries[i].AnchorFor)
+	}
+	return e
+}
+
+// DaysRemaining returns whole days until a certificate expires, negative once expired.
+func (e *ExpiryOutput) DaysRemaining(entry ExpiryEntry) int {
+	return int(math.Floor(entry.Cert.NotAfter.Sub(e.Now).Hours() / 24))
+}
+
+// Status classifies an entry against its role's threshold.
+func (e *ExpiryOutput) Status(entry ExpiryEntry) string {
+	threshold := e.Days
+	if entry.Role == "anchor" {
+		threshold = e.AnchorDays
+	}
+	switch {
+	case e.Now.After(entry.Cert.NotAfter):
+		return ExpiryExpired
+	case e.DaysRemaining(entry) < threshold:
+		return ExpiryExpiring
+	default:
+		return ExpiryOK
+	}
+}
+
+// Expiring reports whether any certificate is expired or within its threshold.
+func (e *ExpiryOutput) Expiring() bool {
+	for _, entry := range e.Entries {
+		if e.Status(entry) != ExpiryOK {
+			return true
+		}
+	}
+	return false
+}
+
+// anchorFor renders an anchor's platforms, "-" for served certificates.
+func (entry ExpiryEntry) anchorFor() string {
+	if len(entry.AnchorFor) == 0 {
+		return "-"
+	}
+	names := make([]string, len(entry.AnchorFor))
+	for i, p := range entry.AnchorFor {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
+
+// FormatText formats a table of certificates with their expiry and days remaining.
+func (e *ExpiryOutput) FormatText() string {
+	tw := NewTableWriter()
+	tw.Header("ROLE", "NAME", "EXPIRES", "DAYS", "STATUS", "ANCHOR FOR")
+	for _, entry := range e.Entries {
+		tw.Row(entry.Role, truststore.CertName(entry.Cert), entry.Cert.NotAfter.UTC().Format(truststore.DateFormat),
+			strconv.Itoa(e.DaysRemaining(entry)), strings.ToUpper(e.Status(entry)), entry.anchorFor())
+	}
+	return "Source: " + e.Source + "\n\n" + tw.String()
+}
+
+// FormatJSON formats the certificates with their expiry, days remaining and status.
+func (e *ExpiryOutput) FormatJSON() ([]byte, error) {
+	out := jsonExpiry{
+		Source:       e.Source,
+		CheckedAt:    e.Now.UTC().Format(jsonTimeFormat),
+		Certificates: make([]jsonExpiryCert, len(e.Entries)),
+	}
+	for i, entry := range e.Entries {
+		jc := jsonExpiryCert{
+			Role:          entry.Role,
+			Name:          truststore.CertName(entry.Cert),
+			Fingerprint:   truststore.FingerprintFromCert(entry.Cert).String(),
+			NotAfter:      entry.Cert.NotAfter.UTC().Format(jsonTimeFormat),
+			DaysRemaining: e.DaysRemaining(entry),
+			Status:        e.Status(entry),
+		}
+		for _, p := range entry.AnchorFor {
+			jc.AnchorFor = append(jc.AnchorFor, string(p))
+		}
+		out.Certificates[i] = jc
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonExpiry struct {
+	Source       string           `json:"source"`
+	CheckedAt    string           `json:"checked_at"`
+	Certificates []jsonExpiryCert `json:"certificates"`
+}
+
+type jsonExpiryCert struct {
+	Role          string   `json:"role"`
+	Name          string   `json:"name"`
+	Fingerprint   string   `json:"fingerprint"`
+	NotAfter      string   `json:"not_after"`
+	DaysRemaining int      `json:"days_remaining"`
+	Status        string   `json:"status"`
+	AnchorFor     []string `json:"anchor_for,omitempty"`
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestExpiryOutput(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := pathTestCert(t, "example.com", "Test CA", key, key)
+	intermediate := pathTestCert(t, "Test CA", "Test Root", key, key)
+	root := pathTestCert(t, "Test Root", "Test Root", key, key)
+	fpRoot := truststore.FingerprintFromCert(root)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fpRoot, root)
+
+	chain := &truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{intermediate}}
+	results := []truststore.TrustResult{
+		{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedFingerprint: fpRoot},
+		{Platform: truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "10"}, Trusted: true, MatchedFingerprint: fpRoot},
+		{Platform: truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"}, Trusted: true, MatchedFingerprint: fpRoot},
+		{Platform: truststore.PlatformVersion{Platform: truststore.PlatformWindows, Version: "current"}},
+	}
+
+	// Test certificates expire within the hour; evaluate as of 10 days earlier
+	now := leaf.NotAfter.Add(-10*24*time.Hour - time.Minute)
+	e := NewExpiryOutput("example.com", chain, results, certs, now, 30, 5)
+
+	if len(e.Entries) != 3 {
+		t.Fatalf("got %d entries, want leaf, intermediate and one anchor", len(e.Entries))
+	}
+	anchor := e.Entries[2]
+	if anchor.Role != "anchor" || anchor.anchorFor() != "android, ios" {
+		t.Errorf("anchor = %s for %q, want anchor for \"android, ios\"", anchor.Role, anchor.anchorFor())
+	}
+	if got := e.DaysRemaining(e.Entries[0]); got != 10 {
+		t.Errorf("DaysRemaining(leaf) = %d, want 10", got)
+	}
+	// Served certificates use the 30-day threshold, anchors the 5-day one
+	if got := e.Status(e.Entries[0]); got != ExpiryExpiring {
+		t.Errorf("Status(leaf) = %s, want %s", got, ExpiryExpiring)
+	}
+	if got := e.Status(anchor); got != ExpiryOK {
+		t.Errorf("Status(anchor) = %s, want %s", got, ExpiryOK)
+	}
+	if !e.Expiring() {
+		t.Error("Expiring() = false, want true")
+	}
+
+	text := e.FormatText()
+	for _, want := range []string{"Source: example.com", "ANCHOR FOR", "EXPIRING", "Test Root"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := e.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonExpiry
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Certificates) != 3 || got.Certificates[0].DaysRemaining != 10 || got.Certificates[2].Fingerprint != fpRoot.String() ||
+		len(got.Certificates[2].AnchorFor) != 2 {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+func TestExpiryOutputStatus(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := pathTestCert(t, "example.com", "Test CA", key, key)
+	chain := &truststore.CertChain{ServerCert: leaf}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"expired", leaf.NotAfter.Add(time.Second), ExpiryExpired},
+		{"expires today", leaf.NotAfter.Add(-time.Minute), ExpiryExpiring},
+		{"at threshold", leaf.NotAfter.Add(-7*24*time.Hour - time.Minute), ExpiryOK},
+		{"beyond threshold", leaf.NotAfter.Add(-100 * 24 * time.Hour), ExpiryOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := NewExpiryOutput("example.com", chain, nil, nil, tt.now, 7, 7)
+			if got := e.Status(e.Entries[0]); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+			if e.Expiring() != (tt.want != ExpiryOK) {
+				t.Errorf("Expiring() = %v for status %s", e.Expiring(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package output
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // G505: SHA-1 fingerprints are displayed, not trusted
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// InspectOutput implements Formatter for certificate chain details without validation.
+type InspectOutput struct {
+	Chain  *truststore.CertChain
+	Logs   map[[32]byte]truststore.CTLog // Names SCT logs; nil shows log IDs only
+	Source string                        // Endpoint or file the chain was read from
+}
+
+// inspectLabelWidth aligns values in text output.
+const inspectLabelWidth = 14
+
+// FormatText formats each certificate as an indented block of labeled fields.
+func (o *InspectOutput) FormatText() string {
+	var b strings.Builder
+	b.WriteString("Source: " + o.Source + "\n")
+	if info := o.Chain.TLS; info != nil {
+		b.WriteString("TLS: " + tls.VersionName(info.Version) + ", " + tls.CipherSuiteName(info.CipherSuite) + "\n")
+	}
+
+	for i, c := range o.certs() {
+		fmt.Fprintf(&b, "\nCertificate %d (%s)\n", i, c.Role)
+		field := func(label string, values ...string) {
+			for j, v := range values {
+				if j == 0 {
+					fmt.Fprintf(&b, "  %-*s %s\n", inspectLabelWidth, label+":", v)
+				} else {
+					fmt.Fprintf(&b, "  %-*s %s\n", inspectLabelWidth, "", v)
+				}
+			}
+		}
+
+		field("Subject", c.Subject)
+		field("Issuer", c.Issuer)
+		field("Serial", c.Serial)
+		field("Not Before", c.NotBefore)
+		field("Not After", c.NotAfter)
+		field("SANs", c.SANs.list()...)
+		field("Key", c.Key.String())
+		field("Signature", c.SignatureAlgorithm)
+		if c.IsCA {
+			field("CA", "yes")
+		}
+		field("SHA-256", c.FingerprintSHA256)
+		field("SHA-1", c.FingerprintSHA1)
+		field("OCSP", c.OCSPServers...)
+		field("CA Issuers", c.CAIssuers...)
+		field("CRL", c.CRLDistributionPoints...)
+		for _, sct := range c.SCTs {
+			field("SCT", fmt.Sprintf("%s, %s (%s)", sct.logName(), sct.Timestamp, sct.Source))
+		}
+	}
+	return b.String()
+}
+
+// FormatJSON formats the chain details as JSON.
+func (o *InspectOutput) FormatJSON() ([]byte, error) {
+	return json.MarshalIndent(jsonInspect{Source: o.Source, Certificates: o.certs()}, "", "  ")
+}
+
+type jsonInspect struct {
+	Source       string            `json:"source"`
+	Certificates []jsonInspectCert `json:"certificates"`
+}
+
+type jsonInspectCert struct {
+	Role                  string           `json:"role"`
+	Subject               string           `json:"subject"`
+	Issuer                string           `json:"issuer"`
+	Serial                string           `json:"serial"`
+	NotBefore             string           `json:"not_before"`
+	NotAfter              string           `json:"not_after"`
+	SANs                  jsonSANs         `json:"sans"`
+	Key                   jsonKey          `json:"key"`
+	SignatureAlgorithm    string           `json:"signature_algorithm"`
+	IsCA                  bool             `json:"is_ca"`
+	FingerprintSHA256     string           `json:"fingerprint_sha256"`
+	FingerprintSHA1       string           `json:"fingerprint_sha1"`
+	OCSPServers           []string         `json:"ocsp_servers,omitempty"`
+	CAIssuers             []string         `json:"ca_issuers,omitempty"`
+	CRLDistributionPoints []string         `json:"crl_distribution_points,omitempty"`
+	SCTs                  []jsonInspectSCT `json:"scts,omitempty"`
+}
+
+type jsonSANs struct {
+	DNS   []string `json:"dns,omitempty"`
+	IP    []string `json:"ip,omitempty"`
+	Email []string `json:"email,omitempty"`
+	URI   []string `json:"uri,omitempty"`
+}
+
+// list returns SANs with type prefixes (e.g., "DNS:example.com").
+func (s jsonSANs) list() []string {
+	var out []string
+	for _, v := range s.DNS {
+		out = append(out, "DNS:"+v)
+	}
+	for _, v := range s.IP {
+		out = append(out, "IP:"+v)
+	}
+	for _, v := range s.Email {
+		out = append(out, "email:"+v)
+	}
+	for _, v := range s.URI {
+		out = append(out, "URI:"+v)
+	}
+	return out
+}
+
+type jsonKey struct {
+	Type  string `json:"type"`
+	Bits  int    `json:"bits,omitempty"`
+	Curve string `json:"curve,omitempty"`
+}
+
+// String describes the key as "RSA 2048", "ECDSA P-256" or "Ed25519".
+func (k jsonKey) String() string {
+	switch {
+	case k.Curve != "":
+		return k.Type + " " + k.Curve
+	case k.Bits > 0 && k.Type == "RSA":
+		return fmt.Sprintf("%s %d", k.Type, k.Bits)
+	default:
+		return k.Type
+	}
+}
+
+type jsonInspectSCT struct {
+	LogID     string `json:"log_id"`
+	Log       string `json:"log,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+}
+
+// logName returns the log description, or its ID if the log is unknown.
+func (s jsonInspectSCT) logName() string {
+	if s.Log != "" {
+		return s.Log
+	}
+	return "log " + s.LogID
+}
+
+// certs converts the chain to display records in served order.
+func (o *InspectOutput) certs() []jsonInspectCert {
+	chain := o.Chain
+	certs := append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...)
+	out := make([]jsonInspectCert, len(certs))
+	for i, cert := range certs {
+		role := "leaf"
+		if i > 0 {
+			role = "intermediate"
+			if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+				role = "root"
+			}
+		}
+		out[i] = newJSONInspectCert(cert, role)
+	}
+
+	// SCTs belong to the leaf
+	for _, sct := range chain.SCTs {
+		js := jsonInspectSCT{
+			LogID:     base64.StdEncoding.EncodeToString(sct.LogID[:]),
+			Timestamp: sct.Timestamp.UTC().Format(jsonTimeFormat),
+			Source:    sctSourceName(sct.Source),
+		}
+		if log, ok := o.Logs[sct.LogID]; ok {
+			js.Log = log.Description
+		}
+		out[0].SCTs = append(out[0].SCTs, js)
+	}
+	return out
+}
+
+// newJSONInspectCert extracts display details from a certificate.
+func newJSONInspectCert(cert *x509.Certificate, role string) jsonInspectCert {
+	c := jsonInspectCert{
+		Role:                  role,
+		Subject:               cert.Subject.String(),
+		Issuer:                cert.Issuer.String(),
+		Serial:                formatSerial(cert),
+		NotBefore:             cert.NotBefore.UTC().Format(jsonTimeFormat),
+		NotAfter:              cert.NotAfter.UTC().Format(jsonTimeFormat),
+		SANs:                  jsonSANs{DNS: cert.DNSNames, Email: cert.EmailAddresses},
+		Key:                   describeKey(cert),
+		SignatureAlgorithm:    cert.SignatureAlgorithm.String(),
+		IsCA:                  cert.IsCA,
+		FingerprintSHA256:     truststore.Fingerprint(sha256.Sum256(cert.Raw)).String(),
+		FingerprintSHA1:       formatHex(sha1Sum(cert.Raw)),
+		OCSPServers:           cert.OCSPServer,
+		CAIssuers:             cert.IssuingCertificateURL,
+		CRLDistributionPoints: cert.CRLDistributionPoints,
+	}
+	for _, ip := range cert.IPAddresses {
+		c.SANs.IP = append(c.SANs.IP, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		c.SANs.URI = append(c.SANs.URI, uri.String())
+	}
+	return c
+}
+
+// describeKey returns the public key algorithm and size.
+func describeKey(cert *x509.Certificate) jsonKey {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return jsonKey{Type: "RSA", Bits: pub.N.BitLen()}
+	case *ecdsa.PublicKey:
+		return jsonKey{Type: "ECDSA", Bits: pub.Curve.Params().BitSize, Curve: pub.Curve.Params().Name}
+	case ed25519.PublicKey:
+		return jsonKey{Type: "Ed25519", Bits: 256}
+	default:
+		return jsonKey{Type: cert.PublicKeyAlgorithm.String()}
+	}
+}
+
+// formatSerial renders a serial number as colon-separated hex octets.
+func formatSerial(cert *x509.Certificate) string {
+	if cert.SerialNumber == nil {
+		return ""
+	}
+	return formatHex(cert.SerialNumber.Bytes())
+}
+
+// formatHex renders bytes as colon-separated uppercase hex octets.
+func formatHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02X", v)
+	}
+	return strings.Join(parts, ":")
+}
+
+// sha1Sum returns the SHA-1 digest of data.
+func sha1Sum(data []byte) []byte {
+	sum := sha1.Sum(data) //nolint:gosec // G401: Legacy fingerprint for display only
+	return sum[:]
+}
+
+// sctSourceName names where an SCT was delivered.
+func sctSourceName(s truststore.SCTSource) string {
+	if s == truststore.SCTSourceEmbedded {
+		return "embedded"
+	}
+	return "tls"
+}
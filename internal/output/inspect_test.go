@@ -0,0 +1,100 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestInspectOutput(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(0x1234),
+		Subject:               pkix.Name{CommonName: "inspect.example.com"},
+		NotBefore:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:              []string{"inspect.example.com", "www.example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("192.0.2.1")},
+		OCSPServer:            []string{"http://ocsp.example.com"},
+		IssuingCertificateURL: []string{"http://ca.example.com/ca.der"},
+		CRLDistributionPoints: []string{"http://crl.example.com/ca.crl"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logID := [32]byte{1, 2, 3}
+	chain := &truststore.CertChain{
+		ServerCert: cert,
+		SCTs: []truststore.SCT{
+			{LogID: logID, Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Source: truststore.SCTSourceEmbedded},
+			{LogID: [32]byte{9}, Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Source: truststore.SCTSourceTLS},
+		},
+	}
+	o := &InspectOutput{
+		Chain:  chain,
+		Logs:   map[[32]byte]truststore.CTLog{logID: {Description: "Test Log"}},
+		Source: "inspect.example.com",
+	}
+
+	text := o.FormatText()
+	for _, want := range []string{
+		"Certificate 0 (leaf)",
+		"CN=inspect.example.com",
+		"Serial:        12:34",
+		"DNS:www.example.com",
+		"IP:192.0.2.1",
+		"ECDSA P-256",
+		"ECDSA-SHA256",
+		"http://ocsp.example.com",
+		"http://crl.example.com/ca.crl",
+		"Test Log, 2025-01-01T00:00:00Z (embedded)",
+		"(tls)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonInspect
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Certificates) != 1 {
+		t.Fatalf("got %d certificates, want 1", len(got.Certificates))
+	}
+	c := got.Certificates[0]
+	if c.Role != "leaf" || c.Key.Type != "ECDSA" || c.Key.Bits != 256 || c.Key.Curve != "P-256" {
+		t.Errorf("role/key = %s/%+v, want leaf/ECDSA P-256", c.Role, c.Key)
+	}
+	if len(c.SANs.DNS) != 2 || len(c.SANs.IP) != 1 {
+		t.Errorf("SANs = %+v, want 2 DNS and 1 IP", c.SANs)
+	}
+	if len(c.SCTs) != 2 || c.SCTs[0].Log != "Test Log" || c.SCTs[1].Log != "" {
+		t.Errorf("SCTs = %+v, want named and unnamed log", c.SCTs)
+	}
+}
@@ -13,11 +13,14 @@ func TestFormatJSON(t *testing.T) {
 		Endpoint:    "example.com",
 		Timestamp:   time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
 		ToolVersion: "v2025.01.15",
+		DataVersion: "v2025.01.10",
+		DataDate:    time.Date(2025, 1, 10, 6, 0, 0, 0, time.UTC),
 		Results: []truststore.TrustResult{
 			{
-				Platform:  truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"},
-				Trusted:   true,
-				MatchedCA: "DigiCert Global Root G2",
+				Platform:           truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"},
+				Trusted:            true,
+				MatchedCA:          "DigiCert Global Root G2",
+				MatchedFingerprint: truststore.Fingerprint{0xCB, 0x3C},
 			},
 			{
 				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "35"},
@@ -44,6 +47,9 @@ func TestFormatJSON(t *testing.T) {
 	if parsed["endpoint"] != "example.com" {
 		t.Errorf("endpoint = %v, want example.com", parsed["endpoint"])
 	}
+	if parsed["data_version"] != "v2025.01.10" || parsed["data_date"] != "2025-01-10T06:00:00Z" {
+		t.Errorf("data_version = %v, data_date = %v", parsed["data_version"], parsed["data_date"])
+	}
 	if parsed["all_passed"] != false {
 		t.Errorf("all_passed = %v, want false", parsed["all_passed"])
 	}
@@ -53,7 +59,16 @@ func TestFormatJSON(t *testing.T) {
 		t.Fatal("results is not an array")
 	}
 	if len(results) != 2 {
-		t.Errorf("len(results) = %d, want 2", len(results))
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+
+	// Sorted: android (failed, no anchor) before ios
+	if _, ok := results[0].(map[string]interface{})["matched_fingerprint"]; ok {
+		t.Error("failed result should omit matched_fingerprint")
+	}
+	want := truststore.Fingerprint{0xCB, 0x3C}
+	if fp := results[1].(map[string]interface{})["matched_fingerprint"]; fp != want.String() {
+		t.Errorf("matched_fingerprint = %v", fp)
 	}
 }
 
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/lint"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// LintOutput implements Formatter for chain lint findings.
+type LintOutput struct {
+	Source   string
+	Certs    []*x509.Certificate // Presented chain, leaf first
+	Findings []lint.Finding
+}
+
+// NewLintOutput lists findings for a chain read from source.
+func NewLintOutput(source string, chain *truststore.CertChain, findings []lint.Finding) *LintOutput {
+	return &LintOutput{
+		Source:   source,
+		Certs:    append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...),
+		Findings: findings,
+	}
+}
+
+// certLabel names the certificate a finding applies to, "chain" for chain-wide findings.
+func (o *LintOutput) certLabel(f lint.Finding) string {
+	if f.Cert < 0 {
+		return "chain"
+	}
+	return fmt.Sprintf("#%d %s", f.Cert, truststore.CertName(o.Certs[f.Cert]))
+}
+
+// FormatText formats a table of findings, most severe first.
+// Header: SEVERITY, RULE, CERTIFICATE, MESSAGE
+func (o *LintOutput) FormatText() string {
+	header := fmt.Sprintf("Source: %s (%d certificates)\n\n", o.Source, len(o.Certs))
+	if len(o.Findings) == 0 {
+		return header + "No issues found"
+	}
+
+	tw := NewTableWriter()
+	tw.Header("SEVERITY", "RULE", "CERTIFICATE", "MESSAGE")
+	counts := make(map[lint.Severity]int)
+	for _, f := range o.Findings {
+		counts[f.Rule.Severity]++
+		tw.Row(strings.ToUpper(f.Rule.Severity.String()), f.Rule.ID, o.certLabel(f), f.Message)
+	}
+	return header + tw.String() + fmt.Sprintf("\n\n%d errors, %d warnings, %d info",
+		counts[lint.SeverityError], counts[lint.SeverityWarning], counts[lint.SeverityInfo])
+}
+
+// FormatJSON formats the findings with rule IDs, severities and certificate indexes.
+func (o *LintOutput) FormatJSON() ([]byte, error) {
+	out := jsonLint{Source: o.Source, Findings: []jsonLintFinding{}}
+	for _, f := range o.Findings {
+		jf := jsonLintFinding{Rule: f.Rule.ID, Severity: f.Rule.Severity.String(), Message: f.Message}
+		if f.Cert >= 0 {
+			idx := f.Cert
+			jf.Certificate = &idx
+			jf.Name = truststore.CertName(o.Certs[f.Cert])
+		}
+		out.Findings = append(out.Findings, jf)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonLint struct {
+	Source   string            `json:"source"`
+	Findings []jsonLintFinding `json:"findings"`
+}
+
+type jsonLintFinding struct {
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Certificate *int   `json:"certificate,omitempty"` // Index in the presented chain; omitted for the whole chain
+	Name        string `json:"name,omitempty"`
+	Message     string `json:"message"`
+}
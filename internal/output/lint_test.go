@@ -0,0 +1,60 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/lint"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestLintOutput(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &truststore.CertChain{ServerCert: pathTestCert(t, "example.com", "Test Root", key, key)}
+	rule := func(id string) lint.Rule {
+		for _, r := range lint.Rules {
+			if r.ID == id {
+				return r
+			}
+		}
+		t.Fatalf("unknown rule %s", id)
+		return lint.Rule{}
+	}
+	o := NewLintOutput("example.com", chain, []lint.Finding{
+		{Rule: rule(lint.RuleChainSize), Cert: -1, Message: "too big"},
+		{Rule: rule(lint.RuleLeafServerAuth), Cert: 0, Message: "no EKU"},
+	})
+
+	text := o.FormatText()
+	for _, want := range []string{"Source: example.com (1 certificates)", "WARNING", "chain-size", "#0 example.com", "0 errors, 2 warnings, 0 info"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonLint
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Findings) != 2 || got.Findings[0].Certificate != nil || got.Findings[1].Certificate == nil ||
+		*got.Findings[1].Certificate != 0 || got.Findings[1].Severity != "warning" {
+		t.Errorf("json = %+v", got)
+	}
+
+	if clean := NewLintOutput("example.com", chain, nil).FormatText(); !strings.HasSuffix(clean, "No issues found") {
+		t.Errorf("FormatText() without findings = %q", clean)
+	}
+}
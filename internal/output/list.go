@@ -1,9 +1,18 @@
 package output
 
 import (
+	"bufio"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/ivoronin/certvet/internal/truststore"
 	"github.com/ivoronin/certvet/internal/version"
 )
 
@@ -13,15 +22,180 @@ type ListEntry struct {
 	Platform    string `json:"platform"`
 	Version     string `json:"version"`
 	Fingerprint string `json:"fingerprint"`
+	SPKI        string `json:"spki_sha256,omitempty"`
 	Issuer      string `json:"issuer"`
+	NotBefore   string `json:"not_before,omitempty"`
+	Expiry      string `json:"expires,omitempty"`
 	Constraints string `json:"constraints,omitempty"`
+	Operator    string `json:"operator,omitempty"` // CA owner from CCADB
+	Country     string `json:"country,omitempty"`
+	KeyType     string `json:"key_type,omitempty"`
+	KeySize     int    `json:"key_size,omitempty"`
+	DataVersion string `json:"data_version,omitempty"` // Set in JSON output from StoreList
+	DataDate    string `json:"data_date,omitempty"`
+}
+
+// ListEntries converts trust stores to list entries, naming roots from certs and describing
+// them with infos (or certs, for roots without CA info). When truncate is true, fingerprints and SPKI hashes are shortened to 4 octets for table display.
+func ListEntries(stores []truststore.Store, certs *truststore.CertIndex, infos map[truststore.Fingerprint]truststore.CAInfo, truncate bool) []ListEntry {
+	var entries []ListEntry
+	for _, store := range stores {
+		entries = appendStoreEntries(entries, store, certs, infos, truncate)
+	}
+	return entries
+}
+
+// ListEntrySeq yields the list entries of stores in StoreList order (platform, version,
+// issuer) without materializing them: entries are built one platform version at a time.
+func ListEntrySeq(stores []truststore.Store, certs *truststore.CertIndex, infos map[truststore.Fingerprint]truststore.CAInfo, truncate bool) iter.Seq[ListEntry] {
+	sorted := slices.Clone(stores)
+	slices.SortStableFunc(sorted, compareStores)
+
+	return func(yield func(ListEntry) bool) {
+		var entries []ListEntry
+		for i := 0; i < len(sorted); {
+			// Versions that compare equal (e.g. "11" and "11.0") are sorted together
+			j := i + 1
+			for j < len(sorted) && compareStores(sorted[i], sorted[j]) == 0 {
+				j++
+			}
+			entries = entries[:0]
+			for _, store := range sorted[i:j] {
+				entries = appendStoreEntries(entries, store, certs, infos, truncate)
+			}
+			slices.SortStableFunc(entries, func(a, b ListEntry) int { return strings.Compare(a.Issuer, b.Issuer) })
+			for _, e := range entries {
+				if !yield(e) {
+					return
+				}
+			}
+			i = j
+		}
+	}
+}
+
+// compareStores orders stores by platform, then version.
+func compareStores(a, b truststore.Store) int {
+	if c := strings.Compare(string(a.Platform), string(b.Platform)); c != 0 {
+		return c
+	}
+	return version.Compare(a.Version, b.Version)
+}
+
+// appendStoreEntries appends the list entries of a store's roots to entries.
+func appendStoreEntries(entries []ListEntry, store truststore.Store, certs *truststore.CertIndex, infos map[truststore.Fingerprint]truststore.CAInfo, truncate bool) []ListEntry {
+	display := func(fp truststore.Fingerprint) string {
+		if truncate {
+			return fp.Truncate(4)
+		}
+		return fp.String()
+	}
+
+	for _, fp := range store.Fingerprints {
+		entry := ListEntry{
+			Platform:    string(store.Platform),
+			Version:     store.Version,
+			Fingerprint: display(fp),
+			Issuer:      "-",
+			Constraints: formatConstraints(store.ConstraintFor(fp)),
+		}
+
+		// Lookup certificate to get issuer, key and validity
+		info, ok := infos[fp]
+		if cert := certs.Get(fp); cert != nil {
+			if name := truststore.CertName(cert); name != "" {
+				entry.Issuer = name
+			}
+			entry.SPKI = display(truststore.SPKIFingerprint(cert))
+			entry.NotBefore = cert.NotBefore.UTC().Format(truststore.DateFormat)
+			entry.Expiry = cert.NotAfter.UTC().Format(truststore.DateFormat)
+			if !ok {
+				info, ok = truststore.NewCAInfo(cert, ""), true
+			}
+		} else if ok {
+			entry.NotBefore = info.NotBefore.Format(truststore.DateFormat)
+			entry.Expiry = info.NotAfter.Format(truststore.DateFormat)
+		}
+		if ok {
+			entry.Operator, entry.Country = info.Operator, info.Country
+			entry.KeyType, entry.KeySize = info.KeyType, info.KeySize
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// formatConstraints returns a short string representation of constraints.
+// Empty string if no constraints set.
+// Format: NB:YYYY-MM-DD (NotBeforeMax), DT:YYYY-MM-DD (DistrustDate), SCT:YYYY-MM-DD (SCTNotAfter)
+func formatConstraints(c truststore.Constraints) string {
+	if c.IsEmpty() {
+		return ""
+	}
+
+	var parts []string
+	if c.NotBeforeMax != nil {
+		parts = append(parts, "NB:"+c.NotBeforeMax.Format(truststore.DateFormat))
+	}
+	if c.DistrustDate != nil {
+		parts = append(parts, "DT:"+c.DistrustDate.Format(truststore.DateFormat))
+	}
+	if c.SCTNotAfter != nil {
+		parts = append(parts, "SCT:"+c.SCTNotAfter.Format(truststore.DateFormat))
+	}
+	return strings.Join(parts, ",")
 }
 
 // StoreList implements Formatter for trust store listings.
 // It outputs a table of trust store entries in text or JSON format.
 type StoreList struct {
 	Entries []ListEntry
-	sorted  bool
+	Columns []string // Text and CSV columns (see ParseListColumns); nil for the defaults
+
+	// DataVersion and DataDate identify the trust data listed; JSON output repeats them
+	// in every entry so the array format is kept. Empty/zero values are omitted.
+	DataVersion string
+	DataDate    time.Time
+
+	sorted bool
+}
+
+// listColumns maps column names to entry fields.
+var listColumns = map[string]func(ListEntry) string{
+	"platform":    func(e ListEntry) string { return e.Platform },
+	"version":     func(e ListEntry) string { return e.Version },
+	"fingerprint": func(e ListEntry) string { return e.Fingerprint },
+	"spki":        func(e ListEntry) string { return e.SPKI },
+	"issuer":      func(e ListEntry) string { return e.Issuer },
+	"not_before":  func(e ListEntry) string { return e.NotBefore },
+	"expiry":      func(e ListEntry) string { return e.Expiry },
+	"constraints": func(e ListEntry) string { return e.Constraints },
+	"operator":    func(e ListEntry) string { return e.Operator },
+	"country":     func(e ListEntry) string { return e.Country },
+	"key":         func(e ListEntry) string { return truststore.CAInfo{KeyType: e.KeyType, KeySize: e.KeySize}.Key() },
+}
+
+// ListColumnNames lists the available columns, for help and error messages.
+var ListColumnNames = []string{"platform", "version", "fingerprint", "spki", "issuer", "not_before", "expiry", "constraints", "operator", "country", "key"}
+
+// Default columns: CSV keeps the order of its original fixed column set.
+var (
+	defaultListTextColumns = []string{"platform", "version", "fingerprint", "constraints", "issuer"}
+	defaultListCSVColumns  = []string{"platform", "version", "fingerprint", "issuer", "constraints"}
+)
+
+// ParseListColumns parses a comma-separated column list, e.g. "platform,version,issuer,expiry".
+func ParseListColumns(spec string) ([]string, error) {
+	var columns []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := listColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(ListColumnNames, ", "))
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
 }
 
 // sort sorts entries by platform ASC, version ASC (semver), issuer ASC.
@@ -42,7 +216,7 @@ func (l *StoreList) sort() {
 }
 
 // FormatText returns kubectl-style table output with aligned columns.
-// Header: PLATFORM, VERSION, FINGERPRINT, CONSTRAINTS, ISSUER
+// Default header: PLATFORM, VERSION, FINGERPRINT, CONSTRAINTS, ISSUER
 // Fingerprints in entries should already be truncated for text display.
 func (l *StoreList) FormatText() string {
 	if len(l.Entries) == 0 {
@@ -50,20 +224,49 @@ func (l *StoreList) FormatText() string {
 	}
 	l.sort()
 
-	tw := NewTableWriter()
-	tw.Header("PLATFORM", "VERSION", "FINGERPRINT", "CONSTRAINTS", "ISSUER")
+	columns := l.Columns
+	if columns == nil {
+		columns = defaultListTextColumns
+	}
 
+	tw := listTable(columns)
 	for _, e := range l.Entries {
-		constraints := e.Constraints
-		if constraints == "" {
-			constraints = "-"
-		}
-		tw.Row(e.Platform, e.Version, e.Fingerprint, constraints, e.Issuer)
+		tw.Row(listTextRow(e, columns)...)
 	}
 
 	return tw.String()
 }
 
+// listTable returns a table with the header of columns, coloring constraints.
+func listTable(columns []string) *TableWriter {
+	tw := NewTableWriter()
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(strings.ReplaceAll(c, "_", " "))
+		if c == "constraints" {
+			tw.ColorColumn(i, func(v string) Color {
+				if v != "-" {
+					return ColorYellow // Root is only partially trusted
+				}
+				return ColorNone
+			})
+		}
+	}
+	tw.Header(header...)
+	return tw
+}
+
+// listTextRow returns the table cells of an entry, with "-" for empty values.
+func listTextRow(e ListEntry, columns []string) []string {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		if row[i] = listColumns[c](e); row[i] == "" {
+			row[i] = "-"
+		}
+	}
+	return row
+}
+
 // FormatJSON returns JSON array output.
 // Fingerprints are expected to be full (not truncated) for JSON output.
 func (l *StoreList) FormatJSON() ([]byte, error) {
@@ -71,5 +274,127 @@ func (l *StoreList) FormatJSON() ([]byte, error) {
 		return []byte("[]"), nil
 	}
 	l.sort()
-	return json.MarshalIndent(l.Entries, "", "  ")
+	entries := l.Entries
+	if l.DataVersion != "" || !l.DataDate.IsZero() {
+		entries = make([]ListEntry, len(l.Entries))
+		for i, e := range l.Entries {
+			entries[i] = withDataVersion(e, l.DataVersion, l.DataDate)
+		}
+	}
+	return json.MarshalIndent(entries, "", "  ")
+}
+
+// FormatCSV returns CSV output with one row per entry.
+// Fingerprints are expected to be full (not truncated) for CSV output.
+func (l *StoreList) FormatCSV() ([]byte, error) {
+	l.sort()
+	columns := l.Columns
+	if columns == nil {
+		columns = defaultListCSVColumns
+	}
+	rows := make([][]string, len(l.Entries))
+	for i, e := range l.Entries {
+		rows[i] = listCSVRow(e, columns)
+	}
+	return writeCSV(columns, rows)
+}
+
+// withDataVersion returns e with the trust data version and date set.
+func withDataVersion(e ListEntry, dataVersion string, dataDate time.Time) ListEntry {
+	e.DataVersion = dataVersion
+	if !dataDate.IsZero() {
+		e.DataDate = dataDate.UTC().Format(jsonTimeFormat)
+	}
+	return e
+}
+
+// listCSVRow returns the CSV fields of an entry.
+func listCSVRow(e ListEntry, columns []string) []string {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = listColumns[c](e)
+	}
+	return row
+}
+
+// ListStream writes list entries as they are produced instead of collecting them in a
+// StoreList first, so listing every store stays fast and small. Entries must already be
+// in output order, as yielded by ListEntrySeq; text output iterates them twice.
+// Output matches the corresponding StoreList format followed by a newline, except that
+// nothing is written when there are no entries.
+type ListStream struct {
+	Entries     iter.Seq[ListEntry]
+	Columns     []string  // nil selects the format's default columns
+	DataVersion string    // Trust data version set on JSON entries
+	DataDate    time.Time // Trust data generation date set on JSON entries
+}
+
+// Write writes the entries to w in format; like FormatOutput, only text, JSON and CSV
+// are supported.
+func (s *ListStream) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		return s.writeJSON(w)
+	case FormatCSV:
+		return s.writeCSV(w)
+	case FormatSARIF:
+		return fmt.Errorf("SARIF output is not supported here")
+	case FormatTAP:
+		return fmt.Errorf("TAP output is not supported here")
+	default:
+		columns := s.Columns
+		if columns == nil {
+			columns = defaultListTextColumns
+		}
+		return listTable(columns).WriteRows(w, func(yield func([]string) bool) {
+			for e := range s.Entries {
+				if !yield(listTextRow(e, columns)) {
+					return
+				}
+			}
+		})
+	}
+}
+
+// writeJSON writes entries as an indented JSON array, one element at a time.
+func (s *ListStream) writeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	sep := "[\n  "
+	for e := range s.Entries {
+		data, err := json.MarshalIndent(withDataVersion(e, s.DataVersion, s.DataDate), "  ", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = bw.WriteString(sep)
+		_, _ = bw.Write(data)
+		sep = ",\n  "
+	}
+	if sep == "[\n  " {
+		return nil
+	}
+	_, _ = bw.WriteString("\n]\n")
+	return bw.Flush()
+}
+
+// writeCSV writes a header and one row per entry.
+func (s *ListStream) writeCSV(w io.Writer) error {
+	columns := s.Columns
+	if columns == nil {
+		columns = defaultListCSVColumns
+	}
+	cw := csv.NewWriter(w)
+	header := false
+	for e := range s.Entries {
+		if !header {
+			if err := cw.Write(columns); err != nil {
+				return err
+			}
+			header = true
+		}
+		if err := cw.Write(listCSVRow(e, columns)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
 }
@@ -1,8 +1,15 @@
 package output
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
 )
 
 func TestStoreList_FormatJSON_Fields(t *testing.T) {
@@ -37,6 +44,33 @@ func TestStoreList_FormatJSON_Fields(t *testing.T) {
 	}
 }
 
+func TestStoreList_FormatJSON_DataVersion(t *testing.T) {
+	entries := []ListEntry{{Platform: "ios", Version: "18", Fingerprint: "AA:BB:CC:DD", Issuer: "Test CA"}}
+
+	data, err := (&StoreList{Entries: entries}).FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "data_version") || strings.Contains(string(data), "data_date") {
+		t.Errorf("data fields present without data version: %s", data)
+	}
+
+	list := &StoreList{Entries: entries, DataVersion: "v1.2.0", DataDate: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)}
+	if data, err = list.FormatJSON(); err != nil {
+		t.Fatal(err)
+	}
+	var parsed []map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed[0]["data_version"] != "v1.2.0" || parsed[0]["data_date"] != "2026-10-15T12:00:00Z" {
+		t.Errorf("data fields = %v, %v", parsed[0]["data_version"], parsed[0]["data_date"])
+	}
+	if entries[0].DataVersion != "" {
+		t.Error("FormatJSON modified the entries")
+	}
+}
+
 func TestStoreList_FormatJSON_Empty(t *testing.T) {
 	list := &StoreList{
 		Entries: []ListEntry{},
@@ -75,3 +109,187 @@ func TestStoreList_FormatJSON_ConstraintsPresent(t *testing.T) {
 		t.Errorf("constraints = %v, want SCT:2025-10-31", entry["constraints"])
 	}
 }
+
+func TestStoreList_FormatCSV(t *testing.T) {
+	list := &StoreList{
+		Entries: []ListEntry{
+			{Platform: "ios", Version: "18", Fingerprint: "AA:BB", Issuer: "Test CA, Inc."},
+			{Platform: "chrome", Version: "current", Fingerprint: "CC:DD", Issuer: "Buypass", Constraints: "NB:2025-01-01,SCT:2025-10-31"},
+		},
+	}
+
+	data, err := list.FormatCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "platform,version,fingerprint,issuer,constraints\n" +
+		"chrome,current,CC:DD,Buypass,\"NB:2025-01-01,SCT:2025-10-31\"\n" +
+		"ios,18,AA:BB,\"Test CA, Inc.\",\n"
+	if string(data) != want {
+		t.Errorf("FormatCSV() =\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestParseListColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"platform,version,issuer,expiry", "platform,version,issuer,expiry", false},
+		{" SPKI , not_before ", "spki,not_before", false},
+		{"platform,serial", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseListColumns(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseListColumns(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("ParseListColumns(%q) = %v, want %s", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreList_Columns(t *testing.T) {
+	t.Parallel()
+
+	newList := func() *StoreList {
+		return &StoreList{
+			Entries: []ListEntry{
+				{Platform: "ios", Version: "18", Fingerprint: "AA:BB", SPKI: "CC:DD", Issuer: "Test CA", Expiry: "2038-01-15"},
+			},
+			Columns: []string{"issuer", "expiry", "spki", "constraints"},
+		}
+	}
+
+	if got, want := newList().FormatText(), "ISSUER    EXPIRY       SPKI    CONSTRAINTS\nTest CA   2038-01-15   CC:DD   -"; got != want {
+		t.Errorf("FormatText() =\n%s\nwant:\n%s", got, want)
+	}
+
+	data, err := newList().FormatCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "issuer,expiry,spki,constraints\nTest CA,2038-01-15,CC:DD,\n"; got != want {
+		t.Errorf("FormatCSV() = %q, want %q", got, want)
+	}
+}
+
+func TestListStream(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, alpha := pathTestCert(t, "Beta Root", "Beta Root", key, key), pathTestCert(t, "Alpha Root", "Alpha Root", key, key)
+	fpBeta, fpAlpha := truststore.FingerprintFromCert(beta), truststore.FingerprintFromCert(alpha)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fpBeta, beta)
+	certs.Add(fpAlpha, alpha)
+
+	distrust := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fpBeta}},
+		{Platform: truststore.PlatformAndroid, Version: "10", Fingerprints: []truststore.Fingerprint{fpBeta, {1}, fpAlpha},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{fpBeta: {DistrustDate: &distrust}}},
+		{Platform: truststore.PlatformAndroid, Version: "7", Fingerprints: []truststore.Fingerprint{fpBeta}},
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{fpAlpha}},
+	}
+	dataDate := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		format   Format
+		truncate bool
+		columns  []string
+	}{
+		{"text", FormatText, true, nil},
+		{"text columns", FormatText, false, []string{"issuer", "expiry", "constraints"}},
+		{"json", FormatJSON, false, nil},
+		{"csv", FormatCSV, false, nil},
+		{"csv columns", FormatCSV, false, []string{"issuer", "spki"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			list := &StoreList{Entries: ListEntries(stores, certs, nil, tt.truncate), Columns: tt.columns, DataVersion: "v1.2.0", DataDate: dataDate}
+			want, err := FormatOutput(list, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stream := &ListStream{Entries: ListEntrySeq(stores, certs, nil, tt.truncate), Columns: tt.columns, DataVersion: "v1.2.0", DataDate: dataDate}
+			var buf strings.Builder
+			if err := stream.Write(&buf, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != want+"\n" {
+				t.Errorf("streamed output =\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	// Versions comparing equal are listed together, by issuer
+	same := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fpBeta}},
+		{Platform: truststore.PlatformIOS, Version: "18.0", Fingerprints: []truststore.Fingerprint{fpAlpha}},
+	}
+	var issuers []string
+	for e := range ListEntrySeq(same, certs, nil, false) {
+		issuers = append(issuers, e.Issuer)
+	}
+	if got := strings.Join(issuers, ", "); got != "Alpha Root, Beta Root" {
+		t.Errorf("issuers of equal versions = %s", got)
+	}
+
+	var buf strings.Builder
+	if err := (&ListStream{Entries: ListEntrySeq(nil, certs, nil, true)}).Write(&buf, FormatText); err != nil || buf.Len() != 0 {
+		t.Errorf("empty stream wrote %q, %v", buf.String(), err)
+	}
+}
+
+func TestListEntriesCAInfo(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := pathTestCert(t, "Gov Root", "Gov Root", key, key)
+	fp := truststore.FingerprintFromCert(root)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fp, root)
+
+	noCert := truststore.Fingerprint{1}
+	infos := map[truststore.Fingerprint]truststore.CAInfo{
+		noCert: {Fingerprint: noCert, Operator: "Government of Spain", Country: "ES", KeyType: "RSA", KeySize: 4096,
+			NotBefore: time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), NotAfter: time.Date(2038, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	stores := []truststore.Store{{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{noCert, fp}}}
+
+	list := &StoreList{Entries: ListEntries(stores, certs, infos, false), Columns: []string{"issuer", "operator", "country", "key", "expiry"}}
+	data, err := list.FormatCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Roots without CA info are described from their certificate
+	want := "issuer,operator,country,key,expiry\n-,Government of Spain,ES,RSA 4096,2038-01-01\nGov Root,,,ECDSA 256," + root.NotAfter.UTC().Format(truststore.DateFormat) + "\n"
+	if got := string(data); got != want {
+		t.Errorf("FormatCSV() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// MatrixOutput implements Formatter for a platform by version grid of validation results.
+type MatrixOutput struct {
+	Report *truststore.ValidationReport
+}
+
+// NewMatrixOutput creates a MatrixOutput, sorting results by platform then version.
+func NewMatrixOutput(report *truststore.ValidationReport) *MatrixOutput {
+	sortResults(report.Results)
+	return &MatrixOutput{Report: report}
+}
+
+// matrixRow holds one platform's results in version order.
+type matrixRow struct {
+	platform truststore.Platform
+	results  []truststore.TrustResult
+}
+
+// rows groups the sorted results by platform.
+func (m *MatrixOutput) rows() []matrixRow {
+	var rows []matrixRow
+	for _, r := range m.Report.Results {
+		if len(rows) == 0 || rows[len(rows)-1].platform != r.Platform.Platform {
+			rows = append(rows, matrixRow{platform: r.Platform.Platform})
+		}
+		last := &rows[len(rows)-1]
+		last.results = append(last.results, r)
+	}
+	return rows
+}
+
+// FormatText formats two lines per platform, versions above their PASS/FAIL marks,
+// with columns aligned across platforms.
+func (m *MatrixOutput) FormatText() string {
+	tw := NewTableWriter()
+	for _, row := range m.rows() {
+		versions := []string{string(row.platform)}
+		marks := []string{""}
+		for _, r := range row.results {
+			versions = append(versions, r.Platform.Label())
+			mark := "PASS"
+			if !r.Trusted {
+				mark = "FAIL"
+			}
+			marks = append(marks, mark)
+		}
+		tw.Row(versions...)
+		tw.Row(marks...)
+	}
+	return tw.String()
+}
+
+// FormatJSON formats the grid as platforms with their versions' trust status.
+func (m *MatrixOutput) FormatJSON() ([]byte, error) {
+	out := jsonMatrix{Endpoint: m.Report.Endpoint, Platforms: []jsonMatrixRow{}}
+	for _, row := range m.rows() {
+		jr := jsonMatrixRow{Platform: string(row.platform)}
+		for _, r := range row.results {
+			jr.Versions = append(jr.Versions, jsonMatrixCell{
+				Version:    r.Platform.Version,
+				ExtraRoots: r.Platform.ExtraRoots,
+				Trusted:    r.Trusted,
+			})
+		}
+		out.Platforms = append(out.Platforms, jr)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonMatrix struct {
+	Endpoint  string          `json:"endpoint"`
+	Platforms []jsonMatrixRow `json:"platforms"`
+}
+
+type jsonMatrixRow struct {
+	Platform string           `json:"platform"`
+	Versions []jsonMatrixCell `json:"versions"`
+}
+
+type jsonMatrixCell struct {
+	Version    string `json:"version"`
+	ExtraRoots bool   `json:"extra_roots,omitempty"`
+	Trusted    bool   `json:"trusted"`
+}
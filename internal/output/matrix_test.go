@@ -0,0 +1,54 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestMatrixOutput(t *testing.T) {
+	t.Parallel()
+
+	result := func(p truststore.Platform, v string, trusted bool) truststore.TrustResult {
+		return truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: p, Version: v}, Trusted: trusted}
+	}
+	m := NewMatrixOutput(&truststore.ValidationReport{
+		Endpoint: "example.com",
+		Results: []truststore.TrustResult{
+			result(truststore.PlatformIOS, "18", true),
+			result(truststore.PlatformAndroid, "10", true),
+			result(truststore.PlatformAndroid, "7", false),
+			result(truststore.PlatformIOS, "17", true),
+		},
+	})
+
+	lines := strings.Split(m.FormatText(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 2 per platform:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "android 7 10" {
+		t.Errorf("android versions = %q", lines[0])
+	}
+	if got := strings.Fields(lines[1]); strings.Join(got, " ") != "FAIL PASS" {
+		t.Errorf("android marks = %q", lines[1])
+	}
+	// Marks sit under their versions
+	if strings.Index(lines[1], "FAIL") != strings.Index(lines[0], "7") {
+		t.Errorf("marks not aligned with versions:\n%s\n%s", lines[0], lines[1])
+	}
+
+	data, err := m.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonMatrix
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Platforms) != 2 || got.Platforms[0].Platform != "android" || got.Platforms[0].Versions[0].Trusted ||
+		len(got.Platforms[1].Versions) != 2 {
+		t.Errorf("json = %+v", got)
+	}
+}
@@ -1,13 +1,41 @@
 package output
 
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
 // Format represents the output format type.
 type Format int
 
 const (
 	FormatText Format = iota
 	FormatJSON
+	FormatCSV
+	FormatSARIF
+	FormatTAP
 )
 
+// ParseFormat converts an --output flag value (text, json, csv, sarif or tap) to a Format.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "sarif":
+		return FormatSARIF, nil
+	case "tap":
+		return FormatTAP, nil
+	default:
+		return FormatText, fmt.Errorf("invalid output format %q (expected text, json, csv, sarif or tap)", name)
+	}
+}
+
 // Formatter is the interface for output formatters.
 // Types implementing this interface can output in text or JSON format.
 type Formatter interface {
@@ -15,6 +43,11 @@ type Formatter interface {
 	FormatJSON() ([]byte, error)
 }
 
+// CSVFormatter is implemented by formatters that can also output CSV.
+type CSVFormatter interface {
+	FormatCSV() ([]byte, error)
+}
+
 // FormatOutput formats the given Formatter based on the specified format.
 func FormatOutput(f Formatter, format Format) (string, error) {
 	switch format {
@@ -24,7 +57,46 @@ func FormatOutput(f Formatter, format Format) (string, error) {
 			return "", err
 		}
 		return string(data), nil
+	case FormatCSV:
+		cf, ok := f.(CSVFormatter)
+		if !ok {
+			return "", fmt.Errorf("CSV output is not supported here")
+		}
+		data, err := cf.FormatCSV()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(data), "\n"), nil
+	case FormatSARIF:
+		sf, ok := f.(SARIFFormatter)
+		if !ok {
+			return "", fmt.Errorf("SARIF output is not supported here")
+		}
+		data, err := sf.FormatSARIF()
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case FormatTAP:
+		tf, ok := f.(TAPFormatter)
+		if !ok {
+			return "", fmt.Errorf("TAP output is not supported here")
+		}
+		return tf.FormatTAP(), nil
 	default:
 		return f.FormatText(), nil
 	}
 }
+
+// writeCSV renders a header and rows as RFC 4180 CSV.
+func writeCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
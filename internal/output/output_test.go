@@ -0,0 +1,57 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"text", FormatText, false},
+		{"json", FormatJSON, false},
+		{"CSV", FormatCSV, false},
+		{"sarif", FormatSARIF, false},
+		{"tap", FormatTAP, false},
+		{"yaml", FormatText, true},
+		{"", FormatText, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOutputCSV(t *testing.T) {
+	t.Parallel()
+
+	list := &StoreList{Entries: []ListEntry{{Platform: "ios", Version: "18", Fingerprint: "AA", Issuer: "CA"}}}
+	out, err := FormatOutput(list, FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasSuffix(out, "\n") {
+		t.Errorf("output should not end with a newline: %q", out)
+	}
+
+	// Formatters without CSV support are rejected rather than silently printing text
+	if _, err := FormatOutput(&MatrixOutput{}, FormatCSV); err == nil {
+		t.Error("expected error for formatter without CSV support")
+	}
+	if _, err := FormatOutput(list, FormatSARIF); err == nil {
+		t.Error("expected error for formatter without SARIF support")
+	}
+}
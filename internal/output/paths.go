@@ -0,0 +1,252 @@
+package output
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Certificate sources in a verified path.
+const (
+	pathSourceServed = "served" // Presented by the server
+	pathSourceAIA    = "aia"    // Fetched from an AIA caIssuers URL
+	pathSourceStore  = "store"  // Trust anchor from the platform's root store
+)
+
+// PathsOutput implements Formatter for the verified path each platform version built.
+// Platform versions that built the same path are grouped together.
+type PathsOutput struct {
+	Report     *truststore.ValidationReport
+	CrossSigns *truststore.CertIndex // Known cross-signed intermediates (nil to detect from paths only)
+}
+
+// NewPathsOutput creates a new PathsOutput formatter. Results are sorted as in NewValidationOutput.
+func NewPathsOutput(report *truststore.ValidationReport, crossSigns *truststore.CertIndex) *PathsOutput {
+	sortResults(report.Results)
+	return &PathsOutput{Report: report, CrossSigns: crossSigns}
+}
+
+// builtPath is a verified path shared by one or more platform versions.
+type builtPath struct {
+	certs   []*x509.Certificate
+	results []truststore.TrustResult
+}
+
+// paths groups trusted results by identical verified chains, in result order.
+func (o *PathsOutput) paths() []*builtPath {
+	var out []*builtPath
+	byKey := make(map[string]*builtPath)
+	for _, r := range o.Report.Results {
+		if !r.Trusted || len(r.VerifiedChain) == 0 {
+			continue
+		}
+		var key strings.Builder
+		for _, cert := range r.VerifiedChain {
+			fp := truststore.FingerprintFromCert(cert)
+			key.Write(fp[:])
+		}
+		p, ok := byKey[key.String()]
+		if !ok {
+			p = &builtPath{certs: r.VerifiedChain}
+			byKey[key.String()] = p
+			out = append(out, p)
+		}
+		p.results = append(p.results, r)
+	}
+	return out
+}
+
+// platformLabel describes the platform versions in results compactly (see versionRange).
+func (o *PathsOutput) platformLabel(results []truststore.TrustResult) string {
+	return platformsLabel(results, o.Report.Results)
+}
+
+// platformsLabel describes the platform versions of subset compactly relative to all results
+// (see versionRange), e.g. "android<=9, ios 15-16".
+func platformsLabel(subset, all []truststore.TrustResult) string {
+	versions := make(map[truststore.Platform][]string)
+	for _, r := range all {
+		versions[platformKey(r.Platform)] = append(versions[platformKey(r.Platform)], r.Platform.Version)
+	}
+
+	var order []truststore.Platform
+	matched := make(map[truststore.Platform][]string)
+	for _, r := range subset {
+		p := platformKey(r.Platform)
+		if _, ok := matched[p]; !ok {
+			order = append(order, p)
+		}
+		matched[p] = append(matched[p], r.Platform.Version)
+	}
+
+	labels := make([]string, len(order))
+	for i, p := range order {
+		labels[i] = versionRange(p, matched[p], versions[p])
+	}
+	return strings.Join(labels, ", ")
+}
+
+// platformKey names a platform, separating stores with extra roots from stock ones.
+func platformKey(pv truststore.PlatformVersion) truststore.Platform {
+	if pv.ExtraRoots {
+		return pv.Platform + "+extra"
+	}
+	return pv.Platform
+}
+
+// source reports where a certificate at position i of a verified path came from.
+func (o *PathsOutput) source(certs []*x509.Certificate, i int) string {
+	return pathSource(&o.Report.Chain, certs, i)
+}
+
+// pathSource reports where a certificate at position i of a verified path came from:
+// the last one is the store's anchor, others were served by the endpoint or fetched via AIA.
+func pathSource(chain *truststore.CertChain, certs []*x509.Certificate, i int) string {
+	if i == len(certs)-1 {
+		return pathSourceStore
+	}
+	if isServed(chain, certs[i]) {
+		return pathSourceServed
+	}
+	return pathSourceAIA
+}
+
+// isServed reports whether the endpoint presented cert.
+func isServed(chain *truststore.CertChain, cert *x509.Certificate) bool {
+	if chain.ServerCert != nil && cert.Equal(chain.ServerCert) {
+		return true
+	}
+	for _, c := range chain.Intermediates {
+		if cert.Equal(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCrossSign reports whether an intermediate carries a root's subject and key under another issuer:
+// either a known cross-sign, or the subject and key of a root anchoring another path.
+func (o *PathsOutput) isCrossSign(cert *x509.Certificate, paths []*builtPath) bool {
+	if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+	if o.CrossSigns != nil && o.CrossSigns.Has(truststore.FingerprintFromCert(cert)) {
+		return true
+	}
+	for _, p := range paths {
+		root := p.certs[len(p.certs)-1]
+		if bytes.Equal(root.RawSubject, cert.RawSubject) && bytes.Equal(root.RawSubjectPublicKeyInfo, cert.RawSubjectPublicKeyInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatText formats one block per distinct path, followed by failing platform versions.
+func (o *PathsOutput) FormatText() string {
+	var b strings.Builder
+	paths := o.paths()
+	for i, p := range paths {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "PATH %d: %s\n", i+1, o.platformLabel(p.results))
+
+		tw := NewTableWriter()
+		for j, cert := range p.certs {
+			notes := []string{o.source(p.certs, j)}
+			if j == len(p.certs)-1 {
+				notes[0] += " " + truststore.FingerprintFromCert(cert).Truncate(4)
+			}
+			if j > 0 && j < len(p.certs)-1 && o.isCrossSign(cert, paths) {
+				notes = append(notes, "cross-signed by "+truststore.CertName(p.certs[j+1]))
+			}
+			tw.Row("  "+strconv.Itoa(j), truststore.CertName(cert), strings.Join(notes, ", "))
+		}
+		b.WriteString(tw.String() + "\n")
+	}
+
+	failures := endpointFailures(o.Report.Results)
+	if len(failures) > 0 {
+		if len(paths) > 0 {
+			b.WriteString("\n")
+		}
+		tw := NewTableWriter()
+		tw.Header("NOT TRUSTED", "REASON")
+		for _, f := range failures {
+			tw.Row(f.Platforms, f.Reason)
+		}
+		b.WriteString(tw.String() + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatJSON formats the grouped paths and failures as JSON.
+func (o *PathsOutput) FormatJSON() ([]byte, error) {
+	out := jsonPaths{Endpoint: o.Report.Endpoint, Paths: []jsonPath{}}
+	paths := o.paths()
+	for _, p := range paths {
+		jp := jsonPath{}
+		for _, r := range p.results {
+			jp.Platforms = append(jp.Platforms, jsonPlatformVersion{
+				Platform: string(r.Platform.Platform), Version: r.Platform.Version, ExtraRoots: r.Platform.ExtraRoots,
+			})
+		}
+		for j, cert := range p.certs {
+			jp.Certificates = append(jp.Certificates, jsonPathCert{
+				Subject:     cert.Subject.String(),
+				Issuer:      cert.Issuer.String(),
+				Fingerprint: truststore.FingerprintFromCert(cert).String(),
+				Source:      o.source(p.certs, j),
+				CrossSign:   j > 0 && j < len(p.certs)-1 && o.isCrossSign(cert, paths),
+			})
+		}
+		out.Paths = append(out.Paths, jp)
+	}
+	for _, r := range o.Report.Results {
+		if !r.Trusted {
+			out.Failures = append(out.Failures, jsonPathFailure{
+				Platform: string(r.Platform.Platform), Version: r.Platform.Version, ExtraRoots: r.Platform.ExtraRoots,
+				Reason: r.FailureReason,
+			})
+		}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonPaths struct {
+	Endpoint string            `json:"endpoint"`
+	Paths    []jsonPath        `json:"paths"`
+	Failures []jsonPathFailure `json:"failures,omitempty"`
+}
+
+type jsonPath struct {
+	Platforms    []jsonPlatformVersion `json:"platforms"`
+	Certificates []jsonPathCert        `json:"certificates"` // Leaf to root
+}
+
+type jsonPlatformVersion struct {
+	Platform   string `json:"platform"`
+	Version    string `json:"version"`
+	ExtraRoots bool   `json:"extra_roots,omitempty"`
+}
+
+type jsonPathCert struct {
+	Subject     string `json:"subject"`
+	Issuer      string `json:"issuer"`
+	Fingerprint string `json:"fingerprint"`
+	Source      string `json:"source"` // served, aia or store
+	CrossSign   bool   `json:"cross_sign,omitempty"`
+}
+
+type jsonPathFailure struct {
+	Platform   string `json:"platform"`
+	Version    string `json:"version"`
+	ExtraRoots bool   `json:"extra_roots,omitempty"`
+	Reason     string `json:"reason"`
+}
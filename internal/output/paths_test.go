@@ -0,0 +1,111 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// pathTestCert issues a certificate for subject's key, signed by issuer's key.
+func pathTestCert(t *testing.T, subject, issuer string, key, issuerKey *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: subject},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	parent := &x509.Certificate{Subject: pkix.Name{CommonName: issuer}}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestPathsOutput(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	oldKey, newKey, leafKey := keys[0], keys[1], keys[2]
+
+	oldRoot := pathTestCert(t, "Old Root", "Old Root", oldKey, oldKey)
+	newRoot := pathTestCert(t, "New Root", "New Root", newKey, newKey)
+	crossSign := pathTestCert(t, "New Root", "Old Root", newKey, oldKey)
+	leaf := pathTestCert(t, "example.com", "New Root", leafKey, newKey)
+
+	trusted := func(p truststore.Platform, version string, chain ...*x509.Certificate) truststore.TrustResult {
+		return truststore.TrustResult{
+			Platform:      truststore.PlatformVersion{Platform: p, Version: version},
+			Trusted:       true,
+			MatchedCA:     truststore.CertName(chain[len(chain)-1]),
+			VerifiedChain: chain,
+		}
+	}
+	report := &truststore.ValidationReport{
+		Endpoint: "example.com",
+		Chain:    truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{crossSign}},
+		Results: []truststore.TrustResult{
+			trusted(truststore.PlatformIOS, "18", leaf, newRoot),
+			trusted(truststore.PlatformAndroid, "14", leaf, newRoot),
+			trusted(truststore.PlatformAndroid, "7", leaf, crossSign, oldRoot),
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "5"},
+				FailureReason: "certificate signed by unknown authority",
+			},
+		},
+	}
+
+	text := NewPathsOutput(report, nil).FormatText()
+	for _, want := range []string{
+		"PATH 1: android=7\n",
+		"served, cross-signed by Old Root",
+		"PATH 2: android>=14, ios\n",
+		"store " + truststore.FingerprintFromCert(newRoot).Truncate(4),
+		"android<=5",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := NewPathsOutput(report, nil).FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonPaths
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Paths) != 2 || len(got.Failures) != 1 {
+		t.Fatalf("got %d paths and %d failures, want 2 and 1", len(got.Paths), len(got.Failures))
+	}
+	cross := got.Paths[0].Certificates[1]
+	if !cross.CrossSign || cross.Source != pathSourceServed {
+		t.Errorf("cross-sign = %+v, want served cross-sign", cross)
+	}
+	if got.Paths[1].Certificates[1].CrossSign || got.Paths[1].Certificates[1].Source != pathSourceStore {
+		t.Errorf("root = %+v, want store anchor", got.Paths[1].Certificates[1])
+	}
+}
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Plan event kinds, one per dated root constraint.
+const (
+	PlanDistrust     = "distrust"
+	PlanNotBeforeMax = "not_before_max"
+	PlanSCTNotAfter  = "sct_not_after"
+)
+
+// planEventLabels describes event kinds in text output.
+var planEventLabels = map[string]string{
+	PlanDistrust:     "Distrusted",
+	PlanNotBeforeMax: "Stops trusting new certificates",
+	PlanSCTNotAfter:  "Stops trusting newly logged certificates",
+}
+
+// PlanEvent is a dated constraint of one root, with the stores applying it.
+type PlanEvent struct {
+	Date        time.Time
+	Kind        string // One of Plan* constants
+	Fingerprint truststore.Fingerprint
+	Name        string
+	Stores      []truststore.Store // Sorted by platform, then version
+}
+
+// PlanGroup holds the events of roots owned by one CA, sorted by date.
+type PlanGroup struct {
+	CA     string
+	Events []PlanEvent
+}
+
+// PlanOutput implements Formatter for upcoming root constraint dates grouped by CA.
+type PlanOutput struct {
+	Groups []PlanGroup // Sorted by first event date, then CA
+	stores []truststore.Store
+}
+
+// NewPlanOutput collects constraint dates on or after since from stores. Roots are grouped
+// by their certificate's organization from certs, falling back to the root name.
+func NewPlanOutput(stores []truststore.Store, certs *truststore.CertIndex, since time.Time) *PlanOutput {
+	type eventKey struct {
+		date string
+		kind string
+		fp   truststore.Fingerprint
+	}
+	events := make(map[eventKey]*PlanEvent)
+	add := func(s truststore.Store, fp truststore.Fingerprint, kind string, date *time.Time) {
+		if date == nil || date.Before(since) {
+			return
+		}
+		key := eventKey{date.UTC().Format(truststore.DateFormat), kind, fp}
+		e, ok := events[key]
+		if !ok {
+			e = &PlanEvent{Date: date.UTC(), Kind: kind, Fingerprint: fp}
+			events[key] = e
+		}
+		e.Stores = append(e.Stores, s)
+	}
+	for _, s := range stores {
+		for fp, c := range s.Constraints {
+			add(s, fp, PlanDistrust, c.DistrustDate)
+			add(s, fp, PlanNotBeforeMax, c.NotBeforeMax)
+			add(s, fp, PlanSCTNotAfter, c.SCTNotAfter)
+		}
+	}
+
+	groups := make(map[string]*PlanGroup)
+	for _, e := range events {
+		ca := "Unknown CA"
+		e.Name = e.Fingerprint.Truncate(4)
+		if cert := certs.Get(e.Fingerprint); cert != nil {
+			e.Name = truststore.CertName(cert)
+			ca = e.Name
+			if len(cert.Subject.Organization) > 0 {
+				ca = cert.Subject.Organization[0]
+			}
+		}
+		sortStores(e.Stores)
+		g, ok := groups[ca]
+		if !ok {
+			g = &PlanGroup{CA: ca}
+			groups[ca] = g
+		}
+		g.Events = append(g.Events, *e)
+	}
+
+	o := &PlanOutput{stores: stores}
+	for _, g := range groups {
+		sort.Slice(g.Events, func(i, j int) bool {
+			ei, ej := g.Events[i], g.Events[j]
+			if !ei.Date.Equal(ej.Date) {
+				return ei.Date.Before(ej.Date)
+			}
+			if ei.Name != ej.Name {
+				return ei.Name < ej.Name
+			}
+			return ei.Kind < ej.Kind
+		})
+		o.Groups = append(o.Groups, *g)
+	}
+	sort.Slice(o.Groups, func(i, j int) bool {
+		di, dj := o.Groups[i].Events[0].Date, o.Groups[j].Events[0].Date
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return o.Groups[i].CA < o.Groups[j].CA
+	})
+	return o
+}
+
+// FormatText formats a block per CA with a table of its roots' events.
+// Header: DATE, EVENT, ROOT, PLATFORMS
+func (o *PlanOutput) FormatText() string {
+	if len(o.Groups) == 0 {
+		return "No upcoming trust store dates"
+	}
+
+	blocks := make([]string, len(o.Groups))
+	for i, g := range o.Groups {
+		tw := NewTableWriter()
+		tw.Header("DATE", "EVENT", "ROOT", "PLATFORMS")
+		for _, e := range g.Events {
+			tw.Row(e.Date.Format(truststore.DateFormat), planEventLabels[e.Kind], e.Name, describeStores(e.Stores, o.stores))
+		}
+		blocks[i] = fmt.Sprintf("%s\n%s", g.CA, tw.String())
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// FormatJSON formats CAs with their events and the stores applying each.
+func (o *PlanOutput) FormatJSON() ([]byte, error) {
+	out := []jsonPlanGroup{}
+	for _, g := range o.Groups {
+		jg := jsonPlanGroup{CA: g.CA}
+		for _, e := range g.Events {
+			je := jsonPlanEvent{
+				Date:        e.Date.Format(truststore.DateFormat),
+				Event:       e.Kind,
+				Fingerprint: e.Fingerprint.String(),
+				Name:        e.Name,
+			}
+			for _, s := range e.Stores {
+				je.Stores = append(je.Stores, jsonPlatformVersion{Platform: string(s.Platform), Version: s.Version})
+			}
+			jg.Events = append(jg.Events, je)
+		}
+		out = append(out, jg)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonPlanGroup struct {
+	CA     string          `json:"ca"`
+	Events []jsonPlanEvent `json:"events"`
+}
+
+type jsonPlanEvent struct {
+	Date        string                `json:"date"`
+	Event       string                `json:"event"`
+	Fingerprint string                `json:"fingerprint"`
+	Name        string                `json:"name"`
+	Stores      []jsonPlatformVersion `json:"stores"`
+}
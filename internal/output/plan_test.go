@@ -0,0 +1,89 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestPlanOutput(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha, beta := pathTestCert(t, "Alpha Root", "Alpha Root", key, key), pathTestCert(t, "Beta Root", "Beta Root", key, key)
+	fpAlpha, fpBeta, fpUnknown := truststore.FingerprintFromCert(alpha), truststore.FingerprintFromCert(beta), truststore.Fingerprint{1}
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fpAlpha, alpha)
+	certs.Add(fpBeta, beta)
+
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(truststore.DateFormat, s)
+		return &d
+	}
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformChrome, Version: "current", Fingerprints: []truststore.Fingerprint{fpAlpha, fpBeta},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fpAlpha: {SCTNotAfter: date("2027-04-15")},
+				fpBeta:  {SCTNotAfter: date("2024-01-01")}, // Past, skipped
+			}},
+		{Platform: truststore.PlatformWindows, Version: "10", Fingerprints: []truststore.Fingerprint{fpAlpha, fpUnknown}},
+		{Platform: truststore.PlatformWindows, Version: "11", Fingerprints: []truststore.Fingerprint{fpAlpha, fpUnknown},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fpAlpha:   {DistrustDate: date("2027-01-01"), NotBeforeMax: date("2027-04-15")},
+				fpUnknown: {DistrustDate: date("2026-12-01")},
+			}},
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fpAlpha},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fpAlpha: {DistrustDate: date("2027-01-01")},
+			}},
+	}
+	since := *date("2026-10-01")
+	o := NewPlanOutput(stores, certs, since)
+
+	if len(o.Groups) != 2 || o.Groups[0].CA != "Unknown CA" || o.Groups[1].CA != "Alpha Root" {
+		t.Fatalf("groups = %+v, want Unknown CA then Alpha Root by first date", o.Groups)
+	}
+	events := o.Groups[1].Events
+	if len(events) != 3 || events[0].Kind != PlanDistrust || len(events[0].Stores) != 2 {
+		t.Fatalf("Alpha Root events = %+v, want merged distrust first", events)
+	}
+
+	text := o.FormatText()
+	for _, want := range []string{"Alpha Root\nDATE", "Distrusted", "windows>=11", "Stops trusting newly logged certificates", "chrome"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Beta Root") {
+		t.Errorf("text output includes past dates:\n%s", text)
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []jsonPlanGroup
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Events[0].Date != "2027-01-01" || got[1].Events[0].Event != PlanDistrust ||
+		got[1].Events[0].Fingerprint != fpAlpha.String() || len(got[1].Events[0].Stores) != 2 {
+		t.Errorf("json = %+v", got)
+	}
+
+	if empty := NewPlanOutput(stores, certs, *date("2030-01-01")); empty.FormatText() != "No upcoming trust store dates" {
+		t.Errorf("FormatText() without events = %q", empty.FormatText())
+	}
+}
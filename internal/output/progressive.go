@@ -0,0 +1,116 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// ResultStream writes validation results as they are produced instead of as one document:
+// text rows with fixed column widths, or NDJSON with one result object per line. Results
+// appear in completion order, before report-wide annotations such as advisories and root
+// generations. It is safe for concurrent use.
+type ResultStream struct {
+	Redaction Redaction // Applied to each result
+
+	w             io.Writer
+	format        Format
+	endpointWidth int
+	mu            sync.Mutex
+	header        bool
+}
+
+// streamPlatformWidth fits the longest platform name ("wincontainer") and version label.
+const streamPlatformWidth = 12
+
+// NewResultStream creates a stream writing to w in format (text or JSON). Text rows are
+// aligned for endpoints up to the longest of endpoints.
+func NewResultStream(w io.Writer, format Format, endpoints []string) (*ResultStream, error) {
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("progressive output supports only text and JSON")
+	}
+	s := &ResultStream{w: w, format: format, endpointWidth: utf8.RuneCountInString("ENDPOINT")}
+	for _, e := range endpoints {
+		s.endpointWidth = max(s.endpointWidth, utf8.RuneCountInString(e))
+	}
+	return s, nil
+}
+
+// Result writes the result of validating chain, fetched from endpoint.
+func (s *ResultStream) Result(endpoint string, chain *truststore.CertChain, r truststore.TrustResult) error {
+	report := s.Redaction.apply(&truststore.ValidationReport{Endpoint: endpoint, Chain: *chain, Results: []truststore.TrustResult{r}})
+	if s.format == FormatJSON {
+		jr := newJSONReport(report, s.Redaction).Results[0]
+		return s.writeJSON(jsonStreamResult{Endpoint: report.Endpoint, jsonResult: &jr})
+	}
+
+	r = report.Results[0]
+	validation, status := resultColumns(r)
+	return s.writeRow(report.Endpoint, string(r.Platform.Platform), r.Platform.Label(), validation, status)
+}
+
+// Error writes an endpoint-level error, such as a failed fetch or hostname mismatch.
+func (s *ResultStream) Error(endpoint, msg string) error {
+	report := s.Redaction.apply(&truststore.ValidationReport{Endpoint: endpoint, Error: msg})
+	if s.format == FormatJSON {
+		return s.writeJSON(jsonStreamResult{Endpoint: report.Endpoint, Error: report.Error})
+	}
+	return s.writeRow(report.Endpoint, "-", "-", "ERROR", report.Error)
+}
+
+// jsonStreamResult is an NDJSON line: a result, or an error without one.
+type jsonStreamResult struct {
+	Endpoint string `json:"endpoint"`
+	*jsonResult
+	Error string `json:"error,omitempty"`
+}
+
+// writeJSON writes v as one line.
+func (s *ResultStream) writeJSON(v jsonStreamResult) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// writeRow writes a table row, preceded by the header on the first call.
+func (s *ResultStream) writeRow(endpoint, platform, version, validation, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	if !s.header {
+		s.header = true
+		s.formatRow(&b, tableCell{text: "ENDPOINT"}, tableCell{text: "PLATFORM"}, tableCell{text: "VERSION"}, tableCell{text: "VALIDATION"}, tableCell{text: "STATUS"})
+	}
+	tw := NewTableWriter()
+	row := tw.cells([]string{endpoint, platform, version, validation, status})
+	s.formatRow(&b, row...)
+	_, err := io.WriteString(s.w, b.String())
+	return err
+}
+
+// formatRow pads cells to the stream's column widths, coloring them if colors are enabled.
+func (s *ResultStream) formatRow(b *strings.Builder, cells ...tableCell) {
+	widths := []int{s.endpointWidth, streamPlatformWidth, streamPlatformWidth, len("VALIDATION") + 2}
+	for i, c := range cells {
+		text := c.text
+		if Colors && c.color != ColorNone {
+			text = ansiColors[c.color] + c.text + ansiReset
+		}
+		b.WriteString(text)
+		if i < len(widths) {
+			b.WriteString(strings.Repeat(" ", max(0, widths[i]-utf8.RuneCountInString(c.text))+3))
+		}
+	}
+	b.WriteByte('\n')
+}
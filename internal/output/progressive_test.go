@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestResultStream(t *testing.T) {
+	t.Parallel()
+
+	chain := &truststore.CertChain{Endpoint: "a.example.com"}
+	trusted := truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "Root A"}
+	failed := truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: truststore.PlatformWinContainer, Version: "ltsc2022"}, FailureReason: "no root"}
+
+	var buf bytes.Buffer
+	s, err := NewResultStream(&buf, FormatText, []string{"a.example.com", "long-name.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []truststore.TrustResult{trusted, failed} {
+		if err := s.Result("a.example.com", chain, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Error("long-name.example.com", "connection refused"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "ENDPOINT") {
+		t.Fatalf("text output =\n%s\nwant a header and 3 rows", buf.String())
+	}
+	// Columns are aligned across rows without knowing later results
+	status := strings.Index(lines[0], "STATUS")
+	for i, want := range []string{"Root A", "no root", "connection refused"} {
+		if strings.Index(lines[i+1], want) != status {
+			t.Errorf("row %d = %q, want %q in the STATUS column", i+1, lines[i+1], want)
+		}
+	}
+
+	buf.Reset()
+	if s, err = NewResultStream(&buf, FormatJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	s.Redaction = Redaction{Endpoints: true}
+	if err := s.Result("a.example.com", chain, failed); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Error("b.example.com", "connection refused"); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 2 || got[0]["platform"] != "wincontainer" || got[0]["trusted"] != false || got[0]["failure_reason"] != "no root" {
+		t.Errorf("result line = %v", got[0])
+	}
+	if got[0]["endpoint"] == "a.example.com" {
+		t.Error("endpoint not redacted")
+	}
+	if _, ok := got[1]["platform"]; ok || got[1]["error"] != "connection refused" {
+		t.Errorf("error line = %v, want only endpoint and error", got[1])
+	}
+
+	if _, err := NewResultStream(&buf, FormatCSV, nil); err == nil {
+		t.Error("NewResultStream accepted CSV")
+	}
+}
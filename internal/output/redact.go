@@ -0,0 +1,220 @@
+package output
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// fingerprintPattern matches full fingerprints embedded in messages (e.g., failure reasons).
+var fingerprintPattern = regexp.MustCompile(`(?:[0-9A-F]{2}:){31}[0-9A-F]{2}`)
+
+// Redaction controls how validation reports are anonymized before formatting,
+// so they can be shared publicly. The zero value leaves reports unchanged.
+type Redaction struct {
+	Endpoints         bool // Replace endpoint hostnames and leaf names with placeholders, drop the leaf fingerprint
+	NameLength        int  // Truncate CA names to this many characters (0 = full)
+	FingerprintOctets int  // Truncate fingerprints to this many octets (0 = full)
+}
+
+// IsZero reports whether the redaction changes nothing.
+func (r Redaction) IsZero() bool {
+	return r == Redaction{}
+}
+
+// apply returns a redacted copy of the report. The original report is not modified.
+func (r Redaction) apply(report *truststore.ValidationReport) *truststore.ValidationReport {
+	if r.IsZero() {
+		return report
+	}
+
+	red := *report
+	hosts := leafNames(report)
+	text := func(s string) string { return r.text(s, hosts) }
+
+	red.Endpoint = text(report.Endpoint)
+	red.Error = text(report.Error)
+
+	if cert := report.Chain.ServerCert; cert != nil {
+		leaf := *cert
+		if r.Endpoints {
+			// The leaf's names and fingerprint identify the endpoint (e.g. through CT logs),
+			// so keep only a placeholder common name and drop the raw certificate.
+			leaf.Subject = pkix.Name{}
+			if cert.Subject.CommonName != "" {
+				leaf.Subject.CommonName = placeholder(cert.Subject.CommonName)
+			}
+			leaf.DNSNames, leaf.EmailAddresses, leaf.IPAddresses, leaf.URIs = nil, nil, nil, nil
+			leaf.Raw, leaf.RawTBSCertificate, leaf.RawSubject = nil, nil, nil
+		}
+		leaf.Issuer.CommonName = r.name(leaf.Issuer.CommonName)
+		red.Chain.ServerCert = &leaf
+	}
+	if report.Chain.Intermediates != nil {
+		red.Chain.Intermediates = make([]*x509.Certificate, len(report.Chain.Intermediates))
+		for i, cert := range report.Chain.Intermediates {
+			red.Chain.Intermediates[i] = r.caCert(cert)
+		}
+	}
+
+	if h := report.Hostname; h != nil {
+		red.Hostname = &truststore.HostnameCheck{Host: text(h.Host), Valid: h.Valid, Error: text(h.Error)}
+	}
+
+	red.Results = make([]truststore.TrustResult, len(report.Results))
+	for i, res := range report.Results {
+		res.MatchedCA = r.name(res.MatchedCA)
+		res.FailureReason = text(res.FailureReason)
+		if f := res.Forecast; f != nil {
+			res.Forecast = &truststore.TrustForecast{Date: f.Date, Reason: text(f.Reason)}
+		}
+		if res.VerifiedChain != nil {
+			verified := make([]*x509.Certificate, len(res.VerifiedChain))
+			for j, cert := range res.VerifiedChain {
+				if j == 0 && report.Chain.ServerCert != nil && cert.Equal(report.Chain.ServerCert) {
+					verified[j] = red.Chain.ServerCert
+				} else {
+					verified[j] = r.caCert(cert)
+				}
+			}
+			res.VerifiedChain = verified
+		}
+		if res.Suggested != nil {
+			suggested := make([]*x509.Certificate, len(res.Suggested))
+			for j, cert := range res.Suggested {
+				suggested[j] = r.caCert(cert)
+			}
+			res.Suggested = suggested
+		}
+		if res.Warnings != nil {
+			warnings := make([]string, len(res.Warnings))
+			for j, w := range res.Warnings {
+				warnings[j] = text(w)
+			}
+			res.Warnings = warnings
+		}
+		red.Results[i] = res
+	}
+
+	if report.Advisories != nil {
+		red.Advisories = make([]truststore.AdvisoryMatch, len(report.Advisories))
+		for i, a := range report.Advisories {
+			a.Subject = r.name(a.Subject)
+			red.Advisories[i] = a
+		}
+	}
+
+	return &red
+}
+
+// caCert returns a copy of a CA certificate with subject and issuer names truncated.
+func (r Redaction) caCert(cert *x509.Certificate) *x509.Certificate {
+	c := *cert
+	c.Subject.CommonName = r.name(c.Subject.CommonName)
+	c.Issuer.CommonName = r.name(c.Issuer.CommonName)
+	return &c
+}
+
+// text redacts the endpoint's hostnames and truncates fingerprints in a free-form message.
+func (r Redaction) text(s string, hosts []string) string {
+	if r.Endpoints {
+		for _, host := range hosts {
+			s = strings.ReplaceAll(s, host, placeholder(host))
+		}
+	}
+	if r.FingerprintOctets > 0 {
+		s = fingerprintPattern.ReplaceAllStringFunc(s, func(m string) string {
+			fp, err := truststore.ParseFingerprint(m)
+			if err != nil {
+				return m
+			}
+			return fp.Truncate(r.FingerprintOctets)
+		})
+	}
+	return s
+}
+
+// name truncates a CA name to NameLength characters.
+func (r Redaction) name(s string) string {
+	runes := []rune(s)
+	if r.NameLength <= 0 || len(runes) <= r.NameLength {
+		return s
+	}
+	return string(runes[:r.NameLength]) + "..."
+}
+
+// certFingerprint formats a certificate's fingerprint, or returns "" for a leaf
+// whose raw data was dropped by endpoint redaction.
+func (r Redaction) certFingerprint(cert *x509.Certificate) string {
+	if cert.Raw == nil {
+		return ""
+	}
+	return r.fingerprint(truststore.FingerprintFromCert(cert))
+}
+
+// fingerprint formats a fingerprint, truncated to FingerprintOctets if set.
+func (r Redaction) fingerprint(fp truststore.Fingerprint) string {
+	if r.FingerprintOctets > 0 {
+		return fp.Truncate(r.FingerprintOctets)
+	}
+	return fp.String()
+}
+
+// endpointHost returns the host part of a host[:port] endpoint.
+func endpointHost(endpoint string) string {
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return endpoint
+}
+
+// leafNames returns the names identifying a report's endpoint: its host and the leaf's
+// common name and SANs (which hostname errors list), longest first so that a name is
+// replaced before any name it contains.
+func leafNames(report *truststore.ValidationReport) []string {
+	names := []string{endpointHost(report.Endpoint)}
+	if h := report.Hostname; h != nil {
+		names = append(names, h.Host)
+	}
+	if cert := report.Chain.ServerCert; cert != nil {
+		names = append(names, cert.Subject.CommonName)
+		names = append(names, cert.DNSNames...)
+		names = append(names, cert.EmailAddresses...)
+		for _, ip := range cert.IPAddresses {
+			names = append(names, ip.String())
+		}
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, n := range names {
+		if n != "" && !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return out
+}
+
+// placeholderSalt keys placeholders, so they can't be reversed by hashing likely hostnames.
+// It is random per run: the same host redacts identically within a run only.
+var placeholderSalt = func() []byte {
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt) // Never fails (crypto/rand panics instead)
+	return salt
+}()
+
+// placeholder returns a pseudonym for a hostname, the same for all reports of a run.
+func placeholder(host string) string {
+	sum := sha256.Sum256(append(append([]byte{}, placeholderSalt...), strings.ToLower(host)...))
+	return "host-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,208 @@
+package output
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func redactionTestReport() *truststore.ValidationReport {
+	fp := truststore.Fingerprint{0xAA, 0xBB, 0xCC, 0xDD, 0xEE}
+	return &truststore.ValidationReport{
+		Endpoint: "internal.corp.example:8443",
+		Chain: truststore.CertChain{ServerCert: &x509.Certificate{
+			Subject: pkix.Name{CommonName: "internal.corp.example"},
+			Issuer:  pkix.Name{CommonName: "Example Issuing CA 2024"},
+		}},
+		Hostname: &truststore.HostnameCheck{Host: "internal.corp.example", Error: "certificate is not valid for internal.corp.example"},
+		Results: []truststore.TrustResult{
+			{
+				Platform:  truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"},
+				Trusted:   true,
+				MatchedCA: "Example Global Root CA",
+			},
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"},
+				FailureReason: "chain roots at known CA (fingerprint " + fp.String() + ") but certificate data unavailable",
+			},
+		},
+	}
+}
+
+func TestRedactionText(t *testing.T) {
+	report := redactionTestReport()
+	vo := NewValidationOutput(report)
+	vo.Redaction = Redaction{Endpoints: true, NameLength: 7, FingerprintOctets: 2}
+
+	out := vo.FormatText()
+	if strings.Contains(out, "internal.corp.example") {
+		t.Errorf("endpoint leaked in text output:\n%s", out)
+	}
+	for _, want := range []string{placeholder("internal.corp.example"), "Example...", "AA:BB..."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if report.Endpoint != "internal.corp.example:8443" || report.Hostname.Host != "internal.corp.example" {
+		t.Error("redaction modified the original report")
+	}
+	for _, r := range report.Results {
+		if r.Trusted && r.MatchedCA != "Example Global Root CA" {
+			t.Errorf("redaction modified original MatchedCA: %q", r.MatchedCA)
+		}
+	}
+}
+
+func TestRedactionJSON(t *testing.T) {
+	vo := NewValidationOutput(redactionTestReport())
+	vo.Redaction = Redaction{Endpoints: true, FingerprintOctets: 4}
+
+	data, err := vo.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if strings.Contains(out, "internal.corp.example") {
+		t.Errorf("endpoint leaked in JSON output:\n%s", out)
+	}
+	if !strings.Contains(out, `"endpoint": "`+placeholder("internal.corp.example")+`:8443"`) {
+		t.Errorf("endpoint not replaced with placeholder keeping port:\n%s", out)
+	}
+	if !strings.Contains(out, `"Example Issuing CA 2024"`) {
+		t.Errorf("issuer should not be truncated without NameLength:\n%s", out)
+	}
+}
+
+func TestRedactionZero(t *testing.T) {
+	report := redactionTestReport()
+	if got := (Redaction{}).apply(report); got != report {
+		t.Error("zero redaction should return the report unchanged")
+	}
+}
+
+func TestPlaceholderStable(t *testing.T) {
+	if placeholder("A.example.com") != placeholder("a.example.com") {
+		t.Error("placeholder should be case-insensitive")
+	}
+	if placeholder("a.example.com") == placeholder("b.example.com") {
+		t.Error("placeholders should differ for different hosts")
+	}
+}
+
+// redactionLeakReport returns a report of a real chain whose leaf names several hosts.
+func redactionLeakReport(t *testing.T) (*truststore.ValidationReport, *x509.Certificate) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := pathTestCert(t, "Example Root", "Example Root", rootKey, rootKey)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "internal.corp.example", Organization: []string{"Corp Secret Org"}},
+		DNSNames:     []string{"internal.corp.example", "vpn.corp.example"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := &truststore.ValidationReport{
+		Endpoint: "internal.corp.example:443",
+		Chain:    truststore.CertChain{ServerCert: leaf},
+		Hostname: &truststore.HostnameCheck{Host: "internal.corp.example", Valid: true},
+		Results: []truststore.TrustResult{
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"},
+				Trusted:       true,
+				MatchedCA:     "Example Root",
+				VerifiedChain: []*x509.Certificate{leaf, root},
+			},
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"},
+				FailureReason: "x509: certificate is valid for internal.corp.example, vpn.corp.example, not other.example",
+			},
+		},
+	}
+	return report, leaf
+}
+
+func TestRedactionDropsLeafIdentity(t *testing.T) {
+	report, leaf := redactionLeakReport(t)
+	fp := truststore.FingerprintFromCert(leaf)
+	secrets := []string{fp.String(), strings.ReplaceAll(fp.String(), ":", ""), "internal.corp.example", "vpn.corp.example", "Corp Secret Org"}
+	redaction := Redaction{Endpoints: true}
+
+	vo := NewValidationOutput(report)
+	vo.Redaction = redaction
+	vo.ShowChain = true
+	bo := NewBulkValidationOutput([]*truststore.ValidationReport{report})
+	bo.Redaction = redaction
+	bo.ShowChain = true
+	ro := NewReplacementOutput(report, report)
+	ro.Redaction = redaction
+
+	formatters := map[string]Formatter{"single": vo, "bulk": bo, "replace": ro}
+	for name, f := range formatters {
+		for _, format := range []Format{FormatText, FormatJSON, FormatCSV, FormatSARIF, FormatTAP} {
+			out, err := FormatOutput(f, format)
+			if err != nil {
+				continue // Format not supported by this formatter
+			}
+			for _, secret := range secrets {
+				if strings.Contains(strings.ToUpper(out), strings.ToUpper(secret)) {
+					t.Errorf("%s output (format %d) leaks %q:\n%s", name, format, secret, out)
+				}
+			}
+		}
+	}
+
+	for _, format := range []Format{FormatText, FormatJSON} {
+		var buf bytes.Buffer
+		stream, err := NewResultStream(&buf, format, []string{report.Endpoint})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Redaction = redaction
+		for _, r := range report.Results {
+			if err := stream.Result(report.Endpoint, &report.Chain, r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, secret := range secrets {
+			if strings.Contains(strings.ToUpper(buf.String()), strings.ToUpper(secret)) {
+				t.Errorf("stream output (format %d) leaks %q:\n%s", format, secret, buf.String())
+			}
+		}
+	}
+}
+
+func TestPlaceholderSalted(t *testing.T) {
+	sum := sha256.Sum256([]byte("a.example.com"))
+	if placeholder("a.example.com") == "host-"+hex.EncodeToString(sum[:4]) {
+		t.Error("placeholder is an unsalted hash of the host")
+	}
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Trust changes between the current chain and a candidate replacement.
+const (
+	changeUnchanged       = "unchanged"
+	changeFixed           = "fixed"            // Fails today, trusted with the candidate
+	changeBroken          = "broken"           // Trusted today, fails with the candidate
+	changeNewWarnings     = "new warnings"     // Still trusted, but gains policy warnings
+	changeWarningsCleared = "warnings cleared" // Still trusted, policy warnings resolved
+)
+
+// ReplacementOutput implements Formatter for a what-if comparison of the served chain
+// against one with a candidate leaf (and optionally intermediates) substituted.
+type ReplacementOutput struct {
+	Current   *truststore.ValidationReport
+	Candidate *truststore.ValidationReport
+	Redaction Redaction // Applied when formatting; reports themselves are not modified
+}
+
+// NewReplacementOutput creates a new ReplacementOutput formatter.
+// Both reports must be validated against the same stores.
+func NewReplacementOutput(current, candidate *truststore.ValidationReport) *ReplacementOutput {
+	sortResults(current.Results)
+	sortResults(candidate.Results)
+	return &ReplacementOutput{Current: current, Candidate: candidate}
+}
+
+// AllPassed reports whether the candidate chain passes; the current chain doesn't matter.
+func (o *ReplacementOutput) AllPassed() bool {
+	return o.Candidate.AllPassed
+}
+
+// trustChange classifies how one platform's result changes with the candidate.
+func trustChange(current, candidate truststore.TrustResult) string {
+	switch {
+	case !current.Trusted && candidate.Trusted:
+		return changeFixed
+	case current.Trusted && !candidate.Trusted:
+		return changeBroken
+	case current.Trusted && len(current.Warnings) == 0 && len(candidate.Warnings) > 0:
+		return changeNewWarnings
+	case current.Trusted && len(current.Warnings) > 0 && len(candidate.Warnings) == 0:
+		return changeWarningsCleared
+	}
+	return changeUnchanged
+}
+
+// resultsByPlatform indexes a report's results by platform version.
+func resultsByPlatform(report *truststore.ValidationReport) map[truststore.PlatformVersion]truststore.TrustResult {
+	byPlatform := make(map[truststore.PlatformVersion]truststore.TrustResult, len(report.Results))
+	for _, r := range report.Results {
+		byPlatform[r.Platform] = r
+	}
+	return byPlatform
+}
+
+// FormatText formats the comparison as a table of current vs candidate validation.
+// Header: PLATFORM, VERSION, CURRENT, CANDIDATE, CHANGE, STATUS (candidate status)
+func (o *ReplacementOutput) FormatText() string {
+	current := o.Redaction.apply(o.Current)
+	candidate := o.Redaction.apply(o.Candidate)
+	byPlatform := resultsByPlatform(current)
+
+	tw := NewTableWriter()
+	tw.Header("PLATFORM", "VERSION", "CURRENT", "CANDIDATE", "CHANGE", "STATUS")
+
+	counts := make(map[string]int)
+	for _, r := range candidate.Results {
+		cur := byPlatform[r.Platform]
+		curValidation, _ := resultColumns(cur)
+		validation, status := resultColumns(r)
+		change := trustChange(cur, r)
+		counts[change]++
+		if change == changeUnchanged {
+			change = "-"
+		}
+		tw.Row(string(r.Platform.Platform), r.Platform.Label(), curValidation, validation, change, status)
+	}
+
+	return formatCandidateLine(candidate.Chain.ServerCert) + formatEvaluatedAt(candidate.EvaluatedAt) + tw.String() +
+		formatHostnameLine(candidate.Hostname) +
+		fmt.Sprintf("\nRotation: %d fixed, %d broken, %d with warning changes, %d unchanged\n",
+			counts[changeFixed], counts[changeBroken], counts[changeNewWarnings]+counts[changeWarningsCleared], counts[changeUnchanged])
+}
+
+// formatCandidateLine identifies the candidate leaf above the table.
+func formatCandidateLine(cert *x509.Certificate) string {
+	return fmt.Sprintf("Candidate: %s (issued by %s, expires %s)\n\n",
+		cert.Subject.CommonName, cert.Issuer.CommonName, cert.NotAfter.Format(truststore.DateFormat))
+}
+
+// FormatJSON formats both reports and the per-platform changes as JSON.
+func (o *ReplacementOutput) FormatJSON() ([]byte, error) {
+	byPlatform := resultsByPlatform(o.Current)
+	out := jsonReplacement{
+		Current:   newJSONReport(o.Redaction.apply(o.Current), o.Redaction),
+		Candidate: newJSONReport(o.Redaction.apply(o.Candidate), o.Redaction),
+		Changes:   []jsonTrustChange{},
+		AllPassed: o.AllPassed(),
+	}
+	for _, r := range o.Candidate.Results {
+		cur := byPlatform[r.Platform]
+		change := trustChange(cur, r)
+		if change == changeUnchanged {
+			continue
+		}
+		out.Changes = append(out.Changes, jsonTrustChange{
+			Platform:   string(r.Platform.Platform),
+			Version:    r.Platform.Version,
+			ExtraRoots: r.Platform.ExtraRoots,
+			Current:    cur.Trusted,
+			Candidate:  r.Trusted,
+			Change:     change,
+		})
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonReplacement struct {
+	Current   jsonReport        `json:"current"`
+	Candidate jsonReport        `json:"candidate"`
+	Changes   []jsonTrustChange `json:"changes"`
+	AllPassed bool              `json:"all_passed"`
+}
+
+type jsonTrustChange struct {
+	Platform   string `json:"platform"`
+	Version    string `json:"version"`
+	ExtraRoots bool   `json:"extra_roots,omitempty"`
+	Current    bool   `json:"current_trusted"`
+	Candidate  bool   `json:"candidate_trusted"`
+	Change     string `json:"change"`
+}
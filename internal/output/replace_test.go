@@ -0,0 +1,97 @@
+package output
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestTrustChange(t *testing.T) {
+	t.Parallel()
+
+	pass := truststore.TrustResult{Trusted: true}
+	warn := truststore.TrustResult{Trusted: true, Warnings: []string{"Apple CT policy"}}
+	fail := truststore.TrustResult{FailureReason: "certificate has expired or is not yet valid"}
+
+	tests := []struct {
+		name               string
+		current, candidate truststore.TrustResult
+		want               string
+	}{
+		{"fixed", fail, pass, changeFixed},
+		{"fixed with warnings", fail, warn, changeFixed},
+		{"broken", pass, fail, changeBroken},
+		{"new warnings", pass, warn, changeNewWarnings},
+		{"warnings cleared", warn, pass, changeWarningsCleared},
+		{"still passing", pass, pass, changeUnchanged},
+		{"still failing", fail, fail, changeUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := trustChange(tt.current, tt.candidate); got != tt.want {
+				t.Errorf("trustChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplacementOutput(t *testing.T) {
+	ios := truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "17"}
+	android := truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "14"}
+	leaf := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "new.example.com"},
+		Issuer:   pkix.Name{CommonName: "New CA"},
+		NotAfter: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	current := &truststore.ValidationReport{
+		Endpoint: "example.com",
+		Results: []truststore.TrustResult{
+			{Platform: ios, Trusted: true, MatchedCA: "Old Root"},
+			{Platform: android, FailureReason: "certificate signed by unknown authority"},
+		},
+	}
+	candidate := &truststore.ValidationReport{
+		Endpoint:  "example.com",
+		Chain:     truststore.CertChain{ServerCert: leaf},
+		AllPassed: true,
+		Results: []truststore.TrustResult{
+			{Platform: ios, Trusted: true, MatchedCA: "New Root"},
+			{Platform: android, Trusted: true, MatchedCA: "New Root"},
+		},
+	}
+
+	out := NewReplacementOutput(current, candidate)
+	if !out.AllPassed() {
+		t.Error("AllPassed() should follow the candidate")
+	}
+
+	text := out.FormatText()
+	for _, want := range []string{"Candidate: new.example.com (issued by New CA, expires 2027-01-01)", "CURRENT", "fixed", "Rotation: 1 fixed, 0 broken"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := out.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded jsonReplacement
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Changes) != 1 || decoded.Changes[0].Platform != "android" || decoded.Changes[0].Change != changeFixed {
+		t.Errorf("changes = %+v, want android fixed only", decoded.Changes)
+	}
+	if len(decoded.Current.Results) != 2 || len(decoded.Candidate.Results) != 2 {
+		t.Errorf("expected both full reports in JSON")
+	}
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// PlatformRollup counts the passing versions of one platform in a report.
+type PlatformRollup struct {
+	Platform   truststore.Platform
+	ExtraRoots bool     // Stores with user-supplied roots are counted separately
+	Passed     int      // Versions that trust the chain
+	Total      int      // Versions validated
+	Failed     []string // Versions that don't, in result order
+}
+
+// Rollup counts passing versions per platform, in result order.
+// Results must be sorted (see sortResults).
+func Rollup(results []truststore.TrustResult) []PlatformRollup {
+	var out []PlatformRollup
+	index := make(map[truststore.Platform]int)
+	for _, r := range results {
+		key := platformKey(r.Platform)
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, PlatformRollup{Platform: r.Platform.Platform, ExtraRoots: r.Platform.ExtraRoots})
+		}
+		out[i].Total++
+		if r.Trusted {
+			out[i].Passed++
+		} else {
+			out[i].Failed = append(out[i].Failed, r.Platform.Version)
+		}
+	}
+	return out
+}
+
+// formatRollup renders rollups on one line, e.g. "android: 6/8 pass (7, 8 fail), ios: 7/7 pass".
+func formatRollup(rollups []PlatformRollup) string {
+	parts := make([]string, len(rollups))
+	for i, r := range rollups {
+		name := string(r.Platform)
+		if r.ExtraRoots {
+			name += "+extra"
+		}
+		parts[i] = fmt.Sprintf("%s: %d/%d pass", name, r.Passed, r.Total)
+		if len(r.Failed) > 0 {
+			parts[i] += " (" + strings.Join(r.Failed, ", ") + " fail)"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newJSONRollup converts rollups to their JSON form.
+func newJSONRollup(rollups []PlatformRollup) []jsonRollup {
+	var out []jsonRollup
+	for _, r := range rollups {
+		out = append(out, jsonRollup{
+			Platform:       string(r.Platform),
+			ExtraRoots:     r.ExtraRoots,
+			Passed:         r.Passed,
+			Total:          r.Total,
+			FailedVersions: r.Failed,
+		})
+	}
+	return out
+}
+
+type jsonRollup struct {
+	Platform       string   `json:"platform"`
+	ExtraRoots     bool     `json:"extra_roots,omitempty"`
+	Passed         int      `json:"passed"`
+	Total          int      `json:"total"`
+	FailedVersions []string `json:"failed_versions,omitempty"`
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestRollup(t *testing.T) {
+	t.Parallel()
+
+	result := func(p truststore.Platform, version string, extra, trusted bool) truststore.TrustResult {
+		return truststore.TrustResult{
+			Platform: truststore.PlatformVersion{Platform: p, Version: version, ExtraRoots: extra},
+			Trusted:  trusted,
+		}
+	}
+	report := &truststore.ValidationReport{
+		Endpoint: "example.com",
+		Results: []truststore.TrustResult{
+			result(truststore.PlatformIOS, "17", false, true),
+			result(truststore.PlatformAndroid, "7", false, false),
+			result(truststore.PlatformAndroid, "8", false, false),
+			result(truststore.PlatformAndroid, "9", false, true),
+			result(truststore.PlatformAndroid, "8", true, true),
+			result(truststore.PlatformIOS, "18", false, true),
+		},
+	}
+
+	text := NewValidationOutput(report).FormatText()
+	if want := "\n\nandroid: 1/3 pass (7, 8 fail), android+extra: 1/1 pass, ios: 2/2 pass"; !strings.Contains(text, want) {
+		t.Errorf("text output missing %q:\n%s", want, text)
+	}
+
+	data, err := NewValidationOutput(report).FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []jsonRollup{
+		{Platform: "android", Passed: 1, Total: 3, FailedVersions: []string{"7", "8"}},
+		{Platform: "android", ExtraRoots: true, Passed: 1, Total: 1},
+		{Platform: "ios", Passed: 2, Total: 2},
+	}
+	if len(got.Rollup) != len(want) {
+		t.Fatalf("rollup = %+v, want %+v", got.Rollup, want)
+	}
+	for i := range want {
+		if got.Rollup[i].Platform != want[i].Platform || got.Rollup[i].ExtraRoots != want[i].ExtraRoots ||
+			got.Rollup[i].Passed != want[i].Passed || got.Rollup[i].Total != want[i].Total ||
+			strings.Join(got.Rollup[i].FailedVersions, ",") != strings.Join(want[i].FailedVersions, ",") {
+			t.Errorf("rollup[%d] = %+v, want %+v", i, got.Rollup[i], want[i])
+		}
+	}
+}
+
+func TestBulkRollup(t *testing.T) {
+	t.Parallel()
+
+	text := NewBulkValidationOutput(testBulkReports()).FormatText()
+	for _, want := range []string{"a.example.com: ios: 1/1 pass", "b.example.com: ios: 0/1 pass (18 fail)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "c.example.com: ") {
+		t.Errorf("failed endpoint has a rollup:\n%s", text)
+	}
+}
@@ -0,0 +1,240 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// SARIF rule IDs, one per kind of validation finding.
+const (
+	sarifUntrusted        = "untrusted-platform"
+	sarifWeakCrypto       = "weak-crypto"
+	sarifUpcomingDistrust = "upcoming-distrust"
+	sarifTrustWarning     = "trust-warning"
+	sarifEndpointError    = "endpoint-error"
+)
+
+// weakCryptoPrefix marks validator warnings about weak signatures and keys.
+const weakCryptoPrefix = "weak crypto: "
+
+// sarifRules describes every rule in the order they appear in the tool driver.
+var sarifRules = []sarifRule{
+	{sarifUntrusted, "error", "Certificate chain is not trusted on some platform versions"},
+	{sarifWeakCrypto, "warning", "Certificate chain uses a signature algorithm or key that some clients reject"},
+	{sarifUpcomingDistrust, "warning", "Certificate chain stops being trusted within the lookahead window"},
+	{sarifTrustWarning, "note", "Certificate chain is trusted but some clients may reject it"},
+	{sarifEndpointError, "error", "Endpoint could not be validated"},
+}
+
+type sarifRule struct {
+	id, level, description string
+}
+
+// SARIFFormatter is implemented by formatters that can also output SARIF 2.1.0.
+type SARIFFormatter interface {
+	FormatSARIF() ([]byte, error)
+}
+
+// FormatSARIF formats the validation findings as a SARIF log.
+func (v *ValidationOutput) FormatSARIF() ([]byte, error) {
+	return newSARIFLog([]*truststore.ValidationReport{v.Redaction.apply(v.Report)}).marshal()
+}
+
+// FormatSARIF formats the findings of all endpoints as a single SARIF log.
+func (b *BulkValidationOutput) FormatSARIF() ([]byte, error) {
+	return newSARIFLog(b.redactedReports()).marshal()
+}
+
+// newSARIFLog converts reports to a SARIF log with one run. Findings are grouped
+// per endpoint by platform version range, as in --summary, so a reason shared by
+// many versions of a platform yields a single result. Reports are expected to be
+// redacted and their results sorted already.
+func newSARIFLog(reports []*truststore.ValidationReport) sarifLog {
+	driver := sarifDriver{
+		Name:           "certvet",
+		InformationURI: "https://github.com/ivoronin/certvet",
+	}
+	ruleIndex := make(map[string]int, len(sarifRules))
+	for i, rule := range sarifRules {
+		ruleIndex[rule.id] = i
+		driver.Rules = append(driver.Rules, sarifReportingDescriptor{
+			ID:                   rule.id,
+			ShortDescription:     sarifMessage{Text: rule.description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.level},
+		})
+	}
+
+	results := []sarifResult{}
+	add := func(endpoint, ruleID, platforms, text string) {
+		rule := sarifRules[ruleIndex[ruleID]]
+		message := endpoint + ": " + text
+		if platforms != "" {
+			message += " (" + platforms + ")"
+		}
+		sum := sha256.Sum256([]byte(endpoint + "\x00" + ruleID + "\x00" + text))
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex[ruleID],
+			Level:     rule.level,
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: endpoint}},
+			}},
+			PartialFingerprints: map[string]string{"certvetFinding/v1": hex.EncodeToString(sum[:8])},
+			Properties:          &sarifProperties{Endpoint: endpoint, Platforms: platforms},
+		})
+	}
+
+	for _, report := range reports {
+		if driver.Version == "" {
+			driver.Version = report.ToolVersion
+		}
+		if report.Error != "" {
+			add(report.Endpoint, sarifEndpointError, "", report.Error)
+			continue
+		}
+		for _, f := range endpointFailures(report.Results) {
+			add(report.Endpoint, sarifUntrusted, f.Platforms, "not trusted: "+f.Reason)
+		}
+		for _, f := range groupByPlatform(report.Results, forecastText) {
+			add(report.Endpoint, sarifUpcomingDistrust, f.Platforms, f.Reason)
+		}
+		for _, f := range groupByPlatform(report.Results, warningTexts) {
+			if strings.HasPrefix(f.Reason, weakCryptoPrefix) {
+				add(report.Endpoint, sarifWeakCrypto, f.Platforms, strings.TrimPrefix(f.Reason, weakCryptoPrefix))
+			} else {
+				add(report.Endpoint, sarifTrustWarning, f.Platforms, f.Reason)
+			}
+		}
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// forecastText describes when a passing result stops being trusted (see --lookahead).
+func forecastText(r truststore.TrustResult) []string {
+	if !r.Trusted || r.Forecast == nil {
+		return nil
+	}
+	return []string{"fails on " + r.Forecast.Date.Format(truststore.DateFormat) + ": " + r.Forecast.Reason}
+}
+
+// warningTexts returns the warnings of a passing result.
+func warningTexts(r truststore.TrustResult) []string {
+	if !r.Trusted {
+		return nil
+	}
+	return r.Warnings
+}
+
+// groupByPlatform collects the versions of each platform reporting the same text
+// and describes them as version ranges, in first-seen order. Results must be sorted.
+func groupByPlatform(results []truststore.TrustResult, texts func(truststore.TrustResult) []string) []FailureGroup {
+	type textKey struct {
+		platform truststore.Platform
+		text     string
+	}
+	matched := make(map[textKey][]string)
+	var keys []textKey
+	versions := make(map[truststore.Platform][]string)
+
+	for _, r := range results {
+		p := r.Platform.Platform
+		if r.Platform.ExtraRoots {
+			p += "+extra"
+		}
+		versions[p] = append(versions[p], r.Platform.Version)
+		for _, text := range texts(r) {
+			key := textKey{p, text}
+			if _, ok := matched[key]; !ok {
+				keys = append(keys, key)
+			}
+			matched[key] = append(matched[key], r.Platform.Version)
+		}
+	}
+
+	out := make([]FailureGroup, len(keys))
+	for i, key := range keys {
+		out[i] = FailureGroup{
+			Platforms: versionRange(key.platform, matched[key], versions[key.platform]),
+			Reason:    key.text,
+		}
+	}
+	return out
+}
+
+func (l sarifLog) marshal() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// sarifLog is the subset of the SARIF 2.1.0 schema certvet emits.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version,omitempty"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          *sarifProperties  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifProperties struct {
+	Endpoint  string `json:"endpoint"`
+	Platforms string `json:"platforms,omitempty"`
+}
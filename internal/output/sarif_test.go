@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	t.Parallel()
+
+	android := func(v string) truststore.PlatformVersion {
+		return truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: v}
+	}
+	reports := []*truststore.ValidationReport{
+		{
+			Endpoint:    "a.example.com",
+			ToolVersion: "1.2.3",
+			Results: []truststore.TrustResult{
+				{Platform: android("7"), FailureReason: "certificate signed by unknown authority"},
+				{Platform: android("8"), FailureReason: "certificate signed by unknown authority"},
+				{Platform: android("9"), Trusted: true, MatchedCA: "Root A",
+					Warnings: []string{`weak crypto: "Leaf" has RSA-1024 key`, "only 1 SCT"},
+					Forecast: &truststore.TrustForecast{Date: time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), Reason: "root distrusted"}},
+			},
+		},
+		{Endpoint: "b.example.com", Error: "connection refused"},
+	}
+
+	data, err := NewBulkValidationOutput(reports).FormatSARIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+					Rules   []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version = %q, runs = %d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("driver version = %q, want 1.2.3", run.Tool.Driver.Version)
+	}
+
+	want := []struct {
+		rule, level, message, uri string
+	}{
+		{sarifUntrusted, "error", "a.example.com: not trusted: missing Root A (android<=8)", "a.example.com"},
+		{sarifUpcomingDistrust, "warning", "a.example.com: fails on 2026-04-15: root distrusted (android>=9)", "a.example.com"},
+		{sarifWeakCrypto, "warning", `a.example.com: "Leaf" has RSA-1024 key (android>=9)`, "a.example.com"},
+		{sarifTrustWarning, "note", "a.example.com: only 1 SCT (android>=9)", "a.example.com"},
+		{sarifEndpointError, "error", "b.example.com: connection refused", "b.example.com"},
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("got %d results, want %d:\n%s", len(run.Results), len(want), data)
+	}
+	for i, w := range want {
+		got := run.Results[i]
+		if got.RuleID != w.rule || got.Level != w.level || got.Message.Text != w.message {
+			t.Errorf("results[%d] = %s/%s %q, want %s/%s %q", i, got.RuleID, got.Level, got.Message.Text, w.rule, w.level, w.message)
+		}
+		if run.Tool.Driver.Rules[got.RuleIndex].ID != got.RuleID {
+			t.Errorf("results[%d].ruleIndex %d points at %s", i, got.RuleIndex, run.Tool.Driver.Rules[got.RuleIndex].ID)
+		}
+		if len(got.Locations) != 1 || got.Locations[0].PhysicalLocation.ArtifactLocation.URI != w.uri {
+			t.Errorf("results[%d].locations = %+v, want %s", i, got.Locations, w.uri)
+		}
+		if got.PartialFingerprints["certvetFinding/v1"] == "" {
+			t.Errorf("results[%d] has no partial fingerprint", i)
+		}
+	}
+}
+
+func TestFormatSARIFClean(t *testing.T) {
+	t.Parallel()
+
+	report := &truststore.ValidationReport{
+		Endpoint:  "example.com",
+		AllPassed: true,
+		Results: []truststore.TrustResult{
+			{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "Root A"},
+		},
+	}
+	data, err := NewValidationOutput(report).FormatSARIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A clean run still lists the rules and an empty results array
+	if !strings.Contains(string(data), `"results": []`) || !strings.Contains(string(data), sarifUntrusted) {
+		t.Errorf("unexpected SARIF for clean run:\n%s", data)
+	}
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// SaveChain writes a report's certificates as PEM files under dir/<endpoint>/:
+//
+//	leaf.pem                      server certificate
+//	intermediate-N.pem            presented intermediates (1-based, in server order)
+//	chain.pem                     leaf followed by intermediates, as presented
+//	verified/<platform>-<ver>.pem verified chain per trusted platform version (leaf to root)
+//
+// Reports without a fetched chain (bulk errors) are skipped.
+func SaveChain(dir string, report *truststore.ValidationReport) error {
+	chain := report.Chain
+	if chain.ServerCert == nil {
+		return nil
+	}
+
+	base := filepath.Join(dir, endpointDirName(report.Endpoint))
+	if err := os.MkdirAll(filepath.Join(base, "verified"), 0o755); err != nil { //nolint:gosec // G301: Artifacts are meant to be shared
+		return fmt.Errorf("create chain directory: %w", err)
+	}
+
+	presented := append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...)
+
+	if err := writePEM(filepath.Join(base, "leaf.pem"), chain.ServerCert); err != nil {
+		return err
+	}
+	for i, cert := range chain.Intermediates {
+		if err := writePEM(filepath.Join(base, fmt.Sprintf("intermediate-%d.pem", i+1)), cert); err != nil {
+			return err
+		}
+	}
+	if err := writePEM(filepath.Join(base, "chain.pem"), presented...); err != nil {
+		return err
+	}
+
+	for _, r := range report.Results {
+		if !r.Trusted || len(r.VerifiedChain) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s-%s.pem", r.Platform.Platform, r.Platform.Label())
+		if err := writePEM(filepath.Join(base, "verified", name), r.VerifiedChain...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// endpointDirName converts an endpoint into a safe directory name ("host:8443" -> "host_8443").
+func endpointDirName(endpoint string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '/', '\\':
+			return '_'
+		}
+		return r
+	}, endpoint)
+}
+
+// writePEM writes certificates to path as concatenated PEM blocks.
+func writePEM(path string, certs ...*x509.Certificate) error {
+	var buf []byte
+	for _, cert := range certs {
+		buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	if err := os.WriteFile(path, buf, 0o644); err != nil { //nolint:gosec // G306: Certificates are public
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
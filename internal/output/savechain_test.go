@@ -0,0 +1,107 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func selfSignedCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func countPEMBlocks(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return n
+		}
+		n++
+	}
+}
+
+func TestSaveChain(t *testing.T) {
+	leaf := selfSignedCert(t, "leaf")
+	inter := selfSignedCert(t, "intermediate")
+	root := selfSignedCert(t, "root")
+
+	report := &truststore.ValidationReport{
+		Endpoint: "example.com:8443",
+		Chain:    truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{inter}},
+		Results: []truststore.TrustResult{
+			{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, VerifiedChain: []*x509.Certificate{leaf, inter, root}},
+			{Platform: truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"}, Trusted: false},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := SaveChain(dir, report); err != nil {
+		t.Fatalf("SaveChain: %v", err)
+	}
+
+	base := filepath.Join(dir, "example.com_8443")
+	want := map[string]int{
+		"leaf.pem":            1,
+		"intermediate-1.pem":  1,
+		"chain.pem":           2,
+		"verified/ios-18.pem": 3,
+	}
+	for name, blocks := range want {
+		if got := countPEMBlocks(t, filepath.Join(base, name)); got != blocks {
+			t.Errorf("%s: %d PEM blocks, want %d", name, got, blocks)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(base, "verified", "android-7.pem")); !os.IsNotExist(err) {
+		t.Error("untrusted result should not produce a verified chain file")
+	}
+}
+
+func TestSaveChainSkipsErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := SaveChain(dir, &truststore.ValidationReport{Endpoint: "down.example.com", Error: "timeout"}); err != nil {
+		t.Fatalf("SaveChain: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected no files for failed endpoint, got %d", len(entries))
+	}
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/jsonschema"
+)
+
+// schemas maps command names to the types of their JSON output.
+// validate prints a single report for one endpoint and a bulk report for several.
+var schemas = map[string][]reflect.Type{
+	"validate": {reflect.TypeFor[jsonReport](), reflect.TypeFor[jsonBulkReport]()},
+	"list":     {reflect.TypeFor[[]ListEntry]()},
+}
+
+// SchemaNames lists the commands with a JSON Schema, for help and error messages.
+var SchemaNames = []string{"validate", "list"}
+
+// Schema returns the JSON Schema of a command's JSON output, generated from the
+// structs it is marshaled from.
+func Schema(command string) ([]byte, error) {
+	types, ok := schemas[command]
+	if !ok {
+		return nil, fmt.Errorf("no schema for %q (available: %s)", command, strings.Join(SchemaNames, ", "))
+	}
+	return json.MarshalIndent(jsonschema.Document("certvet "+command, types...), "", "  ")
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range SchemaNames {
+		data, err := Schema(name)
+		if err != nil {
+			t.Fatalf("Schema(%q): %v", name, err)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("Schema(%q) is not JSON: %v", name, err)
+		}
+		if doc["$schema"] == nil || doc["title"] != "certvet "+name {
+			t.Errorf("Schema(%q) header = %v, %v", name, doc["$schema"], doc["title"])
+		}
+	}
+
+	if _, err := Schema("nope"); err == nil {
+		t.Error("expected error for unknown command")
+	}
+}
+
+// TestSchemaCoversOutput checks that every property of real JSON output is declared,
+// so the schema can't silently drift from the structs.
+func TestSchemaCoversOutput(t *testing.T) {
+	t.Parallel()
+
+	data, err := Schema("validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		OneOf []struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"oneOf"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.OneOf) != 2 {
+		t.Fatalf("validate schema has %d alternatives, want 2", len(schema.OneOf))
+	}
+
+	report := &truststore.ValidationReport{
+		Endpoint: "example.com",
+		Results: []truststore.TrustResult{
+			{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "Root"},
+		},
+	}
+	out, err := NewValidationOutput(report).FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for key := range doc {
+		if _, ok := schema.OneOf[0].Properties[key]; !ok {
+			t.Errorf("output property %q missing from schema", key)
+		}
+	}
+	for _, key := range schema.OneOf[0].Required {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("required property %q missing from output", key)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// SearchMatch is a root certificate matching a search with the stores that include it.
+type SearchMatch struct {
+	Fingerprint truststore.Fingerprint
+	Cert        *x509.Certificate
+	Info        truststore.CAInfo  // Operator, country, key and validity of the root
+	Stores      []truststore.Store // Sorted by platform, then version
+}
+
+// SearchOutput implements Formatter for root certificate search results.
+type SearchOutput struct {
+	Query   string
+	Matches []SearchMatch
+	stores  []truststore.Store // All searched stores, to describe version ranges
+}
+
+// NewSearchOutput looks up the matched roots in certs and infos (described from certs if
+// missing) and the stores that include them. Matches are sorted by name, then fingerprint;
+// fingerprints missing from certs are skipped.
+func NewSearchOutput(query string, fps []truststore.Fingerprint, certs *truststore.CertIndex, infos map[truststore.Fingerprint]truststore.CAInfo, stores []truststore.Store) *SearchOutput {
+	o := &SearchOutput{Query: query, stores: stores}
+	index := truststore.NewStoreIndex(stores)
+	for _, fp := range fps {
+		cert := certs.Get(fp)
+		if cert == nil {
+			continue
+		}
+		info, ok := infos[fp]
+		if !ok {
+			info = truststore.NewCAInfo(cert, "")
+		}
+		m := SearchMatch{Fingerprint: fp, Cert: cert, Info: info, Stores: index.Trusting(fp)}
+		sortStores(m.Stores)
+		o.Matches = append(o.Matches, m)
+	}
+	sort.SliceStable(o.Matches, func(i, j int) bool {
+		ni, nj := truststore.CertName(o.Matches[i].Cert), truststore.CertName(o.Matches[j].Cert)
+		if ni != nj {
+			return ni < nj
+		}
+		return o.Matches[i].Fingerprint.String() < o.Matches[j].Fingerprint.String()
+	})
+	return o
+}
+
+// describeStores describes a subset of stores compactly, e.g. "android>=10, ios, windows",
+// with version ranges relative to all stores. subset must be sorted by platform, then version.
+func describeStores(subset, all []truststore.Store) string {
+	versions := make(map[truststore.Platform][]string)
+	for _, s := range sortedStores(all) {
+		versions[s.Platform] = append(versions[s.Platform], s.Version)
+	}
+
+	var parts []string
+	for i := 0; i < len(subset); {
+		p := subset[i].Platform
+		var matched []string
+		for ; i < len(subset) && subset[i].Platform == p; i++ {
+			matched = append(matched, subset[i].Version)
+		}
+		parts = append(parts, versionRange(p, matched, versions[p]))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedStores returns a copy of stores sorted by platform, then version.
+func sortedStores(stores []truststore.Store) []truststore.Store {
+	sorted := append([]truststore.Store(nil), stores...)
+	sortStores(sorted)
+	return sorted
+}
+
+// FormatText formats matches as a table.
+// Header: FINGERPRINT, NAME, ORGANIZATION, PLATFORMS
+func (o *SearchOutput) FormatText() string {
+	if len(o.Matches) == 0 {
+		return fmt.Sprintf("No root certificate matches %q", o.Query)
+	}
+
+	tw := NewTableWriter()
+	tw.Header("FINGERPRINT", "NAME", "ORGANIZATION", "PLATFORMS")
+	for _, m := range o.Matches {
+		org := "-"
+		if len(m.Cert.Subject.Organization) > 0 {
+			org = strings.Join(m.Cert.Subject.Organization, ", ")
+		}
+		name := truststore.CertName(m.Cert)
+		if name == "" {
+			name = "-"
+		}
+		tw.Row(m.Fingerprint.Truncate(4), name, org, describeStores(m.Stores, o.stores))
+	}
+	return tw.String()
+}
+
+// FormatJSON formats matches with their subject, SPKI hash and the stores that include them.
+func (o *SearchOutput) FormatJSON() ([]byte, error) {
+	out := []jsonSearchMatch{}
+	for _, m := range o.Matches {
+		spki := truststore.SPKIFingerprint(m.Cert)
+		jm := jsonSearchMatch{
+			Fingerprint: m.Fingerprint.String(),
+			Name:        truststore.CertName(m.Cert),
+			Subject:     m.Cert.Subject.String(),
+			SPKISHA256:  base64.StdEncoding.EncodeToString(spki[:]),
+			NotBefore:   m.Cert.NotBefore.UTC().Format(jsonTimeFormat),
+			NotAfter:    m.Cert.NotAfter.UTC().Format(jsonTimeFormat),
+			Operator:    m.Info.Operator,
+			Country:     m.Info.Country,
+			KeyType:     m.Info.KeyType,
+			KeySize:     m.Info.KeySize,
+			Stores:      []jsonPlatformVersion{},
+		}
+		for _, s := range m.Stores {
+			jm.Stores = append(jm.Stores, jsonPlatformVersion{Platform: string(s.Platform), Version: s.Version})
+		}
+		out = append(out, jm)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonSearchMatch struct {
+	Fingerprint string                `json:"fingerprint"`
+	Name        string                `json:"name"`
+	Subject     string                `json:"subject"`
+	SPKISHA256  string                `json:"spki_sha256"` // Base64, as in pin-sha256
+	NotBefore   string                `json:"not_before"`
+	NotAfter    string                `json:"not_after"`
+	Operator    string                `json:"operator,omitempty"` // CA owner from CCADB
+	Country     string                `json:"country,omitempty"`
+	KeyType     string                `json:"key_type"`
+	KeySize     int                   `json:"key_size,omitempty"`
+	Stores      []jsonPlatformVersion `json:"stores"`
+}
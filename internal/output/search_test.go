@@ -0,0 +1,67 @@
+package output
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestSearchOutput(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, alpha := pathTestCert(t, "Beta Root", "Beta Root", key, key), pathTestCert(t, "Alpha Root", "Alpha Root", key, key)
+	fpBeta, fpAlpha := truststore.FingerprintFromCert(beta), truststore.FingerprintFromCert(alpha)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fpBeta, beta)
+	certs.Add(fpAlpha, alpha)
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformAndroid, Version: "7", Fingerprints: []truststore.Fingerprint{fpAlpha}},
+		{Platform: truststore.PlatformAndroid, Version: "10", Fingerprints: []truststore.Fingerprint{fpAlpha, fpBeta}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fpAlpha}},
+	}
+	infos := map[truststore.Fingerprint]truststore.CAInfo{fpAlpha: {Fingerprint: fpAlpha, Operator: "Alpha Trust", Country: "ES", KeyType: "RSA", KeySize: 4096}}
+	o := NewSearchOutput("root", []truststore.Fingerprint{fpBeta, fpAlpha, {1}}, certs, infos, stores)
+
+	if len(o.Matches) != 2 || o.Matches[0].Fingerprint != fpAlpha {
+		t.Fatalf("matches = %+v, want Alpha then Beta (unknown fingerprint skipped)", o.Matches)
+	}
+	text := o.FormatText()
+	for _, want := range []string{"Alpha Root", "android, ios", "Beta Root", "android>=10"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []jsonSearchMatch
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got[0].Stores) != 3 || got[1].SPKISHA256 == "" {
+		t.Errorf("json = %+v", got)
+	}
+	// Roots without CA info are described from their certificate
+	if got[0].Operator != "Alpha Trust" || got[0].Country != "ES" || got[1].Operator != "" || got[1].KeyType != "ECDSA" || got[1].KeySize != 256 {
+		t.Errorf("json CA info = %+v", got)
+	}
+
+	if none := NewSearchOutput("zzz", nil, certs, nil, stores).FormatText(); none != `No root certificate matches "zzz"` {
+		t.Errorf("no match: %q", none)
+	}
+}
@@ -0,0 +1,214 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// DataStats describes the loaded trust data as a whole.
+type DataStats struct {
+	Source       string // Where the data was loaded from (e.g., "embedded")
+	Snapshot     string // Newest changelog snapshot date (empty if unknown)
+	Certificates int    // Root certificates with PEM data
+	CrossSigns   int    // Cross-signed intermediates
+	CTLogs       int
+}
+
+// PlatformStats summarizes one platform's stores.
+type PlatformStats struct {
+	Platform truststore.Platform
+	Versions int
+	Roots    int                      // Distinct roots across versions
+	Unique   []truststore.Fingerprint // Roots no other platform includes, sorted by name
+}
+
+// StatsOutput implements Formatter for trust store statistics.
+type StatsOutput struct {
+	Data        DataStats
+	Stores      []truststore.Store // Sorted by platform, then version
+	Platforms   []PlatformStats
+	Roots       int            // Distinct roots across stores
+	Constrained map[string]int // Distinct roots per constraint kind (Plan* constants)
+	name        func(truststore.Fingerprint) string
+}
+
+// NewStatsOutput computes per-platform and per-version counts over stores.
+func NewStatsOutput(stores []truststore.Store, data DataStats, name func(truststore.Fingerprint) string) *StatsOutput {
+	o := &StatsOutput{Data: data, Stores: sortedStores(stores), Constrained: make(map[string]int), name: name}
+
+	platformsOf := make(map[truststore.Fingerprint]map[truststore.Platform]bool)
+	constrained := map[string]map[truststore.Fingerprint]bool{
+		PlanDistrust: {}, PlanNotBeforeMax: {}, PlanSCTNotAfter: {},
+	}
+	for _, s := range o.Stores {
+		if len(o.Platforms) == 0 || o.Platforms[len(o.Platforms)-1].Platform != s.Platform {
+			o.Platforms = append(o.Platforms, PlatformStats{Platform: s.Platform})
+		}
+		o.Platforms[len(o.Platforms)-1].Versions++
+		for _, fp := range s.Fingerprints {
+			if platformsOf[fp] == nil {
+				platformsOf[fp] = make(map[truststore.Platform]bool)
+			}
+			platformsOf[fp][s.Platform] = true
+		}
+		for fp, c := range s.Constraints {
+			if c.DistrustDate != nil {
+				constrained[PlanDistrust][fp] = true
+			}
+			if c.NotBeforeMax != nil {
+				constrained[PlanNotBeforeMax][fp] = true
+			}
+			if c.SCTNotAfter != nil {
+				constrained[PlanSCTNotAfter][fp] = true
+			}
+		}
+	}
+	for kind, fps := range constrained {
+		o.Constrained[kind] = len(fps)
+	}
+
+	o.Roots = len(platformsOf)
+	for i := range o.Platforms {
+		p := &o.Platforms[i]
+		for fp, platforms := range platformsOf {
+			if !platforms[p.Platform] {
+				continue
+			}
+			p.Roots++
+			if len(platforms) == 1 {
+				p.Unique = append(p.Unique, fp)
+			}
+		}
+		sortByName(p.Unique, name)
+	}
+	return o
+}
+
+// sortByName orders fingerprints by root name, then fingerprint.
+func sortByName(fps []truststore.Fingerprint, name func(truststore.Fingerprint) string) {
+	sort.Slice(fps, func(i, j int) bool {
+		if ni, nj := name(fps[i]), name(fps[j]); ni != nj {
+			return ni < nj
+		}
+		return fps[i].String() < fps[j].String()
+	})
+}
+
+// constrainedCount counts roots with any constraint in a store.
+func constrainedCount(s truststore.Store) int {
+	n := 0
+	for _, c := range s.Constraints {
+		if !c.IsEmpty() {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatText formats data totals, a table per platform and a table per platform version.
+func (o *StatsOutput) FormatText() string {
+	var b strings.Builder
+	snapshot := o.Data.Snapshot
+	if snapshot == "" {
+		snapshot = "unknown"
+	}
+	fmt.Fprintf(&b, "Data:         %s, snapshot %s\n", o.Data.Source, snapshot)
+	fmt.Fprintf(&b, "Certificates: %d roots, %d cross-signed intermediates, %d CT logs\n",
+		o.Data.Certificates, o.Data.CrossSigns, o.Data.CTLogs)
+	fmt.Fprintf(&b, "Stores:       %d platform versions, %d distinct roots\n", len(o.Stores), o.Roots)
+	fmt.Fprintf(&b, "Constraints:  %d roots distrusted, %d with issuance cutoffs, %d with SCT cutoffs\n\n",
+		o.Constrained[PlanDistrust], o.Constrained[PlanNotBeforeMax], o.Constrained[PlanSCTNotAfter])
+
+	tw := NewTableWriter()
+	tw.Header("PLATFORM", "VERSIONS", "ROOTS", "UNIQUE")
+	for _, p := range o.Platforms {
+		tw.Row(string(p.Platform), strconv.Itoa(p.Versions), strconv.Itoa(p.Roots), strconv.Itoa(len(p.Unique)))
+	}
+	b.WriteString(tw.String())
+	b.WriteString("\n\n")
+
+	tw = NewTableWriter()
+	tw.Header("PLATFORM", "VERSION", "ROOTS", "CONSTRAINED")
+	for _, s := range o.Stores {
+		tw.Row(string(s.Platform), s.Version, strconv.Itoa(len(s.Fingerprints)), strconv.Itoa(constrainedCount(s)))
+	}
+	b.WriteString(tw.String())
+	return b.String()
+}
+
+// FormatJSON formats the statistics, listing unique roots by fingerprint and name.
+func (o *StatsOutput) FormatJSON() ([]byte, error) {
+	out := jsonStats{
+		Source:       o.Data.Source,
+		Snapshot:     o.Data.Snapshot,
+		Certificates: o.Data.Certificates,
+		CrossSigns:   o.Data.CrossSigns,
+		CTLogs:       o.Data.CTLogs,
+		Roots:        o.Roots,
+		Constraints: jsonStatsConstraints{
+			Distrust:     o.Constrained[PlanDistrust],
+			NotBeforeMax: o.Constrained[PlanNotBeforeMax],
+			SCTNotAfter:  o.Constrained[PlanSCTNotAfter],
+		},
+		Platforms: []jsonPlatformStats{},
+		Stores:    []jsonStoreStats{},
+	}
+	for _, p := range o.Platforms {
+		jp := jsonPlatformStats{Platform: string(p.Platform), Versions: p.Versions, Roots: p.Roots, UniqueRoots: []jsonStatsRoot{}}
+		for _, fp := range p.Unique {
+			jp.UniqueRoots = append(jp.UniqueRoots, jsonStatsRoot{Fingerprint: fp.String(), Name: o.name(fp)})
+		}
+		out.Platforms = append(out.Platforms, jp)
+	}
+	for _, s := range o.Stores {
+		out.Stores = append(out.Stores, jsonStoreStats{
+			Platform:    string(s.Platform),
+			Version:     s.Version,
+			Roots:       len(s.Fingerprints),
+			Constrained: constrainedCount(s),
+		})
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonStats struct {
+	Source       string               `json:"source"`
+	Snapshot     string               `json:"snapshot,omitempty"`
+	Certificates int                  `json:"certificates"`
+	CrossSigns   int                  `json:"cross_signs"`
+	CTLogs       int                  `json:"ct_logs"`
+	Roots        int                  `json:"roots"`
+	Constraints  jsonStatsConstraints `json:"constraints"`
+	Platforms    []jsonPlatformStats  `json:"platforms"`
+	Stores       []jsonStoreStats     `json:"stores"`
+}
+
+type jsonStatsConstraints struct {
+	Distrust     int `json:"distrust"`
+	NotBeforeMax int `json:"not_before_max"`
+	SCTNotAfter  int `json:"sct_not_after"`
+}
+
+type jsonPlatformStats struct {
+	Platform    string          `json:"platform"`
+	Versions    int             `json:"versions"`
+	Roots       int             `json:"roots"`
+	UniqueRoots []jsonStatsRoot `json:"unique_roots"`
+}
+
+type jsonStatsRoot struct {
+	Fingerprint string `json:"fingerprint"`
+	Name        string `json:"name"`
+}
+
+type jsonStoreStats struct {
+	Platform    string `json:"platform"`
+	Version     string `json:"version"`
+	Roots       int    `json:"roots"`
+	Constrained int    `json:"constrained"`
+}
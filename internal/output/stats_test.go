@@ -0,0 +1,62 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestStatsOutput(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := truststore.Fingerprint{1}, truststore.Fingerprint{2}, truststore.Fingerprint{3}
+	names := map[truststore.Fingerprint]string{a: "Alpha Root", b: "Beta Root", c: "Gamma Root"}
+	distrust := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{a, b}},
+		{Platform: truststore.PlatformAndroid, Version: "10", Fingerprints: []truststore.Fingerprint{a}},
+		{Platform: truststore.PlatformAndroid, Version: "7", Fingerprints: []truststore.Fingerprint{a, c},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{c: {DistrustDate: &distrust, SCTNotAfter: &distrust}}},
+	}
+	o := NewStatsOutput(stores, DataStats{Source: "embedded", Certificates: 3, CTLogs: 2},
+		func(fp truststore.Fingerprint) string { return names[fp] })
+
+	if o.Roots != 3 || len(o.Platforms) != 2 {
+		t.Fatalf("roots = %d, platforms = %+v", o.Roots, o.Platforms)
+	}
+	android := o.Platforms[0]
+	if android.Platform != truststore.PlatformAndroid || android.Versions != 2 || android.Roots != 2 ||
+		len(android.Unique) != 1 || android.Unique[0] != c {
+		t.Errorf("android stats = %+v, want 2 versions, 2 roots, Gamma unique", android)
+	}
+	if o.Constrained[PlanDistrust] != 1 || o.Constrained[PlanSCTNotAfter] != 1 || o.Constrained[PlanNotBeforeMax] != 0 {
+		t.Errorf("constrained = %v", o.Constrained)
+	}
+
+	text := o.FormatText()
+	for _, want := range []string{"embedded, snapshot unknown", "3 platform versions, 3 distinct roots", "1 roots distrusted", "UNIQUE", "CONSTRAINED"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+	// Versions are listed in ascending order
+	if strings.Index(text, "android    7") > strings.Index(text, "android    10") {
+		t.Errorf("versions not sorted:\n%s", text)
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonStats
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Roots != 3 || got.Constraints.Distrust != 1 || len(got.Stores) != 3 || got.Stores[0].Version != "7" ||
+		got.Stores[0].Constrained != 1 || got.Platforms[0].UniqueRoots[0].Name != "Gamma Root" {
+		t.Errorf("json = %+v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// StoreDiffOutput implements Formatter for the root differences between two trust stores.
+type StoreDiffOutput struct {
+	From, To    truststore.Store
+	Added       []changelog.Root
+	Removed     []changelog.Root
+	Constrained []changelog.Root // With To's constraints (see previous for From's)
+}
+
+// NewStoreDiffOutput compares to against from. name resolves root display names.
+func NewStoreDiffOutput(from, to truststore.Store, name func(truststore.Fingerprint) string) *StoreDiffOutput {
+	d := &StoreDiffOutput{From: from, To: to}
+	d.Added, d.Removed, d.Constrained = changelog.DiffRoots(from, to, name)
+	return d
+}
+
+// previous returns a constrained root as it appears in From.
+func (d *StoreDiffOutput) previous(r changelog.Root) changelog.Root {
+	fp, err := truststore.ParseFingerprint(r.Fingerprint)
+	if err != nil {
+		return changelog.Root{}
+	}
+	return changelog.NewRoot(fp, d.From.ConstraintFor(fp), func(truststore.Fingerprint) string { return r.Name })
+}
+
+// FormatText formats a summary line and one row per differing root.
+// Header: CHANGE, FINGERPRINT, ROOT, CONSTRAINTS
+func (d *StoreDiffOutput) FormatText() string {
+	summary := fmt.Sprintf("%s %s -> %s %s: %d added, %d removed, %d constrained",
+		d.From.Platform, d.From.Version, d.To.Platform, d.To.Version,
+		len(d.Added), len(d.Removed), len(d.Constrained))
+	if len(d.Added)+len(d.Removed)+len(d.Constrained) == 0 {
+		return summary
+	}
+
+	constraints := func(r changelog.Root, none string) string {
+		if c := rootConstraints(r); c != "" {
+			return c
+		}
+		return none
+	}
+	name := func(r changelog.Root) string {
+		if r.Name == "" {
+			return "-"
+		}
+		return r.Name
+	}
+
+	tw := NewTableWriter()
+	tw.Header("CHANGE", "FINGERPRINT", "ROOT", "CONSTRAINTS")
+	for _, r := range d.Added {
+		tw.Row("added", shortFingerprint(r.Fingerprint), name(r), constraints(r, "-"))
+	}
+	for _, r := range d.Removed {
+		tw.Row("removed", shortFingerprint(r.Fingerprint), name(r), constraints(r, "-"))
+	}
+	for _, r := range d.Constrained {
+		tw.Row("constrained", shortFingerprint(r.Fingerprint), name(r),
+			constraints(d.previous(r), "none")+" -> "+constraints(r, "none"))
+	}
+	return summary + "\n\n" + tw.String()
+}
+
+// FormatJSON formats the differences as JSON. Constrained roots carry both stores' constraints.
+func (d *StoreDiffOutput) FormatJSON() ([]byte, error) {
+	out := jsonStoreDiff{
+		From:        jsonPlatformVersion{Platform: string(d.From.Platform), Version: d.From.Version},
+		To:          jsonPlatformVersion{Platform: string(d.To.Platform), Version: d.To.Version},
+		Added:       nonNilRoots(d.Added),
+		Removed:     nonNilRoots(d.Removed),
+		Constrained: []jsonConstrainedRoot{},
+	}
+	for _, r := range d.Constrained {
+		prev := d.previous(r)
+		out.Constrained = append(out.Constrained, jsonConstrainedRoot{
+			Root: r,
+			Previous: jsonRootConstraints{
+				NotBeforeMax: prev.NotBeforeMax,
+				DistrustDate: prev.DistrustDate,
+				SCTNotAfter:  prev.SCTNotAfter,
+			},
+		})
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonStoreDiff struct {
+	From        jsonPlatformVersion   `json:"from"`
+	To          jsonPlatformVersion   `json:"to"`
+	Added       []changelog.Root      `json:"added"`
+	Removed     []changelog.Root      `json:"removed"`
+	Constrained []jsonConstrainedRoot `json:"constrained"`
+}
+
+type jsonConstrainedRoot struct {
+	changelog.Root
+	Previous jsonRootConstraints `json:"previous"` // Constraints in the "from" store
+}
+
+type jsonRootConstraints struct {
+	NotBeforeMax string `json:"not_before_max,omitempty"`
+	DistrustDate string `json:"distrust_date,omitempty"`
+	SCTNotAfter  string `json:"sct_not_after,omitempty"`
+}
+
+// nonNilRoots returns roots, or an empty slice so JSON shows [] instead of null.
+func nonNilRoots(roots []changelog.Root) []changelog.Root {
+	if roots == nil {
+		return []changelog.Root{}
+	}
+	return roots
+}
@@ -0,0 +1,55 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestStoreDiffOutput(t *testing.T) {
+	t.Parallel()
+
+	distrust := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	kept, dropped, added := truststore.Fingerprint{1}, truststore.Fingerprint{2}, truststore.Fingerprint{3}
+	from := truststore.Store{Platform: truststore.PlatformChrome, Version: "current", Fingerprints: []truststore.Fingerprint{kept, dropped}}
+	to := truststore.Store{
+		Platform:     truststore.PlatformWindows,
+		Version:      "current",
+		Fingerprints: []truststore.Fingerprint{kept, added},
+		Constraints:  map[truststore.Fingerprint]truststore.Constraints{kept: {DistrustDate: &distrust}},
+	}
+	names := map[truststore.Fingerprint]string{kept: "Kept Root", dropped: "Dropped Root", added: "Added Root"}
+	d := NewStoreDiffOutput(from, to, func(fp truststore.Fingerprint) string { return names[fp] })
+
+	text := d.FormatText()
+	for _, want := range []string{
+		"chrome current -> windows current: 1 added, 1 removed, 1 constrained",
+		"Added Root",
+		"Dropped Root",
+		"none -> DT:2026-06-01",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := d.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonStoreDiff
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Constrained) != 1 || got.Constrained[0].DistrustDate == "" || got.Constrained[0].Previous.DistrustDate != "" {
+		t.Errorf("constrained = %+v, want distrust date set only in the to store", got.Constrained)
+	}
+
+	same := NewStoreDiffOutput(from, from, func(truststore.Fingerprint) string { return "" }).FormatText()
+	if !strings.HasSuffix(same, "0 added, 0 removed, 0 constrained") {
+		t.Errorf("identical stores: %q", same)
+	}
+}
@@ -0,0 +1,266 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// reasonUnknownAuthority is the failure reason produced when no trusted root anchors the chain.
+const reasonUnknownAuthority = "certificate signed by unknown authority"
+
+// FailureGroup aggregates endpoints that fail the same platform versions for the same reason.
+type FailureGroup struct {
+	Platforms string   // Failing versions, e.g. "android<=9" or "ios 15-16"
+	Reason    string   // Failure rule, e.g. "missing ISRG Root X1"
+	Endpoints []string // Affected endpoints, in report order
+}
+
+// SummarizeFailures groups failing results of all reports by (platform version range, reason).
+// Groups are ordered by number of endpoints (descending), then platforms and reason.
+// Results must be sorted (see sortResults).
+func SummarizeFailures(reports []*truststore.ValidationReport) []FailureGroup {
+	type groupKey struct{ platforms, reason string }
+	groups := make(map[groupKey]*FailureGroup)
+	var order []*FailureGroup
+
+	for _, report := range reports {
+		if report.Error != "" {
+			continue
+		}
+		for _, f := range endpointFailures(report.Results) {
+			key := groupKey{f.Platforms, f.Reason}
+			g, ok := groups[key]
+			if !ok {
+				g = &FailureGroup{Platforms: f.Platforms, Reason: f.Reason}
+				groups[key] = g
+				order = append(order, g)
+			}
+			g.Endpoints = append(g.Endpoints, report.Endpoint)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if len(a.Endpoints) != len(b.Endpoints) {
+			return len(a.Endpoints) > len(b.Endpoints)
+		}
+		if a.Platforms != b.Platforms {
+			return a.Platforms < b.Platforms
+		}
+		return a.Reason < b.Reason
+	})
+
+	summary := make([]FailureGroup, len(order))
+	for i, g := range order {
+		summary[i] = *g
+	}
+	return summary
+}
+
+// endpointFailures groups one endpoint's failing results by (platform, reason).
+func endpointFailures(results []truststore.TrustResult) []FailureGroup {
+	type failKey struct {
+		platform truststore.Platform
+		reason   string
+	}
+	failing := make(map[failKey][]string)
+	var keys []failKey
+	versions := make(map[truststore.Platform][]string)
+	anchors := make(map[truststore.Platform]string)
+	var anchor string
+
+	for _, r := range results {
+		p := r.Platform.Platform
+		if r.Platform.ExtraRoots {
+			p += "+extra" // Devices with extra roots are grouped separately from stock ones
+		}
+		versions[p] = append(versions[p], r.Platform.Version)
+		if r.Trusted {
+			if anchors[p] == "" {
+				anchors[p] = r.MatchedCA
+			}
+			if anchor == "" {
+				anchor = r.MatchedCA
+			}
+			continue
+		}
+		key := failKey{p, r.FailureReason}
+		if _, ok := failing[key]; !ok {
+			keys = append(keys, key)
+		}
+		failing[key] = append(failing[key], r.Platform.Version)
+	}
+
+	out := make([]FailureGroup, len(keys))
+	for i, key := range keys {
+		reason := key.reason
+		// Name the root that anchors the chain elsewhere, preferring the same platform
+		if reason == reasonUnknownAuthority {
+			if root := anchors[key.platform]; root != "" {
+				reason = "missing " + root
+			} else if anchor != "" {
+				reason = "missing " + anchor
+			}
+		}
+		out[i] = FailureGroup{
+			Platforms: versionRange(key.platform, failing[key], versions[key.platform]),
+			Reason:    reason,
+		}
+	}
+	return out
+}
+
+// versionRange describes a subset of a platform's sorted versions compactly:
+// "android" (all), "android<=9", "android>=12", "android 8-10" (contiguous)
+// or "android 7, 9" otherwise.
+func versionRange(platform truststore.Platform, subset, all []string) string {
+	name := string(platform)
+	if len(subset) == len(all) {
+		return name
+	}
+
+	first := indexOf(all, subset[0])
+	contiguous := first >= 0
+	for i, v := range subset {
+		if first+i >= len(all) || all[first+i] != v {
+			contiguous = false
+			break
+		}
+	}
+
+	last := subset[len(subset)-1]
+	switch {
+	case !contiguous:
+		return name + " " + strings.Join(subset, ", ")
+	case first == 0:
+		return name + "<=" + last
+	case first+len(subset) == len(all):
+		return name + ">=" + subset[0]
+	case len(subset) == 1:
+		return name + "=" + subset[0]
+	default:
+		return name + " " + subset[0] + "-" + last
+	}
+}
+
+func indexOf(values []string, v string) int {
+	for i, s := range values {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatSummaryTable renders endpoint counts and failure groups.
+func formatSummaryTable(reports []*truststore.ValidationReport, groups []FailureGroup) string {
+	var passed, failed, errored int
+	for _, r := range reports {
+		switch {
+		case r.Error != "":
+			errored++
+		case r.AllPassed:
+			passed++
+		default:
+			failed++
+		}
+	}
+
+	out := fmt.Sprintf("Endpoints: %d passed, %d failed, %d errors\n", passed, failed, errored)
+	if len(groups) == 0 {
+		return out
+	}
+
+	tw := NewTableWriter()
+	tw.Header("ENDPOINTS", "PLATFORMS", "REASON")
+	for _, g := range groups {
+		tw.Row(strconv.Itoa(len(g.Endpoints)), g.Platforms, g.Reason)
+	}
+	return out + "\n" + tw.String()
+}
+
+// RootUsage counts endpoints and platforms whose verified chains a root anchored.
+type RootUsage struct {
+	Name        string
+	Fingerprint truststore.Fingerprint
+	Endpoints   int
+	Platforms   []truststore.Platform // Sorted
+}
+
+// SummarizeRoots reports which roots anchored trusted chains across reports.
+// Roots are ordered by number of endpoints (descending), then name.
+func SummarizeRoots(reports []*truststore.ValidationReport) []RootUsage {
+	usage := make(map[truststore.Fingerprint]*RootUsage)
+	platforms := make(map[truststore.Fingerprint]map[truststore.Platform]bool)
+
+	for _, report := range reports {
+		counted := make(map[truststore.Fingerprint]bool)
+		for _, r := range report.Results {
+			if !r.Trusted || len(r.VerifiedChain) == 0 {
+				continue
+			}
+			root := r.VerifiedChain[len(r.VerifiedChain)-1]
+			fp := truststore.FingerprintFromCert(root)
+			u, ok := usage[fp]
+			if !ok {
+				u = &RootUsage{Name: truststore.CertName(root), Fingerprint: fp}
+				usage[fp] = u
+				platforms[fp] = make(map[truststore.Platform]bool)
+			}
+			if !counted[fp] {
+				counted[fp] = true
+				u.Endpoints++
+			}
+			platforms[fp][r.Platform.Platform] = true
+		}
+	}
+
+	roots := make([]RootUsage, 0, len(usage))
+	for fp, u := range usage {
+		for p := range platforms[fp] {
+			u.Platforms = append(u.Platforms, p)
+		}
+		sort.Slice(u.Platforms, func(i, j int) bool { return u.Platforms[i] < u.Platforms[j] })
+		roots = append(roots, *u)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if roots[i].Endpoints != roots[j].Endpoints {
+			return roots[i].Endpoints > roots[j].Endpoints
+		}
+		if roots[i].Name != roots[j].Name {
+			return roots[i].Name < roots[j].Name
+		}
+		return roots[i].Fingerprint.String() < roots[j].Fingerprint.String()
+	})
+	return roots
+}
+
+// formatRootTable renders root usage (empty if no chain was trusted).
+func formatRootTable(roots []RootUsage, r Redaction) string {
+	if len(roots) == 0 {
+		return ""
+	}
+
+	tw := NewTableWriter()
+	tw.Header("ROOT", "FINGERPRINT", "ENDPOINTS", "PLATFORMS")
+	for _, u := range roots {
+		fp := u.Fingerprint.Truncate(4)
+		if r.FingerprintOctets > 0 {
+			fp = r.fingerprint(u.Fingerprint)
+		}
+		tw.Row(r.name(u.Name), fp, strconv.Itoa(u.Endpoints), joinPlatforms(u.Platforms))
+	}
+	return "\n" + tw.String()
+}
+
+func joinPlatforms(platforms []truststore.Platform) string {
+	names := make([]string, len(platforms))
+	for i, p := range platforms {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,143 @@
+package output
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestVersionRange(t *testing.T) {
+	all := []string{"7", "8", "9", "10", "11"}
+
+	tests := []struct {
+		subset []string
+		want   string
+	}{
+		{all, "android"},
+		{[]string{"7", "8", "9"}, "android<=9"},
+		{[]string{"10", "11"}, "android>=10"},
+		{[]string{"8", "9"}, "android 8-9"},
+		{[]string{"9"}, "android=9"},
+		{[]string{"7", "9"}, "android 7, 9"},
+	}
+
+	for _, tt := range tests {
+		if got := versionRange(truststore.PlatformAndroid, tt.subset, all); got != tt.want {
+			t.Errorf("versionRange(%v) = %q, want %q", tt.subset, got, tt.want)
+		}
+	}
+}
+
+func summaryTestReports() []*truststore.ValidationReport {
+	results := func(failUpTo int) []truststore.TrustResult {
+		var out []truststore.TrustResult
+		for i, v := range []string{"7", "8", "9", "10"} {
+			r := truststore.TrustResult{Platform: truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: v}}
+			if i < failUpTo {
+				r.FailureReason = reasonUnknownAuthority
+			} else {
+				r.Trusted = true
+				r.MatchedCA = "ISRG Root X1"
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+	return []*truststore.ValidationReport{
+		{Endpoint: "a.example.com", Results: results(3)},
+		{Endpoint: "b.example.com", Results: results(3)},
+		{Endpoint: "c.example.com", Results: results(1)},
+		{Endpoint: "d.example.com", Results: results(0), AllPassed: true},
+		{Endpoint: "e.example.com", Error: "connection refused"},
+	}
+}
+
+func TestSummarizeFailures(t *testing.T) {
+	groups := SummarizeFailures(summaryTestReports())
+
+	want := []FailureGroup{
+		{Platforms: "android<=9", Reason: "missing ISRG Root X1", Endpoints: []string{"a.example.com", "b.example.com"}},
+		{Platforms: "android<=7", Reason: "missing ISRG Root X1", Endpoints: []string{"c.example.com"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(groups), len(want), groups)
+	}
+	for i := range want {
+		if groups[i].Platforms != want[i].Platforms || groups[i].Reason != want[i].Reason ||
+			strings.Join(groups[i].Endpoints, ",") != strings.Join(want[i].Endpoints, ",") {
+			t.Errorf("group %d = %+v, want %+v", i, groups[i], want[i])
+		}
+	}
+}
+
+func TestBulkValidationOutputSummary(t *testing.T) {
+	bo := NewBulkValidationOutput(summaryTestReports())
+	bo.Summary = true
+
+	out := bo.FormatText()
+	for _, want := range []string{"Endpoints: 1 passed, 3 failed, 1 errors", "ENDPOINTS", "android<=9", "missing ISRG Root X1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "a.example.com") {
+		t.Errorf("summary should not list individual endpoints:\n%s", out)
+	}
+
+	data, err := bo.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Summary []struct {
+			Platforms string `json:"platforms"`
+			Count     int    `json:"count"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Summary) != 2 || parsed.Summary[0].Count != 2 {
+		t.Errorf("unexpected JSON summary: %+v", parsed.Summary)
+	}
+}
+
+func TestSummarizeRoots(t *testing.T) {
+	rootA := &x509.Certificate{Raw: []byte("root a"), Subject: pkix.Name{CommonName: "Root A"}}
+	rootB := &x509.Certificate{Raw: []byte("root b"), Subject: pkix.Name{CommonName: "Root B"}}
+	trusted := func(p truststore.Platform, root *x509.Certificate) truststore.TrustResult {
+		return truststore.TrustResult{
+			Platform:      truststore.PlatformVersion{Platform: p, Version: "1"},
+			Trusted:       true,
+			VerifiedChain: []*x509.Certificate{{}, root},
+		}
+	}
+
+	reports := []*truststore.ValidationReport{
+		{Endpoint: "a", Results: []truststore.TrustResult{trusted(truststore.PlatformIOS, rootA), trusted(truststore.PlatformAndroid, rootA)}},
+		{Endpoint: "b", Results: []truststore.TrustResult{trusted(truststore.PlatformIOS, rootA), trusted(truststore.PlatformChrome, rootB)}},
+		{Endpoint: "c", Error: "timeout"},
+	}
+
+	roots := SummarizeRoots(reports)
+	if len(roots) != 2 {
+		t.Fatalf("got %d roots, want 2: %+v", len(roots), roots)
+	}
+	if roots[0].Name != "Root A" || roots[0].Endpoints != 2 || len(roots[0].Platforms) != 2 {
+		t.Errorf("roots[0] = %+v, want Root A on 2 endpoints and 2 platforms", roots[0])
+	}
+	if roots[1].Name != "Root B" || roots[1].Endpoints != 1 {
+		t.Errorf("roots[1] = %+v, want Root B on 1 endpoint", roots[1])
+	}
+
+	bo := NewBulkValidationOutput(reports)
+	bo.Summary = true
+	out := bo.FormatText()
+	if !strings.Contains(out, "ROOT") || !strings.Contains(out, "android,ios") {
+		t.Errorf("summary missing root table:\n%s", out)
+	}
+}
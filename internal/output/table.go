@@ -1,44 +1,196 @@
 package output
 
 import (
+	"bufio"
 	"bytes"
+	"io"
+	"iter"
 	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 )
 
+// Color is an ANSI terminal color for table cells.
+type Color int
+
+const (
+	ColorNone Color = iota
+	ColorGreen
+	ColorRed
+	ColorYellow
+)
+
+// Colors enables ANSI colors and status icons in tables. The CLI sets it when
+// stdout is a terminal, unless --no-color or NO_COLOR is given.
+var Colors bool
+
+// ansiColors are the escape sequences starting each color. All have the same length
+// so that colored and plain cells keep the same alignment (see TableWriter.String).
+var ansiColors = map[Color]string{
+	ColorNone:   "\x1b[39m",
+	ColorGreen:  "\x1b[32m",
+	ColorRed:    "\x1b[31m",
+	ColorYellow: "\x1b[33m",
+}
+
+const ansiReset = "\x1b[0m"
+
+// statusStyles colors status values in any column, with an icon for validation outcomes.
+var statusStyles = map[string]struct {
+	color Color
+	icon  string
+}{
+	"PASS":     {ColorGreen, "✓"},
+	"OK":       {ColorGreen, ""},
+	"WARN":     {ColorYellow, "!"},
+	"WARNING":  {ColorYellow, ""},
+	"FAIL":     {ColorRed, "✗"},
+	"ERROR":    {ColorRed, "✗"},
+	"MISMATCH": {ColorRed, ""},
+}
+
+// tableCell is a cell value with its color.
+type tableCell struct {
+	text  string
+	color Color
+}
+
 // TableWriter provides kubectl-style aligned column output using text/tabwriter.
+// With colors enabled, status values (PASS, FAIL, WARN, ...) and cells matched by
+// column colorers are colored.
 type TableWriter struct {
-	buf     bytes.Buffer
-	w       *tabwriter.Writer
-	hasData bool
+	rows     [][]tableCell
+	colorers map[int]func(string) Color
+	colors   bool
 }
 
 // NewTableWriter creates a new TableWriter with standard kubectl-style settings.
 // Settings: minwidth=0, tabwidth=0, padding=3, padchar=' ', flags=0
 func NewTableWriter() *TableWriter {
-	t := &TableWriter{}
-	t.w = tabwriter.NewWriter(&t.buf, 0, 0, 3, ' ', 0)
-	return t
+	return &TableWriter{colors: Colors}
 }
 
 // Header writes the header row with the given column names.
 func (t *TableWriter) Header(columns ...string) {
-	t.hasData = true
-	_, _ = t.w.Write([]byte(strings.Join(columns, "\t") + "\n"))
+	row := make([]tableCell, len(columns))
+	for i, c := range columns {
+		row[i] = tableCell{text: c}
+	}
+	t.rows = append(t.rows, row)
 }
 
 // Row writes a data row with the given values.
 func (t *TableWriter) Row(values ...string) {
-	t.hasData = true
-	_, _ = t.w.Write([]byte(strings.Join(values, "\t") + "\n"))
+	t.rows = append(t.rows, t.cells(values))
+}
+
+// cells styles the values of a data row.
+func (t *TableWriter) cells(values []string) []tableCell {
+	row := make([]tableCell, len(values))
+	for i, v := range values {
+		row[i] = tableCell{text: v}
+		if style, ok := statusStyles[v]; ok {
+			row[i].color = style.color
+			if style.icon != "" && t.colors {
+				row[i].text = style.icon + " " + v
+			}
+		} else if colorer := t.colorers[i]; colorer != nil {
+			row[i].color = colorer(v)
+		}
+	}
+	return row
+}
+
+// ColorColumn colors the data cells of a column as chosen by colorer.
+// It applies to rows written after the call.
+func (t *TableWriter) ColorColumn(column int, colorer func(value string) Color) {
+	if t.colorers == nil {
+		t.colorers = make(map[int]func(string) Color)
+	}
+	t.colorers[column] = colorer
 }
 
 // String flushes the writer and returns the formatted output.
 // Returns empty string if no data was written.
 func (t *TableWriter) String() string {
-	if !t.hasData {
+	if len(t.rows) == 0 {
 		return ""
 	}
-	_ = t.w.Flush()
-	return strings.TrimSuffix(t.buf.String(), "\n")
+
+	// tabwriter counts escape sequences as text, so once any cell is colored every
+	// cell is wrapped in sequences of the same length to keep columns aligned
+	styled := false
+	if t.colors {
+		for _, row := range t.rows {
+			for _, c := range row {
+				styled = styled || c.color != ColorNone
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = c.text
+			if styled {
+				cells[i] = ansiColors[c.color] + c.text + ansiReset
+			}
+		}
+		_, _ = w.Write([]byte(strings.Join(cells, "\t") + "\n"))
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// WriteRows writes the rows written so far followed by rows to w, formatted as String
+// would but without holding rows in memory. rows is iterated twice: once to size the
+// columns and once to write them. Nothing is written if rows is empty.
+func (t *TableWriter) WriteRows(w io.Writer, rows iter.Seq[[]string]) error {
+	var widths []int
+	styled := false
+	measure := func(row []tableCell) {
+		for i, c := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+			styled = styled || t.colors && c.color != ColorNone
+		}
+	}
+	for _, row := range t.rows {
+		measure(row)
+	}
+	empty := true
+	for values := range rows {
+		measure(t.cells(values))
+		empty = false
+	}
+	if empty {
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+	write := func(row []tableCell) {
+		for i, c := range row {
+			if styled {
+				_, _ = bw.WriteString(ansiColors[c.color] + c.text + ansiReset)
+			} else {
+				_, _ = bw.WriteString(c.text)
+			}
+			// Like tabwriter, pad every cell but the last to its column width plus 3
+			if i < len(row)-1 {
+				_, _ = bw.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text)+3))
+			}
+		}
+		_ = bw.WriteByte('\n')
+	}
+	for _, row := range t.rows {
+		write(row)
+	}
+	for values := range rows {
+		write(t.cells(values))
+	}
+	return bw.Flush()
 }
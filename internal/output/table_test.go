@@ -48,3 +48,45 @@ func TestTableWriter_MultipleRows(t *testing.T) {
 		t.Error("expected third row")
 	}
 }
+
+func TestTableWriter_Colors(t *testing.T) {
+	tw := NewTableWriter()
+	tw.colors = true
+	tw.ColorColumn(2, func(v string) Color {
+		if v != "-" {
+			return ColorYellow
+		}
+		return ColorNone
+	})
+	tw.Header("NAME", "STATUS", "NOTE")
+	tw.Row("first", "PASS", "-")
+	tw.Row("second-longer", "FAIL", "DT:2025-01-01")
+	result := tw.String()
+
+	for _, want := range []string{
+		"\x1b[32m✓ PASS\x1b[0m",
+		"\x1b[31m✗ FAIL\x1b[0m",
+		"\x1b[33mDT:2025-01-01\x1b[0m",
+		"\x1b[39m-\x1b[0m",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("output missing %q:\n%q", want, result)
+		}
+	}
+
+	// Escape sequences don't disturb alignment
+	lines := strings.Split(result, "\n")
+	if a, b := strings.Index(lines[0], "\x1b[39mSTATUS"), strings.Index(lines[2], "\x1b[31m✗"); a != b {
+		t.Errorf("columns not aligned:\n%s", result)
+	}
+}
+
+func TestTableWriter_NoColors(t *testing.T) {
+	tw := NewTableWriter()
+	tw.colors = false
+	tw.Header("STATUS")
+	tw.Row("PASS")
+	if result := tw.String(); result != "STATUS\nPASS" {
+		t.Errorf("String() = %q, want plain output", result)
+	}
+}
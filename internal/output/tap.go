@@ -0,0 +1,93 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// TAPFormatter is implemented by formatters that can also output TAP version 13.
+type TAPFormatter interface {
+	FormatTAP() string
+}
+
+// FormatTAP formats the results as TAP, one test point per platform version.
+func (v *ValidationOutput) FormatTAP() string {
+	return formatTAP([]*truststore.ValidationReport{v.Redaction.apply(v.Report)})
+}
+
+// FormatTAP formats the results of all endpoints as a single TAP stream,
+// one test point per endpoint and platform version. Endpoints that could not
+// be validated get a single failing test point.
+func (b *BulkValidationOutput) FormatTAP() string {
+	return formatTAP(b.redactedReports())
+}
+
+// tapDiagnostic is the YAML block attached to a test point.
+type tapDiagnostic struct {
+	message  string
+	warnings []string
+	forecast *truststore.TrustForecast
+}
+
+// write appends the block, or nothing if it is empty.
+func (d tapDiagnostic) write(sb *strings.Builder) {
+	if d.message == "" && len(d.warnings) == 0 && d.forecast == nil {
+		return
+	}
+	sb.WriteString("  ---\n")
+	if d.message != "" {
+		sb.WriteString("  message: " + yamlString(d.message) + "\n")
+	}
+	if len(d.warnings) > 0 {
+		sb.WriteString("  warnings:\n")
+		for _, w := range d.warnings {
+			sb.WriteString("    - " + yamlString(w) + "\n")
+		}
+	}
+	if f := d.forecast; f != nil {
+		sb.WriteString("  fails_at: " + yamlString(f.Date.UTC().Format(jsonTimeFormat)) + "\n")
+		sb.WriteString("  fails_reason: " + yamlString(f.Reason) + "\n")
+	}
+	sb.WriteString("  ...\n")
+}
+
+// formatTAP renders reports as a TAP version 13 stream. Failure reasons,
+// warnings and forecasts are attached as YAML diagnostics.
+func formatTAP(reports []*truststore.ValidationReport) string {
+	var sb strings.Builder
+	n := 0
+	point := func(ok bool, description string, diag tapDiagnostic) {
+		n++
+		status := "ok"
+		if !ok {
+			status = "not ok"
+		}
+		fmt.Fprintf(&sb, "%s %d - %s\n", status, n, description)
+		diag.write(&sb)
+	}
+
+	for _, report := range reports {
+		if report.Error != "" {
+			point(false, report.Endpoint, tapDiagnostic{message: report.Error})
+			continue
+		}
+		for _, r := range report.Results {
+			description := report.Endpoint + " " + string(r.Platform.Platform) + " " + r.Platform.Label()
+			if r.Trusted {
+				description += " (" + r.MatchedCA + ")"
+			}
+			point(r.Trusted, description, tapDiagnostic{message: r.FailureReason, warnings: r.Warnings, forecast: r.Forecast})
+		}
+	}
+
+	return strings.TrimSuffix(fmt.Sprintf("TAP version 13\n1..%d\n", n)+sb.String(), "\n")
+}
+
+// yamlString quotes s as a YAML double-quoted scalar (JSON strings are valid YAML).
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
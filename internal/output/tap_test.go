@@ -0,0 +1,60 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestFormatTAP(t *testing.T) {
+	t.Parallel()
+
+	ios := func(v string) truststore.PlatformVersion {
+		return truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: v}
+	}
+	reports := []*truststore.ValidationReport{
+		{
+			Endpoint: "a.example.com",
+			Results: []truststore.TrustResult{
+				{Platform: ios("17"), FailureReason: `root "X" distrusted`},
+				{Platform: ios("18"), Trusted: true, MatchedCA: "Root A", Warnings: []string{"only 1 SCT"},
+					Forecast: &truststore.TrustForecast{Date: time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), Reason: "expired"}},
+				{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18", ExtraRoots: true}, Trusted: true, MatchedCA: "Corp"},
+			},
+		},
+		{Endpoint: "b.example.com", Error: "connection refused"},
+	}
+
+	want := `TAP version 13
+1..4
+not ok 1 - a.example.com ios 17
+  ---
+  message: "root \"X\" distrusted"
+  ...
+ok 2 - a.example.com ios 18 (Root A)
+  ---
+  warnings:
+    - "only 1 SCT"
+  fails_at: "2026-04-15T00:00:00Z"
+  fails_reason: "expired"
+  ...
+ok 3 - a.example.com ios 18+extra (Corp)
+not ok 4 - b.example.com
+  ---
+  message: "connection refused"
+  ...`
+
+	if got := NewBulkValidationOutput(reports).FormatTAP(); got != want {
+		t.Errorf("FormatTAP() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatTAPSingle(t *testing.T) {
+	t.Parallel()
+
+	report := &truststore.ValidationReport{Endpoint: "example.com"}
+	if got, want := NewValidationOutput(report).FormatTAP(), "TAP version 13\n1..0"; got != want {
+		t.Errorf("FormatTAP() = %q, want %q", got, want)
+	}
+}
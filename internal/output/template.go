@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Output specs selecting a Go template, as in kubectl.
+const (
+	templatePrefix     = "go-template="
+	templateFilePrefix = "go-template-file="
+)
+
+// templateFuncs are available in addition to the text/template builtins.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": func(sep string, v []any) string {
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = fmt.Sprint(e)
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+// ParseTemplate parses a go-template=TEMPLATE or go-template-file=PATH output spec.
+// It returns nil for any other spec.
+func ParseTemplate(spec string) (*template.Template, error) {
+	var text string
+	switch {
+	case strings.HasPrefix(spec, templatePrefix):
+		text = strings.TrimPrefix(spec, templatePrefix)
+	case strings.HasPrefix(spec, templateFilePrefix):
+		data, err := os.ReadFile(strings.TrimPrefix(spec, templateFilePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("read template: %w", err)
+		}
+		text = string(data)
+	default:
+		return nil, nil
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// ExecuteTemplate renders f's JSON document through tmpl, so templates address
+// fields by their JSON names (e.g., {{range .results}}{{.platform}}{{end}}).
+func ExecuteTemplate(f Formatter, tmpl *template.Template) (string, error) {
+	data, err := f.FormatJSON()
+	if err != nil {
+		return "", err
+	}
+
+	// Numbers are kept as written rather than converted to float64
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, doc); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tmpl")
+	if err := os.WriteFile(path, []byte("{{len .}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec    string
+		wantNil bool
+		wantErr bool
+	}{
+		{"text", true, false},
+		{"json", true, false},
+		{"go-template={{.}}", false, false},
+		{"go-template-file=" + path, false, false},
+		{"go-template={{.", true, true},
+		{"go-template-file=" + filepath.Join(t.TempDir(), "missing"), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+			tmpl, err := ParseTemplate(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemplate(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if (tmpl == nil) != tt.wantNil {
+				t.Errorf("ParseTemplate(%q) = %v, wantNil %v", tt.spec, tmpl, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	t.Parallel()
+
+	// StoreList sorts in place, so each subtest gets its own
+	newList := func() *StoreList {
+		return &StoreList{Entries: []ListEntry{
+			{Platform: "ios", Version: "18", Fingerprint: "AA", Issuer: "Root A"},
+			{Platform: "android", Version: "15", Fingerprint: "BB", Issuer: "Root B", Constraints: "DT:2026-01-01"},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"range", `go-template={{range .}}{{.platform}} {{.issuer}}{{"\n"}}{{end}}`, "android Root B\nios Root A"},
+		{"len", `go-template={{len .}}`, "2"},
+		{"json", `go-template={{json (index . 0)}}`, `{"constraints":"DT:2026-01-01","fingerprint":"BB","issuer":"Root B","platform":"android","version":"15"}`},
+		{"with", `go-template={{range .}}{{with .constraints}}{{.}}{{end}}{{end}}`, "DT:2026-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmpl, err := ParseTemplate(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ExecuteTemplate(newList(), tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ExecuteTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
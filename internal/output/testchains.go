@@ -0,0 +1,76 @@
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/ivoronin/certvet/internal/testchains"
+)
+
+// TestChainsOutput implements Formatter for fixture chain outcomes.
+type TestChainsOutput struct {
+	Outcomes []testchains.Outcome
+}
+
+// NewTestChainsOutput creates a new TestChainsOutput formatter.
+func NewTestChainsOutput(outcomes []testchains.Outcome) *TestChainsOutput {
+	return &TestChainsOutput{Outcomes: outcomes}
+}
+
+// AllOK reports whether every fixture triggered its expected rule.
+func (t *TestChainsOutput) AllOK() bool {
+	for _, o := range t.Outcomes {
+		if !o.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatText formats outcomes as a table of expected vs actual rule codes.
+func (t *TestChainsOutput) FormatText() string {
+	tw := NewTableWriter()
+	tw.Header("FIXTURE", "EXPECTED", "ACTUAL", "RESULT", "DESCRIPTION")
+	for _, o := range t.Outcomes {
+		result := "OK"
+		if !o.OK() {
+			result = "MISMATCH"
+		}
+		tw.Row(o.Fixture.Name, o.Fixture.Expected, o.Actual, result, o.Fixture.Description)
+	}
+	return tw.String()
+}
+
+// FormatJSON formats outcomes as JSON.
+func (t *TestChainsOutput) FormatJSON() ([]byte, error) {
+	out := jsonTestChains{
+		Fixtures: make([]jsonFixtureOutcome, len(t.Outcomes)),
+		AllOK:    t.AllOK(),
+	}
+	for i, o := range t.Outcomes {
+		out.Fixtures[i] = jsonFixtureOutcome{
+			Name:          o.Fixture.Name,
+			Description:   o.Fixture.Description,
+			Expected:      o.Fixture.Expected,
+			Actual:        o.Actual,
+			OK:            o.OK(),
+			FailureCode:   string(o.Result.FailureCode),
+			FailureReason: o.Result.FailureReason,
+		}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonTestChains struct {
+	Fixtures []jsonFixtureOutcome `json:"fixtures"`
+	AllOK    bool                 `json:"all_ok"`
+}
+
+type jsonFixtureOutcome struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Expected      string `json:"expected"`
+	Actual        string `json:"actual"`
+	OK            bool   `json:"ok"`
+	FailureCode   string `json:"failure_code,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
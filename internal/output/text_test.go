@@ -1,6 +1,8 @@
 package output
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"strings"
 	"testing"
 	"time"
@@ -105,3 +107,247 @@ func TestFormatTextMixedResults(t *testing.T) {
 		t.Errorf("expected 2 FAILs, got %d", failCount)
 	}
 }
+
+func TestFormatTextAdvisories(t *testing.T) {
+	report := &truststore.ValidationReport{
+		Endpoint: "example.com",
+		Results: []truststore.TrustResult{
+			{
+				Platform:   truststore.PlatformVersion{Platform: truststore.PlatformChrome, Version: "131"},
+				Trusted:    true,
+				MatchedCA:  "Entrust Root Certification Authority - G2",
+				Advisories: []string{"entrust-distrust-2024"},
+			},
+		},
+		Advisories: []truststore.AdvisoryMatch{
+			{
+				ID:       "entrust-distrust-2024",
+				Title:    "Chrome distrust of Entrust TLS roots",
+				Subject:  "Entrust Root Certification Authority - G2",
+				Timeline: []truststore.AdvisoryEvent{{Date: time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC), Description: "SCT cutoff"}},
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+
+	if !strings.Contains(out, "Entrust Root Certification Authority - G2 [entrust-distrust-2024]") {
+		t.Error("missing advisory marker on result status")
+	}
+	if !strings.Contains(out, "ADVISORY") {
+		t.Error("missing advisory table header")
+	}
+	if !strings.Contains(out, "2024-11-11: SCT cutoff") {
+		t.Error("missing advisory timeline")
+	}
+}
+
+func TestFormatTextOCSPStaple(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	leaf := &x509.Certificate{}
+
+	tests := []struct {
+		name  string
+		chain truststore.CertChain
+		want  string
+	}{
+		{
+			name:  "none",
+			chain: truststore.CertChain{ServerCert: leaf},
+			want:  "OCSP staple: none\n",
+		},
+		{
+			name:  "must-staple missing",
+			chain: truststore.CertChain{ServerCert: leaf, MustStaple: true},
+			want:  "OCSP staple: none (certificate requires must-staple)",
+		},
+		{
+			name: "good",
+			chain: truststore.CertChain{ServerCert: leaf, OCSPStaple: &truststore.OCSPStaple{
+				Status:     truststore.OCSPStatusGood,
+				ProducedAt: now.Add(-3 * 24 * time.Hour),
+				NextUpdate: now.Add(4 * 24 * time.Hour),
+			}},
+			want: "OCSP staple: good (produced 3d ago, next update 2025-01-19)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &truststore.ValidationReport{Timestamp: now, Chain: tt.chain}
+			out := NewValidationOutput(report).FormatText()
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestFormatTextWarnings(t *testing.T) {
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:  truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"},
+				Trusted:   true,
+				MatchedCA: "Test CA",
+				Warnings:  []string{"Apple CT policy: 0 embedded SCTs"},
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+
+	if !strings.Contains(out, "WARN") || strings.Contains(out, "PASS") {
+		t.Errorf("expected WARN instead of PASS:\n%s", out)
+	}
+	if !strings.Contains(out, "Test CA (Apple CT policy: 0 embedded SCTs)") {
+		t.Errorf("missing warning in status:\n%s", out)
+	}
+}
+
+func TestFormatTextHostname(t *testing.T) {
+	results := []truststore.TrustResult{
+		{Platform: truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "18"}, Trusted: true, MatchedCA: "CA"},
+	}
+
+	out := NewValidationOutput(&truststore.ValidationReport{Results: results}).FormatText()
+	if strings.Contains(out, "HOSTNAME") {
+		t.Error("HOSTNAME column should only appear when hostname was checked")
+	}
+
+	report := &truststore.ValidationReport{
+		Results:  results,
+		Hostname: &truststore.HostnameCheck{Host: "a.example.com", Error: "certificate is not valid for a.example.com"},
+	}
+	out = NewValidationOutput(report).FormatText()
+	for _, want := range []string{"HOSTNAME", "MISMATCH", "Hostname: certificate is not valid for a.example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatTextForecast(t *testing.T) {
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:  truststore.PlatformVersion{Platform: truststore.PlatformWindows, Version: "current"},
+				Trusted:   true,
+				MatchedCA: "Old Root",
+				Forecast: &truststore.TrustForecast{
+					Date:   time.Date(2026, 6, 1, 0, 0, 1, 0, time.UTC),
+					Reason: "CA distrusted since 2026-06-01",
+				},
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+	if !strings.Contains(out, "PASS") || !strings.Contains(out, "Old Root (fails on 2026-06-01: CA distrusted since 2026-06-01)") {
+		t.Errorf("missing forecast in status:\n%s", out)
+	}
+}
+
+func TestFormatTextPendingRemoval(t *testing.T) {
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:       truststore.PlatformVersion{Platform: truststore.PlatformWindows, Version: "current"},
+				Trusted:        true,
+				MatchedCA:      "Old Root",
+				PendingRemoval: true,
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+	if !strings.Contains(out, "Old Root (root pending removal)") {
+		t.Errorf("missing pending removal in status:\n%s", out)
+	}
+}
+
+func TestFormatTextSuggested(t *testing.T) {
+	crossSign := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "Old Root"},
+		Issuer:  pkix.Name{CommonName: "New Root"},
+	}
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"},
+				FailureReason: "certificate signed by unknown authority",
+				Suggested:     []*x509.Certificate{crossSign},
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+	if !strings.Contains(out, `unknown authority (fix: serve cross-signed "Old Root" via "New Root")`) {
+		t.Errorf("missing suggestion in status:\n%s", out)
+	}
+}
+
+func TestFormatTextExtraRoots(t *testing.T) {
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:  truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "17", ExtraRoots: true},
+				Trusted:   true,
+				MatchedCA: "Corp Root",
+			},
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "17"},
+				FailureReason: "certificate signed by unknown authority",
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+	stock := strings.Index(out, "unknown authority")
+	extra := strings.Index(out, "17+extra")
+	if stock < 0 || extra < 0 || extra < stock {
+		t.Errorf("expected stock result before 17+extra variant:\n%s", out)
+	}
+}
+
+func TestFormatTextTrustedUntil(t *testing.T) {
+	until := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	report := &truststore.ValidationReport{
+		Results: []truststore.TrustResult{
+			{
+				Platform:     truststore.PlatformVersion{Platform: truststore.PlatformIOS, Version: "17"},
+				Trusted:      true,
+				MatchedCA:    "ISRG Root X1",
+				TrustedUntil: until,
+			},
+			{
+				Platform:      truststore.PlatformVersion{Platform: truststore.PlatformAndroid, Version: "7"},
+				FailureReason: "certificate signed by unknown authority",
+			},
+		},
+	}
+
+	out := NewValidationOutput(report).FormatText()
+	if !strings.Contains(out, "TRUSTED UNTIL") {
+		t.Errorf("expected TRUSTED UNTIL column:\n%s", out)
+	}
+	lines := strings.Split(out, "\n")
+	if !strings.Contains(lines[1], "-") || !strings.Contains(lines[2], "2026-09-01") {
+		t.Errorf("expected '-' for the failed row and the date for the trusted row:\n%s", out)
+	}
+
+	bulk := NewBulkValidationOutput([]*truststore.ValidationReport{
+		{Endpoint: "a.example.com", Results: report.Results},
+		{Endpoint: "b.example.com", Error: "connection refused"},
+	}).FormatText()
+	if !strings.Contains(bulk, "TRUSTED UNTIL") || !strings.Contains(bulk, "2026-09-01") {
+		t.Errorf("expected TRUSTED UNTIL column in bulk output:\n%s", bulk)
+	}
+
+	for i := range report.Results {
+		report.Results[i].TrustedUntil = time.Time{}
+	}
+	if out := NewValidationOutput(report).FormatText(); strings.Contains(out, "TRUSTED UNTIL") {
+		t.Errorf("column shown without trust horizons:\n%s", out)
+	}
+}
@@ -1,9 +1,13 @@
 package output
 
 import (
-	"crypto/sha256"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ivoronin/certvet/internal/truststore"
 	"github.com/ivoronin/certvet/internal/version"
@@ -15,89 +19,403 @@ const jsonTimeFormat = "2006-01-02T15:04:05Z"
 
 // ValidationOutput implements Formatter for validation reports.
 type ValidationOutput struct {
-	Report *truststore.ValidationReport
+	Report    *truststore.ValidationReport
+	Redaction Redaction // Applied when formatting; Report itself is not modified
+	ShowChain bool      // Append each result's path and the root constraints applied
 }
 
 // NewValidationOutput creates a new ValidationOutput formatter.
 // Results are sorted by platform (alphabetically) then version (ascending).
 func NewValidationOutput(report *truststore.ValidationReport) *ValidationOutput {
-	// Sort results for consistent output
-	sort.Slice(report.Results, func(i, j int) bool {
-		ri, rj := report.Results[i].Platform, report.Results[j].Platform
+	sortResults(report.Results)
+	return &ValidationOutput{Report: report}
+}
+
+// sortResults orders results by platform (alphabetically) then version (ascending).
+func sortResults(results []truststore.TrustResult) {
+	sort.Slice(results, func(i, j int) bool {
+		ri, rj := results[i].Platform, results[j].Platform
 		if ri.Platform != rj.Platform {
 			return ri.Platform < rj.Platform
 		}
-		return version.CompareAsc(ri.Version, rj.Version)
+		if ri.Version != rj.Version {
+			return version.CompareAsc(ri.Version, rj.Version)
+		}
+		return !ri.ExtraRoots && rj.ExtraRoots // Stock store before its variant with extra roots
 	})
-	return &ValidationOutput{Report: report}
 }
 
 // FormatText formats the validation report as a human-readable table.
 func (v *ValidationOutput) FormatText() string {
-	report := v.Report
+	report := v.Redaction.apply(v.Report)
 
-	tw := NewTableWriter()
-	tw.Header("PLATFORM", "VERSION", "VALIDATION", "STATUS")
+	until := hasTrustedUntil(report.Results)
+	header := []string{"PLATFORM", "VERSION", "VALIDATION"}
+	if report.Hostname != nil {
+		header = append(header, "HOSTNAME")
+	}
+	if until {
+		header = append(header, "TRUSTED UNTIL")
+	}
 
+	tw := NewTableWriter()
+	tw.Header(append(header, "STATUS")...)
 	for _, r := range report.Results {
-		validation := "FAIL"
-		status := r.FailureReason
-		if r.Trusted {
-			validation = "PASS"
-			status = r.MatchedCA
+		validation, status := resultColumns(r)
+		row := []string{string(r.Platform.Platform), r.Platform.Label(), validation}
+		if report.Hostname != nil {
+			row = append(row, hostnameColumn(report.Hostname))
+		}
+		if until {
+			row = append(row, trustedUntilColumn(r))
+		}
+		tw.Row(append(row, status)...)
+	}
+
+	table := tw.String()
+	if len(report.Results) > 0 {
+		table += "\n\n" + formatRollup(Rollup(report.Results)) + "\n"
+	}
+
+	out := formatEvaluatedAt(report.EvaluatedAt) + table + formatHostnameLine(report.Hostname) +
+		formatOCSPLine(report) + formatAdvisoryTable(report.Advisories)
+	if v.ShowChain {
+		out = strings.TrimSuffix(out, "\n") + "\n" + strings.TrimSuffix(formatChainDetails(report, v.Redaction, ""), "\n")
+	}
+	return out
+}
+
+// formatEvaluatedAt notes a simulated validation time (empty if validated now).
+func formatEvaluatedAt(at time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	return "Evaluated at: " + at.UTC().Format(jsonTimeFormat) + " (simulated)\n\n"
+}
+
+// hasTrustedUntil reports whether any result carries a trust horizon (see --trusted-until).
+func hasTrustedUntil(results []truststore.TrustResult) bool {
+	for _, r := range results {
+		if !r.TrustedUntil.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// trustedUntilColumn returns the TRUSTED UNTIL column value ("-" for failed results).
+func trustedUntilColumn(r truststore.TrustResult) string {
+	if r.TrustedUntil.IsZero() {
+		return "-"
+	}
+	return r.TrustedUntil.Format(truststore.DateFormat)
+}
+
+// hostnameColumn returns the HOSTNAME column value.
+func hostnameColumn(h *truststore.HostnameCheck) string {
+	if h.Valid {
+		return "OK"
+	}
+	return "MISMATCH"
+}
+
+// formatHostnameLine explains a hostname mismatch (empty if not checked or valid).
+func formatHostnameLine(h *truststore.HostnameCheck) string {
+	if h == nil || h.Valid {
+		return ""
+	}
+	return "\nHostname: " + h.Error
+}
+
+// formatOCSPLine summarizes the stapled OCSP response (empty if no server certificate).
+func formatOCSPLine(report *truststore.ValidationReport) string {
+	chain := report.Chain
+	if chain.ServerCert == nil {
+		return ""
+	}
+
+	staple := chain.OCSPStaple
+	if staple == nil {
+		if chain.MustStaple {
+			return "\nOCSP staple: none (certificate requires must-staple)\n"
 		}
-		tw.Row(string(r.Platform.Platform), r.Platform.Version, validation, status)
+		return "\nOCSP staple: none\n"
+	}
+	if staple.Status == truststore.OCSPStatusInvalid {
+		return "\nOCSP staple: invalid\n"
+	}
+
+	line := fmt.Sprintf("\nOCSP staple: %s (produced %s ago", staple.Status, formatAge(report.Timestamp.Sub(staple.ProducedAt)))
+	if !staple.NextUpdate.IsZero() {
+		line += ", next update " + staple.NextUpdate.Format(truststore.DateFormat)
+	}
+	return line + ")\n"
+}
+
+// formatAge renders a duration in whole hours, or whole days above two days.
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
 	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
 
-	return tw.String()
+// resultColumns returns the VALIDATION and STATUS column values for a result.
+func resultColumns(r truststore.TrustResult) (validation, status string) {
+	validation = "FAIL"
+	status = r.FailureReason
+	if r.Trusted {
+		validation = "PASS"
+		status = r.MatchedCA
+		if len(r.Warnings) > 0 {
+			validation = "WARN"
+			status += " (" + strings.Join(r.Warnings, "; ") + ")"
+		}
+		if f := r.Forecast; f != nil {
+			status += " (fails on " + f.Date.Format(truststore.DateFormat) + ": " + f.Reason + ")"
+		}
+		if r.PendingRemoval {
+			status += " (root pending removal)"
+		}
+		if r.EV {
+			status += " (EV)"
+		}
+	}
+	if len(r.Suggested) > 0 {
+		names := make([]string, len(r.Suggested))
+		for i, cert := range r.Suggested {
+			names[i] = fmt.Sprintf("%q via %q", cert.Subject.CommonName, cert.Issuer.CommonName)
+		}
+		status += " (fix: serve cross-signed " + strings.Join(names, ", ") + ")"
+	}
+	if len(r.Advisories) > 0 {
+		status += " [" + strings.Join(r.Advisories, ",") + "]"
+	}
+	return validation, status
+}
+
+// formatAdvisoryTable renders matched advisories as a separate table (empty if none).
+func formatAdvisoryTable(advisories []truststore.AdvisoryMatch) string {
+	if len(advisories) == 0 {
+		return ""
+	}
+
+	at := NewTableWriter()
+	at.Header("ADVISORY", "CERTIFICATE", "TIMELINE", "TITLE")
+	for _, a := range advisories {
+		at.Row(a.ID, a.Subject, formatTimeline(a.Timeline), a.Title)
+	}
+	return "\n" + at.String()
+}
+
+// formatTimeline renders advisory events as "date: description" joined by "; ".
+func formatTimeline(events []truststore.AdvisoryEvent) string {
+	parts := make([]string, len(events))
+	for i, e := range events {
+		parts[i] = e.Date.Format(truststore.DateFormat) + ": " + e.Description
+	}
+	return strings.Join(parts, "; ")
 }
 
 // FormatJSON formats the validation report as JSON.
 func (v *ValidationOutput) FormatJSON() ([]byte, error) {
-	report := v.Report
+	report := v.Redaction.apply(v.Report)
+	jr := newJSONReport(report, v.Redaction)
+	if v.ShowChain {
+		addJSONChains(&jr, report, v.Redaction)
+	}
+	return json.MarshalIndent(jr, "", "  ")
+}
+
+// FormatCSV formats the validation results as CSV, one row per platform version.
+func (v *ValidationOutput) FormatCSV() ([]byte, error) {
+	return writeCSV(csvResultHeader, csvResultRows(v.Redaction.apply(v.Report), v.Redaction))
+}
+
+// csvResultHeader is the stable column set of validate CSV output, shared by
+// single and bulk runs so files from both can be concatenated.
+var csvResultHeader = []string{
+	"endpoint", "platform", "version", "extra_roots", "trusted", "matched_ca", "matched_fingerprint",
+	"failure_reason", "warnings", "advisories", "trusted_until", "error",
+}
+
+// csvResultRows converts a report to CSV rows. A report with an error yields a single
+// row carrying only the endpoint and the error. The report is expected to be redacted already.
+func csvResultRows(report *truststore.ValidationReport, r Redaction) [][]string {
+	if report.Error != "" {
+		return [][]string{{report.Endpoint, "", "", "", "", "", "", "", "", "", "", report.Error}}
+	}
+
+	rows := make([][]string, len(report.Results))
+	for i, res := range report.Results {
+		var fp, until string
+		if !res.MatchedFingerprint.IsZero() {
+			fp = r.fingerprint(res.MatchedFingerprint)
+		}
+		if !res.TrustedUntil.IsZero() {
+			until = res.TrustedUntil.UTC().Format(jsonTimeFormat)
+		}
+		rows[i] = []string{
+			report.Endpoint,
+			string(res.Platform.Platform),
+			res.Platform.Version,
+			strconv.FormatBool(res.Platform.ExtraRoots),
+			strconv.FormatBool(res.Trusted),
+			res.MatchedCA,
+			fp,
+			res.FailureReason,
+			strings.Join(res.Warnings, "; "),
+			strings.Join(res.Advisories, ","),
+			until,
+			"",
+		}
+	}
+	return rows
+}
 
+// newJSONReport converts a validation report to its JSON representation.
+// The report is expected to be redacted already; r only controls fingerprint display.
+func newJSONReport(report *truststore.ValidationReport, r Redaction) jsonReport {
 	jr := jsonReport{
 		Endpoint:    report.Endpoint,
 		Timestamp:   report.Timestamp.UTC().Format(jsonTimeFormat),
 		ToolVersion: report.ToolVersion,
+		DataVersion: report.DataVersion,
 		AllPassed:   report.AllPassed,
+		Error:       report.Error,
 		Results:     make([]jsonResult, len(report.Results)),
+		Rollup:      newJSONRollup(Rollup(report.Results)),
+	}
+	if !report.DataDate.IsZero() {
+		jr.DataDate = report.DataDate.UTC().Format(jsonTimeFormat)
+	}
+	if !report.EvaluatedAt.IsZero() {
+		jr.EvaluatedAt = report.EvaluatedAt.UTC().Format(jsonTimeFormat)
 	}
 
 	// Certificate info
 	if report.Chain.ServerCert != nil {
 		cert := report.Chain.ServerCert
-		fp := truststore.Fingerprint(sha256.Sum256(cert.Raw))
 		jr.Certificate = &jsonCert{
 			Subject:           cert.Subject.CommonName,
 			Issuer:            cert.Issuer.CommonName,
 			Expires:           cert.NotAfter.UTC().Format(jsonTimeFormat),
-			FingerprintSHA256: fp.String(),
+			FingerprintSHA256: r.certFingerprint(cert),
 		}
+		jr.OCSP = newJSONOCSP(report)
+	}
+
+	if info := report.Chain.TLS; info != nil {
+		jr.TLS = &jsonTLS{
+			Version:     tls.VersionName(info.Version),
+			CipherSuite: tls.CipherSuiteName(info.CipherSuite),
+		}
+		if info.MinVersion != 0 {
+			jr.TLS.MinVersion = tls.VersionName(info.MinVersion)
+		}
+	}
+
+	if h := report.Hostname; h != nil {
+		jr.Hostname = &jsonHostname{Host: h.Host, Valid: h.Valid, Error: h.Error}
 	}
 
 	// Flat results array
-	for i, r := range report.Results {
+	for i, res := range report.Results {
 		jr.Results[i] = jsonResult{
-			Platform:      string(r.Platform.Platform),
-			Version:       r.Platform.Version,
-			Trusted:       r.Trusted,
-			MatchedCA:     r.MatchedCA,
-			FailureReason: r.FailureReason,
+			Platform:       string(res.Platform.Platform),
+			Version:        res.Platform.Version,
+			ExtraRoots:     res.Platform.ExtraRoots,
+			Trusted:        res.Trusted,
+			MatchedCA:      res.MatchedCA,
+			FailureCode:    string(res.FailureCode),
+			FailureReason:  res.FailureReason,
+			Warnings:       res.Warnings,
+			Advisories:     res.Advisories,
+			PendingRemoval: res.PendingRemoval,
+			EV:             res.EV,
+		}
+		if !res.MatchedFingerprint.IsZero() {
+			jr.Results[i].MatchedFingerprint = r.fingerprint(res.MatchedFingerprint)
+		}
+		if !res.TrustedUntil.IsZero() {
+			jr.Results[i].TrustedUntil = res.TrustedUntil.UTC().Format(jsonTimeFormat)
+		}
+		if f := res.Forecast; f != nil {
+			jr.Results[i].Forecast = &jsonForecast{
+				FailsAt: f.Date.UTC().Format(jsonTimeFormat),
+				Reason:  f.Reason,
+			}
+		}
+		for _, cert := range res.Suggested {
+			jr.Results[i].Suggested = append(jr.Results[i].Suggested, jsonCert{
+				Subject:           cert.Subject.CommonName,
+				Issuer:            cert.Issuer.CommonName,
+				Expires:           cert.NotAfter.UTC().Format(jsonTimeFormat),
+				FingerprintSHA256: r.certFingerprint(cert),
+			})
 		}
 	}
 
-	return json.MarshalIndent(jr, "", "  ")
+	for _, a := range report.Advisories {
+		ja := jsonAdvisory{
+			ID:          a.ID,
+			Title:       a.Title,
+			URL:         a.URL,
+			Fingerprint: r.fingerprint(a.Fingerprint),
+			Subject:     a.Subject,
+		}
+		for _, e := range a.Timeline {
+			ja.Timeline = append(ja.Timeline, jsonAdvisoryEvent{
+				Date:        e.Date.Format(truststore.DateFormat),
+				Description: e.Description,
+			})
+		}
+		jr.Advisories = append(jr.Advisories, ja)
+	}
+
+	return jr
+}
+
+// newJSONOCSP converts OCSP staple info; age is relative to the report timestamp.
+func newJSONOCSP(report *truststore.ValidationReport) *jsonOCSP {
+	chain := report.Chain
+	jo := &jsonOCSP{Stapled: chain.OCSPStaple != nil, MustStaple: chain.MustStaple}
+	if staple := chain.OCSPStaple; staple != nil {
+		jo.Status = staple.Status
+		if !staple.ProducedAt.IsZero() {
+			jo.ProducedAt = staple.ProducedAt.UTC().Format(jsonTimeFormat)
+			jo.AgeSeconds = int64(report.Timestamp.Sub(staple.ProducedAt).Seconds())
+		}
+		if !staple.NextUpdate.IsZero() {
+			jo.NextUpdate = staple.NextUpdate.UTC().Format(jsonTimeFormat)
+		}
+		if !staple.RevokedAt.IsZero() {
+			jo.RevokedAt = staple.RevokedAt.UTC().Format(jsonTimeFormat)
+		}
+	}
+	return jo
 }
 
 // jsonReport is the JSON output structure.
 type jsonReport struct {
-	Endpoint    string       `json:"endpoint"`
-	Timestamp   string       `json:"timestamp"`
-	ToolVersion string       `json:"tool_version"`
-	Certificate *jsonCert    `json:"certificate,omitempty"`
-	Results     []jsonResult `json:"results"`
-	AllPassed   bool         `json:"all_passed"`
+	Endpoint    string         `json:"endpoint"`
+	Timestamp   string         `json:"timestamp"`
+	ToolVersion string         `json:"tool_version"`
+	DataVersion string         `json:"data_version,omitempty"`
+	DataDate    string         `json:"data_date,omitempty"`
+	EvaluatedAt string         `json:"evaluated_at,omitempty"`
+	Certificate *jsonCert      `json:"certificate,omitempty"`
+	Hostname    *jsonHostname  `json:"hostname,omitempty"`
+	OCSP        *jsonOCSP      `json:"ocsp,omitempty"`
+	TLS         *jsonTLS       `json:"tls,omitempty"`
+	Results     []jsonResult   `json:"results"`
+	Rollup      []jsonRollup   `json:"rollup,omitempty"`
+	AllPassed   bool           `json:"all_passed"`
+	Error       string         `json:"error,omitempty"`
+	Advisories  []jsonAdvisory `json:"advisories,omitempty"`
 }
 
 type jsonCert struct {
@@ -107,10 +425,65 @@ type jsonCert struct {
 	FingerprintSHA256 string `json:"fingerprint_sha256,omitempty"`
 }
 
+type jsonHostname struct {
+	Host  string `json:"host"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+type jsonTLS struct {
+	Version     string `json:"version"`
+	CipherSuite string `json:"cipher_suite"`
+	MinVersion  string `json:"min_version,omitempty"`
+}
+
+type jsonOCSP struct {
+	Stapled    bool   `json:"stapled"`
+	MustStaple bool   `json:"must_staple"`
+	Status     string `json:"status,omitempty"`
+	ProducedAt string `json:"produced_at,omitempty"`
+	NextUpdate string `json:"next_update,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+	AgeSeconds int64  `json:"age_seconds,omitempty"`
+}
+
 type jsonResult struct {
-	Platform      string `json:"platform"`
-	Version       string `json:"version"`
-	Trusted       bool   `json:"trusted"`
-	MatchedCA     string `json:"matched_ca,omitempty"`
-	FailureReason string `json:"failure_reason,omitempty"`
+	Platform           string        `json:"platform"`
+	Version            string        `json:"version"`
+	ExtraRoots         bool          `json:"extra_roots,omitempty"`
+	Trusted            bool          `json:"trusted"`
+	MatchedCA          string        `json:"matched_ca,omitempty"`
+	MatchedFingerprint string        `json:"matched_fingerprint,omitempty"`
+	FailureCode        string        `json:"failure_code,omitempty"`
+	FailureReason      string        `json:"failure_reason,omitempty"`
+	Warnings           []string      `json:"warnings,omitempty"`
+	Advisories         []string      `json:"advisories,omitempty"`
+	TrustedUntil       string        `json:"trusted_until,omitempty"`
+	Forecast           *jsonForecast `json:"forecast,omitempty"`
+	PendingRemoval     bool          `json:"pending_removal,omitempty"`
+	EV                 bool          `json:"ev,omitempty"`
+	Suggested          []jsonCert    `json:"suggested_intermediates,omitempty"`
+
+	// With ShowChain: the verified path, or the served path up to the missing issuer
+	Chain       []jsonPathCert       `json:"chain,omitempty"`
+	Constraints *jsonRootConstraints `json:"constraints,omitempty"`
+}
+
+type jsonForecast struct {
+	FailsAt string `json:"fails_at"`
+	Reason  string `json:"reason"`
+}
+
+type jsonAdvisory struct {
+	ID          string              `json:"id"`
+	Title       string              `json:"title"`
+	URL         string              `json:"url,omitempty"`
+	Fingerprint string              `json:"fingerprint_sha256"`
+	Subject     string              `json:"subject"`
+	Timeline    []jsonAdvisoryEvent `json:"timeline,omitempty"`
+}
+
+type jsonAdvisoryEvent struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
 }
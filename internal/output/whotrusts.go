@@ -0,0 +1,158 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// RootTrust lists the stores that include one root.
+type RootTrust struct {
+	Fingerprint truststore.Fingerprint
+	Name        string
+	Stores      []truststore.Store
+}
+
+// WhoTrustsOutput implements Formatter for the platform versions trusting given roots.
+type WhoTrustsOutput struct {
+	Query  string
+	Roots  []RootTrust
+	stores []truststore.Store // All searched stores, to describe version ranges
+}
+
+// NewWhoTrustsOutput finds which of stores include each root in fps, in fps order.
+func NewWhoTrustsOutput(query string, fps []truststore.Fingerprint, stores []truststore.Store, name func(truststore.Fingerprint) string) *WhoTrustsOutput {
+	o := &WhoTrustsOutput{Query: query, stores: stores}
+	index := truststore.NewStoreIndex(stores)
+	for _, fp := range fps {
+		rt := RootTrust{Fingerprint: fp, Name: name(fp), Stores: index.Trusting(fp)}
+		sortStores(rt.Stores)
+		o.Roots = append(o.Roots, rt)
+	}
+	return o
+}
+
+// sortStores orders stores by platform, then version ascending.
+func sortStores(stores []truststore.Store) {
+	sort.SliceStable(stores, func(i, j int) bool {
+		if stores[i].Platform != stores[j].Platform {
+			return stores[i].Platform < stores[j].Platform
+		}
+		return version.CompareAsc(stores[i].Version, stores[j].Version)
+	})
+}
+
+// FormatText formats a block per root: platform versions grouped by identical constraints,
+// followed by the platforms no version of which trusts the root.
+// Header: PLATFORM, VERSIONS, CONSTRAINTS
+func (o *WhoTrustsOutput) FormatText() string {
+	if len(o.Roots) == 0 {
+		return fmt.Sprintf("No trusted root matches %q", o.Query)
+	}
+
+	all := make(map[truststore.Platform][]string)
+	var platforms []truststore.Platform
+	for _, s := range sortedStores(o.stores) {
+		if _, ok := all[s.Platform]; !ok {
+			platforms = append(platforms, s.Platform)
+		}
+		all[s.Platform] = append(all[s.Platform], s.Version)
+	}
+
+	blocks := make([]string, len(o.Roots))
+	for i, rt := range o.Roots {
+		header := fmt.Sprintf("%s (%s)", rt.Name, rt.Fingerprint)
+		if len(rt.Stores) == 0 {
+			blocks[i] = header + "\nNot trusted by any platform version"
+			continue
+		}
+
+		type group struct {
+			platform    truststore.Platform
+			constraints string
+			versions    []string
+		}
+		var groups []*group
+		trusting := make(map[truststore.Platform]bool)
+		for _, s := range rt.Stores {
+			trusting[s.Platform] = true
+			c := formatConstraints(s.ConstraintFor(rt.Fingerprint))
+			var g *group
+			for _, existing := range groups {
+				if existing.platform == s.Platform && existing.constraints == c {
+					g = existing
+				}
+			}
+			if g == nil {
+				g = &group{platform: s.Platform, constraints: c}
+				groups = append(groups, g)
+			}
+			g.versions = append(g.versions, s.Version)
+		}
+
+		tw := NewTableWriter()
+		tw.Header("PLATFORM", "VERSIONS", "CONSTRAINTS")
+		for _, g := range groups {
+			versions := strings.TrimPrefix(versionRange(g.platform, g.versions, all[g.platform]), string(g.platform))
+			if versions == "" {
+				versions = "all"
+			}
+			constraints := g.constraints
+			if constraints == "" {
+				constraints = "-"
+			}
+			tw.Row(string(g.platform), strings.TrimSpace(versions), constraints)
+		}
+		blocks[i] = header + "\n" + tw.String()
+
+		var missing []string
+		for _, p := range platforms {
+			if !trusting[p] {
+				missing = append(missing, string(p))
+			}
+		}
+		if len(missing) > 0 {
+			blocks[i] += "\nNot trusted by: " + strings.Join(missing, ", ")
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// FormatJSON formats each root with the stores that trust it and their constraints.
+func (o *WhoTrustsOutput) FormatJSON() ([]byte, error) {
+	out := []jsonRootTrust{}
+	for _, rt := range o.Roots {
+		jr := jsonRootTrust{Fingerprint: rt.Fingerprint.String(), Name: rt.Name, Stores: []jsonTrustingStore{}}
+		for _, s := range rt.Stores {
+			r := changelog.NewRoot(rt.Fingerprint, s.ConstraintFor(rt.Fingerprint), func(truststore.Fingerprint) string { return "" })
+			jr.Stores = append(jr.Stores, jsonTrustingStore{
+				Platform: string(s.Platform),
+				Version:  s.Version,
+				jsonRootConstraints: jsonRootConstraints{
+					NotBeforeMax: r.NotBeforeMax,
+					DistrustDate: r.DistrustDate,
+					SCTNotAfter:  r.SCTNotAfter,
+				},
+			})
+		}
+		out = append(out, jr)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonRootTrust struct {
+	Fingerprint string              `json:"fingerprint"`
+	Name        string              `json:"name"`
+	Stores      []jsonTrustingStore `json:"stores"`
+}
+
+type jsonTrustingStore struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+	jsonRootConstraints
+}
@@ -0,0 +1,55 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestWhoTrustsOutput(t *testing.T) {
+	t.Parallel()
+
+	distrust := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	root, other := truststore.Fingerprint{1}, truststore.Fingerprint{2}
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformAndroid, Version: "10", Fingerprints: []truststore.Fingerprint{other}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{root},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{root: {DistrustDate: &distrust}}},
+		{Platform: truststore.PlatformIOS, Version: "16", Fingerprints: []truststore.Fingerprint{root}},
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{root}},
+	}
+	o := NewWhoTrustsOutput("Test", []truststore.Fingerprint{root}, stores, func(truststore.Fingerprint) string { return "Test Root" })
+
+	text := o.FormatText()
+	for _, want := range []string{
+		"Test Root (" + root.String() + ")",
+		"<=17",
+		"=18",
+		"DT:2026-06-01",
+		"Not trusted by: android",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := o.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []jsonRootTrust
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Stores) != 3 || got[0].Stores[0].Version != "16" || got[0].Stores[2].DistrustDate == "" {
+		t.Errorf("json = %+v, want ios 16-18 with distrust date on 18", got)
+	}
+
+	none := NewWhoTrustsOutput("Nothing", nil, stores, func(truststore.Fingerprint) string { return "" }).FormatText()
+	if none != `No trusted root matches "Nothing"` {
+		t.Errorf("no match: %q", none)
+	}
+}
@@ -0,0 +1,91 @@
+// Package progress draws a single-line progress bar for long runs on a terminal.
+//
+// A nil *Bar is valid and reports nothing, so callers can disable progress
+// (non-terminal stderr, --quiet) without checks at every update.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Delay is how long a run must take before the bar is first drawn, so quick
+	// runs print nothing.
+	Delay = 500 * time.Millisecond
+	// interval limits how often the bar is redrawn.
+	interval = 100 * time.Millisecond
+	// width is the number of cells in the bar.
+	width = 30
+)
+
+// Bar is a progress bar redrawn in place with carriage returns. It is safe for concurrent use.
+type Bar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	start   time.Time
+	drawn   time.Time // Last redraw (zero if never drawn)
+	now     func() time.Time
+	lineLen int
+}
+
+// New returns a bar labeled label writing to w. The delay starts now.
+func New(w io.Writer, label string) *Bar {
+	return &Bar{w: w, label: label, start: time.Now(), now: time.Now}
+}
+
+// Update reports that done of total steps are complete. Redraws are throttled,
+// but the final step is always drawn once the bar is visible.
+func (b *Bar) Update(done, total int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Sub(b.start) < Delay {
+		return
+	}
+	if !b.drawn.IsZero() && now.Sub(b.drawn) < interval && done < total {
+		return
+	}
+	b.drawn = now
+	b.draw(render(b.label, done, total, now.Sub(b.start)))
+}
+
+// Finish erases the bar so later output starts on a clean line.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.lineLen > 0 {
+		b.draw("")
+	}
+}
+
+// draw overwrites the current line with line.
+func (b *Bar) draw(line string) {
+	pad := max(b.lineLen-len(line), 0)
+	_, _ = fmt.Fprint(b.w, "\r"+line+strings.Repeat(" ", pad)+"\r")
+	b.lineLen = len(line)
+}
+
+// render formats the bar, e.g. "Validating [=========>          ] 312/1040 (4s)".
+func render(label string, done, total int, elapsed time.Duration) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done, total) * width / total
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	return fmt.Sprintf("%s [%s] %d/%d (%s)", label, bar, done, total, elapsed.Round(time.Second))
+}
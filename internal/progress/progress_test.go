@@ -0,0 +1,95 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBar returns a bar writing to buf whose clock is advanced by the returned function.
+func fakeBar(buf *bytes.Buffer) (*Bar, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New(buf, "Fetching")
+	b.start = now
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBarDelay(t *testing.T) {
+	var buf bytes.Buffer
+	b, advance := fakeBar(&buf)
+
+	b.Update(1, 2)
+	b.Update(2, 2)
+	b.Finish()
+	if buf.Len() != 0 {
+		t.Errorf("quick run wrote %q, want nothing", buf.String())
+	}
+
+	advance(Delay)
+	b.Update(1, 4)
+	if !strings.Contains(buf.String(), "Fetching [") || !strings.Contains(buf.String(), "] 1/4 (1s)") {
+		t.Errorf("output = %q, want a drawn bar", buf.String())
+	}
+}
+
+func TestBarThrottle(t *testing.T) {
+	var buf bytes.Buffer
+	b, advance := fakeBar(&buf)
+	advance(Delay)
+
+	b.Update(1, 10)
+	b.Update(2, 10)
+	if strings.Contains(buf.String(), "2/10") {
+		t.Error("redraw within the interval should be skipped")
+	}
+	b.Update(10, 10)
+	if !strings.Contains(buf.String(), "10/10") {
+		t.Error("final step should always be drawn")
+	}
+	advance(interval)
+	b.Update(10, 10)
+	if n := strings.Count(buf.String(), "10/10"); n != 2 {
+		t.Errorf("drawn %d times after the interval, want 2", n)
+	}
+}
+
+func TestBarFinish(t *testing.T) {
+	var buf bytes.Buffer
+	b, advance := fakeBar(&buf)
+	advance(Delay)
+
+	b.Update(3, 4)
+	line := strings.Trim(buf.String(), "\r")
+	buf.Reset()
+	b.Finish()
+
+	want := "\r" + strings.Repeat(" ", len(line)) + "\r"
+	if buf.String() != want {
+		t.Errorf("Finish wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		done, total int
+		want        string
+	}{
+		{0, 4, "Validating [>" + strings.Repeat(" ", width-1) + "] 0/4 (2s)"},
+		{2, 4, "Validating [" + strings.Repeat("=", width/2) + ">" + strings.Repeat(" ", width/2-1) + "] 2/4 (2s)"},
+		{4, 4, "Validating [" + strings.Repeat("=", width) + "] 4/4 (2s)"},
+		{0, 0, "Validating [>" + strings.Repeat(" ", width-1) + "] 0/0 (2s)"},
+	}
+	for _, tt := range tests {
+		if got := render("Validating", tt.done, tt.total, 2*time.Second); got != tt.want {
+			t.Errorf("render(%d, %d) = %q, want %q", tt.done, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestNilBar(t *testing.T) {
+	var b *Bar
+	b.Update(1, 2)
+	b.Finish()
+}
@@ -0,0 +1,110 @@
+// Package release checks whether the running binary and its embedded data are current.
+//
+// certvet embeds trust store data at build time, so the data snapshot is the newest
+// commit touching trustdata/data on main, and the binary is the newest
+// GitHub release. Versions are CalVer build dates (vYYYY.MM.DD). Data installed by
+// certvet update is instead as current as the time its bundle was created.
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// DefaultAPIURL is the GitHub API base for the certvet repository.
+const DefaultAPIURL = "https://api.github.com/repos/ivoronin/certvet"
+
+// dataPath is the repository path of the embedded trust store data.
+const dataPath = "trustdata/data"
+
+// versionDateFormat is the CalVer layout of release versions (without "v" prefix).
+const versionDateFormat = "2006.01.02"
+
+// Status describes how the local build compares to the latest published release and data.
+type Status struct {
+	Current        string    // Running version
+	LatestRelease  string    // Latest release tag
+	DataUpdated    time.Time // Latest data commit on main
+	BinaryOutdated bool      // A newer release exists
+	DataOutdated   bool      // Data changed after the data in use was built
+	Known          bool      // Current is a CalVer release (false for dev builds)
+	DataKnown      bool      // The data in use has a known date (false for data embedded in dev builds)
+}
+
+// Outdated reports whether either the binary or data is outdated.
+func (s *Status) Outdated() bool {
+	return s.BinaryOutdated || s.DataOutdated
+}
+
+// Check queries apiURL for the latest release and data commit and compares them to current
+// and to dataCreated, the creation time of installed data in use (zero for embedded data).
+// Dev builds (non-CalVer versions) and their embedded data are never reported as outdated.
+func Check(apiURL, current string, dataCreated time.Time, timeout time.Duration) (*Status, error) {
+	client := &http.Client{Timeout: timeout}
+
+	var rel struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := getJSON(client, apiURL+"/releases/latest", &rel); err != nil {
+		return nil, fmt.Errorf("fetch latest release: %w", err)
+	}
+
+	var commits []struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	if err := getJSON(client, apiURL+"/commits?per_page=1&path="+dataPath, &commits); err != nil {
+		return nil, fmt.Errorf("fetch data commits: %w", err)
+	}
+
+	status := &Status{Current: current, LatestRelease: rel.TagName}
+	if len(commits) > 0 {
+		status.DataUpdated = commits[0].Commit.Committer.Date
+	}
+	if !dataCreated.IsZero() {
+		status.DataKnown = true
+		status.DataOutdated = status.DataUpdated.After(dataCreated)
+	}
+
+	built, err := time.Parse(versionDateFormat, strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return status, nil
+	}
+	status.Known = true
+	status.BinaryOutdated = version.LessThan(strings.TrimPrefix(current, "v"), strings.TrimPrefix(rel.TagName, "v"))
+	if dataCreated.IsZero() {
+		status.DataKnown = true
+		// Builds include all data committed before the build day ended
+		status.DataOutdated = status.DataUpdated.After(built.Add(24 * time.Hour))
+	}
+
+	return status, nil
+}
+
+// getJSON fetches url and decodes a JSON response into v.
+func getJSON(client *http.Client, url string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
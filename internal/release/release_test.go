@@ -0,0 +1,113 @@
+package release
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestAPI(t *testing.T, tag, dataDate string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"` + tag + `"}`))
+	})
+	mux.HandleFunc("/commits", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("path") != dataPath {
+			t.Errorf("unexpected commits path %q", r.URL.Query().Get("path"))
+		}
+		_, _ = w.Write([]byte(`[{"commit":{"committer":{"date":"` + dataDate + `"}}}]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		tag         string
+		dataDate    string
+		wantKnown   bool
+		wantBinary  bool
+		wantData    bool
+		wantOutdate bool
+	}{
+		{"up to date", "v2025.06.01", "v2025.06.01", "2025-06-01T12:00:00Z", true, false, false, false},
+		{"newer release", "v2025.06.01", "v2025.06.08", "2025-06-01T12:00:00Z", true, true, false, true},
+		{"newer data", "v2025.06.01", "v2025.06.01", "2025-06-05T00:00:00Z", true, false, true, true},
+		{"dev build", "dev", "v2025.06.08", "2025-06-05T00:00:00Z", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestAPI(t, tt.tag, tt.dataDate)
+
+			status, err := Check(srv.URL, tt.current, time.Time{}, time.Second)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if status.LatestRelease != tt.tag {
+				t.Errorf("LatestRelease = %q, want %q", status.LatestRelease, tt.tag)
+			}
+			if status.Known != tt.wantKnown {
+				t.Errorf("Known = %v, want %v", status.Known, tt.wantKnown)
+			}
+			if status.BinaryOutdated != tt.wantBinary {
+				t.Errorf("BinaryOutdated = %v, want %v", status.BinaryOutdated, tt.wantBinary)
+			}
+			if status.DataOutdated != tt.wantData {
+				t.Errorf("DataOutdated = %v, want %v", status.DataOutdated, tt.wantData)
+			}
+			if status.Outdated() != tt.wantOutdate {
+				t.Errorf("Outdated() = %v, want %v", status.Outdated(), tt.wantOutdate)
+			}
+		})
+	}
+}
+
+func TestCheckInstalledData(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		created  string
+		wantData bool
+	}{
+		{"installed after data commit", "v2025.06.01", "2025-06-08T00:00:00Z", false},
+		{"installed before data commit", "v2025.06.01", "2025-06-03T00:00:00Z", true},
+		{"dev build", "dev", "2025-06-03T00:00:00Z", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestAPI(t, "v2025.06.08", "2025-06-05T00:00:00Z")
+			created, err := time.Parse(time.RFC3339, tt.created)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			status, err := Check(srv.URL, tt.current, created, time.Second)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if !status.DataKnown {
+				t.Error("DataKnown = false for installed data")
+			}
+			if status.DataOutdated != tt.wantData {
+				t.Errorf("DataOutdated = %v, want %v", status.DataOutdated, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestCheckHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := Check(srv.URL, "v2025.06.01", time.Time{}, time.Second); err == nil {
+		t.Error("expected error for failing API")
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// pemContentType is returned for certificates requested as PEM.
+const pemContentType = "application/x-pem-file"
+
+type jsonDataStore struct {
+	Platform string           `json:"platform"`
+	Version  string           `json:"version"`
+	Roots    []changelog.Root `json:"roots"`
+}
+
+type jsonDataCert struct {
+	Fingerprint string         `json:"fingerprint"`
+	Kind        string         `json:"kind"` // "root" or "intermediate" (cross-sign)
+	Name        string         `json:"name"`
+	Subject     string         `json:"subject"`
+	Issuer      string         `json:"issuer"`
+	NotBefore   string         `json:"not_before"`
+	NotAfter    string         `json:"not_after"`
+	Stores      []jsonStoreRef `json:"stores"` // Stores trusting the certificate (roots only)
+	PEM         string         `json:"pem"`
+}
+
+type jsonStoreRef struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+}
+
+// handleDataStores returns store records with their roots and constraints, optionally
+// narrowed by the platform and version query parameters.
+func (s *Server) handleDataStores(w http.ResponseWriter, r *http.Request) {
+	platform := strings.ToLower(r.URL.Query().Get("platform"))
+	version := r.URL.Query().Get("version")
+
+	out := []jsonDataStore{}
+	for _, store := range s.stores {
+		if platform != "" && string(store.Platform) != platform {
+			continue
+		}
+		if version != "" && store.Version != version {
+			continue
+		}
+		ds := jsonDataStore{Platform: string(store.Platform), Version: store.Version, Roots: []changelog.Root{}}
+		for _, fp := range store.Fingerprints {
+			ds.Roots = append(ds.Roots, changelog.NewRoot(fp, store.ConstraintFor(fp), s.certName))
+		}
+		out = append(out, ds)
+	}
+
+	if len(out) == 0 && (platform != "" || version != "") {
+		writeError(w, http.StatusNotFound, "no stores match platform="+platform+" version="+version)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleDataCert returns a root or cross-signed intermediate by fingerprint, as JSON or as PEM
+// with ?format=pem or an Accept header preferring PEM.
+func (s *Server) handleDataCert(w http.ResponseWriter, r *http.Request) {
+	fp, err := truststore.ParseFingerprint(r.PathValue("fingerprint"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	kind := "root"
+	cert := s.certs.Get(fp)
+	if cert == nil && s.crossSigns != nil {
+		kind = "intermediate"
+		cert = s.crossSigns.Get(fp)
+	}
+	if cert == nil {
+		writeError(w, http.StatusNotFound, "certificate "+fp.String()+" not found")
+		return
+	}
+
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if r.URL.Query().Get("format") == "pem" || strings.Contains(r.Header.Get("Accept"), pemContentType) {
+		w.Header().Set("Content-Type", pemContentType)
+		_, _ = w.Write(block)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, jsonDataCert{
+		Fingerprint: fp.String(),
+		Kind:        kind,
+		Name:        truststore.CertName(cert),
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:    cert.NotAfter.UTC().Format(time.RFC3339),
+		Stores:      s.storesWith(fp),
+		PEM:         string(block),
+	})
+}
+
+// storesWith lists the stores that include a root.
+func (s *Server) storesWith(fp truststore.Fingerprint) []jsonStoreRef {
+	out := []jsonStoreRef{}
+	for _, store := range s.index.Trusting(fp) {
+		out = append(out, jsonStoreRef{Platform: string(store.Platform), Version: store.Version})
+	}
+	return out
+}
+
+// certName resolves a root's display name (empty if unknown).
+func (s *Server) certName(fp truststore.Fingerprint) string {
+	if cert := s.certs.Get(fp); cert != nil {
+		return truststore.CertName(cert)
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// testRoot creates a self-signed root certificate.
+func testRoot(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// testServer serves two iOS stores and an Android store over generated roots.
+func testServer(t *testing.T) (*Server, *x509.Certificate) {
+	t.Helper()
+
+	root := testRoot(t, "Test Root")
+	fp := truststore.FingerprintFromCert(root)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fp, root)
+
+	distrust := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{fp: {DistrustDate: &distrust}}},
+		{Platform: truststore.PlatformAndroid, Version: "14"},
+	}
+	return New(stores, certs, nil), root
+}
+
+func get(t *testing.T, h http.Handler, url string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDataStores(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := testServer(t)
+	tests := []struct {
+		url        string
+		wantStatus int
+		wantStores int
+	}{
+		{"/data/stores", http.StatusOK, 3},
+		{"/data/stores?platform=ios", http.StatusOK, 2},
+		{"/data/stores?platform=IOS&version=18", http.StatusOK, 1},
+		{"/data/stores?platform=ios&version=99", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		rec := get(t, srv, tt.url)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("GET %s: status %d, want %d", tt.url, rec.Code, tt.wantStatus)
+			continue
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+		var stores []jsonDataStore
+		if err := json.Unmarshal(rec.Body.Bytes(), &stores); err != nil {
+			t.Fatalf("GET %s: %v", tt.url, err)
+		}
+		if len(stores) != tt.wantStores {
+			t.Errorf("GET %s: %d stores, want %d", tt.url, len(stores), tt.wantStores)
+		}
+	}
+
+	var stores []jsonDataStore
+	_ = json.Unmarshal(get(t, srv, "/data/stores?platform=ios&version=18").Body.Bytes(), &stores)
+	if len(stores) != 1 || len(stores[0].Roots) != 1 || stores[0].Roots[0].Name != "Test Root" ||
+		stores[0].Roots[0].DistrustDate != "2026-06-01T00:00:00Z" {
+		t.Errorf("ios 18 store = %+v, want Test Root with distrust date", stores)
+	}
+}
+
+func TestDataCert(t *testing.T) {
+	t.Parallel()
+
+	srv, root := testServer(t)
+	fp := truststore.FingerprintFromCert(root)
+	// Fingerprints are accepted in any format ParseFingerprint understands
+	url := "/data/certs/" + strings.ToLower(strings.ReplaceAll(fp.String(), ":", ""))
+
+	rec := get(t, srv, url)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status %d: %s", url, rec.Code, rec.Body.String())
+	}
+	var cert jsonDataCert
+	if err := json.Unmarshal(rec.Body.Bytes(), &cert); err != nil {
+		t.Fatal(err)
+	}
+	if cert.Kind != "root" || cert.Name != "Test Root" || len(cert.Stores) != 2 || !strings.Contains(cert.PEM, "BEGIN CERTIFICATE") {
+		t.Errorf("cert = %+v", cert)
+	}
+
+	for _, rec := range []*httptest.ResponseRecorder{
+		get(t, srv, url+"?format=pem"),
+		get(t, srv, url, "Accept", pemContentType),
+	} {
+		if rec.Header().Get("Content-Type") != pemContentType || !strings.HasPrefix(rec.Body.String(), "-----BEGIN CERTIFICATE-----") {
+			t.Errorf("PEM response: %s %q", rec.Header().Get("Content-Type"), rec.Body.String())
+		}
+	}
+
+	if rec := get(t, srv, "/data/certs/not-a-fingerprint"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid fingerprint: status %d, want 400", rec.Code)
+	}
+	if rec := get(t, srv, "/data/certs/"+truststore.Fingerprint{1}.String()); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown fingerprint: status %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,88 @@
+// Package server implements certvet's read-only HTTP API (certvet serve).
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Server serves the HTTP API over a trust store dataset.
+type Server struct {
+	stores      []truststore.Store
+	index       *truststore.StoreIndex                       // Roots of stores
+	certs       *truststore.CertIndex                        // Root certificates
+	crossSigns  *truststore.CertIndex                        // Cross-signed intermediates (may be nil)
+	ctLogs      map[[32]byte]truststore.CTLog                // CT logs SCTs are checked against
+	caInfos     map[truststore.Fingerprint]truststore.CAInfo // Root CA metadata of the trust data in use
+	validation  *Validation                                  // Endpoint validation settings (nil until WithValidation)
+	dataVersion string                                       // Reported trust data version (empty until WithDataVersion)
+	dataDate    time.Time                                    // When the trust data was generated (zero if unknown)
+	mux         *http.ServeMux
+}
+
+// New creates a server for stores and their certificates. Validation checks SCTs against
+// the CT logs of the trust data in use, and listed roots are described by its CA info.
+func New(stores []truststore.Store, certs, crossSigns *truststore.CertIndex) *Server {
+	s := &Server{stores: stores, index: truststore.NewStoreIndex(stores), certs: certs, crossSigns: crossSigns, ctLogs: truststore.CTLogs, caInfos: truststore.CAInfos, dataDate: truststore.Sources.Generated, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /data/stores", s.handleDataStores)
+	s.mux.HandleFunc("GET /data/certs/{fingerprint}", s.handleDataCert)
+	s.mux.HandleFunc("GET /stores", s.handleStores)
+	return s
+}
+
+// NewFromDataset creates a server for a dataset independent of the trust data in use,
+// so a long-running process can swap servers when its data is reloaded.
+func NewFromDataset(ds *truststore.Dataset) *Server {
+	s := New(ds.Stores, ds.Certs, ds.CrossSigns)
+	s.ctLogs = ds.CTLogs
+	s.caInfos = ds.CAInfos
+	s.dataDate = ds.Sources.Generated
+	return s
+}
+
+// WithDataVersion sets the trust data version reported in validation and store listings.
+func (s *Server) WithDataVersion(version string) *Server {
+	s.dataVersion = version
+	return s
+}
+
+// ServeHTTP dispatches a request to the API handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// writeFormatted writes a formatter's JSON output, as printed by the matching CLI command.
+func writeFormatted(w http.ResponseWriter, f output.Formatter) {
+	data, err := f.FormatJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, jsonError{Error: msg})
+}
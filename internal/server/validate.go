@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/endpoints"
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/filter"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+// Validation configures the /validate endpoint.
+type Validation struct {
+	Source      fetcher.ChainSource            // Fetches endpoint chains
+	Issuers     func() validator.IssuerFetcher // Creates a per-request AIA fetcher for missing intermediates (nil disables)
+	ToolVersion string                         // Reported in validation reports
+}
+
+// WithValidation enables GET /validate, which connects to caller-supplied endpoints.
+func (s *Server) WithValidation(cfg Validation) *Server {
+	s.validation = &cfg
+	s.mux.HandleFunc("GET /validate", s.handleValidate)
+	return s
+}
+
+// handleValidate validates the endpoint query parameter against the stores matching the
+// optional filter parameter and returns the report in validate's JSON format.
+// With verify_hostname=true a hostname mismatch fails validation, as --verify-hostname does.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("endpoint") == "" {
+		writeError(w, http.StatusBadRequest, "missing endpoint parameter")
+		return
+	}
+	targets, err := endpoints.FromArgs([]string{query.Get("endpoint")})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stores, ok := s.filterStores(w, query["filter"])
+	if !ok {
+		return
+	}
+
+	// The connection error is not returned, so the server can't be used to probe which
+	// hosts and ports are reachable from it and how
+	chain, err := s.validation.Source.FetchChain(targets[0].Endpoint)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "cannot fetch certificate chain from endpoint")
+		return
+	}
+
+	v := validator.NewWithData(stores, s.certs, s.ctLogs)
+	if s.validation.Issuers != nil {
+		v = v.WithIssuerFetcher(s.validation.Issuers())
+	}
+	results := v.Validate(chain)
+
+	allPassed := true
+	for _, r := range results {
+		if !r.Trusted {
+			allPassed = false
+			break
+		}
+	}
+	var hostname *truststore.HostnameCheck
+	if query.Get("verify_hostname") == "true" {
+		check := validator.VerifyHostname(chain)
+		hostname = &check
+		allPassed = allPassed && check.Valid
+	}
+
+	writeFormatted(w, output.NewValidationOutput(&truststore.ValidationReport{
+		Endpoint:    targets[0].Endpoint,
+		Timestamp:   time.Now(),
+		ToolVersion: s.validation.ToolVersion,
+		DataVersion: s.dataVersion,
+		DataDate:    s.dataDate,
+		Chain:       *chain,
+		Results:     results,
+		AllPassed:   allPassed,
+		Hostname:    hostname,
+	}))
+}
+
+// handleStores returns store entries matching the optional filter parameter in list's JSON format.
+func (s *Server) handleStores(w http.ResponseWriter, r *http.Request) {
+	stores, ok := s.filterStores(w, r.URL.Query()["filter"])
+	if !ok {
+		return
+	}
+	writeFormatted(w, &output.StoreList{
+		Entries:     output.ListEntries(stores, s.certs, s.caInfos, false),
+		DataVersion: s.dataVersion,
+		DataDate:    s.dataDate,
+	})
+}
+
+// filterStores selects the stores matching any of the filter expressions (all stores if
+// none), writing an error response and returning false if one is invalid or none match.
+func (s *Server) filterStores(w http.ResponseWriter, exprs []string) ([]truststore.Store, bool) {
+	f, err := filter.ParseAllFor(exprs, s.stores)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid filter: "+err.Error())
+		return nil, false
+	}
+	stores := filter.FilterStores(s.stores, f)
+	if len(stores) == 0 {
+		writeError(w, http.StatusNotFound, "no trust stores match filter")
+		return nil, false
+	}
+	return stores, true
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/fetcher"
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// testChain creates a root registered in truststore.Certs (which the validator reads)
+// and a leaf for example.com issued by it.
+func testChain(t *testing.T) (*x509.Certificate, *truststore.CertChain) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Validate Test Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	truststore.Certs.Add(truststore.FingerprintFromCert(root), root)
+	return root, &truststore.CertChain{Endpoint: "example.com", ServerCert: leaf}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	root, chain := testChain(t)
+	fp := truststore.FingerprintFromCert(root)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "14"},
+	}
+	source := fetcher.ChainSourceFunc(func(endpoint string) (*truststore.CertChain, error) {
+		if endpoint != "example.com" {
+			return nil, errors.New("connection refused")
+		}
+		return chain, nil
+	})
+	srv := New(stores, truststore.Certs, nil).WithValidation(Validation{Source: source, ToolVersion: "test"})
+
+	tests := []struct {
+		url        string
+		wantStatus int
+		wantPassed bool
+		wantCount  int
+	}{
+		{"/validate?endpoint=example.com&filter=ios", http.StatusOK, true, 1},
+		{"/validate?endpoint=example.com&filter=android>=15&filter=ios", http.StatusOK, true, 1},
+		{"/validate?endpoint=https://example.com/path", http.StatusOK, false, 2},
+		{"/validate", http.StatusBadRequest, false, 0},
+		{"/validate?endpoint=example.com&filter=ios>>", http.StatusBadRequest, false, 0},
+		{"/validate?endpoint=example.com&filter=android>=15", http.StatusNotFound, false, 0},
+		{"/validate?endpoint=example.com&filter=windows", http.StatusBadRequest, false, 0},
+		{"/validate?endpoint=other.example", http.StatusBadGateway, false, 0},
+	}
+
+	for _, tt := range tests {
+		rec := get(t, srv, tt.url)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("GET %s: status %d, want %d: %s", tt.url, rec.Code, tt.wantStatus, rec.Body.String())
+			continue
+		}
+		if tt.wantStatus == http.StatusBadGateway && strings.Contains(rec.Body.String(), "connection refused") {
+			t.Errorf("GET %s: connection error disclosed: %s", tt.url, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+		var report struct {
+			Endpoint  string            `json:"endpoint"`
+			AllPassed bool              `json:"all_passed"`
+			Results   []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("GET %s: %v", tt.url, err)
+		}
+		if report.AllPassed != tt.wantPassed || len(report.Results) != tt.wantCount {
+			t.Errorf("GET %s: all_passed=%v with %d results, want %v with %d",
+				tt.url, report.AllPassed, len(report.Results), tt.wantPassed, tt.wantCount)
+		}
+	}
+
+	// Without WithValidation the endpoint doesn't exist
+	if rec := get(t, New(stores, truststore.Certs, nil), "/validate?endpoint=example.com"); rec.Code != http.StatusNotFound {
+		t.Errorf("validation disabled: status %d, want 404", rec.Code)
+	}
+}
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	srv, root := testServer(t)
+	rec := get(t, srv, "/stores?filter=ios>=18")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var entries []struct {
+		Platform    string `json:"platform"`
+		Version     string `json:"version"`
+		Fingerprint string `json:"fingerprint"`
+		Issuer      string `json:"issuer"`
+		Constraints string `json:"constraints"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	want := truststore.FingerprintFromCert(root).String()
+	if len(entries) != 1 || entries[0].Fingerprint != want || entries[0].Issuer != "Test Root" || entries[0].Constraints != "DT:2026-06-01" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if rec := get(t, srv, "/stores?filter=android>=15"); rec.Code != http.StatusNotFound {
+		t.Errorf("unmatched filter: status %d, want 404", rec.Code)
+	}
+}
+
+func TestStoresDatasetPlatforms(t *testing.T) {
+	t.Parallel()
+
+	root := testRoot(t, "Reloaded Root")
+	fp := truststore.FingerprintFromCert(root)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fp, root)
+	if truststore.KnownPlatform("linux") {
+		t.Fatal("embedded data has a linux store")
+	}
+
+	srv := NewFromDataset(&truststore.Dataset{
+		Stores: []truststore.Store{{Platform: "linux", Version: "2", Fingerprints: []truststore.Fingerprint{fp}}},
+		Certs:  certs,
+	})
+	tests := []struct {
+		filter     string
+		wantStatus int
+	}{
+		{"linux", http.StatusOK},          // added by the dataset
+		{"ios", http.StatusBadRequest},    // embedded, but not in the dataset
+		{"linux>=3", http.StatusNotFound}, // known platform, no matching version
+	}
+	for _, tt := range tests {
+		if rec := get(t, srv, "/stores?filter="+tt.filter); rec.Code != tt.wantStatus {
+			t.Errorf("filter %s: status %d, want %d: %s", tt.filter, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+	}
+}
+
+func TestStoresDatasetCAInfo(t *testing.T) {
+	t.Parallel()
+
+	root := testRoot(t, "Reloaded Root")
+	fp := truststore.FingerprintFromCert(root)
+	certs, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs.Add(fp, root)
+	if _, ok := truststore.CAInfos[fp]; ok {
+		t.Fatal("generated root has embedded CA info")
+	}
+
+	srv := NewFromDataset(&truststore.Dataset{
+		Stores:  []truststore.Store{{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}}},
+		Certs:   certs,
+		CAInfos: map[truststore.Fingerprint]truststore.CAInfo{fp: {Fingerprint: fp, Operator: "Reloaded CA Operator", Country: "ZZ"}},
+	})
+	rec := get(t, srv, "/stores")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var entries []struct {
+		Operator string `json:"operator"`
+		Country  string `json:"country"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Operator != "Reloaded CA Operator" || entries[0].Country != "ZZ" {
+		t.Errorf("entries = %+v, want the dataset's CA info", entries)
+	}
+}
@@ -0,0 +1,289 @@
+// Package testchains provides deliberately broken certificate chains for exercising
+// the validation pipeline end to end.
+//
+// Fixtures are generated on each run from fresh keys and validated against a synthetic
+// trust store holding only the fixture root, so results don't depend on embedded data.
+package testchains
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/validator"
+)
+
+// Rule codes reported for fixture outcomes.
+const (
+	CodePass              = "pass"
+	CodeUnknownAuthority  = "unknown_authority"
+	CodeExpired           = "expired"
+	CodeInsecureAlgorithm = "insecure_algorithm"
+	CodeNameConstraint    = "name_constraint"
+	CodeDistrusted        = "distrusted"
+	CodeOther             = "other"
+)
+
+// fixtureHost is the name every fixture leaf is issued for.
+const fixtureHost = "fixture.certvet.test"
+
+// fixturePlatform labels the synthetic trust store; it matches no real platform policy.
+const fixturePlatform truststore.Platform = "fixture"
+
+// Fixture is a generated chain with the rule code it is expected to trigger.
+type Fixture struct {
+	Name        string
+	Description string
+	Expected    string
+	Chain       *truststore.CertChain
+	Store       truststore.Store
+}
+
+// Outcome is the result of validating a fixture.
+type Outcome struct {
+	Fixture Fixture
+	Actual  string // Rule code derived from the result
+	Result  truststore.TrustResult
+}
+
+// OK reports whether the fixture triggered the expected rule.
+func (o Outcome) OK() bool {
+	return o.Actual == o.Fixture.Expected
+}
+
+// Run generates all fixtures and validates each against its synthetic store. Fixture
+// roots are resolved in a private index, leaving the dataset in use untouched.
+func Run() ([]Outcome, error) {
+	fixtures, roots, err := Fixtures()
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]Outcome, len(fixtures))
+	for i, f := range fixtures {
+		result := validator.NewWithData([]truststore.Store{f.Store}, roots, nil).Validate(f.Chain)[0]
+		outcomes[i] = Outcome{Fixture: f, Actual: Code(result), Result: result}
+	}
+	return outcomes, nil
+}
+
+// Code classifies a trust result into a rule code.
+func Code(r truststore.TrustResult) string {
+	if r.Trusted {
+		return CodePass
+	}
+	switch r.FailureCode {
+	case truststore.FailureUnknownAuthority, truststore.FailureMissingIntermediate:
+		return CodeUnknownAuthority
+	case truststore.FailureExpired:
+		return CodeExpired
+	case truststore.FailureInsecureAlgorithm:
+		return CodeInsecureAlgorithm
+	case truststore.FailureNameConstraints:
+		return CodeNameConstraint
+	case truststore.FailureDistrustDate:
+		return CodeDistrusted
+	default:
+		return CodeOther
+	}
+}
+
+// builder generates a fixture chain and the store it is validated against, adding the
+// store's root to roots.
+type builder func(now time.Time, roots *truststore.CertIndex) (*truststore.CertChain, truststore.Store, error)
+
+// specs lists fixtures in display order.
+var specs = []struct {
+	name        string
+	description string
+	expected    string
+	build       builder
+}{
+	{"valid", "Well-formed chain", CodePass, standard(nil)},
+	{"expired", "Leaf expired yesterday", CodeExpired, standard(func(now time.Time, _, leaf *x509.Certificate) {
+		leaf.NotBefore = now.Add(-48 * time.Hour)
+		leaf.NotAfter = now.Add(-24 * time.Hour)
+	})},
+	{"wrong-order", "Two intermediates presented in reverse order", CodePass, buildWrongOrder},
+	{"missing-intermediate", "Server omits the intermediate", CodeUnknownAuthority, buildMissingIntermediate},
+	{"sha1", "Leaf signed with ECDSA-SHA1", CodeInsecureAlgorithm, standard(func(_ time.Time, _, leaf *x509.Certificate) {
+		leaf.SignatureAlgorithm = x509.ECDSAWithSHA1
+	})},
+	{"constrained-ca", "Intermediate name-constrained to another domain", CodeNameConstraint, standard(func(_ time.Time, inter, _ *x509.Certificate) {
+		inter.PermittedDNSDomainsCritical = true
+		inter.PermittedDNSDomains = []string{"example.org"}
+	})},
+	{"distrusted-root", "Root distrusted by the store since yesterday", CodeDistrusted, buildDistrustedRoot},
+}
+
+// Fixtures generates the fixture chains and returns them with an index of their roots.
+func Fixtures() ([]Fixture, *truststore.CertIndex, error) {
+	roots, err := truststore.NewCertIndex([]byte("fingerprint,pem\n"))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	fixtures := make([]Fixture, len(specs))
+	for i, spec := range specs {
+		chain, store, err := spec.build(now, roots)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fixture %s: %w", spec.name, err)
+		}
+		fixtures[i] = Fixture{spec.name, spec.description, spec.expected, chain, store}
+	}
+	return fixtures, roots, nil
+}
+
+// standard builds root -> intermediate -> leaf, applying tweak to the templates before signing.
+func standard(tweak func(now time.Time, inter, leaf *x509.Certificate)) builder {
+	return func(now time.Time, roots *truststore.CertIndex) (*truststore.CertChain, truststore.Store, error) {
+		root, err := newRoot(now, roots)
+		if err != nil {
+			return nil, truststore.Store{}, err
+		}
+		interTmpl, leafTmpl := caTemplate("Fixture Intermediate CA", now), leafTemplate(now)
+		if tweak != nil {
+			tweak(now, interTmpl, leafTmpl)
+		}
+		inter, err := root.issue(interTmpl)
+		if err != nil {
+			return nil, truststore.Store{}, err
+		}
+		leaf, err := inter.issue(leafTmpl)
+		if err != nil {
+			return nil, truststore.Store{}, err
+		}
+		chain := &truststore.CertChain{Endpoint: fixtureHost, ServerCert: leaf.cert, Intermediates: []*x509.Certificate{inter.cert}}
+		return chain, root.store(), nil
+	}
+}
+
+// buildWrongOrder presents root -> A -> B -> leaf as [leaf, A, B] instead of [leaf, B, A].
+func buildWrongOrder(now time.Time, roots *truststore.CertIndex) (*truststore.CertChain, truststore.Store, error) {
+	root, err := newRoot(now, roots)
+	if err != nil {
+		return nil, truststore.Store{}, err
+	}
+	a, err := root.issue(caTemplate("Fixture Intermediate CA", now))
+	if err != nil {
+		return nil, truststore.Store{}, err
+	}
+	b, err := a.issue(caTemplate("Fixture Issuing CA", now))
+	if err != nil {
+		return nil, truststore.Store{}, err
+	}
+	leaf, err := b.issue(leafTemplate(now))
+	if err != nil {
+		return nil, truststore.Store{}, err
+	}
+	chain := &truststore.CertChain{Endpoint: fixtureHost, ServerCert: leaf.cert, Intermediates: []*x509.Certificate{a.cert, b.cert}}
+	return chain, root.store(), nil
+}
+
+func buildMissingIntermediate(now time.Time, roots *truststore.CertIndex) (*truststore.CertChain, truststore.Store, error) {
+	chain, store, err := standard(nil)(now, roots)
+	if err != nil {
+		return nil, store, err
+	}
+	chain.Intermediates = nil
+	return chain, store, nil
+}
+
+func buildDistrustedRoot(now time.Time, roots *truststore.CertIndex) (*truststore.CertChain, truststore.Store, error) {
+	chain, store, err := standard(nil)(now, roots)
+	if err != nil {
+		return nil, store, err
+	}
+	distrust := now.Add(-24 * time.Hour)
+	store.Constraints = map[truststore.Fingerprint]truststore.Constraints{
+		store.Fingerprints[0]: {DistrustDate: &distrust},
+	}
+	return chain, store, nil
+}
+
+// issued is a generated certificate with its key.
+type issued struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newRoot creates a self-signed root and adds it to roots.
+func newRoot(now time.Time, roots *truststore.CertIndex) (*issued, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := caTemplate("Fixture Root CA", now)
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	roots.Add(truststore.FingerprintFromCert(cert), cert)
+	return &issued{cert, key}, nil
+}
+
+// issue signs tmpl with a fresh key.
+func (i *issued) issue(tmpl *x509.Certificate) (*issued, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, i.cert, &key.PublicKey, i.key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &issued{cert, key}, nil
+}
+
+// store returns a synthetic trust store holding only this root.
+func (i *issued) store() truststore.Store {
+	return truststore.Store{
+		Platform:     fixturePlatform,
+		Version:      "current",
+		Fingerprints: []truststore.Fingerprint{truststore.FingerprintFromCert(i.cert)},
+	}
+}
+
+func caTemplate(name string, now time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber:          serial(),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"certvet fixtures"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+}
+
+func leafTemplate(now time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: serial(),
+		Subject:      pkix.Name{CommonName: fixtureHost},
+		DNSNames:     []string{fixtureHost},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+}
+
+// serial returns a random positive 64-bit serial number.
+func serial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 63))
+	return n.Add(n, big.NewInt(1))
+}
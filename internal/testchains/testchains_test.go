@@ -0,0 +1,45 @@
+package testchains
+
+import (
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestRun(t *testing.T) {
+	outcomes, err := Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != len(specs) {
+		t.Fatalf("got %d outcomes, want %d", len(outcomes), len(specs))
+	}
+	for _, o := range outcomes {
+		if !o.OK() {
+			t.Errorf("%s: expected %s, got %s (%s)", o.Fixture.Name, o.Fixture.Expected, o.Actual, o.Result.FailureReason)
+		}
+		// Fixture roots stay out of the dataset in use
+		if fp := o.Fixture.Store.Fingerprints[0]; truststore.Certs.Has(fp) {
+			t.Errorf("%s: fixture root registered in truststore.Certs", o.Fixture.Name)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		result truststore.TrustResult
+		want   string
+	}{
+		{truststore.TrustResult{Trusted: true}, CodePass},
+		{truststore.TrustResult{FailureCode: truststore.FailureUnknownAuthority}, CodeUnknownAuthority},
+		{truststore.TrustResult{FailureCode: truststore.FailureMissingIntermediate}, CodeUnknownAuthority},
+		{truststore.TrustResult{FailureCode: truststore.FailureExpired}, CodeExpired},
+		{truststore.TrustResult{FailureCode: truststore.FailureDistrustDate}, CodeDistrusted},
+		{truststore.TrustResult{FailureCode: truststore.FailureSCTDeadline}, CodeOther},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.result); got != tt.want {
+			t.Errorf("Code(%q) = %s, want %s", tt.result.FailureCode, got, tt.want)
+		}
+	}
+}
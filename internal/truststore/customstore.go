@@ -0,0 +1,91 @@
+package truststore
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// CustomStore is a user-defined trust store, such as a private PKI or an in-house device
+// fleet. It is a platform of its own with a single "current" version trusting Certs.
+type CustomStore struct {
+	Platform Platform
+	Certs    []*x509.Certificate
+}
+
+// customPlatformName restricts custom platform names to what filter expressions can spell.
+var customPlatformName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// customStoreExts are the file extensions read from a custom store directory.
+var customStoreExts = map[string]bool{".pem": true, ".crt": true, ".cer": true}
+
+// LoadCustomStore reads a "name=dir" spec. Every .pem, .crt and .cer file in dir is read
+// as PEM; other files and subdirectories are ignored. The name must not be a known platform.
+func LoadCustomStore(spec string) (CustomStore, error) {
+	name, dir, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || dir == "" {
+		return CustomStore{}, fmt.Errorf("custom store %q: want name=dir", spec)
+	}
+	platform := Platform(name)
+	if !customPlatformName.MatchString(name) || name == version.Current {
+		return CustomStore{}, fmt.Errorf("custom store %q: name must be lowercase letters, digits, '-' or '_'", name)
+	}
+	if KnownPlatform(platform) {
+		return CustomStore{}, fmt.Errorf("custom store %q: platform already exists", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return CustomStore{}, fmt.Errorf("read custom store: %w", err)
+	}
+	var certs []*x509.Certificate
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !customStoreExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path) //nolint:gosec // G304: Path is in a user-specified store directory
+		if err != nil {
+			return CustomStore{}, fmt.Errorf("read custom store: %w", err)
+		}
+		parsed, err := parsePEMCerts(path, data)
+		if err != nil {
+			return CustomStore{}, err
+		}
+		certs = append(certs, parsed...)
+	}
+	if len(certs) == 0 {
+		return CustomStore{}, fmt.Errorf("no PEM certificates in %s", dir)
+	}
+
+	return CustomStore{Platform: platform, Certs: certs}, nil
+}
+
+// Store returns the trust store of c, without duplicate roots.
+func (c CustomStore) Store() Store {
+	s := Store{Platform: c.Platform, Version: version.Current}
+	seen := make(map[Fingerprint]bool)
+	for _, cert := range c.Certs {
+		fp := FingerprintFromCert(cert)
+		if !seen[fp] {
+			seen[fp] = true
+			s.Fingerprints = append(s.Fingerprints, fp)
+		}
+	}
+	return s
+}
+
+// Add registers the roots of c in Certs and appends its store to Stores, so that it is
+// filtered, listed and validated like the embedded platforms. Like Use, it must be
+// called before any validation starts.
+func (c CustomStore) Add() {
+	for i, cert := range c.Certs {
+		c.Certs[i] = Certs.Intern(cert)
+	}
+	useStores(append(Stores, c.Store()))
+}
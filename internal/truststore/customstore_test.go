@@ -0,0 +1,112 @@
+package truststore
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/trustdata"
+)
+
+// writePEM writes certs to path as PEM.
+func writePEM(t *testing.T, path string, certs ...[]byte) {
+	t.Helper()
+	var data []byte
+	for _, der := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCustomStore(t *testing.T) {
+	t.Parallel()
+
+	root1 := generateRootCert(t, "Corp Root 1")
+	root2 := generateRootCert(t, "Corp Root 2")
+
+	dir := t.TempDir()
+	writePEM(t, filepath.Join(dir, "root1.pem"), root1.Raw)
+	writePEM(t, filepath.Join(dir, "root2.CRT"), root2.Raw, root1.Raw)
+	writePEM(t, filepath.Join(dir, "notes.txt"), generateRootCert(t, "Ignored").Raw)
+	if err := os.Mkdir(filepath.Join(dir, "sub.pem"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	empty := t.TempDir()
+	if err := os.WriteFile(filepath.Join(empty, "readme.pem"), []byte("no certificates\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"directory", "corp=" + dir, ""},
+		{"missing name", "=" + dir, "want name=dir"},
+		{"missing dir", "corp", "want name=dir"},
+		{"invalid name", "Corp PKI=" + dir, "name must be"},
+		{"version name", "current=" + dir, "name must be"},
+		{"embedded platform", "ios=" + dir, "already exists"},
+		{"missing directory", "corp=" + filepath.Join(dir, "missing"), "read custom store"},
+		{"no certificates", "corp=" + empty, "no PEM certificates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadCustomStore(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCustomStore() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCustomStore() error = %v", err)
+			}
+			if got.Platform != "corp" || len(got.Certs) != 3 {
+				t.Errorf("LoadCustomStore() = %d certs for %q, want 3 for corp", len(got.Certs), got.Platform)
+			}
+
+			store := got.Store()
+			want := []Fingerprint{FingerprintFromCert(root1), FingerprintFromCert(root2)}
+			if store.Platform != "corp" || store.Version != "current" || len(store.Fingerprints) != len(want) {
+				t.Fatalf("Store() = %s/%s with %d roots, want corp/current with %d", store.Platform, store.Version, len(store.Fingerprints), len(want))
+			}
+			for i, fp := range want {
+				if store.Fingerprints[i] != fp {
+					t.Errorf("Fingerprints[%d] = %s, want %s", i, store.Fingerprints[i], fp)
+				}
+			}
+		})
+	}
+}
+
+// TestCustomStoreAdd replaces package data and must not run in parallel.
+func TestCustomStoreAdd(t *testing.T) {
+	saved := Stores
+	t.Cleanup(func() { useStores(saved) })
+
+	root := generateRootCert(t, "Lab Root")
+	CustomStore{Platform: "lab", Certs: []*x509.Certificate{root}}.Add()
+
+	if !KnownPlatform("lab") {
+		t.Error("custom platform should be known after Add")
+	}
+	store, ok := trustdata.Lookup("lab", "current")
+	if !ok || !store.Includes(FingerprintFromCert(root)) {
+		t.Errorf("Lookup(lab, current) = %v, %v; want the store with its root", store, ok)
+	}
+	if Certs.Get(FingerprintFromCert(root)) == nil {
+		t.Error("custom root should be registered in Certs")
+	}
+	if !ContainsFingerprint(FingerprintFromCert(root)) {
+		t.Error("custom root should be indexed")
+	}
+}
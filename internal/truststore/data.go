@@ -0,0 +1,125 @@
+package truststore
+
+import "github.com/ivoronin/certvet/trustdata"
+
+// Trust store data types and the embedded dataset live in the standalone trustdata
+// module; they are re-exported here so certvet code keeps a single import.
+type (
+	Platform    = trustdata.Platform
+	Store       = trustdata.Store
+	Constraints = trustdata.Constraints
+	Fingerprint = trustdata.Fingerprint
+	CertIndex   = trustdata.CertIndex
+	CTLog       = trustdata.CTLog
+	Dataset     = trustdata.Dataset
+	SourceInfo  = trustdata.SourceInfo
+	RootStatus  = trustdata.RootStatus
+	CAInfo      = trustdata.CAInfo
+	EVPolicy    = trustdata.EVPolicy
+	BlockedCert = trustdata.BlockedCert
+	StoreIndex  = trustdata.StoreIndex
+	StoreKey    = trustdata.StoreKey
+)
+
+const (
+	PlatformIOS          = trustdata.PlatformIOS
+	PlatformIPadOS       = trustdata.PlatformIPadOS
+	PlatformMacOS        = trustdata.PlatformMacOS
+	PlatformTVOS         = trustdata.PlatformTVOS
+	PlatformVisionOS     = trustdata.PlatformVisionOS
+	PlatformWatchOS      = trustdata.PlatformWatchOS
+	PlatformAndroid      = trustdata.PlatformAndroid
+	PlatformChrome       = trustdata.PlatformChrome
+	PlatformWindows      = trustdata.PlatformWindows
+	PlatformWinContainer = trustdata.PlatformWinContainer
+
+	CTLogPending   = trustdata.CTLogPending
+	CTLogQualified = trustdata.CTLogQualified
+	CTLogUsable    = trustdata.CTLogUsable
+	CTLogReadOnly  = trustdata.CTLogReadOnly
+	CTLogRetired   = trustdata.CTLogRetired
+	CTLogRejected  = trustdata.CTLogRejected
+
+	DateFormat = trustdata.DateFormat
+
+	RootIncluded         = trustdata.RootIncluded
+	RootPendingInclusion = trustdata.RootPendingInclusion
+	RootPendingRemoval   = trustdata.RootPendingRemoval
+	RootRemoved          = trustdata.RootRemoved
+
+	ProgramApple     = trustdata.ProgramApple
+	ProgramChrome    = trustdata.ProgramChrome
+	ProgramMicrosoft = trustdata.ProgramMicrosoft
+	ProgramMozilla   = trustdata.ProgramMozilla
+
+	CertBlocked   = trustdata.CertBlocked
+	CertAlwaysAsk = trustdata.CertAlwaysAsk
+)
+
+// The embedded dataset, loaded by trustdata's init. Certs is shared, so roots registered
+// with ExtraRoots.Register are visible to both packages.
+var (
+	Stores        = trustdata.Stores
+	Index         = trustdata.Index
+	Certs         = trustdata.Certs
+	CrossSigns    = trustdata.CrossSigns
+	Preloaded     = trustdata.Preloaded
+	CTLogs        = trustdata.CTLogs
+	ChangelogData = trustdata.ChangelogData
+	Sources       = trustdata.Sources
+	RootStatuses  = trustdata.RootStatuses
+	CAInfos       = trustdata.CAInfos
+	EVPolicies    = trustdata.EVPolicies
+)
+
+var (
+	ParseFingerprint     = trustdata.ParseFingerprint
+	FingerprintFromCert  = trustdata.FingerprintFromCert
+	FingerprintFromBytes = trustdata.FingerprintFromBytes
+	SPKIFingerprint      = trustdata.SPKIFingerprint
+	NewCertIndex         = trustdata.NewCertIndex
+	OpenCertIndex        = trustdata.OpenCertIndex
+	ParseStores          = trustdata.ParseStores
+	WriteStores          = trustdata.WriteStores
+	ParseCTLogs          = trustdata.ParseCTLogs
+	CertName             = trustdata.CertName
+	Lookup               = trustdata.Lookup
+	Trusting             = trustdata.Trusting
+	NewStoreIndex        = trustdata.NewStoreIndex
+	Open                 = trustdata.Open
+	Read                 = trustdata.Read
+	CheckQuality         = trustdata.CheckQuality
+	ParseRootStatuses    = trustdata.ParseRootStatuses
+	ProgramOf            = trustdata.ProgramOf
+	PendingRemoval       = trustdata.PendingRemoval
+	NewCAInfo            = trustdata.NewCAInfo
+	ParseCAInfos         = trustdata.ParseCAInfos
+	ParseEVPolicies      = trustdata.ParseEVPolicies
+	EVPolicyOIDs         = trustdata.EVPolicyOIDs
+	ParseBlocked         = trustdata.ParseBlocked
+)
+
+// Use replaces the trust store data of both packages with ds, for data installed by
+// certvet update. It must be called before any validation starts.
+func Use(ds *Dataset) {
+	trustdata.Use(ds)
+	Stores = ds.Stores
+	Index = trustdata.Index
+	Certs = ds.Certs
+	CrossSigns = ds.CrossSigns
+	Preloaded = ds.Preloaded
+	CTLogs = ds.CTLogs
+	ChangelogData = ds.Changelog
+	Sources = ds.Sources
+	RootStatuses = ds.RootStatuses
+	CAInfos = ds.CAInfos
+	EVPolicies = ds.EVPolicies
+}
+
+// useStores replaces Stores in both packages, reindexing them.
+func useStores(stores []Store) {
+	trustdata.Stores = stores
+	trustdata.Index = trustdata.NewStoreIndex(stores)
+	Stores = stores
+	Index = trustdata.Index
+}
@@ -0,0 +1,103 @@
+package truststore
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExtraRoots are user-supplied root CAs (e.g., an MDM-pushed enterprise root) installed on
+// simulated devices of one platform, or of every platform if Platform is empty.
+type ExtraRoots struct {
+	Certs    []*x509.Certificate
+	Platform Platform
+}
+
+// LoadExtraRoots reads a "file.pem[:platform]" spec. The file may hold several certificates.
+func LoadExtraRoots(spec string) (ExtraRoots, error) {
+	path := spec
+	var platform Platform
+	// A Windows drive letter ("C:\ca.pem") leaves a path separator after the colon
+	if i := strings.LastIndex(spec, ":"); i > 0 && !strings.ContainsAny(spec[i+1:], `/\`) {
+		path, platform = spec[:i], Platform(spec[i+1:])
+		if !KnownPlatform(platform) {
+			return ExtraRoots{}, fmt.Errorf("extra roots %s: unknown platform %q", spec, platform)
+		}
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Path is user-specified for reading roots
+	if err != nil {
+		return ExtraRoots{}, fmt.Errorf("read extra roots: %w", err)
+	}
+
+	certs, err := parsePEMCerts(path, data)
+	if err != nil {
+		return ExtraRoots{}, err
+	}
+	if len(certs) == 0 {
+		return ExtraRoots{}, fmt.Errorf("no PEM certificates in %s", path)
+	}
+
+	return ExtraRoots{Certs: certs, Platform: platform}, nil
+}
+
+// parsePEMCerts parses the CERTIFICATE blocks of PEM data read from path, skipping other blocks.
+func parsePEMCerts(path string, data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate in %s: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// Register adds the roots to Certs so validators can resolve their fingerprints.
+// Roots already in Certs are replaced in e.Certs by the loaded instance.
+func (e ExtraRoots) Register() {
+	for i, cert := range e.Certs {
+		e.Certs[i] = Certs.Intern(cert)
+	}
+}
+
+// KnownPlatform reports whether any store in Stores, embedded or custom, belongs to p.
+func KnownPlatform(p Platform) bool {
+	return len(VersionsFor(p)) > 0
+}
+
+// WithExtraRoots returns stores followed by a copy of every store that receives extra roots,
+// with the roots added and recorded in Extra, so stock and customized results can be compared.
+// Callers must register the roots in Certs (see Register) before validating.
+func WithExtraRoots(stores []Store, extras []ExtraRoots) []Store {
+	out := append([]Store(nil), stores...)
+	for _, s := range stores {
+		variant := s
+		variant.Fingerprints = append([]Fingerprint(nil), s.Fingerprints...)
+		variant.Extra = make(map[Fingerprint]bool)
+		for _, e := range extras {
+			if e.Platform != "" && e.Platform != s.Platform {
+				continue
+			}
+			for _, cert := range e.Certs {
+				fp := FingerprintFromCert(cert)
+				variant.Fingerprints = append(variant.Fingerprints, fp)
+				variant.Extra[fp] = true
+			}
+		}
+		if variant.HasExtraRoots() {
+			out = append(out, variant)
+		}
+	}
+	return out
+}
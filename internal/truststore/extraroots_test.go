@@ -0,0 +1,118 @@
+package truststore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generateRootCert creates a self-signed certificate.
+func generateRootCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestLoadExtraRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certA := generateRootCert(t, "Corp Root A")
+	certB := generateRootCert(t, "Corp Root B")
+	bundle := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certA.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certB.Raw})...)
+	path := filepath.Join(dir, "corp.pem")
+	if err := os.WriteFile(path, bundle, 0600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		spec         string
+		wantPlatform Platform
+		wantErr      string
+	}{
+		{"all platforms", path, "", ""},
+		{"single platform", path + ":ios", PlatformIOS, ""},
+		{"unknown platform", path + ":symbian", "", "unknown platform"},
+		{"missing file", filepath.Join(dir, "missing.pem"), "", "read extra roots"},
+		{"no certificates", empty, "", "no PEM certificates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadExtraRoots(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadExtraRoots() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadExtraRoots() error = %v", err)
+			}
+			if len(got.Certs) != 2 || got.Platform != tt.wantPlatform {
+				t.Errorf("LoadExtraRoots() = %d certs for %q, want 2 for %q", len(got.Certs), got.Platform, tt.wantPlatform)
+			}
+		})
+	}
+}
+
+func TestWithExtraRoots(t *testing.T) {
+	t.Parallel()
+
+	corp := generateRootCert(t, "Corp Root")
+	stock := Fingerprint{0x01}
+	stores := []Store{
+		{Platform: PlatformIOS, Version: "17", Fingerprints: []Fingerprint{stock}},
+		{Platform: PlatformAndroid, Version: "14", Fingerprints: []Fingerprint{stock}},
+	}
+
+	got := WithExtraRoots(stores, []ExtraRoots{{Certs: []*x509.Certificate{corp}, Platform: PlatformIOS}})
+
+	if len(got) != 3 {
+		t.Fatalf("WithExtraRoots() returned %d stores, want stock stores plus one ios variant", len(got))
+	}
+	variant := got[2]
+	if variant.Platform != PlatformIOS || !variant.Extra[FingerprintFromCert(corp)] {
+		t.Errorf("variant = %s/%s extra=%v, want ios with corp root marked extra", variant.Platform, variant.Version, variant.Extra)
+	}
+	if len(variant.Fingerprints) != 2 || variant.Fingerprints[1] != FingerprintFromCert(corp) {
+		t.Errorf("variant fingerprints = %v, want stock plus corp root", variant.Fingerprints)
+	}
+	if len(stores[0].Fingerprints) != 1 {
+		t.Error("stock store was modified")
+	}
+}
@@ -0,0 +1,98 @@
+package truststore
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// DataOverlay is user-supplied trust data in the CSV formats of the embedded data
+// (stores.csv and certificates.csv), such as internally managed device fleets.
+type DataOverlay struct {
+	Stores  []Store
+	Certs   map[Fingerprint]*x509.Certificate
+	Replace bool // Overlay stores replace all loaded stores instead of being merged
+}
+
+// LoadDataOverlay reads a stores CSV and a certificates CSV, either of which may be "".
+// Certificates must match their fingerprints, and every root of an overlay store must
+// have certificate data in the overlay or the loaded data.
+func LoadDataOverlay(storesPath, certsPath string) (DataOverlay, error) {
+	o := DataOverlay{Certs: make(map[Fingerprint]*x509.Certificate)}
+
+	if certsPath != "" {
+		data, err := os.ReadFile(certsPath) //nolint:gosec // G304: Path is user-specified trust data
+		if err != nil {
+			return DataOverlay{}, fmt.Errorf("read certificates: %w", err)
+		}
+		idx, err := NewCertIndex(data)
+		if err != nil {
+			return DataOverlay{}, fmt.Errorf("certificates %s: %w", certsPath, err)
+		}
+		for _, fp := range idx.Fingerprints() {
+			cert, err := idx.Load(fp)
+			if err != nil {
+				return DataOverlay{}, fmt.Errorf("certificates %s: %w", certsPath, err)
+			}
+			if FingerprintFromCert(cert) != fp {
+				return DataOverlay{}, fmt.Errorf("certificates %s: cert %s: fingerprint does not match certificate", certsPath, fp.Truncate(4))
+			}
+			o.Certs[fp] = cert
+		}
+	}
+
+	if storesPath != "" {
+		f, err := os.Open(storesPath) //nolint:gosec // G304: Path is user-specified trust data
+		if err != nil {
+			return DataOverlay{}, fmt.Errorf("read stores: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		if o.Stores, err = ParseStores(f); err != nil {
+			return DataOverlay{}, fmt.Errorf("stores %s: %w", storesPath, err)
+		}
+		sort.Slice(o.Stores, func(i, j int) bool {
+			if o.Stores[i].Platform != o.Stores[j].Platform {
+				return o.Stores[i].Platform < o.Stores[j].Platform
+			}
+			return version.CompareAsc(o.Stores[i].Version, o.Stores[j].Version)
+		})
+	}
+
+	for _, s := range o.Stores {
+		for _, fp := range s.Fingerprints {
+			if o.Certs[fp] == nil && !Certs.Has(fp) {
+				return DataOverlay{}, fmt.Errorf("stores %s: %s %s: no certificate data for root %s", storesPath, s.Platform, s.Version, fp.Truncate(4))
+			}
+		}
+	}
+	return o, nil
+}
+
+// Apply registers the overlay certificates in Certs and merges the overlay stores into
+// Stores: a store replaces the loaded store of the same platform version, others are
+// added. With Replace, only the overlay stores remain. Like Use, it must be called
+// before any validation starts.
+func (o DataOverlay) Apply() {
+	for fp, cert := range o.Certs {
+		o.Certs[fp] = Certs.Intern(cert)
+	}
+
+	stores := o.Stores
+	if !o.Replace {
+		overlay := make(map[PlatformVersion]bool, len(o.Stores))
+		for _, s := range o.Stores {
+			overlay[PlatformVersion{Platform: s.Platform, Version: s.Version}] = true
+		}
+		stores = nil
+		for _, s := range Stores {
+			if !overlay[PlatformVersion{Platform: s.Platform, Version: s.Version}] {
+				stores = append(stores, s)
+			}
+		}
+		stores = append(stores, o.Stores...)
+	}
+	useStores(stores)
+}
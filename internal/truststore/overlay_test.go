@@ -0,0 +1,121 @@
+package truststore
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/trustdata"
+)
+
+// writeFile writes content to name in dir and returns the path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// certsCSV formats certificates.csv records, with the fingerprint column given.
+func certsCSV(fps []Fingerprint, ders [][]byte) string {
+	var b strings.Builder
+	b.WriteString("fingerprint,pem\n")
+	for i, der := range ders {
+		p := strings.ReplaceAll(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), "\n", `\n`)
+		b.WriteString(fps[i].String() + "," + p + "\n")
+	}
+	return b.String()
+}
+
+func TestLoadDataOverlay(t *testing.T) {
+	t.Parallel()
+
+	fleet := generateRootCert(t, "Fleet Root")
+	other := generateRootCert(t, "Other Root")
+	fleetFP, otherFP := FingerprintFromCert(fleet), FingerprintFromCert(other)
+
+	dir := t.TempDir()
+	certs := writeFile(t, dir, "certificates.csv", certsCSV([]Fingerprint{fleetFP}, [][]byte{fleet.Raw}))
+	mismatched := writeFile(t, dir, "mismatched.csv", certsCSV([]Fingerprint{otherFP}, [][]byte{fleet.Raw}))
+	header := "platform,version,fingerprint,not_before_max,distrust_date,sct_not_after\n"
+	stores := writeFile(t, dir, "stores.csv", header+
+		"android,99,"+fleetFP.String()+",,,\n"+
+		"android,98,"+fleetFP.String()+",,2030-01-01T00:00:00Z,\n")
+	missing := writeFile(t, dir, "missing.csv", header+"android,99,"+otherFP.String()+",,,\n")
+
+	tests := []struct {
+		name, stores, certs string
+		wantStores          int
+		wantErr             string
+	}{
+		{"stores and certificates", stores, certs, 2, ""},
+		{"certificates only", "", certs, 0, ""},
+		{"root without certificate", missing, certs, 0, "no certificate data"},
+		{"fingerprint mismatch", "", mismatched, 0, "does not match"},
+		{"missing file", filepath.Join(dir, "nope.csv"), "", 0, "read stores"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadDataOverlay(tt.stores, tt.certs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadDataOverlay() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadDataOverlay() error = %v", err)
+			}
+			if len(got.Stores) != tt.wantStores || got.Certs[fleetFP] == nil {
+				t.Fatalf("LoadDataOverlay() = %d stores, cert %v; want %d stores with the fleet root", len(got.Stores), got.Certs[fleetFP] != nil, tt.wantStores)
+			}
+			if tt.wantStores > 0 && (got.Stores[0].Version != "98" || got.Stores[0].ConstraintFor(fleetFP).DistrustDate == nil) {
+				t.Errorf("Stores[0] = %s %s, want android 98 with a distrust date first", got.Stores[0].Platform, got.Stores[0].Version)
+			}
+		})
+	}
+}
+
+// TestDataOverlayApply replaces package data and must not run in parallel.
+func TestDataOverlayApply(t *testing.T) {
+	saved := Stores
+	t.Cleanup(func() { useStores(saved) })
+
+	fleet := generateRootCert(t, "Fleet Root")
+	fp := FingerprintFromCert(fleet)
+	stock, ok := trustdata.Lookup(PlatformAndroid, "14")
+	if !ok {
+		t.Fatal("embedded data has no android 14 store")
+	}
+	overlay := DataOverlay{
+		Stores: []Store{
+			{Platform: PlatformAndroid, Version: "14", Fingerprints: []Fingerprint{fp}},
+			{Platform: "fleet", Version: "2", Fingerprints: []Fingerprint{fp}},
+		},
+		Certs: map[Fingerprint]*x509.Certificate{fp: fleet},
+	}
+
+	overlay.Apply()
+	if len(Stores) != len(saved)+1 {
+		t.Errorf("merged %d stores, want %d", len(Stores), len(saved)+1)
+	}
+	if s, _ := trustdata.Lookup(PlatformAndroid, "14"); len(s.Fingerprints) != 1 || s.Fingerprints[0] != fp {
+		t.Errorf("android 14 has %d roots, want only the overlay root (stock had %d)", len(s.Fingerprints), len(stock.Fingerprints))
+	}
+	if !KnownPlatform("fleet") || Certs.Get(fp) == nil {
+		t.Error("overlay platform and root should be known after Apply")
+	}
+
+	overlay.Replace = true
+	overlay.Apply()
+	if len(Stores) != 2 || KnownPlatform(PlatformIOS) {
+		t.Errorf("replaced stores = %d, want only the 2 overlay stores", len(Stores))
+	}
+}
@@ -0,0 +1,77 @@
+package truststore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// Query returns the stores in Stores that belong to platform and whose version satisfies
+// constraint, in Stores order. constraint is an operator and version as in filter
+// expressions (">=12", "<17.4", "<=current"), a bare version meaning equality, or ""
+// for every version. As in filters, "current" is newer than any numbered version.
+func Query(platform Platform, constraint string) ([]Store, error) {
+	match, err := versionMatcher(constraint)
+	if err != nil {
+		return nil, err
+	}
+	var out []Store
+	for _, s := range Stores {
+		if s.Platform == platform && match(s.Version) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// versionMatcher parses a version constraint for Query.
+func versionMatcher(constraint string) (func(string) bool, error) {
+	orig := constraint
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	// Longest operators first so ">=" is not read as ">"
+	ops := []struct {
+		op   string
+		test func(cmp int) bool
+	}{
+		{">=", func(cmp int) bool { return cmp >= 0 }},
+		{"<=", func(cmp int) bool { return cmp <= 0 }},
+		{">", func(cmp int) bool { return cmp > 0 }},
+		{"<", func(cmp int) bool { return cmp < 0 }},
+		{"=", func(cmp int) bool { return cmp == 0 }},
+	}
+	test := func(cmp int) bool { return cmp == 0 }
+	for _, o := range ops {
+		if rest, ok := strings.CutPrefix(constraint, o.op); ok {
+			constraint, test = strings.TrimSpace(rest), o.test
+			break
+		}
+	}
+	if constraint == "" || strings.ContainsAny(constraint, "<>=") {
+		return nil, fmt.Errorf("invalid version constraint %q", orig)
+	}
+	return func(v string) bool { return test(version.Compare(v, constraint)) }, nil
+}
+
+// ContainsFingerprint reports whether any store in Stores trusts the root with fingerprint fp.
+func ContainsFingerprint(fp Fingerprint) bool {
+	return Index.Contains(fp)
+}
+
+// VersionsFor returns the versions of platform's stores in Stores, oldest first.
+// It returns nil for a platform without stores.
+func VersionsFor(platform Platform) []string {
+	var out []string
+	for _, s := range Stores {
+		if s.Platform == platform {
+			out = append(out, s.Version)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return version.CompareAsc(out[i], out[j]) })
+	return out
+}
@@ -0,0 +1,62 @@
+package truststore
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestQuery replaces package data and must not run in parallel.
+func TestQuery(t *testing.T) {
+	saved := Stores
+	t.Cleanup(func() { useStores(saved) })
+
+	fp1, fp2 := Fingerprint{0x01}, Fingerprint{0x02}
+	useStores([]Store{
+		{Platform: PlatformAndroid, Version: "14", Fingerprints: []Fingerprint{fp1}},
+		{Platform: PlatformAndroid, Version: "7.1"},
+		{Platform: PlatformAndroid, Version: "10"},
+		{Platform: PlatformChrome, Version: "current", Fingerprints: []Fingerprint{fp1}},
+	})
+
+	tests := []struct {
+		platform   Platform
+		constraint string
+		want       []string
+		wantErr    bool
+	}{
+		{PlatformAndroid, "", []string{"14", "7.1", "10"}, false},
+		{PlatformAndroid, ">=10", []string{"14", "10"}, false},
+		{PlatformAndroid, "< 10", []string{"7.1"}, false},
+		{PlatformAndroid, "14", []string{"14"}, false},
+		{PlatformChrome, ">99", []string{"current"}, false},
+		{PlatformChrome, "<current", nil, false},
+		{PlatformIOS, "", nil, false},
+		{PlatformAndroid, ">=", nil, true},
+		{PlatformAndroid, "=>10", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Query(tt.platform, tt.constraint)
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "invalid version constraint") {
+				t.Errorf("Query(%s, %q) error = %v, want invalid version constraint", tt.platform, tt.constraint, err)
+			}
+			continue
+		}
+		var versions []string
+		for _, s := range got {
+			versions = append(versions, s.Version)
+		}
+		if err != nil || !slices.Equal(versions, tt.want) {
+			t.Errorf("Query(%s, %q) = %v, %v; want %v", tt.platform, tt.constraint, versions, err, tt.want)
+		}
+	}
+
+	if got := VersionsFor(PlatformAndroid); !slices.Equal(got, []string{"7.1", "10", "14"}) {
+		t.Errorf("VersionsFor(android) = %v, want ascending versions", got)
+	}
+	if !ContainsFingerprint(fp1) || ContainsFingerprint(fp2) {
+		t.Error("ContainsFingerprint should find only roots of some store")
+	}
+}
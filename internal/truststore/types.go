@@ -1,4 +1,5 @@
-// Package truststore provides trust store data and validation types.
+// Package truststore provides validation types and re-exports the embedded trust store data
+// from the trustdata module.
 package truststore
 
 import (
@@ -6,58 +7,19 @@ import (
 	"time"
 )
 
-// Platform represents a supported platform.
-type Platform string
-
-const (
-	// Apple platforms
-	PlatformIOS      Platform = "ios"
-	PlatformIPadOS   Platform = "ipados"
-	PlatformMacOS    Platform = "macos"
-	PlatformTVOS     Platform = "tvos"
-	PlatformVisionOS Platform = "visionos"
-	PlatformWatchOS  Platform = "watchos"
-
-	// Other platforms
-	PlatformAndroid Platform = "android"
-	PlatformChrome  Platform = "chrome"
-	PlatformWindows Platform = "windows"
-)
-
-func (p Platform) String() string { return string(p) }
-
 // PlatformVersion represents a specific OS version.
 type PlatformVersion struct {
-	Platform Platform
-	Version  string // Semver string (e.g., "17.4", "18", "10")
-}
-
-// Store represents a platform version's trusted root CAs.
-type Store struct {
-	Platform     Platform
-	Version      string                      // Semver string (e.g., "17.4", "18", "10")
-	Fingerprints []Fingerprint               // SHA-256 fingerprints
-	Constraints  map[Fingerprint]Constraints // Per-CA date constraints (nil if none)
+	Platform   Platform
+	Version    string // Semver string (e.g., "17.4", "18", "10")
+	ExtraRoots bool   // Store includes user-supplied roots
 }
 
-// ConstraintFor returns constraints for a fingerprint (empty if none).
-func (s Store) ConstraintFor(fp Fingerprint) Constraints {
-	if s.Constraints == nil {
-		return Constraints{}
+// Label returns the version for display, suffixed with "+extra" for stores with user-supplied roots.
+func (pv PlatformVersion) Label() string {
+	if pv.ExtraRoots {
+		return pv.Version + "+extra"
 	}
-	return s.Constraints[fp]
-}
-
-// Constraints holds date-based trust constraints for a CA.
-type Constraints struct {
-	NotBeforeMax *time.Time // Windows: cert.NotBefore must be <= this
-	DistrustDate *time.Time // Windows: CA distrusted after this date
-	SCTNotAfter  *time.Time // Chrome: SCT timestamp must be <= this
-}
-
-// IsEmpty returns true if no constraints are set.
-func (c Constraints) IsEmpty() bool {
-	return c.NotBeforeMax == nil && c.DistrustDate == nil && c.SCTNotAfter == nil
+	return pv.Version
 }
 
 // SCTSource indicates where an SCT was obtained.
@@ -68,9 +30,6 @@ const (
 	SCTSourceEmbedded                  // Embedded in certificate
 )
 
-// DateFormat is the ISO 8601 date format used for displaying constraint dates.
-const DateFormat = "2006-01-02"
-
 // SCT represents a Signed Certificate Timestamp (RFC 6962).
 type SCT struct {
 	Timestamp time.Time // When the certificate was logged
@@ -83,16 +42,106 @@ type CertChain struct {
 	Endpoint      string
 	ServerCert    *x509.Certificate
 	Intermediates []*x509.Certificate
-	SCTs          []SCT // Signed Certificate Timestamps (from TLS + embedded)
+	SCTs          []SCT       // Signed Certificate Timestamps (from TLS + embedded)
+	OCSPStaple    *OCSPStaple // Stapled OCSP response (nil if server didn't staple)
+	MustStaple    bool        // Server certificate has the TLS Feature status_request extension
+	TLS           *TLSInfo    // Handshake parameters (nil if not captured)
+}
+
+// TLSInfo describes the TLS handshake observed when fetching a chain.
+type TLSInfo struct {
+	Version     uint16 // Negotiated protocol version (tls.VersionTLS*)
+	CipherSuite uint16 // Negotiated cipher suite
+	MinVersion  uint16 // Lowest protocol version the server accepts (0 if not probed)
+}
+
+// OCSP staple statuses.
+const (
+	OCSPStatusGood    = "good"
+	OCSPStatusRevoked = "revoked"
+	OCSPStatusUnknown = "unknown"
+	OCSPStatusInvalid = "invalid" // Response could not be parsed or doesn't cover the server certificate
+)
+
+// OCSPStaple summarizes an OCSP response stapled during the TLS handshake.
+// The response signature is not verified; it is reported for presence and freshness only.
+type OCSPStaple struct {
+	Status     string    // One of OCSPStatus* constants
+	ProducedAt time.Time // When the responder signed the response
+	ThisUpdate time.Time // Start of validity interval
+	NextUpdate time.Time // End of validity interval (zero if unspecified)
+	RevokedAt  time.Time // Revocation time (if revoked)
 }
 
 // TrustResult represents validation result for one platform version.
 type TrustResult struct {
-	Platform      PlatformVersion
-	Trusted       bool
-	MatchedCA     string              // Root CA name that anchored the chain
-	VerifiedChain []*x509.Certificate // Full validated chain (if trusted)
-	FailureReason string              // Why it failed (if not trusted)
+	Platform           PlatformVersion
+	Trusted            bool
+	MatchedCA          string              // Root CA name that anchored the chain
+	MatchedFingerprint Fingerprint         // Fingerprint of that root (zero if none anchored)
+	Constraints        Constraints         // Date constraints the store applies to that root
+	VerifiedChain      []*x509.Certificate // Full validated chain (if trusted)
+	FailureCode        FailureCode         // Category of the failure (empty if trusted)
+	FailureReason      string              // Why it failed (if not trusted)
+	Warnings           []string            // Non-fatal policy issues (e.g., platform CT policy)
+	Advisories         []string            // IDs of advisories matching certificates used by this result
+	TrustedUntil       time.Time           // Last moment the result stays trusted given known data (zero unless requested)
+	Forecast           *TrustForecast      // Upcoming trust loss within the lookahead window (nil if none)
+	PendingRemoval     bool                // The platform's root program plans to remove the matched root (set with forecasts)
+	EV                 bool                // The platform recognizes the chain as Extended Validation (set in EV mode)
+	Suggested          []*x509.Certificate // Cross-signed intermediates that would make a failing result pass
+}
+
+// FailureCode categorizes why a result is not trusted. Codes are stable identifiers for
+// automation; FailureReason carries the human-readable detail.
+type FailureCode string
+
+// Failure codes.
+const (
+	FailureUnknownAuthority    FailureCode = "unknown_authority"    // Chain does not reach a root in the store
+	FailureMissingIntermediate FailureCode = "missing_intermediate" // Server sent no issuer for its certificate
+	FailureRootUnavailable     FailureCode = "root_unavailable"     // Chain reaches a known root without certificate data
+	FailureNoRoots             FailureCode = "no_roots"             // Store has no usable root certificates
+	FailureExpired             FailureCode = "expired"              // A certificate is expired or not yet valid
+	FailureInvalidChain        FailureCode = "invalid_chain"        // Path is structurally invalid (CA flag, path length, key usage, names)
+	FailureNameConstraints     FailureCode = "name_constraint"      // A CA is not authorized for the host name
+	FailureInsecureAlgorithm   FailureCode = "insecure_algorithm"   // Chain is signed with a rejected algorithm
+	FailureValidityPeriod      FailureCode = "validity_period"      // Leaf validity exceeds the platform limit
+	FailureNotBeforeCutoff     FailureCode = "not_before_cutoff"    // Leaf issued after the root's trust cutoff
+	FailureDistrustDate        FailureCode = "distrusted"           // Root is distrusted
+	FailureSCTDeadline         FailureCode = "sct_deadline"         // No SCT before the root's SCT deadline
+	FailureBlocked             FailureCode = "blocked"              // Chain uses a certificate the platform blocks
+	FailureAlwaysAsk           FailureCode = "always_ask"           // Chain uses a certificate trusted only after a user prompt
+	FailureOther               FailureCode = "other"
+)
+
+// TrustForecast describes when a currently trusted result stops being trusted.
+type TrustForecast struct {
+	Date   time.Time // First moment the result fails
+	Reason string    // Failure reason at that moment
+}
+
+// AdvisoryEvent is a dated milestone in a CA incident timeline.
+type AdvisoryEvent struct {
+	Date        time.Time
+	Description string
+}
+
+// AdvisoryMatch links a known CA incident advisory to a certificate seen during validation.
+type AdvisoryMatch struct {
+	ID          string
+	Title       string
+	URL         string
+	Fingerprint Fingerprint // Matched certificate
+	Subject     string      // Matched certificate display name
+	Timeline    []AdvisoryEvent
+}
+
+// HostnameCheck is the result of verifying the server certificate covers the requested host.
+type HostnameCheck struct {
+	Host  string
+	Valid bool
+	Error string // Why verification failed (if not valid)
 }
 
 // ValidationReport is the complete output.
@@ -100,7 +149,13 @@ type ValidationReport struct {
 	Endpoint    string
 	Timestamp   time.Time
 	ToolVersion string
+	DataVersion string    // Version of the trust data validated against
+	DataDate    time.Time // When the trust data was generated (zero if unknown)
 	Chain       CertChain
 	Results     []TrustResult
 	AllPassed   bool
+	Advisories  []AdvisoryMatch // Matched incident advisories (nil unless requested)
+	Hostname    *HostnameCheck  // Hostname verification result (nil unless requested)
+	Error       string          // Connection error (bulk runs only; Chain and Results are empty)
+	EvaluatedAt time.Time       // Simulated validation time (zero when validated as of Timestamp)
 }
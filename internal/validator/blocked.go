@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// checkBlocked returns the failure for a chain using a certificate the store lists as
+// blocked or always ask (empty reason if none). Blocked certificates are never trusted;
+// always ask ones only after the user approves a prompt, which non-interactive clients
+// never show, so both fail.
+func checkBlocked(store truststore.Store, certs []*x509.Certificate) (truststore.FailureCode, string) {
+	if len(store.Blocked) == 0 {
+		return "", ""
+	}
+	for _, cert := range certs {
+		switch store.Blocked[truststore.FingerprintFromCert(cert)] {
+		case truststore.CertBlocked:
+			return truststore.FailureBlocked, fmt.Sprintf("certificate %q is blocked by the platform", truststore.CertName(cert))
+		case truststore.CertAlwaysAsk:
+			return truststore.FailureAlwaysAsk, fmt.Sprintf("certificate %q is trusted only after the user approves it", truststore.CertName(cert))
+		}
+	}
+	return "", ""
+}
+
+// sentCerts returns the certificates the server sent, leaf first.
+func sentCerts(chain *truststore.CertChain) []*x509.Certificate {
+	return append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...)
+}
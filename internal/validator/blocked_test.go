@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestValidateChainBlocked(t *testing.T) {
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	interCert, interKey := generateTestCert(t, true, caCert, caKey)
+	serverCert, _ := generateTestCert(t, false, interCert, interKey)
+	chain := &truststore.CertChain{
+		Endpoint:      "test.example.com",
+		ServerCert:    serverCert,
+		Intermediates: []*x509.Certificate{interCert},
+	}
+
+	rootFP := truststore.FingerprintFromCert(caCert)
+	interFP := truststore.FingerprintFromCert(interCert)
+	registerTestCert(rootFP, caCert)
+	defer unregisterTestCert(rootFP)
+	otherCA, _ := generateTestCert(t, true, nil, nil)
+	otherFP := truststore.FingerprintFromCert(otherCA)
+	registerTestCert(otherFP, otherCA)
+	defer unregisterTestCert(otherFP)
+
+	tests := []struct {
+		name    string
+		roots   []truststore.Fingerprint
+		blocked map[truststore.Fingerprint]string
+		code    truststore.FailureCode
+	}{
+		{"not listed", []truststore.Fingerprint{rootFP}, map[truststore.Fingerprint]string{{1}: truststore.CertBlocked}, ""},
+		{"blocked intermediate", []truststore.Fingerprint{rootFP}, map[truststore.Fingerprint]string{interFP: truststore.CertBlocked}, truststore.FailureBlocked},
+		{"always ask root", []truststore.Fingerprint{rootFP}, map[truststore.Fingerprint]string{rootFP: truststore.CertAlwaysAsk}, truststore.FailureAlwaysAsk},
+		{"untrusted sent intermediate", nil, map[truststore.Fingerprint]string{interFP: truststore.CertAlwaysAsk}, truststore.FailureAlwaysAsk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores := []truststore.Store{{
+				Platform:     truststore.PlatformIOS,
+				Version:      "18",
+				Fingerprints: append(tt.roots, otherFP),
+				Blocked:      tt.blocked,
+			}}
+			r := ValidateChain(chain, stores)[0]
+			if r.Trusted != (tt.code == "") || r.FailureCode != tt.code {
+				t.Errorf("result = trusted %v, code %q (%s), want code %q", r.Trusted, r.FailureCode, r.FailureReason, tt.code)
+			}
+		})
+	}
+}
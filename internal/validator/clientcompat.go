@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"crypto/tls"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+// tls13Since is the first platform version whose system TLS stack negotiates TLS 1.3 by default.
+// Platforms not listed (Chrome, Windows, visionOS) support it in every version in the data.
+var tls13Since = map[truststore.Platform]string{
+	truststore.PlatformAndroid: "10",
+	truststore.PlatformIOS:     "13",
+	truststore.PlatformIPadOS:  "13",
+	truststore.PlatformTVOS:    "13",
+	truststore.PlatformMacOS:   "10.15",
+	truststore.PlatformWatchOS: "6",
+}
+
+// checkClientCompat returns a warning if the observed handshake excludes clients of the
+// platform version regardless of trust, or empty string if compatible or unknown.
+// Requires a probed minimum version (see fetcher.ProbeMinVersion).
+func checkClientCompat(pv truststore.PlatformVersion, info *truststore.TLSInfo) string {
+	if info == nil || info.MinVersion != tls.VersionTLS13 {
+		return ""
+	}
+	since, ok := tls13Since[pv.Platform]
+	if !ok || !version.LessThan(pv.Version, since) {
+		return ""
+	}
+	return "client: server requires TLS 1.3, not supported before " + string(pv.Platform) + " " + since
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCheckClientCompat(t *testing.T) {
+	t.Parallel()
+
+	tls13Only := &truststore.TLSInfo{Version: tls.VersionTLS13, MinVersion: tls.VersionTLS13}
+	tls12 := &truststore.TLSInfo{Version: tls.VersionTLS13, MinVersion: tls.VersionTLS12}
+	notProbed := &truststore.TLSInfo{Version: tls.VersionTLS13}
+
+	pv := func(p truststore.Platform, v string) truststore.PlatformVersion {
+		return truststore.PlatformVersion{Platform: p, Version: v}
+	}
+
+	tests := []struct {
+		name     string
+		pv       truststore.PlatformVersion
+		info     *truststore.TLSInfo
+		wantWarn bool
+	}{
+		{"android 9 on TLS 1.3 only", pv(truststore.PlatformAndroid, "9"), tls13Only, true},
+		{"android 10 on TLS 1.3 only", pv(truststore.PlatformAndroid, "10"), tls13Only, false},
+		{"ios 12.1.3 on TLS 1.3 only", pv(truststore.PlatformIOS, "12.1.3"), tls13Only, true},
+		{"macos 10.15 on TLS 1.3 only", pv(truststore.PlatformMacOS, "10.15"), tls13Only, false},
+		{"chrome on TLS 1.3 only", pv(truststore.PlatformChrome, "current"), tls13Only, false},
+		{"android 7 with TLS 1.2", pv(truststore.PlatformAndroid, "7"), tls12, false},
+		{"android 7 not probed", pv(truststore.PlatformAndroid, "7"), notProbed, false},
+		{"no handshake info", pv(truststore.PlatformAndroid, "7"), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := checkClientCompat(tt.pv, tt.info)
+			if (got != "") != tt.wantWarn {
+				t.Errorf("checkClientCompat() = %q, wantWarn %v", got, tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// WithCrossSigns returns a validator that retries failing results with the given
+// cross-signed intermediates available and records on each result that would pass
+// the intermediates to serve (TrustResult.Suggested). Root pools are shared with v.
+func (v *Validator) WithCrossSigns(certs []*x509.Certificate) *Validator {
+	c := *v
+	c.crossSigns = certs
+	return &c
+}
+
+// suggest re-validates a failing result with cross-signed intermediates added to the
+// presented ones, returning the non-presented intermediates of the alternate chain.
+func (v *Validator) suggest(chain *truststore.CertChain, alternates *x509.CertPool, pool *storePool, now time.Time) []*x509.Certificate {
+	r := validateAgainstPool(chain, alternates, pool, now)
+	if !r.Trusted || len(r.VerifiedChain) < 3 {
+		return nil
+	}
+
+	presented := make(map[truststore.Fingerprint]bool)
+	for _, cert := range chain.Intermediates {
+		presented[truststore.FingerprintFromCert(cert)] = true
+	}
+
+	var suggested []*x509.Certificate
+	for _, cert := range r.VerifiedChain[1 : len(r.VerifiedChain)-1] {
+		if !presented[truststore.FingerprintFromCert(cert)] {
+			suggested = append(suggested, cert)
+		}
+	}
+	return suggested
+}
+
+// alternatePool returns the presented intermediates plus all cross-signs.
+func (v *Validator) alternatePool(presented *x509.CertPool) *x509.CertPool {
+	pool := presented.Clone()
+	for _, cert := range v.crossSigns {
+		pool.AddCert(cert)
+	}
+	return pool
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestValidatorWithCrossSigns(t *testing.T) {
+	t.Parallel()
+
+	// Old root is not in the store; new root cross-signs the old root's key and subject
+	oldRoot, oldKey := generateTestCert(t, true, nil, nil)
+	newRoot, newKey := generateTestCert(t, true, nil, nil)
+	intermediate, intKey := generateTestCert(t, true, oldRoot, oldKey)
+	serverCert, _ := generateTestCert(t, false, intermediate, intKey)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               oldRoot.Subject,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, newRoot, &oldKey.PublicKey, newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossSign, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newFP := truststore.FingerprintFromCert(newRoot)
+	registerTestCert(newFP, newRoot)
+	defer unregisterTestCert(newFP)
+
+	stores := []truststore.Store{{
+		Platform:     truststore.PlatformAndroid,
+		Version:      "14",
+		Fingerprints: []truststore.Fingerprint{newFP},
+	}}
+	chain := &truststore.CertChain{ServerCert: serverCert, Intermediates: []*x509.Certificate{intermediate}}
+
+	plain := New(stores).Validate(chain)[0]
+	if plain.Trusted || plain.Suggested != nil {
+		t.Fatalf("without cross-signs: trusted=%v suggested=%d, want untrusted without suggestion", plain.Trusted, len(plain.Suggested))
+	}
+
+	got := New(stores).WithCrossSigns([]*x509.Certificate{crossSign}).Validate(chain)[0]
+	if got.Trusted {
+		t.Fatal("suggestion must not change the verdict for the presented chain")
+	}
+	if len(got.Suggested) != 1 || !got.Suggested[0].Equal(crossSign) {
+		t.Errorf("Suggested = %d certs, want the cross-sign", len(got.Suggested))
+	}
+}
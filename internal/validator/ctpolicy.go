@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Apple CT policy (https://support.apple.com/en-us/103214)
+var appleCTPolicyStart = time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+
+const (
+	appleShortLifetime     = 180 * 24 * time.Hour // Boundary between 2 and 3 embedded SCTs
+	appleEmbeddedSCTsShort = 2                    // Lifetime <= 180 days
+	appleEmbeddedSCTsLong  = 3                    // Lifetime > 180 days
+	appleTLSSCTsRequired   = 2                    // SCTs delivered via TLS extension
+)
+
+// sctClockSkew tolerates small differences between a log's clock and the evaluation time.
+const sctClockSkew = 5 * time.Minute
+
+// checkAppleCTPolicy returns a warning if the server certificate lacks the SCTs Apple
+// platforms require, or empty string if compliant. Only SCTs that logs accept (see countsSCT)
+// are counted. Log operator diversity is not checked.
+func checkAppleCTPolicy(chain *truststore.CertChain, logs map[[32]byte]truststore.CTLog) string {
+	cert := chain.ServerCert
+	if cert.NotBefore.Before(appleCTPolicyStart) {
+		return ""
+	}
+
+	var embedded, tls int
+	for _, sct := range chain.SCTs {
+		if !countsSCT(sct, logs) {
+			continue
+		}
+		switch sct.Source {
+		case truststore.SCTSourceEmbedded:
+			embedded++
+		case truststore.SCTSourceTLS:
+			tls++
+		}
+	}
+
+	required := appleEmbeddedSCTsShort
+	if cert.NotAfter.Sub(cert.NotBefore) > appleShortLifetime {
+		required = appleEmbeddedSCTsLong
+	}
+
+	if embedded >= required || tls >= appleTLSSCTsRequired {
+		return ""
+	}
+
+	return fmt.Sprintf("Apple CT policy: %d embedded SCTs (need %d) and %d TLS SCTs (need %d)",
+		embedded, required, tls, appleTLSSCTsRequired)
+}
+
+// countsSCT reports whether an SCT comes from a log in a state that makes it count toward CT
+// compliance. Without log list data every SCT counts.
+func countsSCT(sct truststore.SCT, logs map[[32]byte]truststore.CTLog) bool {
+	if len(logs) == 0 {
+		return true
+	}
+	log, ok := logs[sct.LogID]
+	return ok && log.AcceptsSCT(sct.Timestamp)
+}
+
+// checkSCTLogs returns a warning for each SCT that doesn't count because its log is unknown,
+// retired before the SCT was issued, or not yet (or no longer) qualified.
+func checkSCTLogs(chain *truststore.CertChain, logs map[[32]byte]truststore.CTLog) []string {
+	var warnings []string
+	for _, sct := range chain.SCTs {
+		if countsSCT(sct, logs) {
+			continue
+		}
+		log, ok := logs[sct.LogID]
+		switch {
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("SCT from unknown CT log %X", sct.LogID[:8]))
+		case log.State == truststore.CTLogRetired:
+			warnings = append(warnings, fmt.Sprintf("SCT from retired CT log %s (retired %s)",
+				log.Description, log.StateSince.Format(truststore.DateFormat)))
+		default:
+			warnings = append(warnings, fmt.Sprintf("SCT from %s CT log %s", log.State, log.Description))
+		}
+	}
+	return warnings
+}
+
+// checkSCTTimestamps returns a warning for each SCT whose timestamp is implausible: later than
+// now (clients reject SCTs from the future), or issued by a frozen log after it froze or within
+// its maximum merge delay before, so the entry may never have been incorporated.
+func checkSCTTimestamps(chain *truststore.CertChain, logs map[[32]byte]truststore.CTLog, now time.Time) []string {
+	var warnings []string
+	for _, sct := range chain.SCTs {
+		log, ok := logs[sct.LogID]
+		name := fmt.Sprintf("CT log %X", sct.LogID[:8])
+		if ok {
+			name = log.Description
+		}
+
+		if sct.Timestamp.After(now.Add(sctClockSkew)) {
+			warnings = append(warnings, fmt.Sprintf("SCT from %s timestamped in the future (%s)",
+				name, sct.Timestamp.UTC().Format(time.RFC3339)))
+			continue
+		}
+		if !ok || log.MMD == 0 || log.StateSince.IsZero() {
+			continue
+		}
+
+		frozen := log.StateSince
+		switch {
+		case log.State != truststore.CTLogReadOnly && log.State != truststore.CTLogRetired:
+			continue
+		case !sct.Timestamp.Before(frozen):
+			// Retired logs are already reported by checkSCTLogs
+			if log.State == truststore.CTLogReadOnly {
+				warnings = append(warnings, fmt.Sprintf("SCT from %s issued after the log became read-only (%s)",
+					name, frozen.Format(truststore.DateFormat)))
+			}
+		case sct.Timestamp.After(frozen.Add(-log.MMD)):
+			warnings = append(warnings, fmt.Sprintf("SCT from %s issued within the log's %s maximum merge delay before it became %s",
+				name, formatMMD(log.MMD), log.State))
+		}
+	}
+	return warnings
+}
+
+// formatMMD formats a merge delay without zero units (e.g., "24h", "1m").
+func formatMMD(d time.Duration) string {
+	h, m, sec := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	var s string
+	if h > 0 {
+		s += strconv.Itoa(h) + "h"
+	}
+	if m > 0 {
+		s += strconv.Itoa(m) + "m"
+	}
+	if sec > 0 || s == "" {
+		s += strconv.Itoa(sec) + "s"
+	}
+	return s
+}
@@ -0,0 +1,246 @@
+package validator
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCheckAppleCTPolicy(t *testing.T) {
+	t.Parallel()
+
+	issued := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	shortLived := &x509.Certificate{NotBefore: issued, NotAfter: issued.Add(90 * 24 * time.Hour)}
+	longLived := &x509.Certificate{NotBefore: issued, NotAfter: issued.Add(365 * 24 * time.Hour)}
+	legacy := &x509.Certificate{NotBefore: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), NotAfter: issued}
+
+	scts := func(embedded, tls int) []truststore.SCT {
+		var out []truststore.SCT
+		for range embedded {
+			out = append(out, truststore.SCT{Source: truststore.SCTSourceEmbedded})
+		}
+		for range tls {
+			out = append(out, truststore.SCT{Source: truststore.SCTSourceTLS})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		scts     []truststore.SCT
+		wantWarn bool
+	}{
+		{"short lived with 2 embedded", shortLived, scts(2, 0), false},
+		{"short lived with 1 embedded", shortLived, scts(1, 0), true},
+		{"long lived with 2 embedded", longLived, scts(2, 0), true},
+		{"long lived with 3 embedded", longLived, scts(3, 0), false},
+		{"TLS delivered", longLived, scts(0, 2), false},
+		{"mixed insufficient", longLived, scts(1, 1), true},
+		{"no SCTs", shortLived, nil, true},
+		{"issued before policy", legacy, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chain := &truststore.CertChain{ServerCert: tt.cert, SCTs: tt.scts}
+			got := checkAppleCTPolicy(chain, nil)
+			if (got != "") != tt.wantWarn {
+				t.Errorf("checkAppleCTPolicy() = %q, wantWarn %v", got, tt.wantWarn)
+			}
+			if got != "" && !strings.Contains(got, "Apple CT policy") {
+				t.Errorf("warning should name the policy, got %q", got)
+			}
+		})
+	}
+}
+
+func TestValidateChainAppleCTWarning(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{ServerCert: serverCert} // No SCTs
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "35", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	results := ValidateChain(chain, stores)
+	for _, r := range results {
+		if !r.Trusted {
+			t.Errorf("%s: expected trusted, got %s", r.Platform.Platform, r.FailureReason)
+		}
+		wantWarn := r.Platform.Platform.IsApple()
+		if (len(r.Warnings) > 0) != wantWarn {
+			t.Errorf("%s: warnings = %v, want warning: %v", r.Platform.Platform, r.Warnings, wantWarn)
+		}
+	}
+}
+
+func TestValidateChainAppleCTExtraRoot(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{ServerCert: serverCert} // No SCTs
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{{
+		Platform:     truststore.PlatformIOS,
+		Version:      "18",
+		Fingerprints: []truststore.Fingerprint{fp},
+		Extra:        map[truststore.Fingerprint]bool{fp: true},
+	}}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	r := ValidateChain(chain, stores)[0]
+	if !r.Trusted {
+		t.Fatalf("user-installed root: expected trusted, got %s", r.FailureReason)
+	}
+	for _, w := range r.Warnings {
+		if strings.Contains(w, "CT policy") {
+			t.Errorf("user-installed root should be exempt from CT policy, got %q", w)
+		}
+	}
+	if !r.Platform.ExtraRoots {
+		t.Error("result should be marked as validated with extra roots")
+	}
+}
+
+func TestCheckSCTLogs(t *testing.T) {
+	t.Parallel()
+
+	retiredAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	usable := truststore.CTLog{ID: [32]byte{1}, Description: "Usable log", State: truststore.CTLogUsable}
+	retired := truststore.CTLog{ID: [32]byte{2}, Description: "Old log", State: truststore.CTLogRetired, StateSince: retiredAt}
+	logs := map[[32]byte]truststore.CTLog{usable.ID: usable, retired.ID: retired}
+
+	sct := func(id [32]byte, ts time.Time) truststore.SCT {
+		return truststore.SCT{LogID: id, Timestamp: ts, Source: truststore.SCTSourceEmbedded}
+	}
+	before, after := retiredAt.Add(-time.Hour), retiredAt.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		scts []truststore.SCT
+		logs map[[32]byte]truststore.CTLog
+		want string
+	}{
+		{"usable log", []truststore.SCT{sct(usable.ID, after)}, logs, ""},
+		{"retired log before retirement", []truststore.SCT{sct(retired.ID, before)}, logs, ""},
+		{"retired log after retirement", []truststore.SCT{sct(retired.ID, after)}, logs, "retired CT log Old log (retired 2025-03-01)"},
+		{"unknown log", []truststore.SCT{sct([32]byte{9}, after)}, logs, "unknown CT log 0900000000000000"},
+		{"no log list", []truststore.SCT{sct([32]byte{9}, after)}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := strings.Join(checkSCTLogs(&truststore.CertChain{SCTs: tt.scts}, tt.logs), "; ")
+			if (got == "") != (tt.want == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("checkSCTLogs() = %q, want containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAppleCTPolicyIgnoresRetiredLogs(t *testing.T) {
+	t.Parallel()
+
+	issued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: issued, NotAfter: issued.Add(90 * 24 * time.Hour)}
+	retired := truststore.CTLog{ID: [32]byte{2}, State: truststore.CTLogRetired, StateSince: issued.Add(-time.Hour)}
+	usable := truststore.CTLog{ID: [32]byte{1}, State: truststore.CTLogUsable}
+	logs := map[[32]byte]truststore.CTLog{usable.ID: usable, retired.ID: retired}
+
+	chain := &truststore.CertChain{ServerCert: cert, SCTs: []truststore.SCT{
+		{LogID: usable.ID, Timestamp: issued, Source: truststore.SCTSourceEmbedded},
+		{LogID: retired.ID, Timestamp: issued, Source: truststore.SCTSourceEmbedded},
+	}}
+
+	if got := checkAppleCTPolicy(chain, nil); got != "" {
+		t.Errorf("without log list both SCTs should count, got %q", got)
+	}
+	if got := checkAppleCTPolicy(chain, logs); !strings.Contains(got, "1 embedded SCTs") {
+		t.Errorf("SCT from retired log should not count, got %q", got)
+	}
+}
+
+func TestCheckSCTTimestamps(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	frozenAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	usable := truststore.CTLog{ID: [32]byte{1}, Description: "Usable log", State: truststore.CTLogUsable, MMD: 24 * time.Hour}
+	readOnly := truststore.CTLog{ID: [32]byte{2}, Description: "Frozen log", State: truststore.CTLogReadOnly,
+		StateSince: frozenAt, MMD: 24 * time.Hour}
+	retired := truststore.CTLog{ID: [32]byte{3}, Description: "Old log", State: truststore.CTLogRetired,
+		StateSince: frozenAt, MMD: time.Minute}
+	logs := map[[32]byte]truststore.CTLog{usable.ID: usable, readOnly.ID: readOnly, retired.ID: retired}
+
+	sct := func(id [32]byte, ts time.Time) truststore.SCT {
+		return truststore.SCT{LogID: id, Timestamp: ts, Source: truststore.SCTSourceTLS}
+	}
+
+	tests := []struct {
+		name string
+		sct  truststore.SCT
+		logs map[[32]byte]truststore.CTLog
+		want string
+	}{
+		{"past SCT", sct(usable.ID, now.Add(-time.Hour)), logs, ""},
+		{"within clock skew", sct(usable.ID, now.Add(time.Minute)), logs, ""},
+		{"future SCT", sct(usable.ID, now.Add(time.Hour)), logs, "SCT from Usable log timestamped in the future (2025-06-01T13:00:00Z)"},
+		{"future SCT without log list", sct([32]byte{9}, now.Add(time.Hour)), nil, "SCT from CT log 0900000000000000 timestamped in the future"},
+		{"read-only log well before freeze", sct(readOnly.ID, frozenAt.Add(-48*time.Hour)), logs, ""},
+		{"read-only log within MMD", sct(readOnly.ID, frozenAt.Add(-time.Hour)), logs,
+			"SCT from Frozen log issued within the log's 24h maximum merge delay before it became readonly"},
+		{"read-only log after freeze", sct(readOnly.ID, frozenAt.Add(time.Hour)), logs,
+			"SCT from Frozen log issued after the log became read-only (2025-03-01)"},
+		{"retired log within MMD", sct(retired.ID, frozenAt.Add(-30*time.Second)), logs,
+			"within the log's 1m maximum merge delay before it became retired"},
+		{"retired log after retirement", sct(retired.ID, frozenAt.Add(time.Hour)), logs, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chain := &truststore.CertChain{SCTs: []truststore.SCT{tt.sct}}
+			got := strings.Join(checkSCTTimestamps(chain, tt.logs, now), "; ")
+			if (got == "") != (tt.want == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("checkSCTTimestamps() = %q, want containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMMD(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{24 * time.Hour, "24h"},
+		{30 * time.Minute, "30m"},
+		{10 * time.Minute, "10m"},
+		{90 * time.Second, "1m30s"},
+		{50 * time.Second, "50s"},
+		{25*time.Hour + 10*time.Second, "25h10s"},
+	}
+	for _, tt := range tests {
+		if got := formatMMD(tt.d); got != tt.want {
+			t.Errorf("formatMMD(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
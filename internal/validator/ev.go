@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// anyPolicy (RFC 5280) lets a CA certificate pass on every policy asserted below it.
+const anyPolicy = "2.5.29.32.0"
+
+// WithEV returns a validator that checks whether each trusted result's platform recognizes
+// the chain as Extended Validation, setting TrustResult.EV or warning why not. Root pools
+// are shared with v.
+func (v *Validator) WithEV(enabled bool) *Validator {
+	c := *v
+	c.ev = enabled
+	return &c
+}
+
+// annotateEV sets EV on a trusted result, or adds a warning explaining why the platform
+// doesn't treat the chain as EV.
+func annotateEV(r *truststore.TrustResult) {
+	if reason := checkEV(r.Platform.Platform, r.MatchedFingerprint, r.VerifiedChain); reason != "" {
+		r.Warnings = append(r.Warnings, "not EV: "+reason)
+		return
+	}
+	r.EV = true
+}
+
+// checkEV returns why verified (leaf first, root last) is not EV on platform, or "" if it is:
+// the leaf must assert a policy OID the platform's root program enabled for EV on the root,
+// and every intermediate must assert that policy or anyPolicy.
+func checkEV(platform truststore.Platform, root truststore.Fingerprint, verified []*x509.Certificate) string {
+	if truststore.ProgramOf(platform) == "" {
+		return fmt.Sprintf("%s has no EV root program data", platform)
+	}
+	enabled := truststore.EVPolicyOIDs(platform, root)
+	if len(enabled) == 0 {
+		return "root is not EV-enabled"
+	}
+	if len(verified) == 0 {
+		return "no verified chain"
+	}
+
+	var reason string
+	for _, oid := range policyOIDs(verified[0]) {
+		if !slices.Contains(enabled, oid) {
+			continue
+		}
+		if reason = checkEVPath(oid, verified); reason == "" {
+			return ""
+		}
+	}
+	if reason == "" {
+		reason = "leaf asserts no EV policy of the root"
+	}
+	return reason
+}
+
+// checkEVPath returns which intermediate of verified doesn't permit policy oid ("" if all do).
+func checkEVPath(oid string, verified []*x509.Certificate) string {
+	if len(verified) < 3 {
+		return ""
+	}
+	for _, ca := range verified[1 : len(verified)-1] {
+		policies := policyOIDs(ca)
+		if !slices.Contains(policies, oid) && !slices.Contains(policies, anyPolicy) {
+			return fmt.Sprintf("intermediate %q does not permit policy %s", truststore.CertName(ca), oid)
+		}
+	}
+	return ""
+}
+
+// policyOIDs returns the certificate policy OIDs of cert in dotted form.
+func policyOIDs(cert *x509.Certificate) []string {
+	oids := make([]string, len(cert.Policies))
+	for i, oid := range cert.Policies {
+		oids[i] = oid.String()
+	}
+	return oids
+}
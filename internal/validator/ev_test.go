@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// TestCheckEV replaces package data and must not run in parallel.
+func TestCheckEV(t *testing.T) {
+	oid := func(s string) x509.OID {
+		o, err := x509.ParseOID(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+	ev, other := oid("2.23.140.1.1"), oid("2.23.140.1.2.1")
+
+	root := truststore.Fingerprint{0xEE}
+	truststore.EVPolicies[root] = map[string][]string{truststore.ProgramMicrosoft: {"2.23.140.1.1"}}
+	t.Cleanup(func() { delete(truststore.EVPolicies, root) })
+
+	rootCert := &x509.Certificate{}
+	tests := []struct {
+		name     string
+		platform truststore.Platform
+		root     truststore.Fingerprint
+		chain    []*x509.Certificate
+		want     string // Substring of the reason, empty if EV
+	}{
+		{
+			name:     "EV leaf via anyPolicy intermediate",
+			platform: truststore.PlatformWindows,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{other, ev}}, {Policies: []x509.OID{oid(anyPolicy)}}, rootCert},
+		},
+		{
+			name:     "EV leaf issued by root",
+			platform: truststore.PlatformWinContainer,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{ev}}, rootCert},
+		},
+		{
+			name:     "OV leaf",
+			platform: truststore.PlatformWindows,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{other}}, rootCert},
+			want:     "leaf asserts no EV policy",
+		},
+		{
+			name:     "intermediate without the policy",
+			platform: truststore.PlatformWindows,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{ev}}, {Policies: []x509.OID{other}}, rootCert},
+			want:     "does not permit policy 2.23.140.1.1",
+		},
+		{
+			name:     "root not EV-enabled by the platform's program",
+			platform: truststore.PlatformIOS,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{ev}}, rootCert},
+			want:     "root is not EV-enabled",
+		},
+		{
+			name:     "platform without a root program",
+			platform: truststore.PlatformAndroid,
+			root:     root,
+			chain:    []*x509.Certificate{{Policies: []x509.OID{ev}}, rootCert},
+			want:     "no EV root program data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkEV(tt.platform, tt.root, tt.chain)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("checkEV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidatorWithEV replaces package data and must not run in parallel.
+func TestValidatorWithEV(t *testing.T) {
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fp}}}
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	if r := New(stores).Validate(chain); r[0].EV || len(r[0].Warnings) != 0 {
+		t.Errorf("EV checked without EV mode: %+v", r[0])
+	}
+	r := New(stores).WithEV(true).Validate(chain)
+	if !r[0].Trusted || r[0].EV || len(r[0].Warnings) != 1 || !strings.HasPrefix(r[0].Warnings[0], "not EV: ") {
+		t.Errorf("EV mode result = %+v, want a not EV warning", r[0])
+	}
+}
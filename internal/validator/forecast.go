@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"crypto/x509"
+	"sort"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// WithLookahead returns a validator that records on each trusted result the first moment
+// within window when it will fail (see truststore.TrustForecast). Root pools are shared with v.
+func (v *Validator) WithLookahead(window time.Duration) *Validator {
+	c := *v
+	c.lookahead = window
+	return &c
+}
+
+// WithTrustedUntil returns a validator that records on each trusted result the last moment it
+// stays trusted given known data (see truststore.TrustResult.TrustedUntil). Root pools are shared with v.
+func (v *Validator) WithTrustedUntil(enabled bool) *Validator {
+	c := *v
+	c.horizon = enabled
+	return &c
+}
+
+// endOfTime bounds forecasts that look for the first failure regardless of window.
+var endOfTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// annotateForecast sets the lookahead forecast and trust horizon of a trusted result.
+// Both come from the same search: the horizon just extends it past the lookahead window.
+// Announced removals of the matched root have no date yet and are flagged separately.
+func (v *Validator) annotateForecast(chain *truststore.CertChain, intermediates *x509.CertPool, pool *storePool, r *truststore.TrustResult, now time.Time) {
+	r.PendingRemoval = truststore.PendingRemoval(r.Platform.Platform, r.MatchedFingerprint)
+
+	until := now.Add(v.lookahead)
+	if v.horizon {
+		until = endOfTime
+	}
+
+	f := forecast(chain, intermediates, pool, *r, now, until)
+	if f == nil {
+		return
+	}
+	if v.horizon {
+		r.TrustedUntil = f.Date.Add(-time.Second)
+	}
+	if v.lookahead > 0 && !f.Date.After(now.Add(v.lookahead)) {
+		r.Forecast = f
+	}
+}
+
+// forecast re-validates a trusted result at every upcoming certificate expiry and root
+// distrust date up to until, returning the first failure (nil if none).
+// SCTNotAfter constraints compare SCT issuance times and cannot change for a deployed certificate.
+func forecast(chain *truststore.CertChain, intermediates *x509.CertPool, pool *storePool, result truststore.TrustResult, now, until time.Time) *truststore.TrustForecast {
+	for _, at := range forecastCandidates(pool.store, result.VerifiedChain, now, until) {
+		if r := validateAgainstPool(chain, intermediates, pool, at); !r.Trusted {
+			return &truststore.TrustForecast{Date: at, Reason: r.FailureReason}
+		}
+	}
+	return nil
+}
+
+// forecastCandidates returns sorted moments in (now, until] at which trust may change:
+// just after each verified certificate expires and just after the root's distrust date.
+func forecastCandidates(store truststore.Store, verified []*x509.Certificate, now, until time.Time) []time.Time {
+	var dates []time.Time
+	add := func(t time.Time) {
+		t = t.Add(time.Second)
+		if t.After(now) && !t.After(until) {
+			dates = append(dates, t)
+		}
+	}
+
+	for _, cert := range verified {
+		add(cert.NotAfter)
+	}
+	if n := len(verified); n > 0 {
+		if d := store.ConstraintFor(truststore.FingerprintFromCert(verified[n-1])).DistrustDate; d != nil {
+			add(*d)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// generationOctets is how much of a fingerprint is shown to tell root generations apart.
+const generationOctets = 4
+
+// annotateRootGenerations warns on trusted results of one chain whose anchor shares its subject
+// with a different root anchoring other results. Re-issued roots keep the subject (and often the
+// key) but are distinct trust anchors, and store constraints may apply to only one generation.
+// results and pools are in store order.
+func annotateRootGenerations(results []truststore.TrustResult, pools []*storePool) {
+	bySubject := make(map[string][]truststore.Fingerprint)
+	constrained := make(map[truststore.Fingerprint]bool)
+	for i, r := range results {
+		if !r.Trusted || r.MatchedFingerprint.IsZero() {
+			continue
+		}
+		subject := string(r.VerifiedChain[len(r.VerifiedChain)-1].RawSubject)
+		fps := bySubject[subject]
+		if !slices.Contains(fps, r.MatchedFingerprint) {
+			bySubject[subject] = append(fps, r.MatchedFingerprint)
+		}
+		if !pools[i].store.ConstraintFor(r.MatchedFingerprint).IsEmpty() {
+			constrained[r.MatchedFingerprint] = true
+		}
+	}
+
+	label := func(fp truststore.Fingerprint) string {
+		if constrained[fp] {
+			return fp.Truncate(generationOctets) + " (constrained)"
+		}
+		return fp.Truncate(generationOctets)
+	}
+
+	for i, r := range results {
+		if !r.Trusted || r.MatchedFingerprint.IsZero() {
+			continue
+		}
+		fps := bySubject[string(r.VerifiedChain[len(r.VerifiedChain)-1].RawSubject)]
+		if len(fps) < 2 {
+			continue
+		}
+
+		var others []truststore.Fingerprint
+		for _, fp := range fps {
+			if fp != r.MatchedFingerprint {
+				others = append(others, fp)
+			}
+		}
+		sort.Slice(others, func(a, b int) bool { return bytes.Compare(others[a][:], others[b][:]) < 0 })
+
+		labels := make([]string, len(others))
+		for j, fp := range others {
+			labels[j] = label(fp)
+		}
+		results[i].Warnings = append(results[i].Warnings, fmt.Sprintf(
+			"root generation: anchored at %q %s, other platform versions at %s",
+			r.MatchedCA, label(r.MatchedFingerprint), strings.Join(labels, ", ")))
+	}
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestValidateRootGenerations(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	oldGen, key := signTestCert(t, caTemplate("Reissued Root", now.Add(-48*time.Hour), now.Add(24*time.Hour)), nil, nil)
+
+	// Re-issued root: same subject and key, new validity
+	template := caTemplate("Reissued Root", now.Add(-time.Hour), now.AddDate(10, 0, 0))
+	template.SerialNumber = big.NewInt(2)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newGen, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, _ := signTestCert(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "gen.example.com"},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, oldGen, key)
+
+	oldFP, newFP := truststore.FingerprintFromCert(oldGen), truststore.FingerprintFromCert(newGen)
+	registerTestCert(oldFP, oldGen)
+	registerTestCert(newFP, newGen)
+	defer unregisterTestCert(oldFP)
+	defer unregisterTestCert(newFP)
+
+	distrust := now.AddDate(1, 0, 0)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformAndroid, Version: "10", Fingerprints: []truststore.Fingerprint{oldFP},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{oldFP: {DistrustDate: &distrust}}},
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{newFP}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{newFP}},
+	}
+	chain := &truststore.CertChain{Endpoint: "gen.example.com", ServerCert: leaf}
+
+	results := ValidateChain(chain, stores)
+	for _, r := range results {
+		if !r.Trusted {
+			t.Fatalf("%s %s: %s", r.Platform.Platform, r.Platform.Version, r.FailureReason)
+		}
+	}
+
+	if results[0].MatchedFingerprint != oldFP || results[1].MatchedFingerprint != newFP {
+		t.Errorf("MatchedFingerprint = %s, %s; want old then new generation",
+			results[0].MatchedFingerprint.Truncate(4), results[1].MatchedFingerprint.Truncate(4))
+	}
+
+	want := []string{
+		`root generation: anchored at "Reissued Root" ` + oldFP.Truncate(4) + ` (constrained), other platform versions at ` + newFP.Truncate(4),
+		`root generation: anchored at "Reissued Root" ` + newFP.Truncate(4) + `, other platform versions at ` + oldFP.Truncate(4) + ` (constrained)`,
+		`root generation: anchored at "Reissued Root" ` + newFP.Truncate(4) + `, other platform versions at ` + oldFP.Truncate(4) + ` (constrained)`,
+	}
+	for i, r := range results {
+		if got := strings.Join(r.Warnings, "; "); !strings.Contains(got, want[i]) {
+			t.Errorf("results[%d].Warnings = %q, want containing %q", i, got, want[i])
+		}
+	}
+
+	// A single generation needs no diagnostic
+	for _, r := range ValidateChain(chain, stores[1:]) {
+		for _, w := range r.Warnings {
+			if strings.HasPrefix(w, "root generation") {
+				t.Errorf("unexpected warning with one generation: %q", w)
+			}
+		}
+	}
+}
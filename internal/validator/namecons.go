@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// checkNameConstraints evaluates the name constraints of every CA in a verified chain against
+// the requested host. Go's verifier only checks the leaf's SANs, so a constrained CA can still
+// verify a certificate that clients reject for the host they connected to (e.g., a wildcard SAN
+// that reaches into an excluded subtree). Returns an empty string if no constraint is violated.
+func checkNameConstraints(host string, verified []*x509.Certificate) string {
+	if host == "" || len(verified) < 2 {
+		return ""
+	}
+	ip := net.ParseIP(host)
+
+	for _, ca := range verified[1:] {
+		var violation string
+		if ip != nil {
+			violation = checkIPConstraints(ip, ca)
+		} else {
+			violation = checkDNSConstraints(strings.ToLower(strings.TrimSuffix(host, ".")), ca)
+		}
+		if violation != "" {
+			return fmt.Sprintf("CA is not authorized for this name: %s (name constraints of %q)", violation, truststore.CertName(ca))
+		}
+	}
+	return ""
+}
+
+// checkDNSConstraints checks a hostname against a CA's DNS name constraints.
+func checkDNSConstraints(host string, ca *x509.Certificate) string {
+	for _, c := range ca.ExcludedDNSDomains {
+		if matchDNSConstraint(host, c) {
+			return fmt.Sprintf("DNS name %q is excluded by constraint %q", host, c)
+		}
+	}
+	if len(ca.PermittedDNSDomains) == 0 {
+		return ""
+	}
+	for _, c := range ca.PermittedDNSDomains {
+		if matchDNSConstraint(host, c) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("DNS name %q is not permitted by any constraint", host)
+}
+
+// matchDNSConstraint reports whether host falls within a dNSName constraint (RFC 5280 4.2.1.10):
+// "example.com" matches the domain and its subdomains, ".example.com" only subdomains,
+// and an empty constraint matches every name.
+func matchDNSConstraint(host, constraint string) bool {
+	constraint = strings.ToLower(constraint)
+	switch {
+	case constraint == "":
+		return true
+	case strings.HasPrefix(constraint, "."):
+		return strings.HasSuffix(host, constraint)
+	default:
+		return host == constraint || strings.HasSuffix(host, "."+constraint)
+	}
+}
+
+// checkIPConstraints checks an IP address against a CA's iPAddress name constraints.
+func checkIPConstraints(ip net.IP, ca *x509.Certificate) string {
+	for _, r := range ca.ExcludedIPRanges {
+		if r.Contains(ip) {
+			return fmt.Sprintf("IP address %s is excluded by constraint %s", ip, r)
+		}
+	}
+	if len(ca.PermittedIPRanges) == 0 {
+		return ""
+	}
+	for _, r := range ca.PermittedIPRanges {
+		if r.Contains(ip) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("IP address %s is not permitted by any constraint", ip)
+}
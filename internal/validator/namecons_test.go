@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameConstraints(t *testing.T) {
+	t.Parallel()
+
+	leaf := &x509.Certificate{}
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	permitted := &x509.Certificate{
+		Subject:             pkix.Name{CommonName: "Corp CA"},
+		PermittedDNSDomains: []string{"example.com", ".internal.example.net"},
+		PermittedIPRanges:   []*net.IPNet{private},
+	}
+	excluded := &x509.Certificate{
+		Subject:            pkix.Name{CommonName: "Public CA"},
+		ExcludedDNSDomains: []string{"secret.example.com"},
+		ExcludedIPRanges:   []*net.IPNet{private},
+	}
+	unconstrained := &x509.Certificate{Subject: pkix.Name{CommonName: "Root"}}
+
+	tests := []struct {
+		name     string
+		host     string
+		chain    []*x509.Certificate
+		wantWarn string
+	}{
+		{"permitted domain", "example.com", []*x509.Certificate{leaf, permitted}, ""},
+		{"permitted subdomain", "api.example.com", []*x509.Certificate{leaf, permitted}, ""},
+		{"case and trailing dot", "API.Example.com.", []*x509.Certificate{leaf, permitted}, ""},
+		{"leading dot excludes apex", "internal.example.net", []*x509.Certificate{leaf, permitted}, "not permitted"},
+		{"leading dot permits subdomain", "db.internal.example.net", []*x509.Certificate{leaf, permitted}, ""},
+		{"suffix is not a subdomain", "badexample.com", []*x509.Certificate{leaf, permitted}, "not permitted"},
+		{"excluded subtree", "a.secret.example.com", []*x509.Certificate{leaf, excluded}, "excluded by constraint"},
+		{"outside excluded subtree", "www.example.com", []*x509.Certificate{leaf, excluded}, ""},
+		{"permitted IP", "10.1.2.3", []*x509.Certificate{leaf, permitted}, ""},
+		{"IP outside permitted range", "192.0.2.1", []*x509.Certificate{leaf, permitted}, "not permitted"},
+		{"excluded IP", "10.1.2.3", []*x509.Certificate{leaf, excluded}, "excluded by constraint"},
+		{"constraint on root", "other.org", []*x509.Certificate{leaf, unconstrained, permitted}, "Corp CA"},
+		{"unconstrained", "other.org", []*x509.Certificate{leaf, unconstrained}, ""},
+		{"no host", "", []*x509.Certificate{leaf, permitted}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := checkNameConstraints(tt.host, tt.chain)
+			if (got == "") != (tt.wantWarn == "") || !strings.Contains(got, tt.wantWarn) {
+				t.Errorf("checkNameConstraints(%q) = %q, want containing %q", tt.host, got, tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"crypto/x509"
+	"errors"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// WithPreloaded returns a validator whose verifiers for platforms complete paths the server
+// left without intermediates from certs, modeling clients that hold intermediates without
+// fetching them: Firefox preloads the intermediates disclosed to CCADB, and Windows'
+// intermediate auto-fetching resolves the same set. Verifiers set afterwards with
+// WithIssuerFetcher or WithVerifier replace the model. Root pools are shared with v.
+func (v *Validator) WithPreloaded(certs []*x509.Certificate, platforms ...truststore.Platform) *Validator {
+	if len(certs) == 0 || len(platforms) == 0 {
+		return v
+	}
+	preloaded := x509.NewCertPool()
+	for _, cert := range certs {
+		preloaded.AddCert(cert)
+	}
+
+	c := *v
+	c.pools = make([]*storePool, len(v.pools))
+	for i, pool := range v.pools {
+		c.pools[i] = pool
+		for _, platform := range platforms {
+			if pool.store.Platform == platform {
+				p := *pool
+				p.verifier = preloadVerifier{next: pool.verifier, preloaded: preloaded}
+				c.pools[i] = &p
+				break
+			}
+		}
+	}
+	return &c
+}
+
+// preloadVerifier retries paths next can't build to a root with the preloaded
+// intermediates available alongside the presented ones.
+type preloadVerifier struct {
+	next      Verifier
+	preloaded *x509.CertPool // Must not be modified
+}
+
+func (p preloadVerifier) Verify(req VerifyRequest) ([][]*x509.Certificate, error) {
+	chains, err := p.next.Verify(req)
+	var unknownAuth x509.UnknownAuthorityError
+	if err == nil || !errors.As(err, &unknownAuth) {
+		return chains, err
+	}
+
+	retry := req
+	retry.Intermediates = p.preloaded.Clone()
+	for _, cert := range req.Chain.Intermediates {
+		retry.Intermediates.AddCert(cert)
+	}
+	if chains, rerr := p.next.Verify(retry); rerr == nil {
+		return chains, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestValidatorWithPreloaded(t *testing.T) {
+	t.Parallel()
+
+	rootCert, rootKey := generateTestCert(t, true, nil, nil)
+	intermediate, intKey := generateTestCert(t, true, rootCert, rootKey)
+	serverCert, _ := generateTestCert(t, false, intermediate, intKey)
+	fp := truststore.FingerprintFromCert(rootCert)
+	registerTestCert(fp, rootCert)
+	defer unregisterTestCert(fp)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	v := New(stores)
+	if r := v.Validate(chain); r[0].Trusted {
+		t.Fatal("chain without intermediates trusted before preloading")
+	}
+	r := v.WithPreloaded([]*x509.Certificate{intermediate}, truststore.PlatformWindows).Validate(chain)
+	if !r[0].Trusted || len(r[0].VerifiedChain) != 3 {
+		t.Errorf("windows with preloaded intermediate: trusted %v (%s), want a 3-certificate path", r[0].Trusted, r[0].FailureReason)
+	}
+	if r[1].Trusted {
+		t.Error("android trusted without preloading enabled for it")
+	}
+}
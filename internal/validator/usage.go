@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Apple requires an explicit serverAuth EKU on TLS certificates issued after this date
+// (https://support.apple.com/en-us/103769)
+var appleServerAuthEKUStart = time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+// checkKeyUsage returns warnings for key usage and EKU problems that x509.Verify
+// tolerates but stricter platforms reject. EKU nesting violations already fail
+// verification, so only missing or overly broad EKUs are reported here.
+func checkKeyUsage(platform truststore.Platform, chain *truststore.CertChain, verified []*x509.Certificate) []string {
+	var warnings []string
+	cert := chain.ServerCert
+
+	// Leaf key usage must permit the TLS handshake
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		_, isRSA := cert.PublicKey.(*rsa.PublicKey)
+		if !isRSA || cert.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+			warnings = append(warnings, "key usage: leaf lacks digitalSignature")
+		}
+	}
+
+	// Apple rejects leaves without explicit serverAuth, including anyExtendedKeyUsage
+	if platform.IsApple() && !cert.NotBefore.Before(appleServerAuthEKUStart) &&
+		!slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		warnings = append(warnings, "Apple EKU policy: leaf lacks serverAuth extended key usage")
+	}
+
+	// Chrome Root Program requires intermediates to be dedicated to serverAuth
+	if platform == truststore.PlatformChrome && len(verified) > 2 {
+		for _, ca := range verified[1 : len(verified)-1] {
+			if len(ca.ExtKeyUsage) == 0 || slices.Contains(ca.ExtKeyUsage, x509.ExtKeyUsageAny) {
+				warnings = append(warnings, fmt.Sprintf("Chrome EKU policy: intermediate %q lacks a serverAuth-restricted EKU",
+					truststore.CertName(ca)))
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCheckKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	recent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	serverAuth := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	root := &x509.Certificate{}
+
+	tests := []struct {
+		name         string
+		platform     truststore.Platform
+		leaf         *x509.Certificate
+		intermediate *x509.Certificate
+		want         string // Substring of the expected warning, empty if none
+	}{
+		{
+			name:     "compliant leaf",
+			platform: truststore.PlatformIOS,
+			leaf:     &x509.Certificate{NotBefore: recent, KeyUsage: x509.KeyUsageDigitalSignature, ExtKeyUsage: serverAuth},
+		},
+		{
+			name:     "missing digitalSignature",
+			platform: truststore.PlatformAndroid,
+			leaf:     &x509.Certificate{NotBefore: recent, KeyUsage: x509.KeyUsageCertSign, ExtKeyUsage: serverAuth},
+			want:     "lacks digitalSignature",
+		},
+		{
+			name:     "apple missing serverAuth",
+			platform: truststore.PlatformMacOS,
+			leaf:     &x509.Certificate{NotBefore: recent, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}},
+			want:     "Apple EKU policy",
+		},
+		{
+			name:     "apple pre-2019 leaf without EKU",
+			platform: truststore.PlatformIOS,
+			leaf:     &x509.Certificate{NotBefore: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "android leaf without EKU",
+			platform: truststore.PlatformAndroid,
+			leaf:     &x509.Certificate{NotBefore: recent},
+		},
+		{
+			name:         "chrome intermediate without EKU",
+			platform:     truststore.PlatformChrome,
+			leaf:         &x509.Certificate{NotBefore: recent, ExtKeyUsage: serverAuth},
+			intermediate: &x509.Certificate{},
+			want:         "Chrome EKU policy",
+		},
+		{
+			name:         "chrome intermediate with serverAuth",
+			platform:     truststore.PlatformChrome,
+			leaf:         &x509.Certificate{NotBefore: recent, ExtKeyUsage: serverAuth},
+			intermediate: &x509.Certificate{ExtKeyUsage: serverAuth},
+		},
+		{
+			name:         "windows intermediate without EKU",
+			platform:     truststore.PlatformWindows,
+			leaf:         &x509.Certificate{NotBefore: recent, ExtKeyUsage: serverAuth},
+			intermediate: &x509.Certificate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			verified := []*x509.Certificate{tt.leaf}
+			if tt.intermediate != nil {
+				verified = append(verified, tt.intermediate)
+			}
+			verified = append(verified, root)
+
+			got := checkKeyUsage(tt.platform, &truststore.CertChain{ServerCert: tt.leaf}, verified)
+			if tt.want == "" {
+				if len(got) > 0 {
+					t.Errorf("expected no warnings, got %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("warnings = %v, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,15 @@
 package validator
 
 import (
+	"bytes"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ivoronin/certvet/internal/truststore"
@@ -14,80 +19,336 @@ import (
 // getCertByFingerprint looks up a certificate by fingerprint.
 // Tests can override this variable to inject mock certificates.
 var getCertByFingerprint = func(fp truststore.Fingerprint) *x509.Certificate {
-	return truststore.Certs[fp]
+	return truststore.Certs.Get(fp)
 }
 
 // ValidateChain validates a certificate chain against multiple trust stores.
-// Returns results sorted by platform (alphabetically) and version (ascending).
-func ValidateChain(chain *truststore.CertChain, stores []truststore.Store) []truststore.TrustResult {
+// Returns one result per store, in store order. Hooks in opts, if given, are called
+// as each store is validated.
+func ValidateChain(chain *truststore.CertChain, stores []truststore.Store, opts ...Options) []truststore.TrustResult {
 	if len(stores) == 0 {
 		return nil
 	}
+	v := New(stores)
+	for _, o := range opts {
+		v = v.WithOptions(o)
+	}
+	return v.Validate(chain)
+}
 
-	// Validate in parallel
-	results := make([]truststore.TrustResult, len(stores))
-	var wg sync.WaitGroup
+// Options holds validation hooks. Nil hooks are skipped. Hooks may be called concurrently
+// for different (chain, store) pairs, and results are returned only after every hook ran.
+type Options struct {
+	// OnStoreStart is called before a chain is validated against a store.
+	OnStoreStart func(chain *truststore.CertChain, store truststore.Store)
+	// OnResult is called with each result once it is complete, including forecasts and
+	// suggestions. It may modify the result, for example to apply a custom policy.
+	OnResult func(chain *truststore.CertChain, result *truststore.TrustResult)
+}
 
-	for i, store := range stores {
-		wg.Add(1)
-		go func(idx int, s truststore.Store) {
-			defer wg.Done()
-			results[idx] = validateAgainstStore(chain, s)
-		}(i, store)
-	}
+// Validator holds immutable per-store root pools that can be shared across many chains.
+// It is safe for concurrent use.
+type Validator struct {
+	pools     []*storePool
+	workers   int
+	at        time.Time     // Evaluation time; zero means now
+	lookahead time.Duration // Forecast window for trusted results; zero disables
+	horizon   bool          // Compute TrustedUntil for trusted results
+	first     bool          // Stop validating a chain at its first untrusted result
+	ev        bool          // Check trusted results for Extended Validation
+	progress  func(done, total int)
+	opts      Options
 
-	wg.Wait()
+	crossSigns []*x509.Certificate // Alternate intermediates for suggestions; nil disables
+}
 
-	return results
+// storePool is a trust store with its root pool built once.
+type storePool struct {
+	store truststore.Store
+	*rootSet
+	verifier Verifier                      // Path building semantics of the store's platform
+	ctLogs   map[[32]byte]truststore.CTLog // CT logs known to the store's dataset
 }
 
-func validateAgainstStore(chain *truststore.CertChain, store truststore.Store) truststore.TrustResult {
-	pv := truststore.PlatformVersion{Platform: store.Platform, Version: store.Version}
-	result := truststore.TrustResult{Platform: pv}
+// rootSet is the resolved root pool of a fingerprint set. Stores with the same roots
+// (most versions of a platform) share one; it is read-only once built.
+type rootSet struct {
+	roots     *x509.CertPool
+	rootCerts []*x509.Certificate
+	missing   map[truststore.Fingerprint]bool // Fingerprints without certificate data
+}
+
+// New prepares root pools for the given stores, resolving roots in the trust data in use.
+// Work is bounded by GOMAXPROCS.
+func New(stores []truststore.Store) *Validator {
+	return newValidator(stores, getCertByFingerprint, truststore.CTLogs)
+}
 
-	// Build root CA pool from trust store, tracking missing certs
-	roots := x509.NewCertPool()
-	var rootCerts []*x509.Certificate
-	missingFingerprints := make(map[truststore.Fingerprint]bool)
+// NewWithData is New for stores of a dataset other than the one in use, such as data
+// reloaded by a long-running server: roots are resolved in certs and SCTs checked
+// against ctLogs.
+func NewWithData(stores []truststore.Store, certs *truststore.CertIndex, ctLogs map[[32]byte]truststore.CTLog) *Validator {
+	return newValidator(stores, certs.Get, ctLogs)
+}
+
+func newValidator(stores []truststore.Store, lookup func(truststore.Fingerprint) *x509.Certificate, ctLogs map[[32]byte]truststore.CTLog) *Validator {
+	v := &Validator{
+		pools:   make([]*storePool, len(stores)),
+		workers: runtime.GOMAXPROCS(0),
+	}
+
+	// Build each distinct root set once, in parallel
+	index := make(map[string]int)
+	var unique []truststore.Store
+	setOf := make([]int, len(stores))
+	for i, store := range stores {
+		key := fingerprintSetKey(store.Fingerprints)
+		j, ok := index[key]
+		if !ok {
+			j = len(unique)
+			index[key] = j
+			unique = append(unique, store)
+		}
+		setOf[i] = j
+	}
+	sets := make([]*rootSet, len(unique))
+	v.run(len(unique), func(i int) {
+		sets[i] = newRootSet(unique[i].Fingerprints, lookup)
+	})
+
+	for i, store := range stores {
+		v.pools[i] = &storePool{
+			store:    store,
+			rootSet:  sets[setOf[i]],
+			verifier: platformVerifier(store.Platform, nil),
+			ctLogs:   ctLogs,
+		}
+	}
+	return v
+}
+
+// fingerprintSetKey identifies a set of fingerprints regardless of order.
+func fingerprintSetKey(fps []truststore.Fingerprint) string {
+	sorted := slices.Clone(fps)
+	slices.SortFunc(sorted, func(a, b truststore.Fingerprint) int { return bytes.Compare(a[:], b[:]) })
+	var key strings.Builder
+	key.Grow(len(sorted) * len(truststore.Fingerprint{}))
+	for _, fp := range sorted {
+		key.Write(fp[:])
+	}
+	return key.String()
+}
 
-	for _, fp := range store.Fingerprints {
-		cert := getCertByFingerprint(fp)
+// newRootSet builds the root CA pool for fingerprints, tracking missing certs.
+func newRootSet(fps []truststore.Fingerprint, lookup func(truststore.Fingerprint) *x509.Certificate) *rootSet {
+	s := &rootSet{
+		roots:   x509.NewCertPool(),
+		missing: make(map[truststore.Fingerprint]bool),
+	}
+	for _, fp := range fps {
+		cert := lookup(fp)
 		if cert != nil {
-			roots.AddCert(cert)
-			rootCerts = append(rootCerts, cert)
+			s.roots.AddCert(cert)
+			s.rootCerts = append(s.rootCerts, cert)
 		} else {
-			missingFingerprints[fp] = true
+			s.missing[fp] = true
 		}
 	}
+	return s
+}
 
-	if len(rootCerts) == 0 {
-		result.FailureReason = "no valid root certificates in trust store"
-		return result
+// WithTime returns a validator that evaluates certificate validity and distrust dates
+// as of t instead of the current time. Root pools are shared with v.
+func (v *Validator) WithTime(t time.Time) *Validator {
+	c := *v
+	c.at = t
+	return &c
+}
+
+// WithProgress returns a validator that calls fn after each (chain, store) pair of
+// a ValidateAll run is validated. fn may be called concurrently. Root pools are shared with v.
+func (v *Validator) WithProgress(fn func(done, total int)) *Validator {
+	c := *v
+	c.progress = fn
+	return &c
+}
+
+// WithFirstFailure returns a validator that stops validating a chain against further stores
+// once a result (after OnResult) is untrusted. Results of a stopped chain hold only the
+// stores validated by then, in store order; stores already in progress are still included.
+// Root pools are shared with v.
+func (v *Validator) WithFirstFailure(enabled bool) *Validator {
+	c := *v
+	c.first = enabled
+	return &c
+}
+
+// WithOptions returns a validator that calls the hooks in opts during validation,
+// replacing any set before. Root pools are shared with v.
+func (v *Validator) WithOptions(opts Options) *Validator {
+	c := *v
+	c.opts = opts
+	return &c
+}
+
+// now returns the evaluation time.
+func (v *Validator) now() time.Time {
+	if v.at.IsZero() {
+		return time.Now()
 	}
+	return v.at
+}
+
+// Validate validates one chain against all prepared stores.
+// Returns one result per store, in store order.
+func (v *Validator) Validate(chain *truststore.CertChain) []truststore.TrustResult {
+	return v.ValidateAll([]*truststore.CertChain{chain})[0]
+}
 
-	// Build intermediates pool
-	intermediates := x509.NewCertPool()
-	for _, cert := range chain.Intermediates {
-		intermediates.AddCert(cert)
+// ValidateAll validates many chains, scheduling every (chain, store) pair on a single
+// bounded worker set. Returns results indexed by chain, then store order.
+func (v *Validator) ValidateAll(chains []*truststore.CertChain) [][]truststore.TrustResult {
+	results := make([][]truststore.TrustResult, len(chains))
+	intermediates := make([]*x509.CertPool, len(chains))
+	for i, chain := range chains {
+		results[i] = make([]truststore.TrustResult, len(v.pools))
+		intermediates[i] = x509.NewCertPool()
+		for _, cert := range chain.Intermediates {
+			intermediates[i].AddCert(cert)
+		}
 	}
 
-	// Verify the chain
-	opts := x509.VerifyOptions{
-		Roots:         roots,
-		Intermediates: intermediates,
+	var alternates []*x509.CertPool
+	if len(v.crossSigns) > 0 {
+		alternates = make([]*x509.CertPool, len(chains))
+		for i := range chains {
+			alternates[i] = v.alternatePool(intermediates[i])
+		}
+	}
+
+	n := len(v.pools)
+	now := v.now()
+	var done atomic.Int64
+	stopped := make([]atomic.Bool, len(chains))
+	validated := make([][]bool, len(chains))
+	for i := range validated {
+		validated[i] = make([]bool, n)
+	}
+	v.run(len(chains)*n, func(item int) {
+		ci, si := item/n, item%n
+		if stopped[ci].Load() {
+			if v.progress != nil {
+				v.progress(int(done.Add(1)), len(chains)*n)
+			}
+			return
+		}
+		if v.opts.OnStoreStart != nil {
+			v.opts.OnStoreStart(chains[ci], v.pools[si].store)
+		}
+		r := validateAgainstPool(chains[ci], intermediates[ci], v.pools[si], now)
+		if r.Trusted && (v.lookahead > 0 || v.horizon) {
+			v.annotateForecast(chains[ci], intermediates[ci], v.pools[si], &r, now)
+		}
+		if r.Trusted && v.ev {
+			annotateEV(&r)
+		}
+		if !r.Trusted && alternates != nil {
+			r.Suggested = v.suggest(chains[ci], alternates[ci], v.pools[si], now)
+		}
+		if v.opts.OnResult != nil {
+			v.opts.OnResult(chains[ci], &r)
+		}
+		results[ci][si] = r
+		validated[ci][si] = true
+		if v.first && !r.Trusted {
+			stopped[ci].Store(true)
+		}
+		if v.progress != nil {
+			v.progress(int(done.Add(1)), len(chains)*n)
+		}
+	})
+	for i, r := range results {
+		annotateRootGenerations(r, v.pools)
+		if stopped[i].Load() {
+			results[i] = compactValidated(r, validated[i])
+		}
+	}
+	return results
+}
+
+// compactValidated returns the results of validated stores, in store order.
+func compactValidated(results []truststore.TrustResult, validated []bool) []truststore.TrustResult {
+	var out []truststore.TrustResult
+	for i, r := range results {
+		if validated[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// run executes fn for indexes [0, count) on at most v.workers goroutines.
+func (v *Validator) run(count int, fn func(int)) {
+	workers := min(v.workers, count)
+	next := make(chan int)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
 	}
 
-	chains, err := chain.ServerCert.Verify(opts)
+	for i := range count {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+func validateAgainstPool(chain *truststore.CertChain, intermediates *x509.CertPool, pool *storePool, now time.Time) truststore.TrustResult {
+	store := pool.store
+	pv := truststore.PlatformVersion{Platform: store.Platform, Version: store.Version, ExtraRoots: store.HasExtraRoots()}
+	result := truststore.TrustResult{Platform: pv}
+
+	if len(pool.rootCerts) == 0 {
+		result.FailureCode = truststore.FailureNoRoots
+		result.FailureReason = "no valid root certificates in trust store"
+		return result
+	}
+
+	// Verify the chain with the platform's path building semantics
+	chains, err := pool.verifier.Verify(VerifyRequest{
+		Store:         store,
+		Chain:         chain,
+		Intermediates: intermediates,
+		Roots:         pool.roots,
+		RootCerts:     pool.rootCerts,
+		Time:          now,
+	})
 	if err != nil {
 		// Check if chain terminates at a known but unavailable root
 		if n := len(chain.Intermediates); n > 0 {
 			fp := truststore.FingerprintFromCert(chain.Intermediates[n-1])
-			if missingFingerprints[fp] {
+			if pool.missing[fp] {
+				result.FailureCode = truststore.FailureRootUnavailable
 				result.FailureReason = fmt.Sprintf("chain roots at known CA (fingerprint %s) but certificate data unavailable", fp.String())
 				return result
 			}
 		}
-		result.FailureReason = parseVerifyError(err)
+		result.FailureCode, result.FailureReason = parseVerifyError(err)
+		if result.FailureCode == truststore.FailureUnknownAuthority {
+			// A chain sent up to a blocked or always ask root fails for that reason
+			if code, reason := checkBlocked(store, sentCerts(chain)); reason != "" {
+				result.FailureCode, result.FailureReason = code, reason
+			} else if missingIntermediate(chain) {
+				result.FailureCode = truststore.FailureMissingIntermediate
+			}
+		}
 		return result
 	}
 
@@ -100,34 +361,91 @@ func validateAgainstStore(chain *truststore.CertChain, store truststore.Store) t
 			result.MatchedCA = rootCert.Subject.Organization[0]
 		}
 
-		// Check date constraints on the matched root CA
 		rootFP := truststore.FingerprintFromCert(rootCert)
+		result.MatchedFingerprint = rootFP
 		constraints := store.ConstraintFor(rootFP)
-		if violation := checkConstraints(chain, constraints); violation != "" {
+		result.Constraints = constraints
+
+		// Blocked certificates fail the chain even where a path to a trusted root verifies
+		if code, reason := checkBlocked(store, result.VerifiedChain); reason != "" {
+			result.FailureCode = code
+			result.FailureReason = reason
+			return result
+		}
+
+		// Check date constraints on the matched root CA
+		if code, violation := checkConstraints(chain, constraints, now); violation != "" {
 			result.Trusted = false
+			result.FailureCode = code
+			result.FailureReason = violation
+			return result
+		}
+
+		// Constraints must also cover the host the client asked for, not only the leaf's SANs
+		if violation := checkNameConstraints(chain.Endpoint, result.VerifiedChain); violation != "" {
+			result.FailureCode = truststore.FailureNameConstraints
 			result.FailureReason = violation
 			return result
 		}
 	}
 
+	// Key usage problems are warnings: the chain verifies but some clients reject it
+	result.Warnings = append(result.Warnings, checkKeyUsage(store.Platform, chain, result.VerifiedChain)...)
+	result.Warnings = append(result.Warnings, weakCryptoWarnings(store.Platform, checkWeakCrypto(chain))...)
+	if warning := checkClientCompat(pv, chain.TLS); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	// Apple platforms reject chains without enough SCTs even if the root is trusted,
+	// unless it's a user-installed root
+	ctExempt := anchoredAtExtraRoot(store, result.VerifiedChain)
+	if store.Platform.IsApple() && !ctExempt {
+		if warning := checkAppleCTPolicy(chain, pool.ctLogs); warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	// SCTs from logs that CT-enforcing clients no longer accept
+	if (store.Platform.IsApple() || store.Platform == truststore.PlatformChrome) && !ctExempt {
+		result.Warnings = append(result.Warnings, checkSCTLogs(chain, pool.ctLogs)...)
+		result.Warnings = append(result.Warnings, checkSCTTimestamps(chain, pool.ctLogs, now)...)
+	}
+
 	result.Trusted = true
 	return result
 }
 
-// checkConstraints validates chain against date constraints.
-// Returns empty string if all constraints pass, otherwise returns violation description.
-func checkConstraints(chain *truststore.CertChain, constraints truststore.Constraints) string {
-	if constraints.IsEmpty() {
-		return ""
+// anchoredAtExtraRoot reports whether a verified chain ends at a user-supplied root.
+func anchoredAtExtraRoot(store truststore.Store, verified []*x509.Certificate) bool {
+	if len(verified) == 0 {
+		return false
 	}
+	return store.Extra[truststore.FingerprintFromCert(verified[len(verified)-1])]
+}
 
-	now := time.Now()
+// VerifyHostname checks that the server certificate is valid for the chain's endpoint host.
+func VerifyHostname(chain *truststore.CertChain) truststore.HostnameCheck {
+	check := truststore.HostnameCheck{Host: chain.Endpoint}
+	if err := chain.ServerCert.VerifyHostname(chain.Endpoint); err != nil {
+		_, check.Error = parseVerifyError(err)
+		return check
+	}
+	check.Valid = true
+	return check
+}
+
+// checkConstraints validates chain against date constraints as of now.
+// Returns empty string if all constraints pass, otherwise returns the violation's code and description.
+func checkConstraints(chain *truststore.CertChain, constraints truststore.Constraints, now time.Time) (truststore.FailureCode, string) {
+	if constraints.IsEmpty() {
+		return "", ""
+	}
 
 	// Check NotBeforeMax: server cert's NotBefore must be <= this date
 	// (certificates issued after this date are not trusted)
 	if constraints.NotBeforeMax != nil {
 		if chain.ServerCert.NotBefore.After(*constraints.NotBeforeMax) {
-			return fmt.Sprintf("certificate issued after trust cutoff (%s > %s)",
+			return truststore.FailureNotBeforeCutoff, fmt.Sprintf("certificate issued after trust cutoff (%s > %s)",
 				chain.ServerCert.NotBefore.Format(truststore.DateFormat),
 				constraints.NotBeforeMax.Format(truststore.DateFormat))
 		}
@@ -136,7 +454,7 @@ func checkConstraints(chain *truststore.CertChain, constraints truststore.Constr
 	// Check DistrustDate: CA is completely distrusted after this date
 	if constraints.DistrustDate != nil {
 		if now.After(*constraints.DistrustDate) {
-			return fmt.Sprintf("CA distrusted since %s",
+			return truststore.FailureDistrustDate, fmt.Sprintf("CA distrusted since %s",
 				constraints.DistrustDate.Format(truststore.DateFormat))
 		}
 	}
@@ -145,7 +463,7 @@ func checkConstraints(chain *truststore.CertChain, constraints truststore.Constr
 	if constraints.SCTNotAfter != nil {
 		// Check all SCTs - at least one must be valid
 		if len(chain.SCTs) == 0 {
-			return fmt.Sprintf("SCT required but none found (deadline: %s)",
+			return truststore.FailureSCTDeadline, fmt.Sprintf("SCT required but none found (deadline: %s)",
 				constraints.SCTNotAfter.Format(truststore.DateFormat))
 		}
 
@@ -157,46 +475,69 @@ func checkConstraints(chain *truststore.CertChain, constraints truststore.Constr
 			}
 		}
 		if !hasValidSCT {
-			return fmt.Sprintf("all SCTs issued after deadline (%s)",
+			return truststore.FailureSCTDeadline, fmt.Sprintf("all SCTs issued after deadline (%s)",
 				constraints.SCTNotAfter.Format(truststore.DateFormat))
 		}
 	}
 
-	return ""
+	return "", ""
 }
 
-func parseVerifyError(err error) string {
+// parseVerifyError converts an x509 verification error to a failure code and message.
+func parseVerifyError(err error) (truststore.FailureCode, string) {
 	var unknownAuth x509.UnknownAuthorityError
 	if errors.As(err, &unknownAuth) {
-		return "certificate signed by unknown authority"
+		// A candidate issuer matched but its signature algorithm is rejected
+		if strings.Contains(unknownAuth.Error(), "insecure algorithm") {
+			return truststore.FailureInsecureAlgorithm, "certificate signed with insecure algorithm"
+		}
+		return truststore.FailureUnknownAuthority, "certificate signed by unknown authority"
 	}
 
 	var certInvalid x509.CertificateInvalidError
 	if errors.As(err, &certInvalid) {
 		switch certInvalid.Reason {
 		case x509.Expired:
-			return "certificate has expired or is not yet valid"
+			return truststore.FailureExpired, "certificate has expired or is not yet valid"
 		case x509.NotAuthorizedToSign:
-			return "certificate is not authorized to sign other certificates"
+			return truststore.FailureInvalidChain, "certificate is not authorized to sign other certificates"
 		case x509.TooManyIntermediates:
-			return "too many intermediates for path length constraint"
+			return truststore.FailureInvalidChain, "too many intermediates for path length constraint"
 		case x509.IncompatibleUsage:
-			return "certificate specifies an incompatible key usage"
+			return truststore.FailureInvalidChain, "certificate specifies an incompatible key usage"
 		case x509.NameMismatch:
-			return "issuer name does not match subject"
+			return truststore.FailureInvalidChain, "issuer name does not match subject"
 		case x509.CANotAuthorizedForThisName:
-			return "CA is not authorized for this name"
+			// Detail names the violated constraint
+			if certInvalid.Detail != "" {
+				return truststore.FailureNameConstraints, "CA is not authorized for this name: " + certInvalid.Detail
+			}
+			return truststore.FailureNameConstraints, "CA is not authorized for this name"
 		default:
 			if certInvalid.Detail != "" {
-				return certInvalid.Detail
+				return truststore.FailureOther, certInvalid.Detail
 			}
 		}
 	}
 
+	var validityErr *validityPeriodError
+	if errors.As(err, &validityErr) {
+		return truststore.FailureValidityPeriod, validityErr.Error()
+	}
+
 	var hostnameErr x509.HostnameError
 	if errors.As(err, &hostnameErr) {
-		return fmt.Sprintf("certificate is not valid for %s", hostnameErr.Host)
+		return truststore.FailureOther, fmt.Sprintf("certificate is not valid for %s", hostnameErr.Host)
 	}
 
-	return err.Error()
+	return truststore.FailureOther, err.Error()
+}
+
+// missingIntermediate reports whether an unknown authority failure is more likely an
+// incomplete chain than an untrusted root: none of the served certificates issued the
+// server certificate, and it names an issuer to fetch (CA-issued leaves do, private CA
+// leaves usually don't).
+func missingIntermediate(chain *truststore.CertChain) bool {
+	leaf := chain.ServerCert
+	return len(leaf.IssuingCertificateURL) > 0 && pathTip(leaf, chain.Intermediates) == leaf
 }
@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"math/big"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -160,6 +161,45 @@ func TestValidateChainUntrusted(t *testing.T) {
 	if r.FailureReason == "" {
 		t.Error("FailureReason should be set for untrusted chain")
 	}
+	if r.FailureCode != truststore.FailureUnknownAuthority {
+		t.Errorf("FailureCode = %q, want %q", r.FailureCode, truststore.FailureUnknownAuthority)
+	}
+}
+
+func TestValidateChainMissingIntermediate(t *testing.T) {
+	t.Parallel()
+
+	rootCert, rootKey := generateTestCert(t, true, nil, nil)
+	intermediate, intKey := generateTestCert(t, true, rootCert, rootKey)
+	serverCert, _ := generateTestCert(t, false, intermediate, intKey)
+	fp := truststore.FingerprintFromCert(rootCert)
+	registerTestCert(fp, rootCert)
+	defer unregisterTestCert(fp)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+
+	tests := []struct {
+		name          string
+		aia           []string
+		intermediates []*x509.Certificate
+		want          truststore.FailureCode
+	}{
+		{"issuer not served", []string{"http://ca.example/int.crt"}, nil, truststore.FailureMissingIntermediate},
+		{"leaf without AIA", nil, nil, truststore.FailureUnknownAuthority},
+		{"unrelated intermediate served", []string{"http://ca.example/int.crt"}, []*x509.Certificate{rootCert}, truststore.FailureMissingIntermediate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf := *serverCert
+			leaf.IssuingCertificateURL = tt.aia
+			r := ValidateChain(&truststore.CertChain{Endpoint: "test.example.com", ServerCert: &leaf, Intermediates: tt.intermediates}, stores)[0]
+			if r.Trusted || r.FailureCode != tt.want {
+				t.Errorf("Trusted = %v, FailureCode = %q; want untrusted with %q (%s)", r.Trusted, r.FailureCode, tt.want, r.FailureReason)
+			}
+		})
+	}
 }
 
 func TestValidateChainMultipleStores(t *testing.T) {
@@ -195,6 +235,169 @@ func TestValidateChainMultipleStores(t *testing.T) {
 	}
 }
 
+func TestNewSharesRootSets(t *testing.T) {
+	t.Parallel()
+
+	a := truststore.Fingerprint{0x01}
+	b := truststore.Fingerprint{0x02}
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{a, b}},
+		{Platform: truststore.PlatformIOS, Version: "17", Fingerprints: []truststore.Fingerprint{b, a}},
+		{Platform: truststore.PlatformAndroid, Version: "35", Fingerprints: []truststore.Fingerprint{a}},
+	}
+
+	v := New(stores)
+	if v.pools[0].rootSet != v.pools[1].rootSet {
+		t.Error("stores with the same roots in a different order do not share a root set")
+	}
+	if v.pools[0].rootSet == v.pools[2].rootSet {
+		t.Error("stores with different roots share a root set")
+	}
+	if v.pools[1].store.Version != "17" || !v.pools[2].missing[a] {
+		t.Errorf("pools not built per store: %+v", v.pools[1].store)
+	}
+}
+
+func TestValidateChainOptions(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "35", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	var mu sync.Mutex
+	var started []truststore.Platform
+	results := ValidateChain(chain, stores, Options{
+		OnStoreStart: func(c *truststore.CertChain, store truststore.Store) {
+			mu.Lock()
+			defer mu.Unlock()
+			if c != chain {
+				t.Error("OnStoreStart got a different chain")
+			}
+			started = append(started, store.Platform)
+		},
+		// A custom policy rejecting every Android result
+		OnResult: func(_ *truststore.CertChain, r *truststore.TrustResult) {
+			if r.Platform.Platform == truststore.PlatformAndroid {
+				r.Trusted = false
+				r.FailureCode = truststore.FailureOther
+				r.FailureReason = "rejected by policy"
+			}
+		},
+	})
+
+	if len(started) != len(stores) {
+		t.Errorf("OnStoreStart called for %v, want every store", started)
+	}
+	if !results[0].Trusted || results[1].Trusted || results[1].FailureReason != "rejected by policy" {
+		t.Errorf("results = %v (%s), %v (%s); want iOS trusted and Android rejected by the hook",
+			results[0].Trusted, results[0].FailureReason, results[1].Trusted, results[1].FailureReason)
+	}
+}
+
+func TestValidatorValidateAll(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	trustedCert, _ := generateTestCert(t, false, caCert, caKey)
+	unknownCA, unknownKey := generateTestCert(t, true, nil, nil)
+	untrustedCert, _ := generateTestCert(t, false, unknownCA, unknownKey)
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "35", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	var mu sync.Mutex
+	var steps, lastTotal int
+	v := New(stores).WithProgress(func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps++
+		lastTotal = total
+	})
+	chains := []*truststore.CertChain{
+		{Endpoint: "trusted.example.com", ServerCert: trustedCert},
+		{Endpoint: "untrusted.example.com", ServerCert: untrustedCert},
+		{Endpoint: "trusted2.example.com", ServerCert: trustedCert},
+	}
+	results := v.ValidateAll(chains)
+
+	if want := len(chains) * len(stores); steps != want || lastTotal != want {
+		t.Errorf("progress called %d times with total %d, want %d", steps, lastTotal, want)
+	}
+	if len(results) != len(chains) {
+		t.Fatalf("expected %d result sets, got %d", len(chains), len(results))
+	}
+	for ci, want := range []bool{true, false, true} {
+		if len(results[ci]) != len(stores) {
+			t.Fatalf("chain %d: expected %d results, got %d", ci, len(stores), len(results[ci]))
+		}
+		for si, r := range results[ci] {
+			if r.Trusted != want {
+				t.Errorf("chain %d store %d: trusted = %v, want %v (%s)", ci, si, r.Trusted, want, r.FailureReason)
+			}
+			if r.Platform.Platform != stores[si].Platform {
+				t.Errorf("chain %d store %d: platform = %s, want %s", ci, si, r.Platform.Platform, stores[si].Platform)
+			}
+		}
+	}
+}
+
+func TestValidatorWithFirstFailure(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "35"},
+		{Platform: truststore.PlatformChrome, Version: "current", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	var mu sync.Mutex
+	steps := 0
+	v := New(stores).WithFirstFailure(true).WithProgress(func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps++
+	})
+	v.workers = 1 // Validate stores in order so the stop point is deterministic
+	chains := []*truststore.CertChain{
+		{Endpoint: "a.example.com", ServerCert: serverCert},
+		{Endpoint: "b.example.com", ServerCert: serverCert},
+	}
+	results := v.ValidateAll(chains)
+
+	for ci, rs := range results {
+		if len(rs) != 2 || !rs[0].Trusted || rs[1].Trusted || rs[1].Platform.Platform != truststore.PlatformAndroid {
+			t.Errorf("chain %d: results = %+v, want iOS trusted then the Android failure", ci, rs)
+		}
+	}
+	if want := len(chains) * len(stores); steps != want {
+		t.Errorf("progress called %d times, want %d", steps, want)
+	}
+
+	if all := New(stores).Validate(chains[0]); len(all) != len(stores) {
+		t.Errorf("without WithFirstFailure got %d results, want %d", len(all), len(stores))
+	}
+}
+
 func TestValidateChainChrome(t *testing.T) {
 	t.Parallel()
 
@@ -279,6 +482,9 @@ func TestValidateChainKnownMissingRoot(t *testing.T) {
 	if r.FailureReason != expectedMsg {
 		t.Errorf("expected %q, got %q", expectedMsg, r.FailureReason)
 	}
+	if r.FailureCode != truststore.FailureRootUnavailable {
+		t.Errorf("FailureCode = %q, want %q", r.FailureCode, truststore.FailureRootUnavailable)
+	}
 }
 
 func TestConstraintNotBeforeMax(t *testing.T) {
@@ -319,6 +525,9 @@ func TestConstraintNotBeforeMax(t *testing.T) {
 	if r.FailureReason == "" {
 		t.Error("FailureReason should explain the constraint violation")
 	}
+	if r.FailureCode != truststore.FailureNotBeforeCutoff {
+		t.Errorf("FailureCode = %q, want %q", r.FailureCode, truststore.FailureNotBeforeCutoff)
+	}
 	t.Logf("FailureReason: %s", r.FailureReason)
 }
 
@@ -357,6 +566,9 @@ func TestConstraintNotBeforeMaxPasses(t *testing.T) {
 	if !r.Trusted {
 		t.Errorf("expected trusted, got failure: %s", r.FailureReason)
 	}
+	if r.Constraints.NotBeforeMax == nil || !r.Constraints.NotBeforeMax.Equal(tomorrow) {
+		t.Errorf("Constraints.NotBeforeMax = %v, want %v", r.Constraints.NotBeforeMax, tomorrow)
+	}
 }
 
 func TestConstraintDistrustDate(t *testing.T) {
@@ -396,9 +608,188 @@ func TestConstraintDistrustDate(t *testing.T) {
 	if r.FailureReason == "" {
 		t.Error("FailureReason should explain the distrust")
 	}
+	if r.FailureCode != truststore.FailureDistrustDate {
+		t.Errorf("FailureCode = %q, want %q", r.FailureCode, truststore.FailureDistrustDate)
+	}
 	t.Logf("FailureReason: %s", r.FailureReason)
 }
 
+func TestValidatorWithTime(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	fp := truststore.FingerprintFromCert(caCert)
+	distrust := time.Now().Add(10 * time.Minute)
+	stores := []truststore.Store{
+		{
+			Platform:     truststore.PlatformWindows,
+			Version:      "current",
+			Fingerprints: []truststore.Fingerprint{fp},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fp: {DistrustDate: &distrust},
+			},
+		},
+	}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	v := New(stores)
+	tests := []struct {
+		name        string
+		at          time.Time
+		wantTrusted bool
+		wantReason  string
+	}{
+		{"now", time.Time{}, true, ""},
+		{"after distrust", distrust.Add(20 * time.Minute), false, "CA distrusted since"},
+		{"after expiry", serverCert.NotAfter.Add(time.Hour), false, "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := v.WithTime(tt.at).Validate(chain)[0]
+			if r.Trusted != tt.wantTrusted {
+				t.Errorf("Trusted = %v, want %v (%s)", r.Trusted, tt.wantTrusted, r.FailureReason)
+			}
+			if !strings.Contains(r.FailureReason, tt.wantReason) {
+				t.Errorf("FailureReason = %q, want %q", r.FailureReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestValidatorWithLookahead(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey) // Expires in 1 hour
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	fp := truststore.FingerprintFromCert(caCert)
+	distrust := time.Now().Add(30 * time.Minute)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{
+			Platform:     truststore.PlatformWindows,
+			Version:      "current",
+			Fingerprints: []truststore.Fingerprint{fp},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fp: {DistrustDate: &distrust},
+			},
+		},
+	}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	v := New(stores)
+
+	if r := v.WithLookahead(10 * time.Minute).Validate(chain); r[0].Forecast != nil || r[1].Forecast != nil {
+		t.Errorf("expected no forecast within 10m, got %+v, %+v", r[0].Forecast, r[1].Forecast)
+	}
+
+	results := v.WithLookahead(2 * time.Hour).Validate(chain)
+	ios, windows := results[0], results[1]
+	if !ios.Trusted || ios.Forecast == nil || !strings.Contains(ios.Forecast.Reason, "expired") {
+		t.Errorf("ios: expected trusted with expiry forecast, got %+v", ios.Forecast)
+	}
+	if !windows.Trusted || windows.Forecast == nil || !strings.Contains(windows.Forecast.Reason, "distrusted") {
+		t.Errorf("windows: expected trusted with distrust forecast, got %+v", windows.Forecast)
+	}
+	if windows.Forecast != nil && !windows.Forecast.Date.Equal(distrust.Add(time.Second)) {
+		t.Errorf("windows: forecast date = %v, want just after %v", windows.Forecast.Date, distrust)
+	}
+}
+
+// TestValidatorPendingRemoval replaces package data and must not run in parallel.
+func TestValidatorPendingRemoval(t *testing.T) {
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	fp := truststore.FingerprintFromCert(caCert)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	truststore.RootStatuses[fp] = map[string]string{truststore.ProgramMicrosoft: truststore.RootPendingRemoval}
+	t.Cleanup(func() { delete(truststore.RootStatuses, fp) })
+
+	if r := New(stores).Validate(chain); r[1].PendingRemoval {
+		t.Error("pending removal flagged without forecasts")
+	}
+	results := New(stores).WithLookahead(time.Minute).Validate(chain)
+	if results[0].PendingRemoval || !results[1].Trusted || !results[1].PendingRemoval {
+		t.Errorf("pending removal = %v (ios), %v (windows), want only windows flagged", results[0].PendingRemoval, results[1].PendingRemoval)
+	}
+}
+
+func TestValidatorWithTrustedUntil(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey) // Expires in 1 hour
+	unknownCA, unknownKey := generateTestCert(t, true, nil, nil)
+	untrustedCert, _ := generateTestCert(t, false, unknownCA, unknownKey)
+
+	fp := truststore.FingerprintFromCert(caCert)
+	distrust := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{
+			Platform:     truststore.PlatformWindows,
+			Version:      "current",
+			Fingerprints: []truststore.Fingerprint{fp},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{
+				fp: {DistrustDate: &distrust},
+			},
+		},
+	}
+
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	v := New(stores)
+	if r := v.Validate(&truststore.CertChain{ServerCert: serverCert}); !r[0].TrustedUntil.IsZero() {
+		t.Errorf("TrustedUntil set without WithTrustedUntil: %v", r[0].TrustedUntil)
+	}
+
+	// The horizon is not bounded by a lookahead window, and doesn't produce forecasts by itself
+	results := v.WithTrustedUntil(true).Validate(&truststore.CertChain{ServerCert: serverCert})
+	ios, windows := results[0], results[1]
+	// Both certificates expire in an hour, but the CA may have been created a second earlier
+	expiry := serverCert.NotAfter
+	if caCert.NotAfter.Before(expiry) {
+		expiry = caCert.NotAfter
+	}
+	if !ios.TrustedUntil.Equal(expiry) {
+		t.Errorf("ios: TrustedUntil = %v, want chain expiry %v", ios.TrustedUntil, expiry)
+	}
+	if !windows.TrustedUntil.Equal(distrust) {
+		t.Errorf("windows: TrustedUntil = %v, want distrust date %v", windows.TrustedUntil, distrust)
+	}
+	if ios.Forecast != nil || windows.Forecast != nil {
+		t.Error("forecast set without lookahead")
+	}
+
+	// Combined with a short lookahead, only failures inside the window are forecast
+	results = v.WithTrustedUntil(true).WithLookahead(45 * time.Minute).Validate(&truststore.CertChain{ServerCert: serverCert})
+	if results[0].Forecast != nil || results[1].Forecast == nil || results[0].TrustedUntil.IsZero() {
+		t.Errorf("lookahead 45m: ios forecast %+v, windows forecast %+v", results[0].Forecast, results[1].Forecast)
+	}
+
+	if r := v.WithTrustedUntil(true).Validate(&truststore.CertChain{ServerCert: untrustedCert}); !r[0].TrustedUntil.IsZero() {
+		t.Errorf("failed result has TrustedUntil %v", r[0].TrustedUntil)
+	}
+}
+
 func TestConstraintSCTNotAfter(t *testing.T) {
 	t.Parallel()
 
@@ -441,6 +832,9 @@ func TestConstraintSCTNotAfter(t *testing.T) {
 	if r.FailureReason == "" {
 		t.Error("FailureReason should explain the SCT violation")
 	}
+	if r.FailureCode != truststore.FailureSCTDeadline {
+		t.Errorf("FailureCode = %q, want %q", r.FailureCode, truststore.FailureSCTDeadline)
+	}
 	t.Logf("FailureReason: %s", r.FailureReason)
 }
 
@@ -523,3 +917,29 @@ func TestConstraintSCTNotAfterPasses(t *testing.T) {
 		t.Errorf("expected trusted, got failure: %s", r.FailureReason)
 	}
 }
+
+func TestVerifyHostname(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+	serverCert.DNSNames = []string{"test.example.com"}
+
+	tests := []struct {
+		host      string
+		wantValid bool
+	}{
+		{"test.example.com", true},
+		{"other.example.com", false},
+	}
+
+	for _, tt := range tests {
+		check := VerifyHostname(&truststore.CertChain{Endpoint: tt.host, ServerCert: serverCert})
+		if check.Valid != tt.wantValid {
+			t.Errorf("%s: Valid = %v, want %v", tt.host, check.Valid, tt.wantValid)
+		}
+		if !tt.wantValid && check.Error != "certificate is not valid for "+tt.host {
+			t.Errorf("%s: Error = %q", tt.host, check.Error)
+		}
+	}
+}
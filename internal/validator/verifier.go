@@ -0,0 +1,276 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// maxAIAFetches bounds how many missing issuers are downloaded for one path.
+const maxAIAFetches = 3
+
+// Apple limits the validity period of TLS server certificates chaining to its trusted roots
+// (https://support.apple.com/en-us/102028, https://support.apple.com/en-us/103769)
+var appleValidityLimits = []struct {
+	issuedFrom time.Time
+	maxDays    int
+}{
+	{time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), 398},
+	{time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), 825},
+}
+
+// VerifyRequest is one path-building problem: a presented chain against one store's roots.
+type VerifyRequest struct {
+	Store         truststore.Store
+	Chain         *truststore.CertChain
+	Intermediates *x509.CertPool      // Presented intermediates; must not be modified
+	Roots         *x509.CertPool      // Store roots
+	RootCerts     []*x509.Certificate // Certificates in Roots, for verifiers that inspect anchors
+	Time          time.Time
+}
+
+// options returns crypto/x509 options for the request.
+func (r VerifyRequest) options() x509.VerifyOptions {
+	return x509.VerifyOptions{
+		Roots:         r.Roots,
+		Intermediates: r.Intermediates,
+		CurrentTime:   r.Time,
+	}
+}
+
+// Verifier builds and verifies certificate paths the way one platform's TLS client does.
+// It returns the verified paths (leaf first, root last) or the reason none exists.
+type Verifier interface {
+	Verify(req VerifyRequest) ([][]*x509.Certificate, error)
+}
+
+// VerifierFunc adapts an ordinary function to a Verifier.
+type VerifierFunc func(req VerifyRequest) ([][]*x509.Certificate, error)
+
+// Verify calls f(req).
+func (f VerifierFunc) Verify(req VerifyRequest) ([][]*x509.Certificate, error) {
+	return f(req)
+}
+
+// GoVerifier applies crypto/x509 semantics: paths are built only from presented
+// intermediates, and every certificate including the root must be within its validity period.
+var GoVerifier Verifier = VerifierFunc(func(req VerifyRequest) ([][]*x509.Certificate, error) {
+	return req.Chain.ServerCert.Verify(req.options())
+})
+
+// IssuerFetcher downloads the CA certificate published at an AIA caIssuers URL.
+type IssuerFetcher func(url string) (*x509.Certificate, error)
+
+// platformVerifier returns the built-in verifier modeling a platform's client.
+// fetch enables AIA chasing on platforms that do it; nil keeps paths to presented certificates.
+func platformVerifier(platform truststore.Platform, fetch IssuerFetcher) Verifier {
+	switch {
+	case platform == truststore.PlatformAndroid:
+		return androidVerifier{}
+	case platform.IsApple():
+		return appleVerifier{aia: aiaVerifier{fetch: fetch}}
+	case platform == truststore.PlatformWindows, platform == truststore.PlatformWinContainer,
+		platform == truststore.PlatformChrome:
+		return aiaVerifier{fetch: fetch}
+	default:
+		return GoVerifier
+	}
+}
+
+// WithIssuerFetcher returns a validator whose Apple, Windows and Chrome verifiers download
+// missing intermediates from AIA caIssuers URLs, as those clients do. Android never does.
+// Verifiers set with WithVerifier are replaced. Root pools are shared with v.
+func (v *Validator) WithIssuerFetcher(fetch IssuerFetcher) *Validator {
+	return v.withVerifiers(func(p truststore.Platform) Verifier {
+		return platformVerifier(p, fetch)
+	})
+}
+
+// WithVerifier returns a validator that builds paths for platform with verifier
+// instead of the built-in model. Root pools are shared with v.
+func (v *Validator) WithVerifier(platform truststore.Platform, verifier Verifier) *Validator {
+	return v.withVerifiers(func(p truststore.Platform) Verifier {
+		if p == platform {
+			return verifier
+		}
+		return nil
+	})
+}
+
+// withVerifiers copies v, replacing each pool's verifier with choose(platform) unless nil.
+func (v *Validator) withVerifiers(choose func(truststore.Platform) Verifier) *Validator {
+	c := *v
+	c.pools = make([]*storePool, len(v.pools))
+	for i, pool := range v.pools {
+		c.pools[i] = pool
+		if verifier := choose(pool.store.Platform); verifier != nil {
+			p := *pool
+			p.verifier = verifier
+			c.pools[i] = &p
+		}
+	}
+	return &c
+}
+
+// aiaVerifier follows Go semantics but, when no path exists, downloads the missing issuer
+// from the caIssuers URL of the last certificate reachable from the leaf and retries.
+type aiaVerifier struct {
+	fetch IssuerFetcher
+}
+
+func (a aiaVerifier) Verify(req VerifyRequest) ([][]*x509.Certificate, error) {
+	chains, err := GoVerifier.Verify(req)
+	if err == nil || a.fetch == nil {
+		return chains, err
+	}
+
+	var unknownAuth x509.UnknownAuthorityError
+	if !errors.As(err, &unknownAuth) {
+		return nil, err
+	}
+
+	candidates := append([]*x509.Certificate(nil), req.Chain.Intermediates...)
+	retry := req
+	retry.Intermediates = req.Intermediates.Clone()
+	cur := pathTip(req.Chain.ServerCert, candidates)
+	for range maxAIAFetches {
+		issuer := a.fetchIssuer(cur)
+		if issuer == nil {
+			break
+		}
+		retry.Intermediates.AddCert(issuer)
+		if chains, verr := GoVerifier.Verify(retry); verr == nil {
+			return chains, nil
+		}
+		candidates = append(candidates, issuer)
+		cur = pathTip(issuer, candidates)
+	}
+	return nil, err
+}
+
+// fetchIssuer returns the first caIssuers certificate that actually signed cert, or nil.
+func (a aiaVerifier) fetchIssuer(cert *x509.Certificate) *x509.Certificate {
+	if isSelfSigned(cert) {
+		return nil
+	}
+	for _, url := range cert.IssuingCertificateURL {
+		issuer, err := a.fetch(url)
+		if err == nil && issuedBy(cert, issuer) {
+			return issuer
+		}
+	}
+	return nil
+}
+
+// pathTip follows issuers from cert through candidates and returns the last certificate
+// reached, whose issuer is the one still missing.
+func pathTip(cert *x509.Certificate, candidates []*x509.Certificate) *x509.Certificate {
+	seen := map[*x509.Certificate]bool{cert: true}
+	for {
+		next := findIssuer(cert, candidates, seen)
+		if next == nil {
+			return cert
+		}
+		seen[next] = true
+		cert = next
+	}
+}
+
+// findIssuer returns the first unseen candidate that signed cert.
+func findIssuer(cert *x509.Certificate, candidates []*x509.Certificate, seen map[*x509.Certificate]bool) *x509.Certificate {
+	for _, c := range candidates {
+		if !seen[c] && issuedBy(cert, c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// issuedBy reports whether issuer's name and key match cert's issuer and signature.
+func issuedBy(cert, issuer *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, issuer.RawSubject) && cert.CheckSignatureFrom(issuer) == nil
+}
+
+// androidVerifier models Android's platform verifier: no AIA fetching, and trust anchors
+// are trusted regardless of their own validity dates, which is how chains through an
+// expired root's cross-sign keep working on older Android releases.
+type androidVerifier struct{}
+
+func (androidVerifier) Verify(req VerifyRequest) ([][]*x509.Certificate, error) {
+	chains, err := GoVerifier.Verify(req)
+	if err == nil {
+		return chains, nil
+	}
+
+	for _, root := range req.RootCerts {
+		if !req.Time.After(root.NotAfter) && !req.Time.Before(root.NotBefore) {
+			continue
+		}
+		// Anchor at the presented certificate the out-of-date root signed
+		for _, ca := range req.Chain.Intermediates {
+			if !issuedBy(ca, root) {
+				continue
+			}
+			anchored := req
+			anchored.Roots = x509.NewCertPool()
+			anchored.Roots.AddCert(ca)
+			paths, verr := GoVerifier.Verify(anchored)
+			if verr != nil {
+				continue
+			}
+			for i, path := range paths {
+				paths[i] = append(path[:len(path):len(path)], root)
+			}
+			return paths, nil
+		}
+	}
+	return nil, err
+}
+
+// appleVerifier models Apple's trust evaluation: AIA fetching plus validity period limits
+// that apply to certificates chaining to Apple's trusted roots, not user-installed ones.
+type appleVerifier struct {
+	aia aiaVerifier
+}
+
+func (a appleVerifier) Verify(req VerifyRequest) ([][]*x509.Certificate, error) {
+	chains, err := a.aia.Verify(req)
+	if err != nil || anchoredAtExtraRoot(req.Store, chains[0]) {
+		return chains, err
+	}
+	if err := checkAppleValidity(req.Chain.ServerCert); err != nil {
+		return nil, err
+	}
+	return chains, nil
+}
+
+// checkAppleValidity returns an error if the leaf's validity period exceeds Apple's limit
+// for its issuance date.
+func checkAppleValidity(cert *x509.Certificate) error {
+	days := int(cert.NotAfter.Sub(cert.NotBefore).Hours() / 24)
+	for _, limit := range appleValidityLimits {
+		if cert.NotBefore.Before(limit.issuedFrom) {
+			continue
+		}
+		if days > limit.maxDays {
+			return &validityPeriodError{days: days, maxDays: limit.maxDays, issuedFrom: limit.issuedFrom}
+		}
+		return nil
+	}
+	return nil
+}
+
+// validityPeriodError reports a leaf valid for longer than the platform allows.
+type validityPeriodError struct {
+	days, maxDays int
+	issuedFrom    time.Time
+}
+
+func (e *validityPeriodError) Error() string {
+	return fmt.Sprintf("certificate valid for %d days exceeds Apple limit of %d days for certificates issued since %s",
+		e.days, e.maxDays, e.issuedFrom.Format(truststore.DateFormat))
+}
@@ -0,0 +1,254 @@
+package validator
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// signTestCert issues template with a fresh key, self-signed when parent is nil.
+func signTestCert(t *testing.T, template, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// caTemplate returns a CA template valid in [notBefore, notAfter].
+func caTemplate(name string, notBefore, notAfter time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+}
+
+func TestValidatorAIAFetching(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	root, rootKey := signTestCert(t, caTemplate("AIA Root", now.Add(-time.Hour), now.Add(time.Hour)), nil, nil)
+	intermediate, intKey := signTestCert(t, caTemplate("AIA Intermediate", now.Add(-time.Hour), now.Add(time.Hour)), root, rootKey)
+	leaf, _ := signTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "aia.example.com"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IssuingCertificateURL: []string{"http://ca.test/missing.der", "http://ca.test/intermediate.der"},
+	}, intermediate, intKey)
+
+	fp := truststore.FingerprintFromCert(root)
+	registerTestCert(fp, root)
+	defer unregisterTestCert(fp)
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	chain := &truststore.CertChain{Endpoint: "aia.example.com", ServerCert: leaf}
+
+	var fetched []string
+	fetch := func(url string) (*x509.Certificate, error) {
+		fetched = append(fetched, url)
+		if url == "http://ca.test/intermediate.der" {
+			return intermediate, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	v := New(stores)
+	v.workers = 1 // fetch records URLs without locking
+
+	for _, r := range v.Validate(chain) {
+		if r.Trusted {
+			t.Errorf("%s without fetcher: trusted, want missing intermediate", r.Platform.Platform)
+		}
+	}
+
+	results := v.WithIssuerFetcher(fetch).Validate(chain)
+	if !results[0].Trusted {
+		t.Errorf("windows with fetcher: %s, want trusted via AIA", results[0].FailureReason)
+	}
+	if results[1].Trusted {
+		t.Error("android with fetcher: trusted, want failure (Android doesn't fetch AIA)")
+	}
+	if len(fetched) != 2 {
+		t.Errorf("fetched %v, want both caIssuers URLs tried once", fetched)
+	}
+}
+
+func TestValidatorAndroidExpiredAnchor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	oldRoot, oldKey := signTestCert(t, caTemplate("Expired Root", now.Add(-48*time.Hour), now.Add(-24*time.Hour)), nil, nil)
+	newRoot, newKey := signTestCert(t, caTemplate("New Root", now.Add(-time.Hour), now.Add(time.Hour)), nil, nil)
+
+	// Cross-sign: new root's subject and key, issued by the expired root
+	crossTemplate := caTemplate("New Root", now.Add(-time.Hour), now.Add(time.Hour))
+	der, err := x509.CreateCertificate(rand.Reader, crossTemplate, oldRoot, &newKey.PublicKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossSign, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	intermediate, intKey := signTestCert(t, caTemplate("Issuing CA", now.Add(-time.Hour), now.Add(time.Hour)), newRoot, newKey)
+	leaf, _ := signTestCert(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "legacy.example.com"},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, intermediate, intKey)
+
+	// Only the expired root is trusted, like older Android releases without the new root
+	fp := truststore.FingerprintFromCert(oldRoot)
+	registerTestCert(fp, oldRoot)
+	defer unregisterTestCert(fp)
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformAndroid, Version: "7", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformIOS, Version: "10", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	chain := &truststore.CertChain{
+		Endpoint:      "legacy.example.com",
+		ServerCert:    leaf,
+		Intermediates: []*x509.Certificate{intermediate, crossSign},
+	}
+
+	results := ValidateChain(chain, stores)
+	android, ios := results[0], results[1]
+
+	if !android.Trusted {
+		t.Fatalf("android: %s, want trusted through expired anchor", android.FailureReason)
+	}
+	if got := android.VerifiedChain[len(android.VerifiedChain)-1]; !got.Equal(oldRoot) {
+		t.Errorf("android anchored at %q, want the expired root", got.Subject.CommonName)
+	}
+	if ios.Trusted {
+		t.Error("ios: trusted, want failure on expired root")
+	}
+}
+
+func TestValidatorAppleValidityLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	root, rootKey := signTestCert(t, caTemplate("Validity Root", now.Add(-time.Hour), now.AddDate(5, 0, 0)), nil, nil)
+	leaf, _ := signTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "long.example.com"},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.AddDate(0, 0, 400),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, root, rootKey)
+
+	fp := truststore.FingerprintFromCert(root)
+	registerTestCert(fp, root)
+	defer unregisterTestCert(fp)
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformIOS, Version: "18", Fingerprints: []truststore.Fingerprint{fp},
+			Extra: map[truststore.Fingerprint]bool{fp: true}},
+	}
+	chain := &truststore.CertChain{Endpoint: "long.example.com", ServerCert: leaf}
+
+	results := ValidateChain(chain, stores)
+	if results[0].Trusted || !strings.Contains(results[0].FailureReason, "exceeds Apple limit of 398 days") {
+		t.Errorf("ios: trusted=%v reason=%q, want Apple validity failure", results[0].Trusted, results[0].FailureReason)
+	}
+	if !results[1].Trusted {
+		t.Errorf("android: %s, want trusted", results[1].FailureReason)
+	}
+	if !results[2].Trusted {
+		t.Errorf("ios with extra root: %s, want trusted (limit applies to Apple's roots only)", results[2].FailureReason)
+	}
+}
+
+func TestCheckAppleValidity(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		days      int
+		wantErr   bool
+	}{
+		{"398 days after 2020-09", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 398, false},
+		{"399 days after 2020-09", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 399, true},
+		{"825 days in 2020", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 825, false},
+		{"826 days in 2020", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 826, true},
+		{"long-lived before 2019-07", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), 1000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cert := &x509.Certificate{NotBefore: tt.notBefore, NotAfter: tt.notBefore.Add(time.Duration(tt.days) * day)}
+			if err := checkAppleValidity(cert); (err != nil) != tt.wantErr {
+				t.Errorf("checkAppleValidity() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatorWithVerifier(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey := generateTestCert(t, true, nil, nil)
+	serverCert, _ := generateTestCert(t, false, caCert, caKey)
+
+	fp := truststore.FingerprintFromCert(caCert)
+	registerTestCert(fp, caCert)
+	defer unregisterTestCert(fp)
+
+	stores := []truststore.Store{
+		{Platform: truststore.PlatformWindows, Version: "current", Fingerprints: []truststore.Fingerprint{fp}},
+		{Platform: truststore.PlatformAndroid, Version: "14", Fingerprints: []truststore.Fingerprint{fp}},
+	}
+	chain := &truststore.CertChain{Endpoint: "test.example.com", ServerCert: serverCert}
+
+	reject := VerifierFunc(func(VerifyRequest) ([][]*x509.Certificate, error) {
+		return nil, errors.New("rejected by custom verifier")
+	})
+	v := New(stores)
+	results := v.WithVerifier(truststore.PlatformWindows, reject).Validate(chain)
+
+	if results[0].Trusted || results[0].FailureReason != "rejected by custom verifier" {
+		t.Errorf("windows: trusted=%v reason=%q, want custom verifier failure", results[0].Trusted, results[0].FailureReason)
+	}
+	if !results[1].Trusted {
+		t.Errorf("android: %s, want built-in verifier unchanged", results[1].FailureReason)
+	}
+	if !v.Validate(chain)[0].Trusted {
+		t.Error("WithVerifier modified the original validator")
+	}
+}
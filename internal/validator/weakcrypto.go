@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/dsa" //nolint:staticcheck // SA1019: Only used to detect deprecated DSA keys
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+const (
+	appleMinRSABits = 2048 // iOS 13+, macOS 10.15+ (https://support.apple.com/en-us/103769)
+	minRSABits      = 1024 // Rejected by all supported platforms below this
+)
+
+// weakSignatures are signature algorithms rejected in TLS chains by current platforms.
+var weakSignatures = map[x509.SignatureAlgorithm]string{
+	x509.MD2WithRSA:    "MD2",
+	x509.MD5WithRSA:    "MD5",
+	x509.SHA1WithRSA:   "SHA-1",
+	x509.DSAWithSHA1:   "SHA-1",
+	x509.ECDSAWithSHA1: "SHA-1",
+}
+
+// weakness is a weak cryptography finding with the platforms known to hard-fail on it.
+type weakness struct {
+	description string
+	hardFails   func(truststore.Platform) bool
+}
+
+func allPlatforms(truststore.Platform) bool { return true }
+
+func applePlatforms(p truststore.Platform) bool { return p.IsApple() }
+
+// boringSSLPlatforms use BoringSSL, which dropped P-224 and DSA.
+func boringSSLPlatforms(p truststore.Platform) bool {
+	return p == truststore.PlatformChrome || p == truststore.PlatformAndroid
+}
+
+// checkWeakCrypto finds weak signatures, short RSA keys and deprecated keys
+// anywhere in the presented chain. Self-signed signatures are not checked
+// since clients don't verify them.
+func checkWeakCrypto(chain *truststore.CertChain) []weakness {
+	var found []weakness
+	certs := append([]*x509.Certificate{chain.ServerCert}, chain.Intermediates...)
+
+	for _, cert := range certs {
+		name := fmt.Sprintf("%q", truststore.CertName(cert))
+
+		if alg, ok := weakSignatures[cert.SignatureAlgorithm]; ok && !isSelfSigned(cert) {
+			found = append(found, weakness{fmt.Sprintf("weak crypto: %s has %s signature", name, alg), allPlatforms})
+		}
+
+		switch key := cert.PublicKey.(type) {
+		case *rsa.PublicKey:
+			bits := key.N.BitLen()
+			switch {
+			case bits < minRSABits:
+				found = append(found, weakness{fmt.Sprintf("weak crypto: %s has RSA-%d key", name, bits), allPlatforms})
+			case bits < appleMinRSABits:
+				found = append(found, weakness{fmt.Sprintf("weak crypto: %s has RSA-%d key", name, bits), applePlatforms})
+			}
+		case *ecdsa.PublicKey:
+			if key.Curve == elliptic.P224() {
+				found = append(found, weakness{fmt.Sprintf("weak crypto: %s uses deprecated curve P-224", name), boringSSLPlatforms})
+			}
+		case *dsa.PublicKey:
+			found = append(found, weakness{fmt.Sprintf("weak crypto: %s has deprecated DSA key", name), boringSSLPlatforms})
+		}
+	}
+
+	return found
+}
+
+// weakCryptoWarnings returns the findings that hard-fail on the given platform.
+func weakCryptoWarnings(platform truststore.Platform, found []weakness) []string {
+	var warnings []string
+	for _, w := range found {
+		if w.hardFails(platform) {
+			warnings = append(warnings, w.description)
+		}
+	}
+	return warnings
+}
+
+// isSelfSigned reports whether a certificate names itself as issuer.
+// Signatures aren't checked since Go refuses to verify SHA-1 ones.
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer)
+}
@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestCheckWeakCrypto(t *testing.T) {
+	t.Parallel()
+
+	rsaKey := func(bits int) *rsa.PublicKey {
+		return &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), uint(bits-1)), E: 65537}
+	}
+	cert := func(alg x509.SignatureAlgorithm, key any) *x509.Certificate {
+		return &x509.Certificate{
+			Subject:            pkix.Name{CommonName: "Test"},
+			RawSubject:         []byte("subject"),
+			RawIssuer:          []byte("issuer"),
+			SignatureAlgorithm: alg,
+			PublicKey:          key,
+		}
+	}
+	selfSignedSHA1 := cert(x509.SHA1WithRSA, rsaKey(2048))
+	selfSignedSHA1.RawIssuer = selfSignedSHA1.RawSubject
+
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		want    string                // Substring of the expected finding, empty if none
+		failsOn []truststore.Platform // Platforms expected to hard-fail
+		passOn  []truststore.Platform // Platforms expected not to hard-fail
+	}{
+		{
+			name: "strong RSA",
+			cert: cert(x509.SHA256WithRSA, rsaKey(2048)),
+		},
+		{
+			name:    "SHA-1 signature",
+			cert:    cert(x509.SHA1WithRSA, rsaKey(2048)),
+			want:    "SHA-1 signature",
+			failsOn: []truststore.Platform{truststore.PlatformChrome, truststore.PlatformIOS, truststore.PlatformAndroid},
+		},
+		{
+			name: "self-signed SHA-1 root",
+			cert: selfSignedSHA1,
+		},
+		{
+			name:    "RSA-1536",
+			cert:    cert(x509.SHA256WithRSA, rsaKey(1536)),
+			want:    "RSA-1536",
+			failsOn: []truststore.Platform{truststore.PlatformIOS, truststore.PlatformMacOS},
+			passOn:  []truststore.Platform{truststore.PlatformChrome, truststore.PlatformWindows},
+		},
+		{
+			name:    "RSA-512",
+			cert:    cert(x509.SHA256WithRSA, rsaKey(512)),
+			want:    "RSA-512",
+			failsOn: []truststore.Platform{truststore.PlatformWindows, truststore.PlatformAndroid},
+		},
+		{
+			name:    "P-224",
+			cert:    cert(x509.ECDSAWithSHA256, &ecdsa.PublicKey{Curve: elliptic.P224()}),
+			want:    "P-224",
+			failsOn: []truststore.Platform{truststore.PlatformChrome, truststore.PlatformAndroid},
+			passOn:  []truststore.Platform{truststore.PlatformIOS},
+		},
+		{
+			name: "P-256",
+			cert: cert(x509.ECDSAWithSHA256, &ecdsa.PublicKey{Curve: elliptic.P256()}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			leaf := cert(x509.SHA256WithRSA, rsaKey(2048))
+			found := checkWeakCrypto(&truststore.CertChain{ServerCert: leaf, Intermediates: []*x509.Certificate{tt.cert}})
+
+			if tt.want == "" {
+				if len(found) > 0 {
+					t.Errorf("expected no findings, got %v", found)
+				}
+				return
+			}
+			if len(found) != 1 || !strings.Contains(found[0].description, tt.want) {
+				t.Fatalf("findings = %v, want one containing %q", found, tt.want)
+			}
+			for _, p := range tt.failsOn {
+				if len(weakCryptoWarnings(p, found)) != 1 {
+					t.Errorf("%s: expected warning", p)
+				}
+			}
+			for _, p := range tt.passOn {
+				if len(weakCryptoWarnings(p, found)) != 0 {
+					t.Errorf("%s: expected no warning", p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Command databundle packs trustdata/data into a signed data bundle for certvet update.
+// Usage:
+//
+//	go run ./tools/databundle <version>   # writes build/data-bundle.tar.gz{,.sig}
+//	go run ./tools/databundle -genkey     # prints a new signing key pair
+//
+// The base64 ed25519 private key is read from CERTVET_DATA_SIGNING_KEY and its public half
+// from CERTVET_DATA_PUBLIC_KEY, which release binaries embed. Both are required and must
+// match, so a release can't ship binaries unable to verify the bundle it publishes.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/dataupdate"
+)
+
+const (
+	dataDir   = "trustdata/data"
+	outputDir = "build"
+	keyEnv    = "CERTVET_DATA_SIGNING_KEY"
+	pubKeyEnv = "CERTVET_DATA_PUBLIC_KEY"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: databundle <version> | -genkey")
+		os.Exit(2)
+	}
+	if os.Args[1] == "-genkey" {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("%s=%s\n", pubKeyEnv, base64.StdEncoding.EncodeToString(pub))
+		fmt.Printf("%s=%s\n", keyEnv, base64.StdEncoding.EncodeToString(priv))
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(keyEnv))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		fail(fmt.Errorf("%s must be a base64 ed25519 private key", keyEnv))
+	}
+	pub := base64.StdEncoding.EncodeToString(ed25519.PrivateKey(key).Public().(ed25519.PublicKey))
+	if os.Getenv(pubKeyEnv) != pub {
+		fail(fmt.Errorf("%s must be set to the public half of %s", pubKeyEnv, keyEnv))
+	}
+
+	meta := dataupdate.Metadata{Version: os.Args[1], Created: time.Now().UTC().Truncate(time.Second)}
+	bundle, err := dataupdate.Pack(dataDir, meta)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil { //nolint:gosec // G301: 0755 is standard for build output
+		fail(err)
+	}
+	path := filepath.Join(outputDir, "data-bundle.tar.gz")
+	if err := os.WriteFile(path, bundle, 0644); err != nil { //nolint:gosec // G306: Release artifact
+		fail(err)
+	}
+	if err := os.WriteFile(path+".sig", dataupdate.Sign(ed25519.PrivateKey(key), bundle), 0644); err != nil { //nolint:gosec // G306: Release artifact
+		fail(err)
+	}
+	fmt.Printf("✓ %s (%s, %d bytes)\n", path, meta.Version, len(bundle))
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
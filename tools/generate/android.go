@@ -22,6 +22,10 @@ const (
 	androidRefsURL    = "https://android.googlesource.com/platform/system/ca-certificates/+refs/heads?format=TEXT"
 	androidArchiveURL = "https://android.googlesource.com/platform/system/ca-certificates/+archive/refs/heads/%s/files.tar.gz"
 	minAndroidVersion = 7 // Android 7 (Nougat) and later only
+
+	// Conscrypt Mainline releases are tagged aml_con_<version code> in the same repository
+	conscryptTagsURL    = "https://android.googlesource.com/platform/system/ca-certificates/+refs/tags?format=TEXT"
+	conscryptArchiveURL = "https://android.googlesource.com/platform/system/ca-certificates/+archive/refs/tags/%s/files.tar.gz"
 )
 
 // AndroidGenerator implements StoreGenerator for Android trust store data.
@@ -38,27 +42,158 @@ func (AndroidGenerator) Generate() ([]TrustEntry, error) {
 	}
 
 	var entries []TrustEntry
+	stores := make(map[string][]truststore.Fingerprint) // Latest store of each version, for Conscrypt updates
 
 	for _, v := range versions {
 		fingerprints, err := ScrapeAndroidVersion(v.Branch)
 		if err != nil {
-			Log.Warn("Android %s: %v", v.Version, err)
+			Log.Skip("Android version failed", "version", v.Version, "err", err)
 			continue
 		}
+		stores[v.Version] = fingerprints
+		entries = append(entries, androidEntries(v.Version, fingerprints)...)
+	}
 
-		// Create TrustEntry for each fingerprint
-		for _, fp := range fingerprints {
-			entries = append(entries, TrustEntry{
-				Platform:    "android",
-				Version:     v.Version,
-				Fingerprint: fp,
-			})
+	// Conscrypt updates are optional: without them only the OS release stores are known
+	releases, err := DiscoverConscryptReleases()
+	if err != nil {
+		Log.Warn("Conscrypt releases unavailable", "err", err)
+		return entries, nil
+	}
+	for _, r := range releases {
+		base, ok := stores[r.Android]
+		if !ok {
+			continue
+		}
+		fingerprints, err := ScrapeConscryptRelease(r.Tag)
+		if err != nil {
+			Log.Skip("Conscrypt release failed", "version_code", r.VersionCode, "err", err)
+			continue
 		}
+		// Only updates that change the CA store become sub-versions
+		if sameFingerprints(base, fingerprints) {
+			continue
+		}
+		stores[r.Android] = fingerprints
+		entries = append(entries, androidEntries(r.Version(), fingerprints)...)
 	}
 
 	return entries, nil
 }
 
+// androidEntries returns the trust entries of an Android store version.
+func androidEntries(version string, fingerprints []truststore.Fingerprint) []TrustEntry {
+	entries := make([]TrustEntry, 0, len(fingerprints))
+	for _, fp := range fingerprints {
+		entries = append(entries, TrustEntry{
+			Platform:    "android",
+			Version:     version,
+			Fingerprint: fp,
+		})
+	}
+	return entries
+}
+
+// sameFingerprints reports whether a and b hold the same fingerprints, in any order.
+func sameFingerprints(a, b []truststore.Fingerprint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[truststore.Fingerprint]bool, len(a))
+	for _, fp := range a {
+		set[fp] = true
+	}
+	for _, fp := range b {
+		if !set[fp] {
+			return false
+		}
+	}
+	return true
+}
+
+// ConscryptRelease is a Conscrypt Mainline module release. Since Android 14 the CA store
+// ships in the Conscrypt module, so devices receive CA updates through Google Play system
+// updates independent of OS updates; earlier releases don't carry CA certificates.
+type ConscryptRelease struct {
+	VersionCode string // Module version code, as shown for com.android.conscrypt on devices
+	Android     string // Android version the release targets, e.g. "14"
+	Tag         string
+}
+
+// Version returns the Android store version of devices patched with the release: a
+// sub-version of the Android version, e.g. "14.341110000", so "android>=14" includes it.
+func (r ConscryptRelease) Version() string {
+	return r.Android + "." + r.VersionCode
+}
+
+var conscryptTagRE = regexp.MustCompile(`^aml_con_((\d{2})\d{7})$`)
+
+// conscryptAndroidVersions maps the SDK level leading Conscrypt version codes to Android
+// versions. Only those shipping the CA store in the module are listed.
+var conscryptAndroidVersions = map[string]string{
+	"34": "14",
+	"35": "15",
+	"36": "16",
+}
+
+// DiscoverConscryptReleases fetches the Conscrypt releases from the CA certificate tags.
+func DiscoverConscryptReleases() ([]ConscryptRelease, error) {
+	resp, err := httpClient.Get(conscryptTagsURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch conscrypt tags: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conscrypt tags returned status %d", resp.StatusCode)
+	}
+
+	return ParseConscryptTags(resp.Body)
+}
+
+// ParseConscryptTags parses the refs API response for tags to extract Conscrypt releases,
+// sorted by version code.
+func ParseConscryptTags(r io.Reader) ([]ConscryptRelease, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read tags: %w", err)
+	}
+
+	var releases []ConscryptRelease
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+		tag := strings.TrimPrefix(parts[1], "refs/tags/")
+		matches := conscryptTagRE.FindStringSubmatch(tag)
+		if matches == nil {
+			continue
+		}
+		if android, ok := conscryptAndroidVersions[matches[2]]; ok {
+			releases = append(releases, ConscryptRelease{VersionCode: matches[1], Android: android, Tag: tag})
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].VersionCode < releases[j].VersionCode })
+
+	return releases, nil
+}
+
+// ScrapeConscryptRelease downloads and extracts fingerprints from a Conscrypt release tag.
+func ScrapeConscryptRelease(tag string) ([]truststore.Fingerprint, error) {
+	resp, err := httpClient.Get(fmt.Sprintf(conscryptArchiveURL, tag))
+	if err != nil {
+		return nil, fmt.Errorf("fetch conscrypt archive: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conscrypt archive returned status %d", resp.StatusCode)
+	}
+
+	return ParseAndroidArchive(resp.Body)
+}
+
 // AndroidVersion represents an Android version and its branch.
 type AndroidVersion struct {
 	Version string // Version as string (e.g., "10", "14")
@@ -193,7 +328,7 @@ func ParseAndroidArchive(r io.Reader) ([]truststore.Fingerprint, error) {
 		cert, err := x509.ParseCertificate(block.Bytes)
 		if err != nil {
 			// Skip unparseable certificates with warning - Go's TLS stack would reject them too
-			Log.Warn("skipping %s: %v", header.Name, err)
+			Log.Skip("certificate unparsable", "file", header.Name, "err", err)
 			continue
 		}
 
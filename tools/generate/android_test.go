@@ -118,3 +118,40 @@ WD9f
 		t.Errorf("fingerprint = %q, want %q", fingerprints[0].String(), want.String())
 	}
 }
+
+func TestParseConscryptTags(t *testing.T) {
+	t.Parallel()
+
+	input := "abc123 refs/tags/aml_con_351010000\n" +
+		"def456 refs/tags/aml_con_341110000\n" +
+		"aaa111 refs/tags/aml_con_331820000\n" + // Android 13: no CA store in the module
+		"bbb222 refs/tags/android-14.0.0_r1\n"
+
+	releases, err := ParseConscryptTags(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("got %d releases, want 2: %+v", len(releases), releases)
+	}
+	if releases[0].Version() != "14.341110000" || releases[0].Tag != "aml_con_341110000" {
+		t.Errorf("releases[0] = %+v, want 14.341110000 from aml_con_341110000", releases[0])
+	}
+	if releases[1].Android != "15" {
+		t.Errorf("releases[1].Android = %q, want 15", releases[1].Android)
+	}
+}
+
+func TestSameFingerprints(t *testing.T) {
+	t.Parallel()
+
+	a := truststore.Fingerprint{1}
+	b := truststore.Fingerprint{2}
+	c := truststore.Fingerprint{3}
+	if !sameFingerprints([]truststore.Fingerprint{a, b}, []truststore.Fingerprint{b, a}) {
+		t.Error("same fingerprints in another order should be equal")
+	}
+	if sameFingerprints([]truststore.Fingerprint{a, b}, []truststore.Fingerprint{a, c}) {
+		t.Error("different fingerprints should not be equal")
+	}
+}
@@ -1,11 +1,13 @@
 package generate
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/PuerkitoBio/goquery"
@@ -26,24 +28,30 @@ func (AppleGenerator) Generate() ([]TrustEntry, error) {
 	}
 
 	// Track URLs we've already scraped (multiple platforms share the same page)
-	scrapedURLs := make(map[string][]truststore.Fingerprint)
+	scrapedURLs := make(map[string]AppleTrustPage)
+	var latest time.Time // Newest publication date among the pages
 
 	var entries []TrustEntry
 
 	for _, v := range versions {
 		// Check if we've already scraped this URL
-		fingerprints, cached := scrapedURLs[v.URL]
+		page, cached := scrapedURLs[v.URL]
 		if !cached {
-			fingerprints, err = ScrapeAppleVersion(v.URL)
+			var published time.Time
+			page, published, err = scrapeAppleVersion(v.URL)
 			if err != nil {
-				Log.Warn("%s %s: %v", v.Platform, v.Version, err)
+				Log.Skip("version page failed", "platform", v.Platform, "version", v.Version, "err", err)
 				continue
 			}
-			scrapedURLs[v.URL] = fingerprints
+			scrapedURLs[v.URL] = page
+			if published.After(latest) {
+				latest = published
+			}
 		}
+		RecordBlocked(v.Platform, v.Version, page.Blocked)
 
 		// Create TrustEntry for each fingerprint
-		for _, fp := range fingerprints {
+		for _, fp := range page.Trusted {
 			entries = append(entries, TrustEntry{
 				Platform:    string(v.Platform),
 				Version:     v.Version,
@@ -52,6 +60,9 @@ func (AppleGenerator) Generate() ([]TrustEntry, error) {
 		}
 	}
 
+	if !latest.IsZero() {
+		RecordSourceVersion("apple", latest.Format(truststore.DateFormat))
+	}
 	return entries, nil
 }
 
@@ -178,64 +189,146 @@ func ParseAppleMasterPage(r io.Reader) ([]ApplePlatformVersion, error) {
 
 // ScrapeAppleVersion fetches a version page and extracts fingerprints.
 func ScrapeAppleVersion(url string) ([]truststore.Fingerprint, error) {
+	page, _, err := scrapeAppleVersion(url)
+	return page.Trusted, err
+}
+
+// scrapeAppleVersion fetches a version page, returning its certificates by section and
+// its publication date (zero if the page shows none).
+func scrapeAppleVersion(url string) (AppleTrustPage, time.Time, error) {
 	resp, err := httpClient.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("fetch Apple version page: %w", err)
+		return AppleTrustPage{}, time.Time{}, fmt.Errorf("fetch Apple version page: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("apple version page returned status %d", resp.StatusCode)
+		return AppleTrustPage{}, time.Time{}, fmt.Errorf("apple version page returned status %d", resp.StatusCode)
+	}
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return AppleTrustPage{}, time.Time{}, fmt.Errorf("read Apple version page: %w", err)
 	}
+	certs, err := ParseAppleTrustPage(bytes.NewReader(page))
+	if err != nil {
+		return AppleTrustPage{}, time.Time{}, err
+	}
+	published, _ := ParseApplePublishedDate(string(page))
+	return certs, published, nil
+}
 
-	return ParseAppleVersionPage(resp.Body)
+var (
+	htmlTagPattern       = regexp.MustCompile(`<[^>]*>`)
+	applePublishedFormat = "January 2, 2006"
+	applePublishedDate   = regexp.MustCompile(`Published Date:\s*([A-Z][a-z]+ \d{1,2}, \d{4})`)
+)
+
+// ParseApplePublishedDate returns the "Published Date" an Apple support article shows.
+func ParseApplePublishedDate(page string) (time.Time, bool) {
+	text := strings.Join(strings.Fields(htmlTagPattern.ReplaceAllString(page, " ")), " ")
+	m := applePublishedDate.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(applePublishedFormat, m[1])
+	return t, err == nil
 }
 
 // ParseAppleVersionPage extracts fingerprints from a version page HTML.
 // This is identical to ParseIOSVersionPage - reused for all Apple platforms.
 func ParseAppleVersionPage(r io.Reader) ([]truststore.Fingerprint, error) {
+	page, err := ParseAppleTrustPage(r)
+	if err != nil {
+		return nil, err
+	}
+	return page.Trusted, nil
+}
+
+// AppleTrustPage holds the certificates of an Apple trust store page by section.
+type AppleTrustPage struct {
+	Trusted []truststore.Fingerprint
+	Blocked map[truststore.Fingerprint]string // Blocked and Always Ask certificates by status
+}
+
+// appleSectionStatus returns the status of the certificates listed under an Apple page
+// heading: blocked, always ask, or empty for trusted ones.
+func appleSectionStatus(heading string) string {
+	heading = strings.ToLower(heading)
+	switch {
+	case strings.Contains(heading, "blocked"):
+		return truststore.CertBlocked
+	case strings.Contains(heading, "always ask"):
+		return truststore.CertAlwaysAsk
+	}
+	return ""
+}
+
+// ParseAppleTrustPage extracts the fingerprints of a version page HTML, assigning each
+// table to the section of the heading before it. Pages without section headings list
+// trusted certificates only.
+func ParseAppleTrustPage(r io.Reader) (AppleTrustPage, error) {
+	var page AppleTrustPage
 	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("parse HTML: %w", err)
+		return page, fmt.Errorf("parse HTML: %w", err)
 	}
 
-	var fingerprints []truststore.Fingerprint
 	var parseErr error
 	rowNum := 0
+	status := ""
 
-	// Find table rows with certificate data
-	doc.Find("table tr, .table-data tr").Each(func(_ int, row *goquery.Selection) {
+	// Headings and tables in document order; each heading starts a section
+	doc.Find("h1, h2, h3, h4, table").Each(func(_ int, el *goquery.Selection) {
 		if parseErr != nil {
 			return // Stop processing if we hit an error
 		}
-
-		cells := row.Find("td")
-		if cells.Length() < 9 {
-			return // Not a data row
+		if !el.Is("table") {
+			status = appleSectionStatus(el.Text())
+			return
 		}
 
-		// SHA-256 fingerprint is in the last column (9th)
-		fpCell := strings.TrimSpace(cells.Eq(8).Text())
+		// Find table rows with certificate data
+		el.Find("tr").Each(func(_ int, row *goquery.Selection) {
+			if parseErr != nil {
+				return
+			}
 
-		// Skip header rows - some older pages use <td> instead of <th> for headers
-		if strings.Contains(strings.ToLower(fpCell), "fingerprint") ||
-			strings.Contains(strings.ToLower(fpCell), "sha-256") ||
-			fpCell == "" {
-			return
-		}
+			cells := row.Find("td")
+			if cells.Length() < 9 {
+				return // Not a data row
+			}
 
-		rowNum++
-		fp, err := truststore.ParseFingerprint(fpCell)
-		if err != nil {
-			parseErr = fmt.Errorf("row %d: invalid fingerprint %q: %w", rowNum, fpCell, err)
-			return
-		}
-		fingerprints = append(fingerprints, fp)
+			// SHA-256 fingerprint is in the last column (9th)
+			fpCell := strings.TrimSpace(cells.Eq(8).Text())
+
+			// Skip header rows - some older pages use <td> instead of <th> for headers
+			if strings.Contains(strings.ToLower(fpCell), "fingerprint") ||
+				strings.Contains(strings.ToLower(fpCell), "sha-256") ||
+				fpCell == "" {
+				return
+			}
+
+			rowNum++
+			fp, err := truststore.ParseFingerprint(fpCell)
+			if err != nil {
+				parseErr = fmt.Errorf("row %d: invalid fingerprint %q: %w", rowNum, fpCell, err)
+				return
+			}
+			if status == "" {
+				page.Trusted = append(page.Trusted, fp)
+				return
+			}
+			if page.Blocked == nil {
+				page.Blocked = make(map[truststore.Fingerprint]string)
+			}
+			page.Blocked[fp] = status
+		})
 	})
 
 	if parseErr != nil {
-		return nil, parseErr
+		return AppleTrustPage{}, parseErr
 	}
 
-	return fingerprints, nil
+	return page, nil
 }
@@ -1,6 +1,7 @@
 package generate
 
 import (
+	"maps"
 	"os"
 	"strings"
 	"testing"
@@ -279,3 +280,58 @@ func TestParseAppleVersionPageSkipsEmptyRows(t *testing.T) {
 	}
 }
 
+
+func TestParseAppleTrustPageSections(t *testing.T) {
+	t.Parallel()
+
+	row := func(fp string) string {
+		return "<tr><td>CA</td><td>CA</td><td>RSA</td><td>2048 bits</td><td>SHA-256</td>" +
+			"<td>01</td><td>2030</td><td></td><td>" + fp + "</td></tr>"
+	}
+	trusted := strings.Repeat("AA", 32)
+	alwaysAsk := strings.Repeat("BB", 32)
+	blocked := strings.Repeat("CC", 32)
+	html := "<html><body><h1>iOS 12 - List of available trusted root certificates</h1>" +
+		"<h2>Trusted certificates</h2><table>" + row(trusted) + "</table>" +
+		"<h2>Always Ask certificates</h2><div><table>" + row(alwaysAsk) + "</table></div>" +
+		"<h2>Blocked certificates</h2><table>" + row(blocked) + "</table></body></html>"
+
+	page, err := ParseAppleTrustPage(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Trusted) != 1 || page.Trusted[0] != mustFingerprint(t, trusted) {
+		t.Errorf("trusted = %v, want only %s", page.Trusted, trusted)
+	}
+	want := map[truststore.Fingerprint]string{
+		mustFingerprint(t, alwaysAsk): truststore.CertAlwaysAsk,
+		mustFingerprint(t, blocked):   truststore.CertBlocked,
+	}
+	if !maps.Equal(page.Blocked, want) {
+		t.Errorf("blocked = %v, want %v", page.Blocked, want)
+	}
+}
+
+func mustFingerprint(t *testing.T, s string) truststore.Fingerprint {
+	t.Helper()
+	fp, err := truststore.ParseFingerprint(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fp
+}
+
+func TestParseApplePublishedDate(t *testing.T) {
+	t.Parallel()
+
+	page := `<div class="note"><p><b>Published Date:</b>
+		September 15, 2025</p></div>`
+	date, ok := ParseApplePublishedDate(page)
+	if !ok || date.Format("2006-01-02") != "2025-09-15" {
+		t.Errorf("ParseApplePublishedDate() = %v, %v, want 2025-09-15", date, ok)
+	}
+
+	if _, ok := ParseApplePublishedDate("<p>No date here</p>"); ok {
+		t.Error("expected no date for a page without one")
+	}
+}
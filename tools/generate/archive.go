@@ -0,0 +1,92 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// inputArchive is a directory of raw source inputs (CAB files, textprotos, HTML pages,
+// tarballs), one file per URL under host/path, so they can be inspected and replayed.
+// Archiving a run and regenerating offline from the archive reproduces its data, and
+// lets parser changes be debugged against fixed inputs.
+type inputArchive struct {
+	Dir string
+}
+
+// unsafeNameChars are replaced in archive file names derived from hosts and queries.
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._=-]`)
+
+// path returns the archive file of u: Dir/host/path, with the query appended to the file
+// name. Directory-like paths are stored as "index".
+func (a inputArchive) path(u *url.URL) string {
+	p := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if p == "" || strings.HasSuffix(u.Path, "/") {
+		p = path.Join(p, "index")
+	}
+	if u.RawQuery != "" {
+		p += "_" + unsafeNameChars.ReplaceAllString(u.RawQuery, "_")
+	}
+	return filepath.Join(a.Dir, unsafeNameChars.ReplaceAllString(u.Host, "_"), filepath.FromSlash(p))
+}
+
+// RoundTrip serves GET requests from the archive, failing for inputs it doesn't hold.
+func (a inputArchive) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("offline: %s %s not supported", req.Method, req.URL)
+	}
+	body, err := os.ReadFile(a.path(req.URL))
+	if err != nil {
+		return nil, fmt.Errorf("offline: %s not archived: %w", req.URL, err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Length": {strconv.Itoa(len(body))}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// archiveRecorder is an http.RoundTripper saving the body of every successful GET
+// response to Archive.
+type archiveRecorder struct {
+	Archive inputArchive
+	Next    http.RoundTripper
+}
+
+// RoundTrip passes req on and archives a 200 response body.
+func (r *archiveRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	file := r.Archive.path(req.URL)
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return nil, fmt.Errorf("archive %s: %w", req.URL, err)
+	}
+	if err := os.WriteFile(file, body, 0o644); err != nil { //nolint:gosec // G306: archived inputs are public downloads
+		return nil, fmt.Errorf("archive %s: %w", req.URL, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
@@ -0,0 +1,79 @@
+package generate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInputArchivePath(t *testing.T) {
+	t.Parallel()
+
+	a := inputArchive{Dir: "/snap"}
+	tests := map[string]string{
+		"http://ctldl.windowsupdate.com/msdownload/authrootstl.cab":              "/snap/ctldl.windowsupdate.com/msdownload/authrootstl.cab",
+		"https://ccadb.my.salesforce-sites.com/ccadb/Certs?NotBeforeDecade=2010": "/snap/ccadb.my.salesforce-sites.com/ccadb/Certs_NotBeforeDecade=2010",
+		"https://support.apple.com/":                                             "/snap/support.apple.com/index",
+		"https://example.com/a/../../etc/passwd":                                 "/snap/example.com/etc/passwd",
+		"http://127.0.0.1:8080/x":                                                "/snap/127.0.0.1_8080/x",
+	}
+	for raw, want := range tests {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.path(u); got != filepath.FromSlash(want) {
+			t.Errorf("path(%s) = %s, want %s", raw, got, want)
+		}
+	}
+}
+
+func TestInputArchiveReplay(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "input "+r.URL.RequestURI())
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	get := func(client *http.Client, path string) (string, error) {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	online := newHTTPClient(httpConfig{Timeout: 5 * time.Second, ArchiveDir: dir})
+	for _, path := range []string{"/data.cab", "/list?page=2", "/missing"} {
+		if _, err := get(online, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv.Close() // Offline reads must not reach the server
+	offline := newHTTPClient(httpConfig{OfflineDir: dir})
+	for _, path := range []string{"/data.cab", "/list?page=2"} {
+		if got, err := get(offline, path); err != nil || got != "input "+path {
+			t.Errorf("offline GET %s = %q, %v, want the archived input", path, got, err)
+		}
+	}
+	if _, err := get(offline, "/missing"); err == nil {
+		t.Error("offline GET of an input that wasn't archived succeeded")
+	}
+
+	if err := (httpConfig{Timeout: time.Second, ArchiveDir: dir, OfflineDir: dir}).validate(); err == nil {
+		t.Error("validate() accepted -archive with -offline")
+	}
+}
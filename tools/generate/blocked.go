@@ -0,0 +1,109 @@
+package generate
+
+import (
+	"encoding/csv"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Blocked and always ask certificates noted by generators during a run, by platform,
+// written to blocked.csv.
+var (
+	blockedMu sync.Mutex
+	blocked   = make(map[truststore.Platform][]truststore.BlockedCert)
+)
+
+// RecordBlocked notes the blocked and always ask certificates of a platform version, such
+// as those listed on Apple's trust store pages. Platforms recorded in a run replace their
+// certificates of the previous data.
+func RecordBlocked(platform truststore.Platform, version string, certs map[truststore.Fingerprint]string) {
+	blockedMu.Lock()
+	defer blockedMu.Unlock()
+	list := blocked[platform]
+	if list == nil {
+		list = []truststore.BlockedCert{} // Recorded even if empty, to clear previous data
+	}
+	for fp, status := range certs {
+		list = append(list, truststore.BlockedCert{Platform: platform, Version: version, Fingerprint: fp, Status: status})
+	}
+	blocked[platform] = list
+}
+
+// writeBlocked writes the blocked certificates of stores to blocked.csv, sorted by platform,
+// version and fingerprint, and returns how many were written. Platforms whose source did
+// not run keep their previous certificates. Nothing is written if no platform was recorded.
+func writeBlocked(stores []truststore.Store) (int, error) {
+	blockedMu.Lock()
+	defer blockedMu.Unlock()
+	if len(blocked) == 0 {
+		return 0, nil
+	}
+
+	path := filepath.Join(dataDir, "blocked.csv")
+	var certs []truststore.BlockedCert
+	f, err := os.Open(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return 0, err
+	default:
+		prev, err := truststore.ParseBlocked(f)
+		_ = f.Close()
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range prev {
+			if _, replaced := blocked[c.Platform]; !replaced {
+				certs = append(certs, c)
+			}
+		}
+	}
+	for _, list := range blocked {
+		certs = append(certs, list...)
+	}
+
+	// Only versions still in stores
+	exists := make(map[truststore.StoreKey]bool, len(stores))
+	for _, s := range stores {
+		exists[truststore.StoreKey{Platform: s.Platform, Version: s.Version}] = true
+	}
+	var kept []truststore.BlockedCert
+	for _, c := range certs {
+		if exists[truststore.StoreKey{Platform: c.Platform, Version: c.Version}] {
+			kept = append(kept, c)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Platform != kept[j].Platform {
+			return kept[i].Platform < kept[j].Platform
+		}
+		if kept[i].Version != kept[j].Version {
+			return kept[i].Version < kept[j].Version
+		}
+		return kept[i].Fingerprint.String() < kept[j].Fingerprint.String()
+	})
+
+	out, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = out.Close() }()
+
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"platform", "version", "fingerprint", "status"}); err != nil {
+		return 0, err
+	}
+	for _, c := range kept {
+		if err := w.Write([]string{string(c.Platform), c.Version, c.Fingerprint.String(), c.Status}); err != nil {
+			return 0, err
+		}
+	}
+	w.Flush()
+	return len(kept), w.Error()
+}
@@ -2,6 +2,7 @@
 package generate
 
 import (
+	"bytes"
 	"crypto/x509"
 	"encoding/csv"
 	"encoding/pem"
@@ -9,13 +10,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/ivoronin/certvet/internal/truststore"
 )
 
 const ccadbBaseURL = "https://ccadb.my.salesforce-sites.com/ccadb/AllCertificatePEMsCSVFormat"
 
+// ccadbRootStatusURL is CCADB's report of all certificate records, including the
+// inclusion status of each root in each root program.
+const ccadbRootStatusURL = "https://ccadb.my.salesforce-sites.com/ccadb/AllCertificateRecordsCSVFormatv2"
+
 // ccadbDecades lists all decades to fetch certificates from.
 var ccadbDecades = []string{"1990", "2000", "2010", "2020"}
 
@@ -32,6 +40,25 @@ func (CCADBGenerator) Generate() ([]Certificate, error) {
 		return nil, err
 	}
 
+	// Root statuses, owners and EV policies are advisory: without them the previous ones are kept
+	records, err := FetchCCADBRootRecords()
+	if err != nil {
+		Log.Warn("CCADB root records unavailable", "err", err)
+	} else {
+		RecordRootStatuses(records.Statuses)
+		RecordCAOwners(records.Owners)
+		for program, policies := range records.EVPolicies {
+			RecordEVPolicies(program, policies)
+		}
+	}
+
+	// Preloaded intermediates are optional: without them the previous ones are kept
+	if fps, err := FetchMozillaPreloaded(); err != nil {
+		Log.Warn("Mozilla preloaded intermediates unavailable", "err", err)
+	} else {
+		RecordPreloaded(fps)
+	}
+
 	return filterValidCerts(certs), nil
 }
 
@@ -41,11 +68,11 @@ func filterValidCerts(certs []CCADBCert) []Certificate {
 	for _, cert := range certs {
 		block, _ := pem.Decode([]byte(cert.PEM))
 		if block == nil {
-			Log.Warn("skipping cert %s: failed to decode PEM", cert.Fingerprint.Truncate(4))
+			Log.Skip("certificate unparsable", "fingerprint", cert.Fingerprint.Truncate(4), "err", "failed to decode PEM")
 			continue
 		}
 		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
-			Log.Warn("skipping cert %s: %v", cert.Fingerprint.Truncate(4), err)
+			Log.Skip("certificate unparsable", "fingerprint", cert.Fingerprint.Truncate(4), "err", err)
 			continue
 		}
 		valid = append(valid, Certificate(cert))
@@ -137,3 +164,219 @@ func ParseCCADBCSV(r io.Reader) ([]CCADBCert, error) {
 
 	return certs, nil
 }
+
+// Root records noted by the CCADB generator during a run, written to rootstatus.csv and
+// cainfo.csv. Nil if none were fetched.
+var (
+	rootRecordMu sync.Mutex
+	rootStatuses []truststore.RootStatus
+	caOwners     map[truststore.Fingerprint]string
+)
+
+// RecordRootStatuses notes the root inclusion statuses fetched in this run.
+func RecordRootStatuses(statuses []truststore.RootStatus) {
+	rootRecordMu.Lock()
+	defer rootRecordMu.Unlock()
+	rootStatuses = statuses
+}
+
+// recordedRootStatuses returns the root statuses noted in this run, nil if none.
+func recordedRootStatuses() []truststore.RootStatus {
+	rootRecordMu.Lock()
+	defer rootRecordMu.Unlock()
+	return rootStatuses
+}
+
+// RecordCAOwners notes the CA owners of roots fetched in this run.
+func RecordCAOwners(owners map[truststore.Fingerprint]string) {
+	rootRecordMu.Lock()
+	defer rootRecordMu.Unlock()
+	caOwners = owners
+}
+
+// recordedCAOwners returns the CA owners noted in this run, nil if none.
+func recordedCAOwners() map[truststore.Fingerprint]string {
+	rootRecordMu.Lock()
+	defer rootRecordMu.Unlock()
+	return caOwners
+}
+
+// CCADBRootRecords holds what the generator takes from CCADB's root certificate records.
+type CCADBRootRecords struct {
+	Statuses   []truststore.RootStatus
+	Owners     map[truststore.Fingerprint]string
+	EVPolicies map[string]map[truststore.Fingerprint][]string // By program, for programs the report covers
+}
+
+// FetchCCADBRootRecords downloads CCADB's certificate records and parses the root program
+// inclusion statuses, CA owners and EV policy OIDs of roots from them.
+func FetchCCADBRootRecords() (*CCADBRootRecords, error) {
+	resp, err := httpClient.Get(ccadbRootStatusURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch CCADB certificate records: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CCADB certificate records returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read CCADB certificate records: %w", err)
+	}
+
+	var records CCADBRootRecords
+	if records.Statuses, err = ParseCCADBRootStatuses(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if records.Owners, err = ParseCCADBRootOwners(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if records.EVPolicies, err = ParseCCADBEVPolicies(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return &records, nil
+}
+
+// ccadbPrograms maps CCADB root program names to Program* constants.
+var ccadbPrograms = map[string]string{
+	"apple":         truststore.ProgramApple,
+	"google chrome": truststore.ProgramChrome,
+	"microsoft":     truststore.ProgramMicrosoft,
+	"mozilla":       truststore.ProgramMozilla,
+}
+
+// ccadbStatuses maps CCADB root inclusion statuses to Root* constants.
+var ccadbStatuses = map[string]string{
+	"included":          truststore.RootIncluded,
+	"pending inclusion": truststore.RootPendingInclusion,
+	"not yet included":  truststore.RootPendingInclusion,
+	"pending removal":   truststore.RootPendingRemoval,
+	"removed":           truststore.RootRemoved,
+}
+
+// ParseCCADBRootStatuses parses root inclusion statuses from CCADB's certificate records
+// CSV. The status column lists "Program: Status" pairs separated by semicolons. Programs
+// and statuses certvet doesn't know are skipped.
+func ParseCCADBRootStatuses(r io.Reader) ([]truststore.RootStatus, error) {
+	var statuses []truststore.RootStatus
+	err := readCCADBRootRecords(r, []string{"Status of Root Cert"}, func(fingerprint truststore.Fingerprint, field func(string) (string, bool)) {
+		value, _ := field("Status of Root Cert")
+		for _, pair := range strings.Split(value, ";") {
+			program, status, found := strings.Cut(pair, ":")
+			if !found {
+				continue
+			}
+			program, ok := ccadbPrograms[strings.ToLower(strings.TrimSpace(program))]
+			if !ok {
+				continue
+			}
+			status, ok = ccadbStatuses[strings.ToLower(strings.TrimSpace(status))]
+			if !ok {
+				continue
+			}
+			statuses = append(statuses, truststore.RootStatus{Fingerprint: fingerprint, Program: program, Status: status})
+		}
+	})
+	return statuses, err
+}
+
+// ParseCCADBRootOwners parses the CA owner of each root from CCADB's certificate records CSV.
+func ParseCCADBRootOwners(r io.Reader) (map[truststore.Fingerprint]string, error) {
+	owners := make(map[truststore.Fingerprint]string)
+	err := readCCADBRootRecords(r, []string{"CA Owner"}, func(fingerprint truststore.Fingerprint, field func(string) (string, bool)) {
+		value, _ := field("CA Owner")
+		if owner := strings.TrimSpace(value); owner != "" {
+			owners[fingerprint] = owner
+		}
+	})
+	return owners, err
+}
+
+// ccadbEVColumns maps root programs to the CCADB column listing the policy OIDs for which
+// they enabled a root for EV. Chrome's come from its own root store.
+var ccadbEVColumns = map[string]string{
+	truststore.ProgramApple:     "Apple EV Policy OID(s)",
+	truststore.ProgramMicrosoft: "Microsoft EV Policy OID(s)",
+	truststore.ProgramMozilla:   "Mozilla EV Policy OID(s)",
+}
+
+// policyOIDPattern matches a dotted policy OID.
+var policyOIDPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+// ParseCCADBEVPolicies parses the EV-enabled policy OIDs of roots per program from CCADB's
+// certificate records CSV. Only programs whose column the report has are returned, so the
+// EV policies of other programs are kept; OID lists may be separated by semicolons, commas
+// or whitespace, and entries that aren't OIDs (such as "Not EV") are skipped.
+func ParseCCADBEVPolicies(r io.Reader) (map[string]map[truststore.Fingerprint][]string, error) {
+	policies := make(map[string]map[truststore.Fingerprint][]string)
+	err := readCCADBRootRecords(r, nil, func(fingerprint truststore.Fingerprint, field func(string) (string, bool)) {
+		for program, column := range ccadbEVColumns {
+			value, ok := field(column)
+			if !ok {
+				continue
+			}
+			if policies[program] == nil {
+				policies[program] = make(map[truststore.Fingerprint][]string)
+			}
+			for _, oid := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' || unicode.IsSpace(r) }) {
+				if policyOIDPattern.MatchString(oid) {
+					policies[program][fingerprint] = append(policies[program][fingerprint], oid)
+				}
+			}
+		}
+	})
+	return policies, err
+}
+
+// readCCADBRootRecords calls visit with the fingerprint and a field lookup by column name
+// (false for columns the report lacks) for every root certificate record in CCADB's
+// certificate records CSV. Columns are located by header; required ones must be present.
+func readCCADBRootRecords(r io.Reader, required []string, visit func(truststore.Fingerprint, func(string) (string, bool))) error {
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.TrimSpace(name)] = i
+	}
+	for _, name := range append([]string{"SHA-256 Fingerprint", "Certificate Record Type"}, required...) {
+		if _, ok := cols[name]; !ok {
+			return fmt.Errorf("missing %s column", name)
+		}
+	}
+	fpCol, typeCol := cols["SHA-256 Fingerprint"], cols["Certificate Record Type"]
+	reader.FieldsPerRecord = len(header)
+
+	lineNum := 1 // Header was line 1
+	for {
+		lineNum++
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: read record: %w", lineNum, err)
+		}
+		if record[typeCol] != "Root Certificate" {
+			continue
+		}
+
+		fingerprint, err := truststore.ParseFingerprint(record[fpCol])
+		if err != nil {
+			return fmt.Errorf("line %d: invalid fingerprint: %w", lineNum, err)
+		}
+		visit(fingerprint, func(column string) (string, bool) {
+			if i, ok := cols[column]; ok {
+				return record[i], true
+			}
+			return "", false
+		})
+	}
+
+	return nil
+}
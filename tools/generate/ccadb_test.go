@@ -2,6 +2,8 @@ package generate
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ivoronin/certvet/internal/truststore"
@@ -105,3 +107,83 @@ func TestFilterValidCertsSkipsMalformed(t *testing.T) {
 		t.Errorf("got %d valid certs, want 0 for malformed input", len(valid))
 	}
 }
+
+func TestParseCCADBRootStatuses(t *testing.T) {
+	t.Parallel()
+
+	root := strings.Repeat("AB", 32)
+	csv := "\"CA Owner\",\"SHA-256 Fingerprint\",\"Certificate Record Type\",\"Status of Root Cert\"\n" +
+		"Example,\"" + root + "\",\"Root Certificate\",\"Apple: Included; Google Chrome: Not Yet Included; Microsoft: Pending Removal; Mozilla: Removed; Other: Included\"\n" +
+		"Example,\"" + strings.Repeat("CD", 32) + "\",\"Intermediate Certificate\",\"Microsoft: Pending Removal\"\n"
+	statuses, err := ParseCCADBRootStatuses(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fp, err := truststore.ParseFingerprint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []truststore.RootStatus{
+		{Fingerprint: fp, Program: truststore.ProgramApple, Status: truststore.RootIncluded},
+		{Fingerprint: fp, Program: truststore.ProgramChrome, Status: truststore.RootPendingInclusion},
+		{Fingerprint: fp, Program: truststore.ProgramMicrosoft, Status: truststore.RootPendingRemoval},
+		{Fingerprint: fp, Program: truststore.ProgramMozilla, Status: truststore.RootRemoved},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("ParseCCADBRootStatuses() = %+v, want %+v", statuses, want)
+	}
+
+	if _, err := ParseCCADBRootStatuses(strings.NewReader("\"SHA-256 Fingerprint\"\n")); err == nil {
+		t.Error("ParseCCADBRootStatuses() accepted a report without status columns")
+	}
+}
+
+func TestParseCCADBRootOwners(t *testing.T) {
+	t.Parallel()
+
+	root := strings.Repeat("AB", 32)
+	csv := "\"CA Owner\",\"SHA-256 Fingerprint\",\"Certificate Record Type\"\n" +
+		"\"Government of Spain, ACCV\",\"" + root + "\",\"Root Certificate\"\n" +
+		"\"Intermediate Owner\",\"" + strings.Repeat("CD", 32) + "\",\"Intermediate Certificate\"\n"
+	owners, err := ParseCCADBRootOwners(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fp, err := truststore.ParseFingerprint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 1 || owners[fp] != "Government of Spain, ACCV" {
+		t.Errorf("ParseCCADBRootOwners() = %v", owners)
+	}
+}
+
+func TestParseCCADBEVPolicies(t *testing.T) {
+	t.Parallel()
+
+	root := strings.Repeat("AB", 32)
+	csv := "\"SHA-256 Fingerprint\",\"Certificate Record Type\",\"Microsoft EV Policy OID(s)\",\"Mozilla EV Policy OID(s)\"\n" +
+		"\"" + root + "\",\"Root Certificate\",\"2.23.140.1.1; 1.3.6.1.4.1.34697.2.1\",\"Not EV\"\n" +
+		"\"" + strings.Repeat("CD", 32) + "\",\"Intermediate Certificate\",\"2.23.140.1.1\",\"\"\n"
+	policies, err := ParseCCADBEVPolicies(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fp, err := truststore.ParseFingerprint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := policies[truststore.ProgramMicrosoft][fp]; !reflect.DeepEqual(got, []string{"2.23.140.1.1", "1.3.6.1.4.1.34697.2.1"}) {
+		t.Errorf("microsoft EV OIDs = %v", got)
+	}
+	// Mozilla's column is present without OIDs; Apple's is missing, keeping previous data
+	if mozilla, ok := policies[truststore.ProgramMozilla]; !ok || len(mozilla) != 0 {
+		t.Errorf("mozilla EV policies = %v, %v; want recorded and empty", mozilla, ok)
+	}
+	if _, ok := policies[truststore.ProgramApple]; ok {
+		t.Error("apple EV policies recorded without an Apple column")
+	}
+}
@@ -39,20 +39,52 @@ func (ChromeGenerator) Generate() ([]TrustEntry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("fetching Chrome Root Store: %w", err)
 	}
-	_, anchors, err := ParseChromeTextproto(protoContent, textprotoContent)
+	major, anchors, err := ParseChromeTextproto(protoContent, textprotoContent)
 	if err != nil {
 		return nil, fmt.Errorf("parsing: %w", err)
 	}
+	RecordSourceVersion("chrome", strconv.Itoa(major))
 
+	evPolicies := make(map[truststore.Fingerprint][]string)
+	for _, anchor := range anchors {
+		if len(anchor.EVPolicyOIDs) > 0 {
+			evPolicies[anchor.Fingerprint] = anchor.EVPolicyOIDs
+		}
+	}
+	RecordEVPolicies(truststore.ProgramChrome, evPolicies)
+
+	// Released milestones get the root store their release branch shipped; versions only
+	// derived from constraint boundaries are kept for upcoming releases
+	released := FetchChromeReleaseStores(protoContent)
+	latest := 0
+	for milestone := range released {
+		latest = max(latest, milestone)
+	}
+
+	var entries []TrustEntry
+	for milestone, branchAnchors := range released {
+		entries = append(entries, chromeEntries(branchAnchors, []string{strconv.Itoa(milestone)})...)
+	}
+	var upcoming []string
+	for _, v := range SynthesizeVersions(anchors) {
+		if n, err := strconv.Atoi(v); err != nil || n > latest {
+			upcoming = append(upcoming, v)
+		}
+	}
+	entries = append(entries, chromeEntries(anchors, upcoming)...)
+
+	return entries, nil
+}
+
+// chromeEntries returns the trust entries of anchors in each of versions, with constraints
+// evaluated at generation time.
+func chromeEntries(anchors []ChromeTrustAnchor, versions []string) []TrustEntry {
 	// Build fingerprint -> anchor map for looking up SCT constraints
 	anchorByFP := make(map[truststore.Fingerprint]ChromeTrustAnchor, len(anchors))
 	for _, anchor := range anchors {
 		anchorByFP[anchor.Fingerprint] = anchor
 	}
 
-	// Synthesize versions from constraint boundaries
-	versions := SynthesizeVersions(anchors)
-
 	// Generate version-mapped fingerprints (constraints evaluated at generation time)
 	versionMap := generateVersionMappedFingerprints(anchors, versions)
 
@@ -75,7 +107,7 @@ func (ChromeGenerator) Generate() ([]TrustEntry, error) {
 		}
 	}
 
-	return entries, nil
+	return entries
 }
 
 // ChromeTrustAnchor represents a parsed trust anchor from the Chrome Root Store.
@@ -214,7 +246,7 @@ func ParseChromeTextproto(protoContent, textprotoContent []byte) (int, []ChromeT
 		sha256HexField := taDesc.Fields().ByName("sha256_hex")
 		sha256Hex := ta.Get(sha256HexField).String()
 		if sha256Hex == "" {
-			Log.Warn("skipping trust anchor %d: no SHA256 fingerprint", i)
+			Log.Skip("trust anchor without SHA-256 fingerprint", "index", i)
 			continue
 		}
 
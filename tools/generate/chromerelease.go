@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ChromeMilestonesURL lists Chrome milestones with their release branches.
+const ChromeMilestonesURL = "https://chromiumdash.appspot.com/fetch_milestones?only_branched=true"
+
+// chromeBranchRootStoreURL is the Chrome Root Store textproto as shipped by a release branch.
+const chromeBranchRootStoreURL = "https://chromium.googlesource.com/chromium/src/+/refs/branch-heads/%s/net/data/ssl/chrome_root_store/root_store.textproto?format=TEXT"
+
+// chromeFirstRootStoreMilestone is the first milestone that shipped the Chrome Root Store.
+const chromeFirstRootStoreMilestone = 105
+
+// ChromeRelease is a Chrome milestone and the branch it was released from.
+type ChromeRelease struct {
+	Milestone int
+	Branch    string // Chromium branch number, e.g. "6533"
+}
+
+// ParseChromeMilestones parses the milestone list of Chromium Dash, skipping milestones
+// before the Chrome Root Store or without a branch. Releases are sorted by milestone.
+func ParseChromeMilestones(data []byte) ([]ChromeRelease, error) {
+	var milestones []struct {
+		Milestone int    `json:"milestone"`
+		Branch    string `json:"chromium_branch"`
+	}
+	if err := json.Unmarshal(data, &milestones); err != nil {
+		return nil, fmt.Errorf("parse milestones: %w", err)
+	}
+
+	var releases []ChromeRelease
+	for _, m := range milestones {
+		if m.Milestone >= chromeFirstRootStoreMilestone && m.Branch != "" {
+			releases = append(releases, ChromeRelease{Milestone: m.Milestone, Branch: m.Branch})
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Milestone < releases[j].Milestone })
+	return releases, nil
+}
+
+// FetchChromeReleaseStores returns the trust anchors each released milestone shipped, read
+// from its release branch with the proto schema protoContent. Milestones whose root store
+// can't be fetched or parsed are skipped with a warning; without the milestone list none
+// are returned, and versions are only derived from constraints.
+func FetchChromeReleaseStores(protoContent []byte) map[int][]ChromeTrustAnchor {
+	data, err := FetchURL(ChromeMilestonesURL)
+	if err != nil {
+		Log.Warn("Chrome release history unavailable", "err", err)
+		return nil
+	}
+	releases, err := ParseChromeMilestones(data)
+	if err != nil {
+		Log.Warn("Chrome release history unavailable", "err", err)
+		return nil
+	}
+
+	stores := make(map[int][]ChromeTrustAnchor, len(releases))
+	for _, r := range releases {
+		anchors, err := fetchChromeBranchStore(protoContent, r.Branch)
+		if err != nil {
+			Log.Skip("Chrome release store failed", "milestone", r.Milestone, "err", err)
+			continue
+		}
+		stores[r.Milestone] = anchors
+	}
+	return stores
+}
+
+// fetchChromeBranchStore fetches and parses the root store of a release branch.
+func fetchChromeBranchStore(protoContent []byte, branch string) ([]ChromeTrustAnchor, error) {
+	data, err := FetchURL(fmt.Sprintf(chromeBranchRootStoreURL, branch))
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64: %w", err)
+	}
+	_, anchors, err := ParseChromeTextproto(protoContent, decoded)
+	if err != nil {
+		return nil, fmt.Errorf("branch %s: %w", branch, err)
+	}
+	return anchors, nil
+}
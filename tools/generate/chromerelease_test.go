@@ -0,0 +1,29 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChromeMilestones(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`[
+		{"milestone": 131, "chromium_branch": "6778", "schedule_phase": "beta"},
+		{"milestone": 130, "chromium_branch": "6723"},
+		{"milestone": 104, "chromium_branch": "5112"},
+		{"milestone": 132}
+	]`)
+	releases, err := ParseChromeMilestones(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ChromeRelease{{Milestone: 130, Branch: "6723"}, {Milestone: 131, Branch: "6778"}}
+	if !reflect.DeepEqual(releases, want) {
+		t.Errorf("ParseChromeMilestones() = %+v, want %+v (pre-root store and unbranched milestones skipped)", releases, want)
+	}
+
+	if _, err := ParseChromeMilestones([]byte("<html>")); err == nil {
+		t.Error("ParseChromeMilestones() accepted a non-JSON response")
+	}
+}
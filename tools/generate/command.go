@@ -0,0 +1,748 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/changelog"
+	"github.com/ivoronin/certvet/internal/output"
+	"github.com/ivoronin/certvet/internal/truststore"
+	"github.com/ivoronin/certvet/internal/version"
+)
+
+const dataDir = "trustdata/data"
+
+// Main runs the generate command with args (without the program name) and returns its
+// exit code. It regenerates the CSV data files in trustdata/data with the registered
+// generators, or those selected with -stores and -certs. When only some store generators
+// run, stores of the platforms they did not produce are kept from the previous data.
+// -only regenerates just the listed sources (generators, or ctlogs) and keeps the data of
+// all others, including certificates and CT logs. -dry-run stops before writing data files,
+// after running the sanity checks of the embedded data on the generated data.
+// -archive saves the raw inputs of a run, and -offline regenerates from such an archive.
+//
+// Private stores can be generated without forking: a main package that imports a package
+// registering its generators (see RegisterStoreGenerator) and calls Main builds a generate
+// command including them.
+func Main(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	storeNames := flags.String("stores", "", "Comma-separated store `generators` to run (default all: "+strings.Join(StoreGeneratorNames(), ", ")+")")
+	certNames := flags.String("certs", "", "Comma-separated certificate `generators` to run (default all: "+strings.Join(CertGeneratorNames(), ", ")+")")
+	only := flags.String("only", "", "Comma-separated `sources` to regenerate (generators, or "+ctLogsSource+"), keeping the data of all others")
+	cfg := defaultHTTPConfig
+	flags.StringVar(&cfg.CacheDir, "http-cache", defaultHTTPCacheDir(), "Cache source downloads in `dir` and re-download only changed ones (empty disables)")
+	flags.IntVar(&cfg.Retries, "retries", cfg.Retries, "Retries of a failed download (connection errors, 429 and 5xx responses)")
+	flags.DurationVar(&cfg.RetryWait, "retry-wait", cfg.RetryWait, "Wait before the first retry, doubled for each further one")
+	flags.DurationVar(&cfg.RetryMaxWait, "retry-max-wait", cfg.RetryMaxWait, "Longest wait between retries")
+	flags.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "Timeout of each download attempt")
+	flags.DurationVar(&cfg.RequestInterval, "request-interval", cfg.RequestInterval, "Minimum time between requests to the same host (0 disables)")
+	flags.IntVar(&cfg.HostConcurrency, "host-concurrency", cfg.HostConcurrency, "Most requests in flight to the same host (0 = unlimited)")
+	flags.StringVar(&cfg.UserAgent, "user-agent", cfg.UserAgent, "User-Agent `header` of downloads")
+	flags.StringVar(&cfg.ArchiveDir, "archive", "", "Save the raw inputs of this run to `dir` for offline regeneration")
+	flags.StringVar(&cfg.OfflineDir, "offline", "", "Read raw inputs from archive `dir` (see -archive) instead of the network")
+	caBundle := flags.String("ca-bundle", "", "Also trust the CA certificates in PEM `file` for downloads, e.g. of a TLS-intercepting proxy")
+	dryRun := flags.Bool("dry-run", false, "Fetch, parse and sanity-check the data without writing data files")
+	diffFile := flags.String("diff", "", "Also write the report of store changes to `file`")
+	sourceTimeoutSpec := flags.String("source-timeout", "", "Comma-separated per-source download timeouts overriding -timeout (e.g., ccadb=5m,apple=2m)")
+	logLevel := flags.String("log-level", "info", "Lowest `level` of log records written to stderr: debug, info, warn or error")
+	logFormat := flags.String("log-format", "text", "Log record `format`: text or json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	level, err := ParseLogLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -log-level: %v\n", err)
+		return 2
+	}
+	if *logFormat != "text" && *logFormat != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid -log-format: %q (want text or json)\n", *logFormat)
+		return 2
+	}
+	Log = NewLogger(os.Stderr, level, *logFormat == "json")
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *caBundle != "" {
+		pool, err := loadCABundle(*caBundle)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -ca-bundle: %v\n", err)
+			return 2
+		}
+		cfg.RootCAs = pool
+	}
+	cfg.limiter = newHostLimiter(cfg.RequestInterval, cfg.HostConcurrency)
+	sources := append(append(StoreGeneratorNames(), CertGeneratorNames()...), ctLogsSource)
+	sourceTimeouts, err := parseSourceTimeouts(*sourceTimeoutSpec, sources)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -source-timeout: %v\n", err)
+		return 2
+	}
+	// useSource configures downloads for the named source
+	useSource := func(name string) {
+		c := cfg
+		c.Source = name
+		if d, ok := sourceTimeouts[name]; ok {
+			c.Timeout = d
+		}
+		httpClient = newHTTPClient(c)
+	}
+
+	var (
+		storeGenerators []namedGenerator[StoreGenerator]
+		certGenerators  []namedGenerator[CertGenerator]
+		fetchCTLogs     = true
+	)
+	if *only != "" {
+		if *storeNames != "" || *certNames != "" {
+			fmt.Fprintln(os.Stderr, "Error: invalid -only: cannot be combined with -stores or -certs")
+			return 2
+		}
+		storeGenerators, certGenerators, fetchCTLogs, err = selectOnly(*only)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -only: %v\n", err)
+			return 2
+		}
+	} else {
+		if storeGenerators, err = selectStoreGenerators(*storeNames); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if certGenerators, err = selectCertGenerators(*certNames); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	// Ensure data directory exists
+	if err := os.MkdirAll(dataDir, 0755); err != nil { //nolint:gosec // G301: 0755 is standard for data directories
+		Log.Error("create data directory failed", "dir", dataDir, "err", err)
+		return 1
+	}
+
+	// Snapshot previous data before it's overwritten, to record what changed
+	prev, err := readSnapshot()
+	if err != nil {
+		Log.Error("read previous data snapshot failed", "err", err)
+		return 1
+	}
+
+	var failed bool
+
+	// Collect all trust entries from vendor generators first
+	// (we need fingerprints to filter certificates)
+	var allEntries []TrustEntry
+
+	for _, g := range storeGenerators {
+		done := Log.StartSource(g.name)
+		useSource(g.name)
+		entries, err := g.generator.Generate()
+		done(len(entries), err != nil)
+		if err != nil {
+			Log.Error("generate trust stores failed", "source", g.name, "err", err)
+			failed = true
+			continue
+		}
+
+		allEntries = append(allEntries, entries...)
+		Log.Info("generated trust stores", "source", g.name, "entries", len(entries))
+	}
+
+	// A partial run keeps the platforms it didn't regenerate
+	if *storeNames != "" || *only != "" {
+		kept := keptEntries(prev.stores, allEntries)
+		allEntries = append(allEntries, kept...)
+		Log.Info("kept entries of other platforms", "entries", len(kept))
+	}
+
+	// Build set of needed fingerprints
+	neededFPs := make(map[string]bool)
+	for _, e := range allEntries {
+		neededFPs[e.Fingerprint.String()] = true
+	}
+	Log.Info("collected store roots", "fingerprints", len(neededFPs))
+
+	// Generate certificates (filtered to only needed ones)
+	var allCerts []Certificate
+	certsFailed := false
+	for _, g := range certGenerators {
+		done := Log.StartSource(g.name)
+		useSource(g.name)
+		certs, err := g.generator.Generate()
+		done(len(certs), err != nil)
+		if err != nil {
+			Log.Error("generate certificates failed", "source", g.name, "err", err)
+			failed, certsFailed = true, true
+			continue
+		}
+		allCerts = append(allCerts, certs...)
+		Log.Info("generated certificates", "source", g.name, "certificates", len(certs))
+	}
+
+	// Report store changes before the data files are overwritten
+	stores := buildStores(allEntries)
+	changes := changelog.Diff(prev.stores, stores, rootNames(prev, allCerts))
+	report := output.NewChangelogOutput(&changelog.Changelog{
+		Snapshots: []changelog.Snapshot{{Date: time.Now().UTC().Format(truststore.DateFormat), Changes: changes}},
+	}).FormatText()
+	fmt.Printf("Store changes:\n%s\n", report)
+	if *diffFile != "" {
+		if err := os.WriteFile(*diffFile, []byte(report+"\n"), 0644); err != nil { //nolint:gosec // G306: the report is as readable as the data it describes
+			Log.Error("write failed", "file", *diffFile, "err", err)
+			failed = true
+		}
+	}
+
+	// Certificates of the roots in stores
+	var certs []Certificate
+	switch {
+	case certsFailed:
+	case len(certGenerators) == 0:
+		// Without certificate sources, the roots still needed keep their certificates
+		var missing int
+		if certs, missing = keptCertificates(prev, allEntries); missing > 0 {
+			Log.Error("new roots have no certificate, regenerate certificates too", "roots", missing)
+			failed, certsFailed = true, true
+		}
+	default:
+		// Filter to only certificates referenced in stores
+		for _, c := range allCerts {
+			if neededFPs[c.Fingerprint.String()] {
+				certs = append(certs, c)
+			}
+		}
+	}
+
+	// CT log list for SCT log state checks
+	var ctLogs []CTLogEntry
+	writeCTLogs := fetchCTLogs
+	if fetchCTLogs {
+		done := Log.StartSource(ctLogsSource)
+		useSource(ctLogsSource)
+		ctLogs, err = FetchCTLogs()
+		done(len(ctLogs), err != nil)
+		if err != nil {
+			Log.Error("generate CT logs failed", "source", ctLogsSource, "err", err)
+			failed, writeCTLogs = true, false
+		}
+	}
+
+	if *dryRun {
+		if !certsFailed && !checkGenerated(stores, certs) {
+			failed = true
+		}
+		Log.Info("dry run: no data files written")
+		printSummary()
+		if failed {
+			return 1
+		}
+		return 0
+	}
+
+	if !certsFailed {
+		if err := writeCertificatesCSV("certificates.csv", certs); err != nil {
+			Log.Error("write failed", "file", "certificates.csv", "err", err)
+			failed = true
+		} else if len(certGenerators) == 0 {
+			Log.Info("wrote data file", "file", "certificates.csv", "certificates", len(certs), "kept", true)
+		} else {
+			Log.Info("wrote data file", "file", "certificates.csv", "certificates", len(certs), "fetched", len(allCerts))
+		}
+	}
+	if !certsFailed && len(certGenerators) > 0 {
+		// Cross-signed intermediates enable alternate chain suggestions
+		crossSigns := FindCrossSigns(allCerts, certs)
+		if err := writeCertificatesCSV("intermediates.csv", crossSigns); err != nil {
+			Log.Error("write failed", "file", "intermediates.csv", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "intermediates.csv", "cross_signs", len(crossSigns))
+		}
+	}
+	if fps := recordedPreloaded(); !certsFailed && fps != nil {
+		// Intermediates clients hold without the server sending them
+		found := FindPreloaded(allCerts, fps)
+		if err := writeCertificatesCSV("preloaded.csv", found); err != nil {
+			Log.Error("write failed", "file", "preloaded.csv", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "preloaded.csv", "intermediates", len(found), "preloaded", len(fps))
+		}
+	}
+	if statuses := recordedRootStatuses(); !certsFailed && statuses != nil {
+		// Root program statuses of the roots in stores, for pending removal advisories
+		n, err := writeRootStatusesCSV(statuses, neededFPs)
+		if err != nil {
+			Log.Error("write failed", "file", "rootstatus.csv", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "rootstatus.csv", "statuses", n)
+		}
+	}
+	if owners := recordedCAOwners(); !certsFailed && owners != nil {
+		// Operators and certificate basics of the roots in stores, for listing and search
+		n, operated, err := writeCAInfosCSV(certs, owners)
+		if err != nil {
+			Log.Error("write failed", "file", "cainfo.csv", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "cainfo.csv", "cas", n, "with_operator", operated)
+		}
+	}
+
+	if writeCTLogs {
+		if err := writeCTLogsCSV(ctLogs); err != nil {
+			Log.Error("write failed", "file", "ctlogs.csv", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "ctlogs.csv", "logs", len(ctLogs))
+		}
+	}
+
+	// Write all trust entries to stores.csv
+	if err := writeStoresCSV(stores); err != nil {
+		Log.Error("write failed", "file", "stores.csv", "err", err)
+		failed = true
+	} else {
+		Log.Info("wrote data file", "file", "stores.csv", "entries", len(allEntries))
+	}
+
+	// EV policy OIDs of the roots in stores, from the root programs that ran
+	if n, err := writeEVPolicies(neededFPs); err != nil {
+		Log.Error("write failed", "file", "evpolicies.csv", "err", err)
+		failed = true
+	} else if n > 0 {
+		Log.Info("wrote data file", "file", "evpolicies.csv", "policies", n)
+	}
+
+	// Apple's blocked and always ask certificates of the versions in stores
+	if n, err := writeBlocked(stores); err != nil {
+		Log.Error("write failed", "file", "blocked.csv", "err", err)
+		failed = true
+	} else if n > 0 {
+		Log.Info("wrote data file", "file", "blocked.csv", "certificates", n)
+	}
+
+	if !failed {
+		if err := updateChangelog(changes); err != nil {
+			Log.Error("write failed", "file", "changelog.json", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "changelog.json", "changes", len(changes))
+		}
+	}
+
+	generated := time.Now().UTC().Truncate(time.Second)
+	if !failed {
+		info, err := writeSources(generated)
+		if err != nil {
+			Log.Error("write failed", "file", "sources.json", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "sources.json", "versions", len(info.Versions))
+		}
+	}
+
+	if !failed {
+		n, err := writeProvenance(generated)
+		if err != nil {
+			Log.Error("write failed", "file", "provenance.json", "err", err)
+			failed = true
+		} else {
+			Log.Info("wrote data file", "file", "provenance.json", "sources", n)
+		}
+	}
+
+	printSummary()
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// printSummary prints the per-source summary of the run, so warnings and skipped items
+// are noticed rather than scrolling by.
+func printSummary() {
+	fmt.Println("Summary:")
+	_ = writeSummary(os.Stdout, Log.Summary())
+}
+
+// keptEntries returns the entries of previous stores whose platform has no generated entry.
+func keptEntries(prev []truststore.Store, generated []TrustEntry) []TrustEntry {
+	regenerated := make(map[string]bool)
+	for _, e := range generated {
+		regenerated[e.Platform] = true
+	}
+
+	var kept []TrustEntry
+	for _, s := range prev {
+		if regenerated[string(s.Platform)] {
+			continue
+		}
+		for _, fp := range s.Fingerprints {
+			c := s.ConstraintFor(fp)
+			kept = append(kept, TrustEntry{
+				Platform:     string(s.Platform),
+				Version:      s.Version,
+				Fingerprint:  fp,
+				NotBeforeMax: c.NotBeforeMax,
+				DistrustDate: c.DistrustDate,
+				SCTNotAfter:  c.SCTNotAfter,
+			})
+		}
+	}
+	return kept
+}
+
+// keptCertificates returns the previous certificates of the roots in entries, and the
+// number of new roots, not in any previous store, without one. Known roots may lack a
+// certificate: not every root is published by a certificate source.
+func keptCertificates(prev *snapshot, entries []TrustEntry) ([]Certificate, int) {
+	known := truststore.NewStoreIndex(prev.stores)
+	seen := make(map[truststore.Fingerprint]bool)
+	var certs []Certificate
+	missing := 0
+	for _, e := range entries {
+		if seen[e.Fingerprint] {
+			continue
+		}
+		seen[e.Fingerprint] = true
+		cert := prev.certs.Get(e.Fingerprint)
+		if cert == nil {
+			if !known.Contains(e.Fingerprint) {
+				missing++
+			}
+			continue
+		}
+		block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		certs = append(certs, Certificate{Fingerprint: e.Fingerprint, PEM: strings.TrimSuffix(string(block), "\n")})
+	}
+	return certs, missing
+}
+
+// writeCertificatesCSV writes certificates to name in the data directory
+// Format: fingerprint,pem
+// Sorted by: fingerprint (ascending)
+func writeCertificatesCSV(name string, certs []Certificate) error {
+	// Sort by fingerprint ascending
+	// Use SliceStable for consistency with writeStoresCSV
+	sort.SliceStable(certs, func(i, j int) bool {
+		return certs[i].Fingerprint.String() < certs[j].Fingerprint.String()
+	})
+
+	path := filepath.Join(dataDir, name)
+	f, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return err
+	}
+	if err := encodeCertificates(f, certs); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeCertificates writes certificates as CSV in the format of certificates.csv.
+func encodeCertificates(out io.Writer, certs []Certificate) error {
+	w := csv.NewWriter(out)
+
+	// Write header
+	if err := w.Write([]string{"fingerprint", "pem"}); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, cert := range certs {
+		// Escape newlines so each record is a single line
+		escapedPEM := strings.ReplaceAll(cert.PEM, "\n", "\\n")
+		if err := w.Write([]string{cert.Fingerprint.String(), escapedPEM}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// checkGenerated runs the sanity checks of the embedded data (truststore.CheckQuality) on
+// generated stores and certificates, reporting problems. Returns whether there were none.
+func checkGenerated(stores []truststore.Store, certs []Certificate) bool {
+	var buf bytes.Buffer
+	if err := encodeCertificates(&buf, certs); err != nil {
+		Log.Error("encode certificates failed", "err", err)
+		return false
+	}
+	idx, err := truststore.NewCertIndex(buf.Bytes())
+	if err != nil {
+		Log.Error("index certificates failed", "err", err)
+		return false
+	}
+
+	problems := truststore.CheckQuality(stores, idx)
+	for _, p := range problems {
+		Log.Error("sanity check failed", "problem", p)
+	}
+	if len(problems) > 0 {
+		return false
+	}
+	Log.Info("sanity checks passed", "stores", len(stores), "certificates", len(certs))
+	return true
+}
+
+// writeCTLogsCSV writes CT logs to ctlogs.csv
+// Format: log_id,operator,description,state,state_since,mmd
+// Sorted by: log_id (ascending, as returned by ParseCTLogList)
+func writeCTLogsCSV(logs []CTLogEntry) error {
+	path := filepath.Join(dataDir, "ctlogs.csv")
+	f, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	// Write header
+	if err := w.Write([]string{"log_id", "operator", "description", "state", "state_since", "mmd"}); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, l := range logs {
+		if err := w.Write([]string{l.LogID, l.Operator, l.Description, l.State, formatTime(l.StateSince), mmdString(l.MMD)}); err != nil {
+			return err
+		}
+	}
+
+	return w.Error()
+}
+
+// writeRootStatusesCSV writes the statuses of needed roots to rootstatus.csv, sorted by
+// fingerprint and program, and returns how many were written.
+func writeRootStatusesCSV(statuses []truststore.RootStatus, needed map[string]bool) (int, error) {
+	var kept []truststore.RootStatus
+	for _, s := range statuses {
+		if needed[s.Fingerprint.String()] {
+			kept = append(kept, s)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if fi, fj := kept[i].Fingerprint.String(), kept[j].Fingerprint.String(); fi != fj {
+			return fi < fj
+		}
+		return kept[i].Program < kept[j].Program
+	})
+
+	path := filepath.Join(dataDir, "rootstatus.csv")
+	f, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if err := w.Write([]string{"fingerprint", "program", "status"}); err != nil {
+		return 0, err
+	}
+	for _, s := range kept {
+		if err := w.Write([]string{s.Fingerprint.String(), s.Program, s.Status}); err != nil {
+			return 0, err
+		}
+	}
+
+	w.Flush()
+	return len(kept), w.Error()
+}
+
+// writeCAInfosCSV writes the CA info of certs, operated by owners, to cainfo.csv sorted by
+// fingerprint, and returns how many were written and how many of those have an operator.
+func writeCAInfosCSV(certs []Certificate, owners map[truststore.Fingerprint]string) (n, operated int, err error) {
+	var infos []truststore.CAInfo
+	for _, c := range certs {
+		if cert := parsePEM(c.PEM); cert != nil {
+			infos = append(infos, truststore.NewCAInfo(cert, owners[c.Fingerprint]))
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Fingerprint.String() < infos[j].Fingerprint.String() })
+
+	path := filepath.Join(dataDir, "cainfo.csv")
+	f, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if err := w.Write([]string{"fingerprint", "operator", "country", "key_type", "key_size", "not_before", "not_after"}); err != nil {
+		return 0, 0, err
+	}
+	for _, i := range infos {
+		if i.Operator != "" {
+			operated++
+		}
+		record := []string{i.Fingerprint.String(), i.Operator, i.Country, i.KeyType, strconv.Itoa(i.KeySize), i.NotBefore.Format(time.RFC3339), i.NotAfter.Format(time.RFC3339)}
+		if err := w.Write(record); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	w.Flush()
+	return len(infos), operated, w.Error()
+}
+
+// mmdString formats a maximum merge delay in seconds, empty if unknown
+func mmdString(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return strconv.Itoa(seconds)
+}
+
+// buildStores groups trust entries into stores.
+// Sorted by: platform (asc), version (semver asc)
+func buildStores(entries []TrustEntry) []truststore.Store {
+	// Sort entries: platform asc, version semver asc
+	// Use SliceStable to ensure deterministic output when versions are semantically equal
+	// (e.g., "11" and "11.0" both parse to semver 11.0.0)
+	sort.SliceStable(entries, func(i, j int) bool {
+		// Compare platform first
+		if entries[i].Platform != entries[j].Platform {
+			return entries[i].Platform < entries[j].Platform
+		}
+		// Compare version using centralized version comparison
+		return entries[i].Version != entries[j].Version && version.LessThan(entries[i].Version, entries[j].Version)
+	})
+
+	// Group consecutive entries into stores; WriteStores sorts fingerprints
+	var stores []truststore.Store
+	for _, entry := range entries {
+		n := len(stores)
+		if n == 0 || string(stores[n-1].Platform) != entry.Platform || stores[n-1].Version != entry.Version {
+			stores = append(stores, truststore.Store{Platform: truststore.Platform(entry.Platform), Version: entry.Version})
+			n++
+		}
+		s := &stores[n-1]
+		s.Fingerprints = append(s.Fingerprints, entry.Fingerprint)
+		if entry.HasConstraints() {
+			if s.Constraints == nil {
+				s.Constraints = make(map[truststore.Fingerprint]truststore.Constraints)
+			}
+			s.Constraints[entry.Fingerprint] = truststore.Constraints{
+				NotBeforeMax: entry.NotBeforeMax,
+				DistrustDate: entry.DistrustDate,
+				SCTNotAfter:  entry.SCTNotAfter,
+			}
+		}
+	}
+	return stores
+}
+
+// writeStoresCSV writes stores to stores.csv, each platform's first version in full
+// and later versions as changes to the previous one (see truststore.ParseStores).
+// Sorted by: platform (asc), version (semver asc), fingerprint (asc)
+func writeStoresCSV(stores []truststore.Store) error {
+	path := filepath.Join(dataDir, "stores.csv")
+	f, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return err
+	}
+	if err := truststore.WriteStores(f, stores); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// formatTime converts a time pointer to RFC3339 string or empty if nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// snapshot is a data snapshot as read back from the data directory.
+type snapshot struct {
+	stores []truststore.Store
+	certs  *truststore.CertIndex
+}
+
+// readSnapshot reads stores.csv and certificates.csv from the data directory.
+func readSnapshot() (*snapshot, error) {
+	storesData, err := os.ReadFile(filepath.Join(dataDir, "stores.csv")) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return nil, err
+	}
+	stores, err := truststore.ParseStores(bytes.NewReader(storesData))
+	if err != nil {
+		return nil, fmt.Errorf("parse stores.csv: %w", err)
+	}
+
+	certsData, err := os.ReadFile(filepath.Join(dataDir, "certificates.csv")) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return nil, err
+	}
+	certs, err := truststore.NewCertIndex(certsData)
+	if err != nil {
+		return nil, fmt.Errorf("parse certificates.csv: %w", err)
+	}
+
+	return &snapshot{stores: stores, certs: certs}, nil
+}
+
+// rootNames resolves root display names from the generated certificates, falling back to
+// the previous ones for removed roots.
+func rootNames(prev *snapshot, certs []Certificate) func(truststore.Fingerprint) string {
+	pems := make(map[truststore.Fingerprint]string, len(certs))
+	for _, c := range certs {
+		pems[c.Fingerprint] = c.PEM
+	}
+	return func(fp truststore.Fingerprint) string {
+		if block, _ := pem.Decode([]byte(pems[fp])); block != nil {
+			if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+				return truststore.CertName(cert)
+			}
+		}
+		if cert := prev.certs.Get(fp); cert != nil {
+			return truststore.CertName(cert)
+		}
+		return ""
+	}
+}
+
+// updateChangelog prepends changes, dated today, to changelog.json.
+func updateChangelog(changes []changelog.Change) error {
+	path := filepath.Join(dataDir, "changelog.json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return err
+	}
+	cl, err := changelog.Parse(data)
+	if err != nil {
+		return err
+	}
+	cl.Add(time.Now().UTC().Format(truststore.DateFormat), changes)
+
+	out, err := json.MarshalIndent(cl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644) //nolint:gosec // G306: data files are world-readable like other generated CSVs
+}
@@ -0,0 +1,45 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+)
+
+// FindCrossSigns returns CA certificates from all that cross-sign one of the roots:
+// they carry a root's subject and public key but are issued by a different CA.
+// Serving such a certificate lets clients that lack the root chain to its issuer instead.
+func FindCrossSigns(all, roots []Certificate) []Certificate {
+	type identity struct{ subject, spki string }
+	rootIDs := make(map[identity]bool)
+	for _, c := range roots {
+		if cert := parsePEM(c.PEM); cert != nil {
+			rootIDs[identity{string(cert.RawSubject), string(cert.RawSubjectPublicKeyInfo)}] = true
+		}
+	}
+
+	var crossSigns []Certificate
+	for _, c := range all {
+		cert := parsePEM(c.PEM)
+		if cert == nil || !cert.IsCA || bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+			continue
+		}
+		if rootIDs[identity{string(cert.RawSubject), string(cert.RawSubjectPublicKeyInfo)}] {
+			crossSigns = append(crossSigns, c)
+		}
+	}
+	return crossSigns
+}
+
+// parsePEM decodes a single PEM certificate, returning nil if invalid.
+func parsePEM(data string) *x509.Certificate {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestFindCrossSigns(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	create := func(name string, key *ecdsa.PrivateKey, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey) (*x509.Certificate, Certificate) {
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		if issuer == nil {
+			issuer, issuerKey = tmpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, issuerKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pemData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		return cert, Certificate{Fingerprint: truststore.Fingerprint(sha256.Sum256(der)), PEM: pemData}
+	}
+
+	oldKey, newRootKey, otherKey := newKey(), newKey(), newKey()
+	oldRoot, oldRootEntry := create("Old Root", oldKey, nil, nil)
+	_, newRootEntry := create("New Root", newRootKey, nil, nil)
+	_, crossEntry := create("New Root", newRootKey, oldRoot, oldKey)   // New Root cross-signed by Old Root
+	_, unrelatedEntry := create("Other CA", otherKey, oldRoot, oldKey) // Ordinary intermediate
+
+	all := []Certificate{oldRootEntry, newRootEntry, crossEntry, unrelatedEntry}
+	got := FindCrossSigns(all, []Certificate{oldRootEntry, newRootEntry})
+
+	if len(got) != 1 || got[0].Fingerprint != crossEntry.Fingerprint {
+		t.Errorf("FindCrossSigns() = %d certs, want only the New Root cross-sign", len(got))
+	}
+}
@@ -0,0 +1,102 @@
+package generate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CTLogListURL is Google's Certificate Transparency log list (v3 schema).
+const CTLogListURL = "https://www.gstatic.com/ct/log_list/v3/log_list.json"
+
+// ctLogsSource names the CT log list download in -source-timeout.
+const ctLogsSource = "ctlogs"
+
+// CTLogEntry is a CT log with its current state, as written to ctlogs.csv.
+type CTLogEntry struct {
+	LogID       string     // Base64 SHA-256 of the log's public key
+	Operator    string     // Log operator name
+	Description string     // Log description
+	State       string     // usable, qualified, readonly, retired, pending or rejected
+	StateSince  *time.Time // When the log entered State (nil if unknown)
+	MMD         int        // Maximum merge delay in seconds
+}
+
+// ctLogList mirrors the parts of the v3 log list schema certvet uses.
+type ctLogList struct {
+	Version   string `json:"version"`
+	Operators []struct {
+		Name      string    `json:"name"`
+		Logs      []ctLogV3 `json:"logs"`
+		TiledLogs []ctLogV3 `json:"tiled_logs"`
+	} `json:"operators"`
+}
+
+type ctLogV3 struct {
+	Description string                `json:"description"`
+	LogID       string                `json:"log_id"`
+	MMD         int                   `json:"mmd"`
+	State       map[string]ctLogState `json:"state"` // Single key: the current state
+}
+
+type ctLogState struct {
+	Timestamp *time.Time `json:"timestamp"`
+}
+
+// FetchCTLogs downloads and parses Google's CT log list.
+func FetchCTLogs() ([]CTLogEntry, error) {
+	data, err := FetchURL(CTLogListURL)
+	if err != nil {
+		return nil, err
+	}
+	entries, listVersion, err := parseCTLogList(data)
+	if err != nil {
+		return nil, err
+	}
+	if listVersion != "" {
+		RecordSourceVersion("ctlogs", listVersion)
+	}
+	return entries, nil
+}
+
+// ParseCTLogList parses a v3 log list into entries sorted by log ID.
+// RFC 6962 and static (tiled) logs are both included; logs without a state are skipped.
+func ParseCTLogList(data []byte) ([]CTLogEntry, error) {
+	entries, _, err := parseCTLogList(data)
+	return entries, err
+}
+
+// parseCTLogList is ParseCTLogList also returning the log list version.
+func parseCTLogList(data []byte) ([]CTLogEntry, string, error) {
+	var list ctLogList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, "", fmt.Errorf("parse log list: %w", err)
+	}
+
+	var entries []CTLogEntry
+	for _, op := range list.Operators {
+		for _, l := range append(op.Logs, op.TiledLogs...) {
+			id, err := base64.StdEncoding.DecodeString(l.LogID)
+			if err != nil || len(id) != 32 {
+				Log.Skip("CT log with invalid log_id", "log", l.Description)
+				continue
+			}
+			for state, info := range l.State {
+				entries = append(entries, CTLogEntry{
+					LogID:       l.LogID,
+					Operator:    op.Name,
+					Description: l.Description,
+					State:       state,
+					StateSince:  info.Timestamp,
+					MMD:         l.MMD,
+				})
+				break // A log is in exactly one state
+			}
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].LogID < entries[j].LogID })
+	return entries, list.Version, nil
+}
@@ -0,0 +1,47 @@
+package generate
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseCTLogList(t *testing.T) {
+	data, err := os.ReadFile("testdata/log_list.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, listVersion, err := parseCTLogList(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listVersion != "51.12" {
+		t.Errorf("log list version = %q, want 51.12", listVersion)
+	}
+
+	// The log with an invalid ID is skipped; tiled logs are included
+	if len(entries) != 3 {
+		t.Fatalf("ParseCTLogList() returned %d entries, want 3", len(entries))
+	}
+
+	byDescription := make(map[string]CTLogEntry)
+	for i, e := range entries {
+		if i > 0 && entries[i-1].LogID > e.LogID {
+			t.Errorf("entries not sorted by log ID at %d", i)
+		}
+		byDescription[e.Description] = e
+	}
+
+	retired := byDescription["Google 'Argon2023' log"]
+	if retired.State != "retired" || retired.Operator != "Google" || retired.StateSince == nil ||
+		retired.StateSince.Format("2006-01-02") != "2024-02-01" || retired.MMD != 86400 {
+		t.Errorf("retired log = %+v", retired)
+	}
+	if tiled := byDescription["Let's Encrypt 'Willow2026h1'"]; tiled.State != "qualified" || tiled.MMD != 60 {
+		t.Errorf("tiled log = %+v", tiled)
+	}
+
+	if _, err := ParseCTLogList([]byte("{")); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+}
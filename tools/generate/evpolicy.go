@@ -0,0 +1,100 @@
+package generate
+
+import (
+	"encoding/csv"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// EV policy OIDs noted by generators during a run, by program, written to evpolicies.csv.
+var (
+	evPolicyMu sync.Mutex
+	evPolicies = make(map[string]map[truststore.Fingerprint][]string)
+)
+
+// RecordEVPolicies notes the EV-enabled policy OIDs of roots in program's root store, such
+// as Chrome's ev_policy_oids, replacing those of program recorded before.
+func RecordEVPolicies(program string, policies map[truststore.Fingerprint][]string) {
+	evPolicyMu.Lock()
+	defer evPolicyMu.Unlock()
+	evPolicies[program] = policies
+}
+
+// writeEVPolicies writes the EV policies of needed roots to evpolicies.csv, sorted by
+// fingerprint, program and OID, and returns how many were written. Programs recorded in
+// this run replace those of the previous data; programs whose source did not run keep
+// their previous policies. Nothing is written if no program was recorded.
+func writeEVPolicies(needed map[string]bool) (int, error) {
+	evPolicyMu.Lock()
+	defer evPolicyMu.Unlock()
+	if len(evPolicies) == 0 {
+		return 0, nil
+	}
+
+	path := filepath.Join(dataDir, "evpolicies.csv")
+	var policies []truststore.EVPolicy
+	f, err := os.Open(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return 0, err
+	default:
+		prev, err := truststore.ParseEVPolicies(f)
+		_ = f.Close()
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range prev {
+			if _, replaced := evPolicies[p.Program]; !replaced {
+				policies = append(policies, p)
+			}
+		}
+	}
+	for program, byRoot := range evPolicies {
+		for fp, oids := range byRoot {
+			for _, oid := range oids {
+				policies = append(policies, truststore.EVPolicy{Fingerprint: fp, Program: program, OID: oid})
+			}
+		}
+	}
+
+	var kept []truststore.EVPolicy
+	for _, p := range policies {
+		if needed[p.Fingerprint.String()] {
+			kept = append(kept, p)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if fi, fj := kept[i].Fingerprint.String(), kept[j].Fingerprint.String(); fi != fj {
+			return fi < fj
+		}
+		if kept[i].Program != kept[j].Program {
+			return kept[i].Program < kept[j].Program
+		}
+		return kept[i].OID < kept[j].OID
+	})
+
+	out, err := os.Create(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = out.Close() }()
+
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"fingerprint", "program", "oid"}); err != nil {
+		return 0, err
+	}
+	for _, p := range kept {
+		if err := w.Write([]string{p.Fingerprint.String(), p.Program, p.OID}); err != nil {
+			return 0, err
+		}
+	}
+	w.Flush()
+	return len(kept), w.Error()
+}
@@ -1,6 +1,13 @@
 // Package generate provides trust store generation tools.
 package generate
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // CertGenerator generates certificate data (CCADB).
 type CertGenerator interface {
 	Name() string
@@ -12,3 +19,147 @@ type StoreGenerator interface {
 	Name() string
 	Generate() ([]TrustEntry, error)
 }
+
+// Generators are registered by name; Main runs them in registration order.
+var (
+	registryMu      sync.Mutex
+	storeGenerators []namedGenerator[StoreGenerator]
+	certGenerators  []namedGenerator[CertGenerator]
+)
+
+type namedGenerator[G any] struct {
+	name      string
+	generator G
+}
+
+func init() {
+	RegisterStoreGenerator("apple", AppleGenerator{})
+	RegisterStoreGenerator("android", AndroidGenerator{})
+	RegisterStoreGenerator("chrome", ChromeGenerator{})
+	RegisterStoreGenerator("windows", WindowsGenerator{})
+	RegisterStoreGenerator("wincontainer", WindowsContainerGenerator{})
+	RegisterCertGenerator("ccadb", CCADBGenerator{})
+}
+
+// RegisterStoreGenerator makes a store generator available to Main under name, the value
+// selecting it with -stores. Packages with private or vendor stores call it from init.
+// It panics if name is already registered.
+func RegisterStoreGenerator(name string, g StoreGenerator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	storeGenerators = register(storeGenerators, "store", name, g)
+}
+
+// RegisterCertGenerator makes a certificate generator available to Main under name, the
+// value selecting it with -certs. Certificates of all generators run are combined.
+// It panics if name is already registered.
+func RegisterCertGenerator(name string, g CertGenerator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	certGenerators = register(certGenerators, "certificate", name, g)
+}
+
+func register[G any](registered []namedGenerator[G], kind, name string, g G) []namedGenerator[G] {
+	for _, r := range registered {
+		if r.name == name {
+			panic(fmt.Sprintf("generate: %s generator %q registered twice", kind, name))
+		}
+	}
+	return append(registered, namedGenerator[G]{name, g})
+}
+
+// StoreGeneratorNames returns the names of registered store generators, sorted.
+func StoreGeneratorNames() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return names(storeGenerators)
+}
+
+// CertGeneratorNames returns the names of registered certificate generators, sorted.
+func CertGeneratorNames() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return names(certGenerators)
+}
+
+func names[G any](registered []namedGenerator[G]) []string {
+	out := make([]string, len(registered))
+	for i, r := range registered {
+		out[i] = r.name
+	}
+	sort.Strings(out)
+	return out
+}
+
+// selectStoreGenerators returns the store generators named in a comma-separated list,
+// with their names, in registration order, or all of them if the list is empty.
+func selectStoreGenerators(list string) ([]namedGenerator[StoreGenerator], error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return selectGenerators(storeGenerators, "store", list)
+}
+
+// selectCertGenerators is selectStoreGenerators for certificate generators.
+func selectCertGenerators(list string) ([]namedGenerator[CertGenerator], error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return selectGenerators(certGenerators, "certificate", list)
+}
+
+func selectGenerators[G any](registered []namedGenerator[G], kind, list string) ([]namedGenerator[G], error) {
+	want := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			want[name] = true
+		}
+	}
+
+	all := len(want) == 0
+	var out []namedGenerator[G]
+	for _, r := range registered {
+		if all || want[r.name] {
+			out = append(out, r)
+			delete(want, r.name)
+		}
+	}
+	for name := range want {
+		return nil, fmt.Errorf("unknown %s generator %q (known: %s)", kind, name, strings.Join(names(registered), ", "))
+	}
+	return out, nil
+}
+
+// selectOnly resolves a comma-separated list of sources to regenerate: store and
+// certificate generators, in registration order, and whether ctlogs is listed.
+func selectOnly(list string) (stores []namedGenerator[StoreGenerator], certs []namedGenerator[CertGenerator], ctLogs bool, err error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	want := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			want[name] = true
+		}
+	}
+	ctLogs = want[ctLogsSource]
+	delete(want, ctLogsSource)
+	for _, r := range storeGenerators {
+		if want[r.name] {
+			stores = append(stores, r)
+			delete(want, r.name)
+		}
+	}
+	for _, r := range certGenerators {
+		if want[r.name] {
+			certs = append(certs, r)
+			delete(want, r.name)
+		}
+	}
+	for name := range want {
+		known := append(append(names(storeGenerators), names(certGenerators)...), ctLogsSource)
+		return nil, nil, false, fmt.Errorf("unknown source %q (known: %s)", name, strings.Join(known, ", "))
+	}
+	if len(stores) == 0 && len(certs) == 0 && !ctLogs {
+		return nil, nil, false, fmt.Errorf("no sources listed")
+	}
+	return stores, certs, ctLogs, nil
+}
@@ -0,0 +1,171 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// fakeStoreGenerator is a registrable store generator returning fixed entries.
+type fakeStoreGenerator struct{ entries []TrustEntry }
+
+func (fakeStoreGenerator) Name() string                      { return "Fake" }
+func (g fakeStoreGenerator) Generate() ([]TrustEntry, error) { return g.entries, nil }
+
+func TestSelectStoreGenerators(t *testing.T) {
+	t.Parallel()
+
+	all, err := selectStoreGenerators("")
+	if err != nil || len(all) != len(StoreGeneratorNames()) {
+		t.Fatalf("selectStoreGenerators(\"\") = %d generators, %v; want all %d", len(all), err, len(StoreGeneratorNames()))
+	}
+
+	got, err := selectStoreGenerators("windows, apple")
+	if err != nil {
+		t.Fatalf("selectStoreGenerators() error = %v", err)
+	}
+	if len(got) != 2 || got[0].generator.Name() != "Apple" || got[1].name != "windows" {
+		t.Errorf("selectStoreGenerators(windows, apple) = %v, want Apple then Windows in registration order", got)
+	}
+
+	if _, err := selectStoreGenerators("apple,symbian"); err == nil || !strings.Contains(err.Error(), `"symbian"`) {
+		t.Errorf("unknown generator error = %v, want one naming symbian", err)
+	}
+
+	certs, err := selectCertGenerators("ccadb")
+	if err != nil || len(certs) != 1 || certs[0].generator.Name() != "CCADB" {
+		t.Errorf("selectCertGenerators(ccadb) = %v, %v; want CCADB", certs, err)
+	}
+}
+
+// TestRegisterStoreGenerator changes the registry and must not run in parallel.
+func TestRegisterStoreGenerator(t *testing.T) {
+	saved := storeGenerators
+	t.Cleanup(func() { storeGenerators = saved })
+
+	RegisterStoreGenerator("fleet", fakeStoreGenerator{})
+	got, err := selectStoreGenerators("fleet")
+	if err != nil || len(got) != 1 || got[0].generator.Name() != "Fake" {
+		t.Fatalf("selectStoreGenerators(fleet) = %v, %v; want the registered generator", got, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice should panic")
+		}
+	}()
+	RegisterStoreGenerator("apple", fakeStoreGenerator{})
+}
+
+func TestKeptEntries(t *testing.T) {
+	t.Parallel()
+
+	distrust := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fp1, fp2 := truststore.Fingerprint{0x01}, truststore.Fingerprint{0x02}
+	prev := []truststore.Store{
+		{Platform: "ios", Version: "18", Fingerprints: []truststore.Fingerprint{fp1}},
+		{Platform: "windows", Version: "current", Fingerprints: []truststore.Fingerprint{fp1, fp2},
+			Constraints: map[truststore.Fingerprint]truststore.Constraints{fp2: {DistrustDate: &distrust}}},
+	}
+	generated := []TrustEntry{{Platform: "ios", Version: "26", Fingerprint: fp2}}
+
+	kept := keptEntries(prev, generated)
+	if len(kept) != 2 {
+		t.Fatalf("kept %d entries, want the 2 windows entries: %+v", len(kept), kept)
+	}
+	for _, e := range kept {
+		if e.Platform != "windows" || e.Version != "current" {
+			t.Errorf("kept %s %s, want only windows current", e.Platform, e.Version)
+		}
+	}
+	if kept[1].Fingerprint != fp2 || kept[1].DistrustDate == nil || !kept[1].DistrustDate.Equal(distrust) {
+		t.Errorf("kept[1] = %+v, want fp2 with its distrust date", kept[1])
+	}
+}
+
+func TestBuildStores(t *testing.T) {
+	t.Parallel()
+
+	distrust := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fp1, fp2 := truststore.Fingerprint{0x01}, truststore.Fingerprint{0x02}
+	stores := buildStores([]TrustEntry{
+		{Platform: "ios", Version: "26", Fingerprint: fp2},
+		{Platform: "android", Version: "14", Fingerprint: fp1, DistrustDate: &distrust},
+		{Platform: "ios", Version: "18", Fingerprint: fp1},
+		{Platform: "ios", Version: "26", Fingerprint: fp1},
+	})
+
+	var got []string
+	for _, s := range stores {
+		got = append(got, fmt.Sprintf("%s %s %d", s.Platform, s.Version, len(s.Fingerprints)))
+	}
+	if want := "android 14 1, ios 18 1, ios 26 2"; strings.Join(got, ", ") != want {
+		t.Errorf("buildStores() = %s, want %s", strings.Join(got, ", "), want)
+	}
+	if c := stores[0].ConstraintFor(fp1); c.DistrustDate == nil || !c.DistrustDate.Equal(distrust) {
+		t.Errorf("android 14 constraint = %+v, want the distrust date", c)
+	}
+}
+
+func TestSelectOnly(t *testing.T) {
+	t.Parallel()
+
+	stores, certs, ctLogs, err := selectOnly(" chrome, ctlogs,apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 2 || stores[0].name != "apple" || stores[1].name != "chrome" || len(certs) != 0 || !ctLogs {
+		t.Errorf("selectOnly() = %v, %v, %v, want apple and chrome stores and CT logs", stores, certs, ctLogs)
+	}
+
+	if stores, certs, ctLogs, err := selectOnly("ccadb"); err != nil || len(stores) != 0 || len(certs) != 1 || ctLogs {
+		t.Errorf("selectOnly(ccadb) = %v, %v, %v, %v", stores, certs, ctLogs, err)
+	}
+	for _, list := range []string{"symbian", " , "} {
+		if _, _, _, err := selectOnly(list); err == nil {
+			t.Errorf("selectOnly(%q) succeeded", list)
+		}
+	}
+}
+
+func TestKeptCertificates(t *testing.T) {
+	t.Parallel()
+
+	prev := &snapshot{stores: truststore.Stores, certs: truststore.Certs}
+	entries := keptEntries(truststore.Stores, nil)
+	if certs, missing := keptCertificates(prev, entries); missing != 0 || len(certs) == 0 {
+		t.Errorf("keptCertificates(embedded) = %d certificates, %d missing, want known roots without certificates accepted", len(certs), missing)
+	}
+
+	fp := truststore.Stores[0].Fingerprints[0]
+	entries = []TrustEntry{
+		{Platform: "ios", Version: "18", Fingerprint: fp},
+		{Platform: "ios", Version: "26", Fingerprint: fp},
+		{Platform: "ios", Version: "26", Fingerprint: truststore.Fingerprint{0x01}},
+	}
+	certs, missing := keptCertificates(prev, entries)
+	if len(certs) != 1 || missing != 1 {
+		t.Fatalf("keptCertificates() = %d certificates, %d missing, want 1 and the new root", len(certs), missing)
+	}
+	if certs[0].Fingerprint != fp || !strings.HasPrefix(certs[0].PEM, "-----BEGIN CERTIFICATE-----") || strings.HasSuffix(certs[0].PEM, "\n") {
+		t.Errorf("certs[0] = %+v, want the PEM of the previous certificate", certs[0])
+	}
+}
+
+func TestCheckGenerated(t *testing.T) {
+	t.Parallel()
+
+	entries := keptEntries(truststore.Stores, nil)
+	certs, _ := keptCertificates(&snapshot{stores: truststore.Stores, certs: truststore.Certs}, entries)
+	if !checkGenerated(buildStores(entries), certs) {
+		t.Error("checkGenerated() rejected the embedded data")
+	}
+
+	// Roots of the dropped stores become orphans, and platforms go missing
+	if checkGenerated(buildStores(entries[:1]), certs) {
+		t.Error("checkGenerated() accepted a single store")
+	}
+}
@@ -1,32 +1,160 @@
 package generate
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 )
 
-// httpTimeout is the standard timeout for all HTTP requests.
-const httpTimeout = time.Minute
+// httpConfig configures the HTTP client of generators.
+type httpConfig struct {
+	Retries      int            // Retries of a failed request (connection errors, 429 and 5xx responses)
+	RetryWait    time.Duration  // Wait before the first retry, doubled for each further one
+	RetryMaxWait time.Duration  // Longest wait between retries
+	Timeout      time.Duration  // Timeout of each attempt
+	CacheDir     string         // Response cache directory (empty disables, see httpCache)
+	RootCAs      *x509.CertPool // CAs trusted for HTTPS (nil = system roots), e.g. of an intercepting proxy
+	Source       string         // Source whose inputs are recorded for provenance.json (empty records none)
+	ArchiveDir   string         // Directory raw inputs are saved to (empty disables, see inputArchive)
+	OfflineDir   string         // Directory raw inputs are read from instead of the network (empty = online)
+
+	RequestInterval time.Duration // Minimum time between requests to the same host (0 = none)
+	HostConcurrency int           // Most requests in flight to the same host (0 = unlimited)
+	UserAgent       string        // User-Agent header of requests
+
+	limiter *hostLimiter // Shared by the clients of a run (nil = one per client)
+}
+
+// defaultHTTPConfig is the configuration used unless changed with Main's flags.
+var defaultHTTPConfig = httpConfig{
+	Retries:      3,
+	RetryWait:    5 * time.Second,
+	RetryMaxWait: 30 * time.Second,
+	Timeout:      time.Minute,
+
+	RequestInterval: 500 * time.Millisecond,
+	HostConcurrency: 2,
+	UserAgent:       defaultUserAgent,
+}
+
+// validate checks settings given on the command line.
+func (c httpConfig) validate() error {
+	switch {
+	case c.Retries < 0:
+		return fmt.Errorf("invalid -retries: must not be negative")
+	case c.RetryWait < 0:
+		return fmt.Errorf("invalid -retry-wait: must not be negative")
+	case c.RetryMaxWait < c.RetryWait:
+		return fmt.Errorf("invalid -retry-max-wait: must be at least -retry-wait")
+	case c.Timeout <= 0:
+		return fmt.Errorf("invalid -timeout: must be positive")
+	case c.RequestInterval < 0:
+		return fmt.Errorf("invalid -request-interval: must not be negative")
+	case c.HostConcurrency < 0:
+		return fmt.Errorf("invalid -host-concurrency: must not be negative")
+	case c.ArchiveDir != "" && c.OfflineDir != "":
+		return fmt.Errorf("invalid -archive: cannot be combined with -offline")
+	}
+	return nil
+}
+
+// newHTTPClient creates an HTTP client retrying transient failures with exponential
+// backoff (honoring Retry-After) as configured by cfg. Requests carry cfg.UserAgent, are
+// paced per host (see hostLimiter) and go through the proxy set by HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY. With cfg.OfflineDir, requests are served from that archive instead.
+func newHTTPClient(cfg httpConfig) *http.Client {
+	if cfg.OfflineDir != "" {
+		client := &http.Client{Transport: inputArchive{Dir: cfg.OfflineDir}}
+		if cfg.Source != "" {
+			client.Transport = &provenanceRecorder{Source: cfg.Source, Next: client.Transport}
+		}
+		return client
+	}
 
-// newHTTPClient creates a standard HTTP client with retry logic for transient failures.
-func newHTTPClient() *http.Client {
 	rc := retryablehttp.NewClient()
-	rc.RetryMax = 3
-	rc.RetryWaitMin = 5 * time.Second
-	rc.RetryWaitMax = 30 * time.Second
+	rc.RetryMax = cfg.Retries
+	rc.RetryWaitMin = cfg.RetryWait
+	rc.RetryWaitMax = cfg.RetryMaxWait
+	rc.Backoff = retryablehttp.DefaultBackoff
 	rc.Logger = nil // suppress default logging
-	rc.HTTPClient.Timeout = httpTimeout
+	rc.HTTPClient.Timeout = cfg.Timeout
+	if t, ok := rc.HTTPClient.Transport.(*http.Transport); ok {
+		t.Proxy = http.ProxyFromEnvironment
+		if cfg.RootCAs != nil {
+			t.TLSClientConfig = &tls.Config{RootCAs: cfg.RootCAs, MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	client := rc.StandardClient()
+	limiter := cfg.limiter
+	if limiter == nil {
+		limiter = newHostLimiter(cfg.RequestInterval, cfg.HostConcurrency)
+	}
+	client.Transport = &politeTransport{Limiter: limiter, UserAgent: cfg.UserAgent, Next: client.Transport}
+	if cfg.CacheDir != "" {
+		client.Transport = &httpCache{Dir: cfg.CacheDir, Next: client.Transport}
+	}
+	if cfg.ArchiveDir != "" {
+		client.Transport = &archiveRecorder{Archive: inputArchive{Dir: cfg.ArchiveDir}, Next: client.Transport}
+	}
+	if cfg.Source != "" {
+		client.Transport = &provenanceRecorder{Source: cfg.Source, Next: client.Transport}
+	}
+	return client
+}
+
+// httpClient is the shared HTTP client of generators. Main replaces it before running
+// each source with one configured for the source.
+var httpClient = newHTTPClient(defaultHTTPConfig)
 
-	return rc.StandardClient()
+// loadCABundle returns the system roots plus the PEM certificates in path.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is given on the command line
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s: no PEM certificates", path)
+	}
+	return pool, nil
 }
 
-// httpClient is the shared HTTP client with retry logic and the standard timeout.
-var httpClient = newHTTPClient()
+// parseSourceTimeouts parses a comma-separated list of source=duration pairs, such as
+// "ccadb=5m,apple=2m". Sources must be one of known.
+func parseSourceTimeouts(spec string, known []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid source timeout %q (expected source=duration)", pair)
+		}
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown source %q (known: %s)", name, strings.Join(known, ", "))
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout for %s: %q", name, value)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
+}
 
 // FetchURL fetches a URL and returns the response body.
 // Returns an error if the request fails or returns a non-200 status.
@@ -48,14 +176,3 @@ func FetchURL(url string) ([]byte, error) {
 
 	return data, nil
 }
-
-// Logger provides simple logging for generators.
-type Logger struct{}
-
-// Log is the package-level logger instance used by all generators.
-var Log = &Logger{}
-
-// Warn prints a warning message to stderr.
-func (l *Logger) Warn(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "WARNING: "+format+"\n", args...)
-}
@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientRetries(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	cfg := httpConfig{Retries: 2, RetryWait: time.Millisecond, RetryMaxWait: 4 * time.Millisecond, Timeout: time.Second}
+	resp, err := newHTTPClient(cfg).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts.Load() != 3 {
+		t.Errorf("status %d after %d attempts, want 200 after 3", resp.StatusCode, attempts.Load())
+	}
+
+	attempts.Store(0)
+	cfg.Retries = 1
+	if resp, err := newHTTPClient(cfg).Get(srv.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Errorf("got status %d with 1 retry, want an error after 2 attempts", resp.StatusCode)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("%d attempts with 1 retry, want 2", attempts.Load())
+	}
+}
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cfg := httpConfig{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if resp, err := newHTTPClient(cfg).Get(srv.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("request to a hanging server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v, want it to time out after 50ms", elapsed)
+	}
+}
+
+func TestNewHTTPClientCABundle(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	cfg := httpConfig{Timeout: 5 * time.Second}
+	if resp, err := newHTTPClient(cfg).Get(srv.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("server with an untrusted certificate was accepted")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "proxy-ca.pem")
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	if cfg.RootCAs, err = loadCABundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newHTTPClient(cfg).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET with the CA bundle: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCABundle(empty); err == nil {
+		t.Error("loadCABundle accepted a file without certificates")
+	}
+}
+
+func TestParseSourceTimeouts(t *testing.T) {
+	t.Parallel()
+
+	known := []string{"apple", "ccadb", ctLogsSource}
+	got, err := parseSourceTimeouts(" ccadb=5m, apple = 90s ", known)
+	if err != nil || len(got) != 2 || got["ccadb"] != 5*time.Minute || got["apple"] != 90*time.Second {
+		t.Errorf("parseSourceTimeouts() = %v, %v", got, err)
+	}
+	if got, err := parseSourceTimeouts("", known); err != nil || len(got) != 0 {
+		t.Errorf("empty spec = %v, %v", got, err)
+	}
+
+	for spec, want := range map[string]string{
+		"symbian=1m": `unknown source "symbian"`,
+		"apple":      "expected source=duration",
+		"apple=soon": "invalid timeout for apple",
+		"apple=0s":   "invalid timeout for apple",
+	} {
+		if _, err := parseSourceTimeouts(spec, known); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseSourceTimeouts(%q) error = %v, want %q", spec, err, want)
+		}
+	}
+}
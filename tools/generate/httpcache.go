@@ -0,0 +1,154 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// httpCache is an http.RoundTripper that stores GET responses carrying an ETag or
+// Last-Modified validator on disk, keyed by URL, and revalidates them with conditional
+// requests, so regeneration only downloads sources that changed upstream.
+type httpCache struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// cachedResponse is the on-disk form of a response.
+type cachedResponse struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// defaultHTTPCacheDir returns the per-user directory source downloads are cached in
+// (empty if there is none).
+func defaultHTTPCacheDir() string {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cache, "certvet", "generate")
+}
+
+// path returns the cache file of url.
+func (c *httpCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// RoundTrip serves a cached response if the server reports it unchanged, and caches
+// new responses with validators. Other requests and responses pass through.
+func (c *httpCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.Next.RoundTrip(req)
+	}
+	url := req.URL.String()
+
+	cached := c.load(url)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_ = resp.Body.Close()
+		return cached.response(req), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	entry := &cachedResponse{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       resp.Header,
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return resp, nil // Nothing to revalidate with
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	entry.Body = body
+	c.save(entry) // A response that can't be cached is still returned
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// load returns the cached response of url, or nil if none can be read.
+func (c *httpCache) load(url string) *cachedResponse {
+	data, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return nil
+	}
+	var entry cachedResponse
+	if json.Unmarshal(data, &entry) != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// save caches entry, writing a temporary file and renaming it so readers never see a
+// partial entry. Errors are ignored: the response is fetched again next time.
+func (c *httpCache) save(entry *cachedResponse) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o750); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(c.Dir, "response-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(entry.URL))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// response rebuilds a 200 response to req from the cache.
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,70 @@
+package generate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHTTPCache(t *testing.T) {
+	t.Parallel()
+
+	var body atomic.Value
+	body.Store("v1")
+	var full, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + body.Load().(string) + `"`
+		if r.URL.Path == "/plain" {
+			_, _ = io.WriteString(w, "no validators")
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		_, _ = io.WriteString(w, body.Load().(string))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &httpCache{Dir: t.TempDir(), Next: http.DefaultTransport}}
+	get := func(path string) string {
+		t.Helper()
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: status %d", path, resp.StatusCode)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if got := get("/data"); got != "v1" {
+		t.Errorf("first GET = %q, want v1", got)
+	}
+	if got := get("/data"); got != "v1" || full.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("cached GET = %q after %d full and %d not-modified responses, want v1 revalidated once", got, full.Load(), notModified.Load())
+	}
+
+	body.Store("v2")
+	if got := get("/data"); got != "v2" || full.Load() != 2 {
+		t.Errorf("GET after change = %q after %d full responses, want v2 downloaded again", got, full.Load())
+	}
+	if got := get("/data"); got != "v2" || notModified.Load() != 2 {
+		t.Errorf("GET of new version = %q, want v2 from the cache", got)
+	}
+
+	if got := get("/plain"); got != "no validators" {
+		t.Errorf("GET without validators = %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Logger is the leveled, structured logger of a generate run. Records carry the source
+// being generated, and warnings and skipped items are counted per source for the summary
+// printed at the end of the run. It is safe for concurrent use.
+type Logger struct {
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	source string
+	stats  map[string]*SourceStats
+	order  []string // Sources in the order they started
+}
+
+// SourceStats summarizes what one source produced in a run.
+type SourceStats struct {
+	Source   string
+	Entries  int // Trust entries, certificates or CT logs generated
+	Warnings int // Problems that did not leave anything out
+	Skipped  int // Items left out of the data, such as a version page that failed to parse
+	Duration time.Duration
+	Failed   bool
+}
+
+// Log is the package-level logger used by all generators. Main replaces it according to
+// its -log-level and -log-format flags.
+var Log = NewLogger(os.Stderr, slog.LevelInfo, false)
+
+// NewLogger returns a logger writing records at level and above to w, as JSON lines or
+// as logfmt-style text.
+func NewLogger(w io.Writer, level slog.Level, json bool) *Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if json {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return &Logger{logger: slog.New(handler), stats: make(map[string]*SourceStats)}
+}
+
+// ParseLogLevel parses a -log-level value: debug, info, warn or error.
+func ParseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil || strings.ContainsAny(s, "+-") {
+		return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+// Debug logs details only needed to investigate a run.
+func (l *Logger) Debug(msg string, args ...any) { l.log(slog.LevelDebug, msg, args) }
+
+// Info logs the progress of a run.
+func (l *Logger) Info(msg string, args ...any) { l.log(slog.LevelInfo, msg, args) }
+
+// Warn logs a problem that did not leave anything out of the data, such as optional
+// upstream data being unavailable, and counts it for the current source.
+func (l *Logger) Warn(msg string, args ...any) {
+	l.count(func(s *SourceStats) { s.Warnings++ })
+	l.log(slog.LevelWarn, msg, args)
+}
+
+// Skip logs an item left out of the data, such as a certificate that failed to parse, and
+// counts it for the current source.
+func (l *Logger) Skip(msg string, args ...any) {
+	l.count(func(s *SourceStats) { s.Skipped++ })
+	l.log(slog.LevelWarn, msg, append(args, "skipped", true))
+}
+
+// Error logs a failure of the run.
+func (l *Logger) Error(msg string, args ...any) { l.log(slog.LevelError, msg, args) }
+
+func (l *Logger) log(level slog.Level, msg string, args []any) {
+	l.mu.Lock()
+	source := l.source
+	l.mu.Unlock()
+	if source != "" {
+		args = append([]any{"source", source}, args...)
+	}
+	l.logger.Log(context.Background(), level, msg, args...)
+}
+
+// count applies fn to the stats of the current source, if any.
+func (l *Logger) count(fn func(*SourceStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if s := l.stats[l.source]; s != nil {
+		fn(s)
+	}
+}
+
+// StartSource attributes records to the named source until the returned function is
+// called with how many entries it generated and whether it failed.
+func (l *Logger) StartSource(name string) func(entries int, failed bool) {
+	start := time.Now()
+	l.mu.Lock()
+	l.source = name
+	if l.stats[name] == nil {
+		l.stats[name] = &SourceStats{Source: name}
+		l.order = append(l.order, name)
+	}
+	l.mu.Unlock()
+	l.Info("generating")
+
+	return func(entries int, failed bool) {
+		l.mu.Lock()
+		s := l.stats[name]
+		s.Entries, s.Failed = entries, failed
+		s.Duration = time.Since(start)
+		l.source = ""
+		l.mu.Unlock()
+	}
+}
+
+// Summary returns the stats of the sources run so far, in the order they started.
+func (l *Logger) Summary() []SourceStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	summary := make([]SourceStats, 0, len(l.order))
+	for _, name := range l.order {
+		summary = append(summary, *l.stats[name])
+	}
+	return summary
+}
+
+// writeSummary prints the per-source summary table of a run.
+func writeSummary(w io.Writer, summary []SourceStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SOURCE\tENTRIES\tWARNINGS\tSKIPPED\tDURATION\tSTATUS")
+	for _, s := range summary {
+		status := "ok"
+		if s.Failed {
+			status = "FAILED"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\n",
+			s.Source, s.Entries, s.Warnings, s.Skipped, s.Duration.Round(100*time.Millisecond), status)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerCountsPerSource(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewLogger(&buf, slog.LevelInfo, false)
+	l.Warn("outside any source") // Not counted
+
+	done := l.StartSource("apple")
+	l.Warn("optional data unavailable")
+	l.Skip("version failed", "version", "17")
+	l.Skip("version failed", "version", "18")
+	done(120, false)
+
+	done = l.StartSource("android")
+	l.Debug("not written")
+	done(0, true)
+
+	summary := l.Summary()
+	if len(summary) != 2 || summary[0].Source != "apple" || summary[1].Source != "android" {
+		t.Fatalf("Summary() = %+v, want apple then android", summary)
+	}
+	apple := summary[0]
+	if apple.Entries != 120 || apple.Warnings != 1 || apple.Skipped != 2 || apple.Failed {
+		t.Errorf("apple stats = %+v, want 120 entries, 1 warning, 2 skipped", apple)
+	}
+	if !summary[1].Failed {
+		t.Error("android not marked failed")
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "source=apple") || !strings.Contains(out, "version=18") || !strings.Contains(out, "skipped=true") {
+		t.Errorf("log missing structured fields:\n%s", out)
+	}
+	if strings.Contains(out, "not written") {
+		t.Errorf("debug record written at info level:\n%s", out)
+	}
+
+	var table bytes.Buffer
+	if err := writeSummary(&table, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "FAILED") || !strings.HasPrefix(table.String(), "SOURCE") {
+		t.Errorf("writeSummary() =\n%s", table.String())
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewLogger(&buf, slog.LevelWarn, true)
+	done := l.StartSource("ccadb")
+	l.Warn("root records unavailable", "err", "timeout")
+	done(1, false)
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("record is not one JSON line: %v\n%s", err, buf.String())
+	}
+	if rec["source"] != "ccadb" || rec["err"] != "timeout" || rec["level"] != "WARN" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLogLevel(in); err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "verbose", "info+2"} {
+		if _, err := ParseLogLevel(in); err == nil {
+			t.Errorf("ParseLogLevel(%q) succeeded", in)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package generate
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultUserAgent identifies the generator to the sites it downloads from, with a URL
+// their operators can follow instead of blocking it.
+const defaultUserAgent = "certvet-generate (+https://github.com/ivoronin/certvet)"
+
+// hostLimiter spaces and caps the requests made to each host, so that regular regeneration
+// doesn't hammer Apple's and Google's sites and get blocked. One limiter is shared by the
+// clients of all sources of a run, as several sources download from the same hosts.
+type hostLimiter struct {
+	interval      time.Duration // Minimum time between the starts of requests to a host (0 = none)
+	maxConcurrent int           // Most requests in flight to a host (0 = unlimited)
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	next  time.Time     // Earliest start of the next request
+	slots chan struct{} // Taken by requests in flight, nil if unlimited
+}
+
+// newHostLimiter returns a limiter starting requests to a host at most once per interval,
+// with at most maxConcurrent of them in flight.
+func newHostLimiter(interval time.Duration, maxConcurrent int) *hostLimiter {
+	return &hostLimiter{interval: interval, maxConcurrent: maxConcurrent, hosts: make(map[string]*hostState)}
+}
+
+// acquire waits until a request to host may start and returns the function ending it.
+// It fails only if req's context is done while waiting.
+func (l *hostLimiter) acquire(req *http.Request) (release func(), err error) {
+	ctx := req.Context()
+	l.mu.Lock()
+	h := l.hosts[req.URL.Host]
+	if h == nil {
+		h = &hostState{}
+		if l.maxConcurrent > 0 {
+			h.slots = make(chan struct{}, l.maxConcurrent)
+		}
+		l.hosts[req.URL.Host] = h
+	}
+	l.mu.Unlock()
+
+	release = func() {}
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-h.slots }) }
+	}
+
+	// Reserve the next start slot, then wait for it
+	l.mu.Lock()
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// politeTransport is an http.RoundTripper identifying requests with UserAgent and pacing
+// them with Limiter. A request stays in flight until its response body is closed.
+type politeTransport struct {
+	Limiter   *hostLimiter
+	UserAgent string
+	Next      http.RoundTripper
+}
+
+// RoundTrip waits for the limiter and passes req on with the User-Agent set, unless the
+// caller set one.
+func (p *politeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := p.Limiter.acquire(req)
+	if err != nil {
+		return nil, err
+	}
+	if p.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", p.UserAgent)
+	}
+	resp, err := p.Next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody ends the request of a response when its body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
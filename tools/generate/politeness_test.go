@@ -0,0 +1,91 @@
+package generate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientUserAgent(t *testing.T) {
+	t.Parallel()
+
+	var got atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got.Store(r.UserAgent())
+	}))
+	defer srv.Close()
+
+	cfg := httpConfig{Timeout: time.Second, UserAgent: "certvet-test/1"}
+	resp, err := newHTTPClient(cfg).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if got.Load() != "certvet-test/1" {
+		t.Errorf("User-Agent = %q, want certvet-test/1", got.Load())
+	}
+}
+
+func TestHostLimiterInterval(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	const interval = 50 * time.Millisecond
+	client := &http.Client{Transport: &politeTransport{Limiter: newHostLimiter(interval, 0), Next: http.DefaultTransport}}
+	start := time.Now()
+	for range 3 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("3 requests took %v, want at least %v", elapsed, 2*interval)
+	}
+}
+
+func TestHostLimiterConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &politeTransport{Limiter: newHostLimiter(0, 2), Next: http.DefaultTransport}}
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrent requests = %d, want at most 2", p)
+	}
+}
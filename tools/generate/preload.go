@@ -0,0 +1,90 @@
+package generate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// mozillaIntermediatesURL is the Remote Settings collection from which Firefox preloads
+// the intermediates disclosed to CCADB.
+const mozillaIntermediatesURL = "https://firefox.settings.services.mozilla.com/v1/buckets/security-state/collections/intermediates/records"
+
+// Preloaded intermediate fingerprints noted during a run, written to preloaded.csv. Nil if
+// none were fetched.
+var (
+	preloadedMu sync.Mutex
+	preloaded   map[truststore.Fingerprint]bool
+)
+
+// RecordPreloaded notes the fingerprints of the intermediates clients preload.
+func RecordPreloaded(fps map[truststore.Fingerprint]bool) {
+	preloadedMu.Lock()
+	defer preloadedMu.Unlock()
+	preloaded = fps
+}
+
+// recordedPreloaded returns the preloaded intermediates noted in this run, nil if none.
+func recordedPreloaded() map[truststore.Fingerprint]bool {
+	preloadedMu.Lock()
+	defer preloadedMu.Unlock()
+	return preloaded
+}
+
+// FetchMozillaPreloaded downloads the fingerprints of the intermediates Firefox preloads.
+func FetchMozillaPreloaded() (map[truststore.Fingerprint]bool, error) {
+	resp, err := httpClient.Get(mozillaIntermediatesURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch Mozilla intermediates: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mozilla intermediates returned status %d", resp.StatusCode)
+	}
+
+	return ParseMozillaIntermediates(resp.Body)
+}
+
+// ParseMozillaIntermediates parses the intermediates Remote Settings records, whose
+// derHash is the base64 SHA-256 of the certificate.
+func ParseMozillaIntermediates(r io.Reader) (map[truststore.Fingerprint]bool, error) {
+	var records struct {
+		Data []struct {
+			DERHash string `json:"derHash"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("parse Mozilla intermediates: %w", err)
+	}
+
+	fps := make(map[truststore.Fingerprint]bool, len(records.Data))
+	for _, rec := range records.Data {
+		hash, err := base64.StdEncoding.DecodeString(rec.DERHash)
+		if err != nil || len(hash) != len(truststore.Fingerprint{}) {
+			return nil, fmt.Errorf("invalid derHash %q", rec.DERHash)
+		}
+		fps[truststore.Fingerprint(hash)] = true
+	}
+	return fps, nil
+}
+
+// FindPreloaded returns the CA certificates from all that are preloaded. Certificates come
+// from CCADB, where preloaded intermediates are disclosed.
+func FindPreloaded(all []Certificate, fps map[truststore.Fingerprint]bool) []Certificate {
+	var found []Certificate
+	for _, c := range all {
+		if !fps[c.Fingerprint] {
+			continue
+		}
+		if cert := parsePEM(c.PEM); cert != nil && cert.IsCA {
+			found = append(found, c)
+		}
+	}
+	return found
+}
@@ -0,0 +1,67 @@
+package generate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestParseMozillaIntermediates(t *testing.T) {
+	t.Parallel()
+
+	hash := sha256.Sum256([]byte("intermediate"))
+	input := `{"data": [{"derHash": "` + base64.StdEncoding.EncodeToString(hash[:]) + `", "subject": "CN=Test CA"}]}`
+	fps, err := ParseMozillaIntermediates(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(fps) != 1 || !fps[truststore.Fingerprint(hash)] {
+		t.Errorf("got %v, want only %x", fps, hash)
+	}
+
+	if _, err := ParseMozillaIntermediates(strings.NewReader(`{"data": [{"derHash": "AAAA"}]}`)); err == nil {
+		t.Error("accepted a short derHash")
+	}
+}
+
+func TestFindPreloaded(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := Certificate{
+		Fingerprint: truststore.Fingerprint(sha256.Sum256(der)),
+		PEM:         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+	other := Certificate{Fingerprint: truststore.Fingerprint{1}, PEM: ca.PEM}
+
+	got := FindPreloaded([]Certificate{ca, other}, map[truststore.Fingerprint]bool{ca.Fingerprint: true})
+	if len(got) != 1 || got[0].Fingerprint != ca.Fingerprint {
+		t.Errorf("FindPreloaded() = %d certs, want only the preloaded CA", len(got))
+	}
+}
@@ -0,0 +1,163 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// provenance is the content of provenance.json: what every source's data was generated
+// from and by which build of the generator, for reproducibility audits.
+type provenance struct {
+	Generated time.Time                   `json:"generated"`
+	Sources   map[string]sourceProvenance `json:"sources"`
+}
+
+// sourceProvenance describes the last run of one source (a generator, or ctlogs).
+type sourceProvenance struct {
+	Generated time.Time         `json:"generated"`
+	Generator generatorBuild    `json:"generator"`
+	Version   string            `json:"version,omitempty"` // Upstream version, as in sources.json
+	Inputs    []provenanceInput `json:"inputs"`
+}
+
+// generatorBuild identifies the build of the generate command.
+type generatorBuild struct {
+	Module   string `json:"module,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Revision string `json:"revision,omitempty"` // VCS revision, if stamped
+	Modified bool   `json:"modified,omitempty"` // Built from a working tree with local changes
+	Go       string `json:"go"`
+}
+
+// provenanceInput is one downloaded input of a source.
+type provenanceInput struct {
+	URL     string    `json:"url"`
+	Fetched time.Time `json:"fetched"`
+	SHA256  string    `json:"sha256"`
+	Size    int       `json:"size"`
+}
+
+// Inputs downloaded during a run by source, written to provenance.json.
+var (
+	inputMu     sync.Mutex
+	inputsBySrc = make(map[string][]provenanceInput)
+)
+
+// provenanceRecorder is an http.RoundTripper recording the successful GET responses of
+// Source as its inputs. Cached responses are recorded like downloaded ones.
+type provenanceRecorder struct {
+	Source string
+	Next   http.RoundTripper
+}
+
+// RoundTrip passes req on and records the hash of a 200 response body.
+func (p *provenanceRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.Next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	input := provenanceInput{
+		URL:     req.URL.String(),
+		Fetched: time.Now().UTC().Truncate(time.Second),
+		SHA256:  hex.EncodeToString(sum[:]),
+		Size:    len(body),
+	}
+
+	inputMu.Lock()
+	inputsBySrc[p.Source] = append(inputsBySrc[p.Source], input)
+	inputMu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// currentBuild describes the running generator from its build info.
+func currentBuild() generatorBuild {
+	b := generatorBuild{Go: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	b.Module, b.Version = info.Main.Path, info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
+
+// update replaces the provenance of the sources that ran, those with recorded inputs,
+// keeping that of sources that did not run.
+func (p *provenance) update(generated time.Time, build generatorBuild) {
+	p.Generated = generated
+	if p.Sources == nil {
+		p.Sources = make(map[string]sourceProvenance)
+	}
+
+	inputMu.Lock()
+	defer inputMu.Unlock()
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	for source, inputs := range inputsBySrc {
+		inputs = append([]provenanceInput(nil), inputs...)
+		sort.SliceStable(inputs, func(i, j int) bool { return inputs[i].URL < inputs[j].URL })
+		p.Sources[source] = sourceProvenance{
+			Generated: generated,
+			Generator: build,
+			Version:   sourceVersions[source],
+			Inputs:    inputs,
+		}
+	}
+}
+
+// writeProvenance writes provenance.json dated generated, keeping the previous provenance
+// of sources that did not run. Returns the number of sources described.
+func writeProvenance(generated time.Time) (int, error) {
+	path := filepath.Join(dataDir, "provenance.json")
+	var p provenance
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return 0, err
+	default:
+		if err := json.Unmarshal(data, &p); err != nil {
+			return 0, fmt.Errorf("parse provenance.json: %w", err)
+		}
+	}
+
+	p.update(generated, currentBuild())
+
+	out, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil { //nolint:gosec // G306: data files are world-readable like other generated CSVs
+		return 0, err
+	}
+	return len(p.Sources), nil
+}
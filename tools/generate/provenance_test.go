@@ -0,0 +1,57 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProvenanceRecorder(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "data of "+r.URL.Path)
+	}))
+	defer srv.Close()
+
+	const source = "provenance-test"
+	client := newHTTPClient(httpConfig{Timeout: 5 * time.Second, Source: source})
+	for _, path := range []string{"/b", "/a", "/missing"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode == http.StatusOK && string(body) != "data of "+path {
+			t.Errorf("GET %s returned %q after recording", path, body)
+		}
+	}
+
+	generated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := provenance{Sources: map[string]sourceProvenance{"apple": {Version: "kept"}}}
+	p.update(generated, generatorBuild{Go: "go1.25"})
+
+	if p.Sources["apple"].Version != "kept" {
+		t.Errorf("apple = %+v, want the previous provenance kept", p.Sources["apple"])
+	}
+	got := p.Sources[source]
+	if !got.Generated.Equal(generated) || got.Generator.Go != "go1.25" || len(got.Inputs) != 2 {
+		t.Fatalf("%s = %+v, want 2 inputs generated by go1.25", source, got)
+	}
+	sum := sha256.Sum256([]byte("data of /a"))
+	if in := got.Inputs[0]; in.URL != srv.URL+"/a" || in.SHA256 != hex.EncodeToString(sum[:]) || in.Size != len("data of /a") || in.Fetched.IsZero() {
+		t.Errorf("inputs[0] = %+v, want /a with its hash and size", in)
+	}
+}
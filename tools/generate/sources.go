@@ -0,0 +1,64 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// Upstream data versions noted by generators during a run, written to sources.json.
+var (
+	sourceMu       sync.Mutex
+	sourceVersions = make(map[string]string)
+)
+
+// RecordSourceVersion notes the version of the upstream data a generator fetched, such as
+// the Chrome root store's version_major, to be recorded in sources.json under source.
+func RecordSourceVersion(source, version string) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	sourceVersions[source] = version
+}
+
+// writeSources writes sources.json dated generated. Versions recorded in this run replace
+// those of the previous data; sources that did not run keep their previous versions.
+func writeSources(generated time.Time) (truststore.SourceInfo, error) {
+	path := filepath.Join(dataDir, "sources.json")
+	var info truststore.SourceInfo
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Path is constant dataDir + filename
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return info, err
+	default:
+		if err := json.Unmarshal(data, &info); err != nil {
+			return info, fmt.Errorf("parse sources.json: %w", err)
+		}
+	}
+
+	info.Generated = generated
+	if info.Versions == nil {
+		info.Versions = make(map[string]string)
+	}
+	sourceMu.Lock()
+	for source, version := range sourceVersions {
+		info.Versions[source] = version
+	}
+	sourceMu.Unlock()
+
+	out, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return info, err
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil { //nolint:gosec // G306: data files are world-readable like other generated CSVs
+		return info, err
+	}
+	return info, nil
+}
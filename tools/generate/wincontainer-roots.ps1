@@ -0,0 +1,20 @@
+# Prints the roots of a Windows container image's LocalMachine\Root store in the format of
+# wincontainer_roots.txt. Run it inside the image, pinned by digest, on a Windows Docker host:
+#
+#   $image = "mcr.microsoft.com/windows/servercore:ltsc2022@sha256:<digest>"
+#   docker run --rm -v "${PWD}:C:\src" $image powershell -File C:\src\tools\generate\wincontainer-roots.ps1 -Image $image |
+#     Set-Content -Encoding ascii tools\generate\wincontainer_roots.txt
+#
+# Then regenerate the data with: go run ./tools/generate/cmd -stores wincontainer
+param([Parameter(Mandatory)][string]$Image)
+
+$sha256 = [Security.Cryptography.SHA256]::Create()
+"# Roots in the LocalMachine\Root store of the Windows container base image, one"
+"# ""<SHA-256 fingerprint> <subject>"" per line. Regenerate with wincontainer-roots.ps1"
+"# (see the comment at its top); the generator warns while image is ""unverified""."
+"# image: $Image"
+"# exported: $(Get-Date -Format yyyy-MM-dd)"
+Get-ChildItem Cert:\LocalMachine\Root | ForEach-Object {
+    $fp = [BitConverter]::ToString($sha256.ComputeHash($_.RawData)).Replace("-", ":")
+    "$fp $($_.GetNameInfo([Security.Cryptography.X509Certificates.X509NameType]::SimpleName, $false))"
+} | Sort-Object -Unique
@@ -0,0 +1,120 @@
+package generate
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+// windowsContainerRootsFile is the root store of a Windows container base image (Server Core,
+// Nano Server), exported from the image by wincontainer-roots.ps1. Containers don't run
+// automatic root updates, so only these roots are available unless the image adds more.
+//
+//go:embed wincontainer_roots.txt
+var windowsContainerRootsFile string
+
+// containerRoots is a parsed wincontainer_roots.txt.
+type containerRoots struct {
+	Image        string // Image reference the roots were exported from ("unverified..." if not)
+	Exported     string // Export date (YYYY-MM-DD), empty if unknown
+	Fingerprints []truststore.Fingerprint
+}
+
+// verified reports whether the roots were exported from a named image.
+func (c *containerRoots) verified() bool {
+	return c.Image != "" && !strings.HasPrefix(c.Image, "unverified")
+}
+
+// parseContainerRoots reads wincontainer_roots.txt: "# key: value" header comments, then
+// "<fingerprint> <subject>" lines.
+func parseContainerRoots(data string) (*containerRoots, error) {
+	roots := &containerRoots{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			key, value, _ := strings.Cut(strings.TrimSpace(comment), ":")
+			switch key {
+			case "image":
+				roots.Image = strings.TrimSpace(value)
+			case "exported":
+				roots.Exported = strings.TrimSpace(value)
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		field, _, _ := strings.Cut(line, " ")
+		fp, err := truststore.ParseFingerprint(field)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		roots.Fingerprints = append(roots.Fingerprints, fp)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(roots.Fingerprints) == 0 {
+		return nil, fmt.Errorf("no roots")
+	}
+	return roots, nil
+}
+
+// WindowsContainerGenerator implements StoreGenerator for Windows container trust store data.
+type WindowsContainerGenerator struct{}
+
+// Name returns the generator's display name.
+func (WindowsContainerGenerator) Name() string { return "Windows Container" }
+
+// Generate returns the exported container roots that are still present in the Windows CTL.
+func (WindowsContainerGenerator) Generate() ([]TrustEntry, error) {
+	roots, err := parseContainerRoots(windowsContainerRootsFile)
+	if err != nil {
+		return nil, fmt.Errorf("parse wincontainer_roots.txt: %w", err)
+	}
+	if roots.verified() {
+		RecordSourceVersion("wincontainer", roots.Image+" ("+roots.Exported+")")
+	} else {
+		Log.Warn("container roots were not exported from an image, refresh them with wincontainer-roots.ps1")
+	}
+
+	trustedCTL, err := fetchWindowsCTL()
+	if err != nil {
+		return nil, err
+	}
+	return windowsContainerEntries(roots, trustedCTL), nil
+}
+
+// windowsContainerEntries filters the container roots to those in the CTL and enabled
+// for server authentication. CTL date constraints are dropped: containers never receive
+// CTL property updates.
+func windowsContainerEntries(roots *containerRoots, ctl *CTL) []TrustEntry {
+	inCTL := make(map[truststore.Fingerprint]windowsEntry, len(ctl.Entries))
+	for _, we := range ctl.Entries {
+		inCTL[we.Fingerprint] = we
+	}
+
+	var entries []TrustEntry
+	for _, fp := range roots.Fingerprints {
+		we, ok := inCTL[fp]
+		if !ok {
+			Log.Skip("container root not in Windows CTL", "fingerprint", fp.Truncate(4))
+			continue
+		}
+		if !we.ServerAuth {
+			Log.Skip("root not enabled for server authentication", "fingerprint", fp.Truncate(4))
+			continue
+		}
+		entries = append(entries, TrustEntry{
+			Platform:    "wincontainer",
+			Version:     "current",
+			Fingerprint: fp,
+		})
+	}
+
+	return entries
+}
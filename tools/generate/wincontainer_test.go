@@ -0,0 +1,82 @@
+package generate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ivoronin/certvet/internal/truststore"
+)
+
+func TestWindowsContainerEntries(t *testing.T) {
+	t.Parallel()
+
+	allowed := truststore.FingerprintFromBytes(append(make([]byte, 31), 1))
+	missing := truststore.FingerprintFromBytes(append(make([]byte, 31), 2))
+	codeSigning := truststore.FingerprintFromBytes(append(make([]byte, 31), 3))
+	other := truststore.FingerprintFromBytes(make([]byte, 32))
+	distrust := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ctl := &CTL{Entries: []windowsEntry{
+		{Fingerprint: allowed, DistrustDate: &distrust, ServerAuth: true},
+		{Fingerprint: codeSigning},
+		{Fingerprint: other, ServerAuth: true},
+	}}
+
+	entries := windowsContainerEntries(&containerRoots{Fingerprints: []truststore.Fingerprint{allowed, missing, codeSigning}}, ctl)
+
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Platform != "wincontainer" || e.Version != "current" || e.Fingerprint != allowed {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.HasConstraints() {
+		t.Error("container entries should not carry CTL constraints")
+	}
+}
+
+func TestParseContainerRoots(t *testing.T) {
+	t.Parallel()
+
+	fp := truststore.FingerprintFromBytes(append(make([]byte, 31), 1))
+	data := "# Roots of the image\n" +
+		"# image: mcr.microsoft.com/windows/servercore:ltsc2022@sha256:abc\n" +
+		"# exported: 2026-01-02\n" +
+		"\n" +
+		fp.String() + " Example Root CA\n"
+
+	roots, err := parseContainerRoots(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roots.Image != "mcr.microsoft.com/windows/servercore:ltsc2022@sha256:abc" || roots.Exported != "2026-01-02" || !roots.verified() {
+		t.Errorf("unexpected header: %+v", roots)
+	}
+	if len(roots.Fingerprints) != 1 || roots.Fingerprints[0] != fp {
+		t.Errorf("fingerprints = %v", roots.Fingerprints)
+	}
+
+	if _, err := parseContainerRoots("# image: x\nnot-a-fingerprint Root\n"); err == nil {
+		t.Error("expected error for invalid fingerprint")
+	}
+	if _, err := parseContainerRoots("# image: x\n"); err == nil {
+		t.Error("expected error for a file without roots")
+	}
+}
+
+func TestWindowsContainerRootsFile(t *testing.T) {
+	t.Parallel()
+
+	roots, err := parseContainerRoots(windowsContainerRootsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[truststore.Fingerprint]bool)
+	for _, fp := range roots.Fingerprints {
+		if seen[fp] {
+			t.Errorf("duplicate fingerprint %s", fp)
+		}
+		seen[fp] = true
+	}
+}
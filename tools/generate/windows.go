@@ -2,11 +2,16 @@ package generate
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/asn1"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,19 +41,45 @@ var (
 
 	// OIDDisallowedFiletime is the OID for Disallowed constraint (CA completely distrusted after this date).
 	OIDDisallowedFiletime = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 10, 11, 104}
+
+	// OIDEnhkeyUsage is the OID for the purposes a root is enabled for (CERT_ENHKEY_USAGE_PROP_ID).
+	OIDEnhkeyUsage = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 10, 11, 9}
+
+	// OIDNotBeforeEnhkeyUsage is the OID for the purposes the NotBefore constraint applies to.
+	OIDNotBeforeEnhkeyUsage = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 10, 11, 127}
+
+	// OIDDisallowedEnhkeyUsage is the OID for the purposes the Disallowed constraint applies to.
+	OIDDisallowedEnhkeyUsage = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 10, 11, 122}
+
+	// OIDServerAuth is the serverAuth extended key usage.
+	OIDServerAuth = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 1}
+
+	// OIDRootListSigner is the extended key usage of Microsoft's CTL signing certificates.
+	OIDRootListSigner = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 10, 3, 9}
 )
 
+// windowsCTLSignerCAs pins the CAs allowed to issue the CTL signing certificate by the
+// SHA-256 hash of their SubjectPublicKeyInfo. The STL carries the signer and its issuing
+// CA but not Microsoft's root, so the issuing CA's key is pinned; add the new key here
+// when Microsoft rotates it.
+var windowsCTLSignerCAs = map[string]string{
+	"c3e48f94c7ce02fac584357f3cfa004ad2476f2764315e04c7460db464e05fab": "Microsoft Certificate List CA 2011",
+}
+
 // windowsEntry holds extracted data for a single Windows CTL entry.
 type windowsEntry struct {
 	Fingerprint  truststore.Fingerprint
 	NotBeforeMax *time.Time // OID .126: certs issued after this not trusted
 	DistrustDate *time.Time // OID .104: CA completely distrusted after this
+	ServerAuth   bool       // OID .9: root enabled for server authentication (true if unrestricted)
 }
 
 // CTL represents a parsed Microsoft Certificate Trust List.
 type CTL struct {
 	// Entries contains the parsed CTL entries with fingerprints and constraints.
 	Entries []windowsEntry
+	// SequenceNumber identifies the CTL release (hex, as certutil shows it).
+	SequenceNumber string
 }
 
 // ctlAttribute represents an attribute in a CTL entry.
@@ -95,6 +126,39 @@ func (WindowsGenerator) Name() string { return "Windows" }
 
 // Generate fetches Windows trust store data and returns TrustEntry structs.
 func (WindowsGenerator) Generate() ([]TrustEntry, error) {
+	trustedCTL, err := fetchWindowsCTL()
+	if err != nil {
+		return nil, err
+	}
+	RecordSourceVersion("windows", trustedCTL.SequenceNumber)
+
+	return windowsEntries(trustedCTL), nil
+}
+
+// windowsEntries creates a TrustEntry for each CTL root enabled for server authentication
+// (Windows has only "current" version). Windows rejects TLS chains to roots whose CTL
+// purposes exclude serverAuth, although x509.Verify ignores those purposes.
+func windowsEntries(ctl *CTL) []TrustEntry {
+	var entries []TrustEntry
+	for _, we := range ctl.Entries {
+		if !we.ServerAuth {
+			Log.Skip("root not enabled for server authentication", "fingerprint", we.Fingerprint.Truncate(4))
+			continue
+		}
+		entries = append(entries, TrustEntry{
+			Platform:     "windows",
+			Version:      "current",
+			Fingerprint:  we.Fingerprint,
+			NotBeforeMax: we.NotBeforeMax,
+			DistrustDate: we.DistrustDate,
+		})
+	}
+
+	return entries
+}
+
+// fetchWindowsCTL downloads and parses the Windows Update trusted root CTL.
+func fetchWindowsCTL() (*CTL, error) {
 	trustedCAB, err := fetchCAB(windowsAuthrootURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetch trusted roots: %w", err)
@@ -109,19 +173,7 @@ func (WindowsGenerator) Generate() ([]TrustEntry, error) {
 		return nil, fmt.Errorf("parse trusted CTL: %w", err)
 	}
 
-	// Create TrustEntry for each entry (Windows has only "current" version)
-	entries := make([]TrustEntry, len(trustedCTL.Entries))
-	for i, we := range trustedCTL.Entries {
-		entries[i] = TrustEntry{
-			Platform:     "windows",
-			Version:      "current",
-			Fingerprint:  we.Fingerprint,
-			NotBeforeMax: we.NotBeforeMax,
-			DistrustDate: we.DistrustDate,
-		}
-	}
-
-	return entries, nil
+	return trustedCTL, nil
 }
 
 // fetchCAB downloads a CAB file from the given URL.
@@ -179,6 +231,9 @@ func parseCTL(stlData []byte) (*CTL, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse pkcs7: %w", err)
 	}
+	if err := verifyCTLSignature(p7); err != nil {
+		return nil, fmt.Errorf("verify signature: %w", err)
+	}
 
 	// The CTL content is an implicit SEQUENCE - elements are directly in the content
 	// without an outer SEQUENCE wrapper. We parse elements individually.
@@ -192,8 +247,8 @@ func parseCTL(stlData []byte) (*CTL, error) {
 		return nil, fmt.Errorf("parse subject usage: %w", err)
 	}
 
-	// Skip: SequenceNumber (INTEGER)
-	var seqNum asn1.RawValue
+	// Parse: SequenceNumber (INTEGER)
+	var seqNum *big.Int
 	content, err = asn1.Unmarshal(content, &seqNum)
 	if err != nil {
 		return nil, fmt.Errorf("parse sequence number: %w", err)
@@ -231,14 +286,41 @@ func parseCTL(stlData []byte) (*CTL, error) {
 	for _, entry := range entries {
 		we, err := extractWindowsEntry(entry.Attributes)
 		if err != nil {
-			// Log warning but continue - some entries might not have SHA-256
-			Log.Warn("skipping entry: %v", err)
+			// Skip but continue - some entries might not have SHA-256
+			Log.Skip("CTL entry unusable", "err", err)
 			continue
 		}
 		windowsEntries = append(windowsEntries, we)
 	}
 
-	return &CTL{Entries: windowsEntries}, nil
+	return &CTL{Entries: windowsEntries, SequenceNumber: fmt.Sprintf("%X", seqNum)}, nil
+}
+
+// verifyCTLSignature checks that p7 has a single valid signature by a trust list signing
+// certificate issued by a pinned CA (see windowsCTLSignerCAs), so a tampered CTL served
+// by the CDN is rejected.
+func verifyCTLSignature(p7 *pkcs7.PKCS7) error {
+	signer := p7.GetOnlySigner()
+	if signer == nil {
+		return fmt.Errorf("expected a single signer with its certificate")
+	}
+	if !slices.ContainsFunc(signer.UnknownExtKeyUsage, OIDRootListSigner.Equal) {
+		return fmt.Errorf("signer %q is not a trust list signer", signer.Subject.CommonName)
+	}
+
+	pinned := x509.NewCertPool()
+	for _, cert := range p7.Certificates {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		if _, ok := windowsCTLSignerCAs[hex.EncodeToString(sum[:])]; ok {
+			pinned.AddCert(cert)
+		}
+	}
+	// A CTL stays in use after its signing certificate expires, so the chain is
+	// checked as of the signer's issuance
+	if err := p7.VerifyWithChainAtTime(pinned, signer.NotBefore); err != nil {
+		return fmt.Errorf("signer %q: %w", signer.Subject.CommonName, err)
+	}
+	return nil
 }
 
 // parseTrustedSubjects parses the SEQUENCE OF TrustedSubject entries.
@@ -258,10 +340,26 @@ func parseTrustedSubjects(data []byte) ([]ctlEntry, error) {
 	return entries, nil
 }
 
+// parseEnhkeyUsage decodes a CTL purpose attribute: an OCTET STRING holding a
+// DER SEQUENCE OF OBJECT IDENTIFIER.
+func parseEnhkeyUsage(attr ctlAttribute) ([]asn1.ObjectIdentifier, error) {
+	var octets []byte
+	if _, err := asn1.Unmarshal(attr.Values.Bytes, &octets); err != nil {
+		return nil, err
+	}
+	var oids []asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(octets, &oids); err != nil {
+		return nil, err
+	}
+	return oids, nil
+}
+
 // extractWindowsEntry extracts fingerprint and constraints from CTL attributes.
 // Uses two-pass processing to ensure fingerprint is available for error context.
+// Constraints scoped to purposes other than serverAuth (e.g. a code signing
+// NotBefore) are dropped, as Windows doesn't apply them to TLS chains.
 func extractWindowsEntry(attrs []ctlAttribute) (windowsEntry, error) {
-	var entry windowsEntry
+	entry := windowsEntry{ServerAuth: true}
 
 	// First pass: extract fingerprint for error context
 	for _, attr := range attrs {
@@ -287,12 +385,12 @@ func extractWindowsEntry(attrs []ctlAttribute) (windowsEntry, error) {
 			// NotBefore constraint: certs issued after this date not trusted
 			var ftBytes []byte
 			if _, err := asn1.Unmarshal(attr.Values.Bytes, &ftBytes); err != nil {
-				Log.Warn("cert %s: unmarshal NotBeforeFiletime: %v", fpPrefix, err)
+				Log.Warn("NotBeforeFiletime ignored", "cert", fpPrefix, "err", err)
 				continue
 			}
 			t, err := parseFiletime(ftBytes)
 			if err != nil {
-				Log.Warn("cert %s: parse NotBeforeFiletime: %v", fpPrefix, err)
+				Log.Warn("NotBeforeFiletime ignored", "cert", fpPrefix, "err", err)
 				continue
 			}
 			entry.NotBeforeMax = &t
@@ -301,15 +399,45 @@ func extractWindowsEntry(attrs []ctlAttribute) (windowsEntry, error) {
 			// Disallowed constraint: CA completely distrusted after this date
 			var ftBytes []byte
 			if _, err := asn1.Unmarshal(attr.Values.Bytes, &ftBytes); err != nil {
-				Log.Warn("cert %s: unmarshal DisallowedFiletime: %v", fpPrefix, err)
+				Log.Warn("DisallowedFiletime ignored", "cert", fpPrefix, "err", err)
 				continue
 			}
 			t, err := parseFiletime(ftBytes)
 			if err != nil {
-				Log.Warn("cert %s: parse DisallowedFiletime: %v", fpPrefix, err)
+				Log.Warn("DisallowedFiletime ignored", "cert", fpPrefix, "err", err)
 				continue
 			}
 			entry.DistrustDate = &t
+
+		case attr.Type.Equal(OIDEnhkeyUsage):
+			// Root purposes: absent means the root is trusted for all purposes
+			oids, err := parseEnhkeyUsage(attr)
+			if err != nil {
+				Log.Warn("EnhkeyUsage ignored", "cert", fpPrefix, "err", err)
+				continue
+			}
+			entry.ServerAuth = slices.ContainsFunc(oids, OIDServerAuth.Equal)
+		}
+	}
+
+	// Third pass: drop date constraints whose purposes exclude serverAuth
+	for _, attr := range attrs {
+		var constraint **time.Time
+		switch {
+		case attr.Type.Equal(OIDNotBeforeEnhkeyUsage):
+			constraint = &entry.NotBeforeMax
+		case attr.Type.Equal(OIDDisallowedEnhkeyUsage):
+			constraint = &entry.DistrustDate
+		default:
+			continue
+		}
+		oids, err := parseEnhkeyUsage(attr)
+		if err != nil {
+			Log.Warn("constraint EnhkeyUsage ignored", "cert", fpPrefix, "err", err)
+			continue
+		}
+		if !slices.ContainsFunc(oids, OIDServerAuth.Equal) {
+			*constraint = nil
 		}
 	}
 
@@ -1,9 +1,13 @@
 package generate
 
 import (
+	"encoding/asn1"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/ivoronin/certvet/internal/truststore"
 )
 
 func TestExtractSTLFromCAB(t *testing.T) {
@@ -59,6 +63,14 @@ func TestParseCTL(t *testing.T) {
 	if len(ctl.Entries) == 0 {
 		t.Error("no entries found")
 	}
+	if ctl.SequenceNumber == "" {
+		t.Error("no sequence number found")
+	}
+
+	// The CTL includes roots enabled only for other purposes (code signing, email, ...)
+	if !slices.ContainsFunc(ctl.Entries, func(e windowsEntry) bool { return !e.ServerAuth }) {
+		t.Error("no entries without serverAuth found")
+	}
 
 	// Track constraint counts for verification
 	var withNotBefore, withDistrust int
@@ -115,6 +127,118 @@ func TestParseCTLInvalidData(t *testing.T) {
 	}
 }
 
+func TestParseCTLTampered(t *testing.T) {
+	t.Parallel()
+
+	stlData, err := os.ReadFile("testdata/authroot.stl")
+	if err != nil {
+		t.Fatalf("read test data: %v", err)
+	}
+	// Flip a byte in the CTL content, which precedes the certificates and signature
+	tampered := slices.Clone(stlData)
+	tampered[len(tampered)/4] ^= 0x01
+	if _, err := parseCTL(tampered); err == nil || !strings.Contains(err.Error(), "verify signature") {
+		t.Errorf("parseCTL(tampered) error = %v, want a signature error", err)
+	}
+}
+
+// TestParseCTLUnpinnedSigner replaces package data and must not run in parallel.
+func TestParseCTLUnpinnedSigner(t *testing.T) {
+	stlData, err := os.ReadFile("testdata/authroot.stl")
+	if err != nil {
+		t.Fatalf("read test data: %v", err)
+	}
+	pins := windowsCTLSignerCAs
+	windowsCTLSignerCAs = map[string]string{}
+	t.Cleanup(func() { windowsCTLSignerCAs = pins })
+
+	if _, err := parseCTL(stlData); err == nil || !strings.Contains(err.Error(), "Microsoft Certificate Trust List Publisher") {
+		t.Errorf("parseCTL() without pins error = %v, want the signer rejected", err)
+	}
+}
+
+// ctlAttr builds a CTL attribute whose value is the DER encoding of v.
+func ctlAttr(t *testing.T, oid asn1.ObjectIdentifier, v any) ctlAttribute {
+	t.Helper()
+	der, err := asn1.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %v: %v", oid, err)
+	}
+	return ctlAttribute{Type: oid, Values: asn1.RawValue{Bytes: der}}
+}
+
+// ekuAttr builds a CTL purpose attribute: an OCTET STRING holding a SEQUENCE OF OID.
+func ekuAttr(t *testing.T, oid asn1.ObjectIdentifier, ekus ...asn1.ObjectIdentifier) ctlAttribute {
+	t.Helper()
+	der, err := asn1.Marshal(ekus)
+	if err != nil {
+		t.Fatalf("marshal purposes: %v", err)
+	}
+	return ctlAttr(t, oid, der)
+}
+
+func TestExtractWindowsEntryPurposes(t *testing.T) {
+	t.Parallel()
+
+	codeSigning := asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 3}
+	fp := ctlAttr(t, OIDSHA256Fingerprint, append(make([]byte, 31), 1))
+	filetime := []byte{0x00, 0x00, 0x05, 0x69, 0x36, 0xc0, 0xd5, 0x01} // 2020-01-01
+	notBefore := ctlAttr(t, OIDNotBeforeFiletime, filetime)
+	disallowed := ctlAttr(t, OIDDisallowedFiletime, filetime)
+
+	tests := []struct {
+		name           string
+		attrs          []ctlAttribute
+		wantServerAuth bool
+		wantNotBefore  bool
+		wantDistrust   bool
+	}{
+		{"no purposes", []ctlAttribute{fp, notBefore, disallowed}, true, true, true},
+		{"serverAuth root", []ctlAttribute{fp, ekuAttr(t, OIDEnhkeyUsage, codeSigning, OIDServerAuth)}, true, false, false},
+		{"code signing root", []ctlAttribute{fp, ekuAttr(t, OIDEnhkeyUsage, codeSigning)}, false, false, false},
+		{"NotBefore for serverAuth", []ctlAttribute{fp, notBefore, ekuAttr(t, OIDNotBeforeEnhkeyUsage, OIDServerAuth)}, true, true, false},
+		{"NotBefore for code signing", []ctlAttribute{fp, notBefore, ekuAttr(t, OIDNotBeforeEnhkeyUsage, codeSigning)}, true, false, false},
+		{"Disallowed for serverAuth", []ctlAttribute{fp, disallowed, ekuAttr(t, OIDDisallowedEnhkeyUsage, OIDServerAuth)}, true, false, true},
+		{"Disallowed for code signing", []ctlAttribute{ekuAttr(t, OIDDisallowedEnhkeyUsage, codeSigning), fp, disallowed}, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := extractWindowsEntry(tt.attrs)
+			if err != nil {
+				t.Fatalf("extractWindowsEntry() error = %v", err)
+			}
+			if got.ServerAuth != tt.wantServerAuth {
+				t.Errorf("ServerAuth = %v, want %v", got.ServerAuth, tt.wantServerAuth)
+			}
+			if (got.NotBeforeMax != nil) != tt.wantNotBefore {
+				t.Errorf("NotBeforeMax = %v, want set %v", got.NotBeforeMax, tt.wantNotBefore)
+			}
+			if (got.DistrustDate != nil) != tt.wantDistrust {
+				t.Errorf("DistrustDate = %v, want set %v", got.DistrustDate, tt.wantDistrust)
+			}
+		})
+	}
+}
+
+func TestWindowsEntries(t *testing.T) {
+	t.Parallel()
+
+	tls := truststore.FingerprintFromBytes(append(make([]byte, 31), 1))
+	codeSigning := truststore.FingerprintFromBytes(append(make([]byte, 31), 2))
+	ctl := &CTL{Entries: []windowsEntry{
+		{Fingerprint: tls, ServerAuth: true},
+		{Fingerprint: codeSigning},
+	}}
+
+	entries := windowsEntries(ctl)
+	if len(entries) != 1 || entries[0].Fingerprint != tls {
+		t.Errorf("windowsEntries() = %+v, want only the serverAuth root", entries)
+	}
+}
+
 func TestParseFiletime(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,89 @@
+package trustdata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// Statuses of certificates a platform lists outside its trusted roots, from the Blocked and
+// Always Ask sections of Apple's trust store pages.
+const (
+	CertBlocked   = "blocked"    // Never trusted, even if a chain through it verifies
+	CertAlwaysAsk = "always_ask" // Trusted only after the user approves it in a prompt
+)
+
+// BlockedCert is a certificate a platform version blocks or asks the user about.
+type BlockedCert struct {
+	Platform    Platform
+	Version     string
+	Fingerprint Fingerprint
+	Status      string // One of Cert* constants
+}
+
+// loadBlocked attaches the blocked certificates of blocked.csv in fsys to stores. Data
+// generated before they were recorded has none.
+func loadBlocked(fsys fs.FS, stores []Store) error {
+	data, err := fs.ReadFile(fsys, "blocked.csv")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	certs, err := ParseBlocked(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	byKey := make(map[StoreKey]map[Fingerprint]string)
+	for _, c := range certs {
+		key := StoreKey{Platform: c.Platform, Version: c.Version}
+		if byKey[key] == nil {
+			byKey[key] = make(map[Fingerprint]string)
+		}
+		byKey[key][c.Fingerprint] = c.Status
+	}
+	for i := range stores {
+		stores[i].Blocked = byKey[StoreKey{Platform: stores[i].Platform, Version: stores[i].Version}]
+	}
+	return nil
+}
+
+// ParseBlocked reads blocked certificates from a blocked CSV (with header).
+// CSV format: platform,version,fingerprint,status
+func ParseBlocked(reader io.Reader) ([]BlockedCert, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = 4
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var certs []BlockedCert
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		fp, err := ParseFingerprint(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", record[2], err)
+		}
+		switch record[3] {
+		case CertBlocked, CertAlwaysAsk:
+		default:
+			return nil, fmt.Errorf("unknown status %q for %s", record[3], record[2])
+		}
+		certs = append(certs, BlockedCert{Platform: Platform(record[0]), Version: record[1], Fingerprint: fp, Status: record[3]})
+	}
+	return certs, nil
+}
@@ -0,0 +1,31 @@
+package trustdata
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadBlocked(t *testing.T) {
+	t.Parallel()
+
+	hex := strings.Repeat("CD", 32)
+	fp := mustParseFingerprint(t, hex)
+	fsys := fstest.MapFS{"blocked.csv": {Data: []byte("platform,version,fingerprint,status\n" +
+		"ios,18," + hex + ",blocked\n" + "macos,15," + hex + ",always_ask\n")}}
+	stores := []Store{{Platform: PlatformIOS, Version: "18"}, {Platform: PlatformIOS, Version: "17"}, {Platform: PlatformMacOS, Version: "15"}}
+	if err := loadBlocked(fsys, stores); err != nil {
+		t.Fatal(err)
+	}
+	if stores[0].Blocked[fp] != CertBlocked || stores[1].Blocked != nil || stores[2].Blocked[fp] != CertAlwaysAsk {
+		t.Errorf("blocked = %v, %v, %v", stores[0].Blocked, stores[1].Blocked, stores[2].Blocked)
+	}
+
+	// Data generated before blocked certificates were recorded has none
+	if err := loadBlocked(fstest.MapFS{}, stores[:1]); err != nil {
+		t.Errorf("loadBlocked(no file) = %v", err)
+	}
+	if _, err := ParseBlocked(strings.NewReader("platform,version,fingerprint,status\nios,18," + hex + ",distrusted\n")); err == nil {
+		t.Error("ParseBlocked() accepted an unknown status")
+	}
+}
@@ -0,0 +1,128 @@
+package trustdata
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"time"
+)
+
+// CAInfo describes a root CA: who operates it and the basics of its certificate, so roots
+// can be listed and filtered without parsing certificates.
+type CAInfo struct {
+	Fingerprint Fingerprint
+	Operator    string    // CA owner as recorded in CCADB (e.g., "Government of Spain, ..."); empty if unknown
+	Country     string    // Country of the certificate subject (ISO 3166 code); empty if unset
+	KeyType     string    // Public key algorithm: RSA, ECDSA, Ed25519
+	KeySize     int       // Public key size in bits (0 if unknown)
+	NotBefore   time.Time // Certificate validity
+	NotAfter    time.Time
+}
+
+// CAInfos indexes root CA metadata by fingerprint. Empty if the data has none, in which
+// case roots have no operator and country.
+var CAInfos map[Fingerprint]CAInfo
+
+// NewCAInfo describes the root CA of cert, operated by operator.
+func NewCAInfo(cert *x509.Certificate, operator string) CAInfo {
+	info := CAInfo{
+		Fingerprint: FingerprintFromCert(cert),
+		Operator:    operator,
+		NotBefore:   cert.NotBefore.UTC(),
+		NotAfter:    cert.NotAfter.UTC(),
+	}
+	if len(cert.Subject.Country) > 0 {
+		info.Country = cert.Subject.Country[0]
+	}
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.KeyType, info.KeySize = "RSA", pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		info.KeyType, info.KeySize = "ECDSA", pub.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		info.KeyType, info.KeySize = "Ed25519", 256
+	default:
+		info.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+	return info
+}
+
+// Key returns the key type and size, e.g. "RSA 4096" (just the type if the size is unknown).
+func (i CAInfo) Key() string {
+	if i.KeySize == 0 {
+		return i.KeyType
+	}
+	return i.KeyType + " " + strconv.Itoa(i.KeySize)
+}
+
+// loadCAInfos indexes root CA metadata from cainfo.csv in fsys. Data generated before it
+// was recorded has none.
+func loadCAInfos(fsys fs.FS) (map[Fingerprint]CAInfo, error) {
+	index := make(map[Fingerprint]CAInfo)
+	data, err := fs.ReadFile(fsys, "cainfo.csv")
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := ParseCAInfos(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		index[info.Fingerprint] = info
+	}
+	return index, nil
+}
+
+// ParseCAInfos reads root CA metadata from a cainfo CSV (with header).
+// CSV format: fingerprint,operator,country,key_type,key_size,not_before,not_after
+func ParseCAInfos(reader io.Reader) ([]CAInfo, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = 7
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var infos []CAInfo
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		fp, err := ParseFingerprint(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", record[0], err)
+		}
+		info := CAInfo{Fingerprint: fp, Operator: record[1], Country: record[2], KeyType: record[3]}
+		if record[4] != "" {
+			if info.KeySize, err = strconv.Atoi(record[4]); err != nil || info.KeySize < 0 {
+				return nil, fmt.Errorf("parse key_size %s: invalid bits", record[4])
+			}
+		}
+		if info.NotBefore, err = time.Parse(time.RFC3339, record[5]); err != nil {
+			return nil, fmt.Errorf("parse not_before %s: %w", record[5], err)
+		}
+		if info.NotAfter, err = time.Parse(time.RFC3339, record[6]); err != nil {
+			return nil, fmt.Errorf("parse not_after %s: %w", record[6], err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,70 @@
+package trustdata
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestParseCAInfos(t *testing.T) {
+	t.Parallel()
+
+	hex := strings.Repeat("AB", 32)
+	csv := "fingerprint,operator,country,key_type,key_size,not_before,not_after\n" +
+		hex + ",\"Government of Spain, ACCV\",ES,RSA,4096,2011-07-07T15:00:00Z,2030-12-31T00:00:00Z\n"
+	infos, err := ParseCAInfos(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CAInfo{
+		Fingerprint: mustParseFingerprint(t, hex),
+		Operator:    "Government of Spain, ACCV",
+		Country:     "ES",
+		KeyType:     "RSA",
+		KeySize:     4096,
+		NotBefore:   time.Date(2011, 7, 7, 15, 0, 0, 0, time.UTC),
+		NotAfter:    time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if len(infos) != 1 || infos[0] != want {
+		t.Errorf("ParseCAInfos() = %+v, want %+v", infos, want)
+	}
+	if got := want.Key(); got != "RSA 4096" {
+		t.Errorf("Key() = %q, want RSA 4096", got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"fingerprint,operator,country,key_type,key_size,not_before,not_after\n" + hex + ",Op,ES,RSA,big,2011-07-07T15:00:00Z,2030-12-31T00:00:00Z\n",
+		"fingerprint,operator,country,key_type,key_size,not_before,not_after\n" + hex + ",Op,ES,RSA,4096,2011-07-07,2030-12-31T00:00:00Z\n",
+	} {
+		if _, err := ParseCAInfos(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseCAInfos(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestNewCAInfo(t *testing.T) {
+	t.Parallel()
+
+	for _, fp := range Certs.Fingerprints() {
+		cert := Certs.Get(fp)
+		if cert == nil {
+			continue
+		}
+		info := NewCAInfo(cert, "Operator")
+		if info.Fingerprint != fp || info.Operator != "Operator" || info.KeyType == "" || info.KeySize == 0 || !info.NotAfter.Equal(cert.NotAfter) {
+			t.Errorf("NewCAInfo(%s) = %+v", fp.Truncate(4), info)
+		}
+		return
+	}
+	t.Skip("no embedded certificates")
+}
+
+func TestLoadCAInfos(t *testing.T) {
+	t.Parallel()
+
+	if index, err := loadCAInfos(fstest.MapFS{}); err != nil || len(index) != 0 {
+		t.Errorf("loadCAInfos(no file) = %v, %v; want empty", index, err)
+	}
+}
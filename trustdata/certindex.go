@@ -0,0 +1,248 @@
+package trustdata
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CertIndex is a read-only fingerprint index over certificate CSV data.
+// Records are located at load time but parsed on first lookup, so startup cost
+// is independent of how many certificates a validation actually touches.
+type CertIndex struct {
+	data    []byte                 // Raw CSV data (embedded or memory-mapped)
+	records map[Fingerprint]record // PEM field location per fingerprint
+
+	mu     sync.Mutex
+	parsed map[Fingerprint]*x509.Certificate // One certificate per fingerprint, shared by all lookups
+	failed map[Fingerprint]error             // Records that failed to parse, so they are parsed once
+}
+
+// record locates an escaped PEM field within CertIndex.data.
+type record struct {
+	start, end int
+	quoted     bool // Field is CSV-quoted and must be unquoted before use
+}
+
+// NewCertIndex indexes certificate CSV data (format: fingerprint,pem with header).
+// data must not be modified after the call.
+func NewCertIndex(data []byte) (*CertIndex, error) {
+	idx := &CertIndex{
+		data:    data,
+		records: make(map[Fingerprint]record),
+		parsed:  make(map[Fingerprint]*x509.Certificate),
+		failed:  make(map[Fingerprint]error),
+	}
+
+	// Skip header
+	pos := bytes.IndexByte(data, '\n')
+	if pos < 0 {
+		return nil, fmt.Errorf("read header: missing newline")
+	}
+	pos++
+
+	for pos < len(data) {
+		end := bytes.IndexByte(data[pos:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += pos
+		}
+		line := bytes.TrimSuffix(data[pos:end], []byte("\r"))
+		lineStart := pos
+		pos = end + 1
+
+		if len(line) == 0 {
+			continue
+		}
+
+		comma := bytes.IndexByte(line, ',')
+		if comma < 0 {
+			return nil, fmt.Errorf("malformed record at offset %d", lineStart)
+		}
+
+		fpStr := string(line[:comma])
+		fp, err := parseCanonicalFingerprint(fpStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", fpStr, err)
+		}
+
+		field := line[comma+1:]
+		idx.records[fp] = record{
+			start:  lineStart + comma + 1,
+			end:    lineStart + comma + 1 + len(field),
+			quoted: len(field) > 0 && field[0] == '"',
+		}
+	}
+
+	return idx, nil
+}
+
+// Len returns the number of indexed certificates.
+func (c *CertIndex) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.records)
+	for fp := range c.parsed {
+		if _, ok := c.records[fp]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// Has reports whether a certificate is indexed, without parsing it.
+func (c *CertIndex) Has(fp Fingerprint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.records[fp]; ok {
+		return true
+	}
+	_, ok := c.parsed[fp]
+	return ok
+}
+
+// Get returns the certificate for a fingerprint, or nil if unknown or unparseable.
+func (c *CertIndex) Get(fp Fingerprint) *x509.Certificate {
+	cert, _ := c.Load(fp)
+	return cert
+}
+
+// Load returns the certificate for a fingerprint, parsing it on first access.
+// Returns (nil, nil) if the fingerprint is not indexed.
+func (c *CertIndex) Load(fp Fingerprint) (*x509.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.load(fp)
+}
+
+// load is Load for callers holding c.mu.
+func (c *CertIndex) load(fp Fingerprint) (*x509.Certificate, error) {
+	if cert, ok := c.parsed[fp]; ok {
+		return cert, nil
+	}
+	if err, ok := c.failed[fp]; ok {
+		return nil, err
+	}
+
+	rec, ok := c.records[fp]
+	if !ok {
+		return nil, nil
+	}
+
+	cert, err := c.parse(rec)
+	if err != nil {
+		err = fmt.Errorf("cert %s: %w", fp.Truncate(4), err)
+		c.failed[fp] = err
+		return nil, err
+	}
+
+	c.parsed[fp] = cert
+	return cert, nil
+}
+
+// LoadAll parses and returns every indexed certificate in fingerprint order.
+func (c *CertIndex) LoadAll() ([]*x509.Certificate, error) {
+	fps := c.Fingerprints()
+	certs := make([]*x509.Certificate, 0, len(fps))
+	for _, fp := range fps {
+		cert, err := c.Load(fp)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// Add registers an already parsed certificate, replacing any indexed entry.
+func (c *CertIndex) Add(fp Fingerprint, cert *x509.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.parsed[fp] = cert
+	delete(c.failed, fp)
+}
+
+// Intern returns the indexed certificate with the fingerprint of cert, adding cert if
+// there is none, so that a root loaded from several sources is held once. Certificates
+// with the same fingerprint have the same DER encoding, so either instance can be used.
+func (c *CertIndex) Intern(cert *x509.Certificate) *x509.Certificate {
+	fp := FingerprintFromCert(cert)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if indexed, err := c.load(fp); err == nil && indexed != nil {
+		return indexed
+	}
+	c.parsed[fp] = cert
+	delete(c.failed, fp)
+	return cert
+}
+
+// Fingerprints returns all indexed fingerprints in ascending order.
+func (c *CertIndex) Fingerprints() []Fingerprint {
+	c.mu.Lock()
+	fps := make([]Fingerprint, 0, len(c.records)+len(c.parsed))
+	for fp := range c.records {
+		fps = append(fps, fp)
+	}
+	for fp := range c.parsed {
+		if _, ok := c.records[fp]; !ok {
+			fps = append(fps, fp)
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(fps, func(i, j int) bool {
+		return bytes.Compare(fps[i][:], fps[j][:]) < 0
+	})
+	return fps
+}
+
+// parse decodes a PEM field. Caller must hold c.mu.
+func (c *CertIndex) parse(rec record) (*x509.Certificate, error) {
+	field := c.data[rec.start:rec.end]
+
+	if rec.quoted {
+		values, err := csv.NewReader(bytes.NewReader(field)).Read()
+		if err != nil {
+			return nil, fmt.Errorf("unquote PEM field: %w", err)
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("expected one PEM field, got %d", len(values))
+		}
+		field = []byte(values[0])
+	}
+
+	// Unescape newlines (stored as literal \n for single-line CSV records)
+	pemData := bytes.ReplaceAll(field, []byte(`\n`), []byte("\n"))
+
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cert: %w", err)
+	}
+	return cert, nil
+}
+
+// OpenCertIndex indexes an external certificate CSV file.
+// On unix the file is memory-mapped, so only the pages of looked-up records are read.
+func OpenCertIndex(path string) (*CertIndex, error) {
+	data, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertIndex(data)
+}
@@ -0,0 +1,170 @@
+package trustdata
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generateIndexCert(t *testing.T, cn string) (*x509.Certificate, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escaped := strings.ReplaceAll(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), "\n", `\n`)
+	return cert, escaped
+}
+
+func TestCertIndex(t *testing.T) {
+	certA, pemA := generateIndexCert(t, "A")
+	certB, pemB := generateIndexCert(t, "B")
+	fpA, fpB := FingerprintFromCert(certA), FingerprintFromCert(certB)
+	bad := FingerprintFromBytes(make([]byte, 32))
+
+	data := "fingerprint,pem\r\n" +
+		fpA.String() + "," + pemA + "\r\n" +
+		fpB.String() + `,"` + pemB + `"` + "\n" +
+		bad.String() + ",garbage\n"
+
+	idx, err := NewCertIndex([]byte(data))
+	if err != nil {
+		t.Fatalf("NewCertIndex: %v", err)
+	}
+
+	if idx.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", idx.Len())
+	}
+	if len(idx.parsed) != 0 {
+		t.Errorf("certificates parsed eagerly: %d", len(idx.parsed))
+	}
+
+	if got := idx.Get(fpA); got == nil || got.Subject.CommonName != "A" {
+		t.Errorf("Get(A) = %v", got)
+	}
+	if got := idx.Get(fpB); got == nil || got.Subject.CommonName != "B" {
+		t.Errorf("Get(B) (quoted) = %v", got)
+	}
+	if len(idx.parsed) != 2 {
+		t.Errorf("parsed = %d, want 2", len(idx.parsed))
+	}
+
+	if _, err := idx.Load(bad); err == nil {
+		t.Error("expected error for malformed PEM")
+	}
+	if _, ok := idx.failed[bad]; !ok {
+		t.Error("parse failure not recorded")
+	}
+	if !idx.Has(bad) {
+		t.Error("Has should report indexed entry without parsing")
+	}
+
+	unknown := FingerprintFromBytes([]byte(strings.Repeat("x", 32)))
+	if cert, err := idx.Load(unknown); cert != nil || err != nil {
+		t.Errorf("Load(unknown) = %v, %v", cert, err)
+	}
+
+	// Added certificates are visible to lookups and enumeration
+	certC, _ := generateIndexCert(t, "C")
+	idx.Add(unknown, certC)
+	if idx.Get(unknown) != certC || idx.Len() != 4 || len(idx.Fingerprints()) != 4 {
+		t.Error("Add not reflected in Get/Len/Fingerprints")
+	}
+
+	// Interning a copy of an indexed certificate returns the indexed instance
+	copyA, err := x509.ParseCertificate(certA.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.Intern(copyA); got != idx.Get(fpA) {
+		t.Error("Intern(copy of A) did not return the indexed instance")
+	}
+	certD, _ := generateIndexCert(t, "D")
+	if got := idx.Intern(certD); got != certD || idx.Get(FingerprintFromCert(certD)) != certD {
+		t.Error("Intern(new) did not add the certificate")
+	}
+}
+
+func TestCertIndexQuotedFieldCount(t *testing.T) {
+	fp := FingerprintFromBytes(make([]byte, 32))
+	idx, err := NewCertIndex([]byte("fingerprint,pem\n" + fp.String() + `,"a","b"` + "\n"))
+	if err != nil {
+		t.Fatalf("NewCertIndex: %v", err)
+	}
+	_, err = idx.Load(fp)
+	if err == nil || !strings.Contains(err.Error(), "expected one PEM field, got 2") {
+		t.Errorf("Load() error = %v, want field count error", err)
+	}
+}
+
+func TestNewCertIndexErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no header newline", "fingerprint,pem"},
+		{"missing comma", "fingerprint,pem\nnocomma\n"},
+		{"bad fingerprint", "fingerprint,pem\nzz,pem\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCertIndex([]byte(tt.data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestOpenCertIndex(t *testing.T) {
+	cert, pemData := generateIndexCert(t, "File")
+	fp := FingerprintFromCert(cert)
+
+	path := filepath.Join(t.TempDir(), "certificates.csv")
+	if err := os.WriteFile(path, []byte("fingerprint,pem\n"+fp.String()+","+pemData+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := OpenCertIndex(path)
+	if err != nil {
+		t.Fatalf("OpenCertIndex: %v", err)
+	}
+	if got := idx.Get(fp); got == nil || got.Subject.CommonName != "File" {
+		t.Errorf("Get = %v", got)
+	}
+}
+
+func BenchmarkLoadCertificates(b *testing.B) {
+	data, err := dataFS.ReadFile("data/certificates.csv")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := NewCertIndex(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package trustdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"time"
+)
+
+// CT log states from Google's log list (https://www.gstatic.com/ct/log_list/v3/log_list.json).
+const (
+	CTLogPending   = "pending"
+	CTLogQualified = "qualified"
+	CTLogUsable    = "usable"
+	CTLogReadOnly  = "readonly"
+	CTLogRetired   = "retired"
+	CTLogRejected  = "rejected"
+)
+
+// CTLog describes a Certificate Transparency log and its current state.
+type CTLog struct {
+	ID          [32]byte      // SHA-256 of the log's public key (matches SCT.LogID)
+	Operator    string        // Log operator (e.g., "Google")
+	Description string        // Log name (e.g., "Google 'Argon2025h1' log")
+	State       string        // One of CTLog* constants
+	StateSince  time.Time     // When the log entered State
+	MMD         time.Duration // Maximum merge delay promised for incorporating SCTs (0 if unknown)
+}
+
+// AcceptsSCT reports whether an SCT issued by the log at ts counts toward CT compliance.
+// SCTs from retired logs only count if issued before retirement.
+func (l CTLog) AcceptsSCT(ts time.Time) bool {
+	switch l.State {
+	case CTLogQualified, CTLogUsable, CTLogReadOnly:
+		return true
+	case CTLogRetired:
+		return ts.Before(l.StateSince)
+	}
+	return false
+}
+
+// CTLogs indexes known CT logs by log ID. Empty if the embedded log list has no entries,
+// in which case SCTs are counted without log state checks.
+var CTLogs map[[32]byte]CTLog
+
+// loadCTLogs indexes CT logs from ctlogs.csv in fsys.
+// CSV format: log_id (base64),operator,description,state,state_since,mmd (seconds)
+func loadCTLogs(fsys fs.FS) (map[[32]byte]CTLog, error) {
+	data, err := fs.ReadFile(fsys, "ctlogs.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := ParseCTLogs(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[[32]byte]CTLog, len(logs))
+	for _, l := range logs {
+		index[l.ID] = l
+	}
+	return index, nil
+}
+
+// ParseCTLogs reads CT logs from a ctlogs CSV (with header).
+func ParseCTLogs(reader io.Reader) ([]CTLog, error) {
+	r := csv.NewReader(reader)
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var logs []CTLog
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		id, err := base64.StdEncoding.DecodeString(record[0])
+		if err != nil || len(id) != 32 {
+			return nil, fmt.Errorf("parse log_id %s: invalid SHA-256", record[0])
+		}
+
+		l := CTLog{Operator: record[1], Description: record[2], State: record[3]}
+		copy(l.ID[:], id)
+		if record[4] != "" {
+			if l.StateSince, err = time.Parse(time.RFC3339, record[4]); err != nil {
+				return nil, fmt.Errorf("parse state_since %s: %w", record[4], err)
+			}
+		}
+		if record[5] != "" {
+			seconds, err := strconv.Atoi(record[5])
+			if err != nil || seconds < 0 {
+				return nil, fmt.Errorf("parse mmd %s: invalid seconds", record[5])
+			}
+			l.MMD = time.Duration(seconds) * time.Second
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
@@ -0,0 +1,63 @@
+package trustdata
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCTLogs(t *testing.T) {
+	t.Parallel()
+
+	csv := "log_id,operator,description,state,state_since,mmd\n" +
+		"AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=,Google,Google 'Argon2025h1' log,retired,2025-07-01T00:00:00Z,86400\n" +
+		"AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI=,Let's Encrypt,Let's Encrypt 'Oak2026h1' log,usable,,\n"
+
+	logs, err := ParseCTLogs(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("ParseCTLogs() returned %d logs, want 2", len(logs))
+	}
+	if logs[0].ID != [32]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1} ||
+		logs[0].State != CTLogRetired || logs[0].StateSince.IsZero() || logs[0].MMD != 24*time.Hour {
+		t.Errorf("logs[0] = %+v", logs[0])
+	}
+	if logs[1].Operator != "Let's Encrypt" || !logs[1].StateSince.IsZero() || logs[1].MMD != 0 {
+		t.Errorf("logs[1] = %+v", logs[1])
+	}
+
+	if _, err := ParseCTLogs(strings.NewReader("header\nnot-base64,a,b,usable,,\n")); err == nil {
+		t.Error("expected error for invalid log ID")
+	}
+	if _, err := ParseCTLogs(strings.NewReader("header\nAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=,a,b,usable,,1d\n")); err == nil {
+		t.Error("expected error for invalid mmd")
+	}
+}
+
+func TestCTLogAcceptsSCT(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		state string
+		ts    time.Time
+		want  bool
+	}{
+		{CTLogUsable, since.Add(time.Hour), true},
+		{CTLogQualified, since.Add(time.Hour), true},
+		{CTLogReadOnly, since.Add(time.Hour), true},
+		{CTLogRetired, since.Add(-time.Hour), true},
+		{CTLogRetired, since.Add(time.Hour), false},
+		{CTLogPending, since.Add(time.Hour), false},
+		{CTLogRejected, since.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		l := CTLog{State: tt.state, StateSince: since}
+		if got := l.AcceptsSCT(tt.ts); got != tt.want {
+			t.Errorf("CTLog{State: %s}.AcceptsSCT(%s) = %v, want %v", tt.state, tt.ts.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
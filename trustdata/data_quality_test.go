@@ -0,0 +1,78 @@
+package trustdata
+
+import (
+	"strings"
+	"testing"
+)
+
+// reportProblems fails t with each problem found by a CheckQuality check.
+func reportProblems(t *testing.T, problems []string) {
+	t.Helper()
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+func TestDataQuality_CertificateCount(t *testing.T) {
+	reportProblems(t, checkCertificateCount(Certs))
+}
+
+func TestDataQuality_CertificatesParse(t *testing.T) {
+	reportProblems(t, checkCertificatesParse(Certs))
+}
+
+func TestDataQuality_AllPlatformsPresent(t *testing.T) {
+	reportProblems(t, checkAllPlatformsPresent(Stores))
+}
+
+func TestDataQuality_MinCertsPerStore(t *testing.T) {
+	reportProblems(t, checkMinCertsPerStore(Stores))
+}
+
+func TestDataQuality_MinVersionsPerPlatform(t *testing.T) {
+	reportProblems(t, checkMinVersionsPerPlatform(Stores))
+}
+
+// TestDataQuality_AllCertsAreUsed ensures no orphaned certificates exist.
+// Every cert in Certs should be referenced by at least one store.
+func TestDataQuality_AllCertsAreUsed(t *testing.T) {
+	reportProblems(t, checkAllCertsAreUsed(Stores, Certs))
+}
+
+// TestDataQuality_NoDuplicateStoreEntries ensures no duplicate (platform, version, fingerprint) tuples.
+func TestDataQuality_NoDuplicateStoreEntries(t *testing.T) {
+	reportProblems(t, checkNoDuplicateStoreEntries(Stores))
+}
+
+// TestDataQuality_ConstraintDatesReasonable ensures all constraint dates are within sane bounds.
+func TestDataQuality_ConstraintDatesReasonable(t *testing.T) {
+	reportProblems(t, checkConstraintDatesReasonable(Stores))
+}
+
+// TestDataQuality_VersionFormat ensures all version strings are valid.
+func TestDataQuality_VersionFormat(t *testing.T) {
+	reportProblems(t, checkVersionFormat(Stores))
+}
+
+func TestCheckQuality(t *testing.T) {
+	t.Parallel()
+
+	if problems := CheckQuality(Stores, Certs); len(problems) != 0 {
+		t.Errorf("embedded data has problems: %v", problems)
+	}
+
+	fp := Stores[0].Fingerprints[0]
+	broken := []Store{
+		{Platform: PlatformIOS, Version: "18 beta", Fingerprints: []Fingerprint{fp, fp}},
+	}
+	problems := CheckQuality(broken, Certs)
+	for _, want := range []string{"invalid version format", "duplicate entry", "platform \"android\" not found", "orphaned certificate"} {
+		found := false
+		for _, p := range problems {
+			found = found || strings.Contains(p, want)
+		}
+		if !found {
+			t.Errorf("CheckQuality() = %v, want a problem containing %q", problems, want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Package trustdata provides certvet's curated root trust store dataset: the trusted roots
+// and their constraints for each platform version, the root and cross-signed intermediate
+// certificates, and the Certificate Transparency log list.
+//
+// The data is embedded and loaded at init, so importing the package is enough to query it:
+//
+//	store, ok := trustdata.Lookup(trustdata.PlatformIOS, "18")
+//	for _, s := range trustdata.Index.Trusting(fp) {
+//		fmt.Println(s.Platform, s.Version, s.ConstraintFor(fp))
+//	}
+//
+// The module is versioned separately from the certvet CLI, which consumes the same data.
+package trustdata
@@ -0,0 +1,87 @@
+package trustdata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// EVPolicy is a policy OID for which a root program treats a root as issuing Extended
+// Validation certificates.
+type EVPolicy struct {
+	Fingerprint Fingerprint
+	Program     string // One of Program* constants
+	OID         string // Dotted policy OID, e.g. "2.23.140.1.1"
+}
+
+// EVPolicies indexes EV-enabled policy OIDs by root fingerprint, then program. Empty if the
+// data has none, in which case no chain is recognized as EV.
+var EVPolicies map[Fingerprint]map[string][]string
+
+// EVPolicyOIDs returns the policy OIDs for which the root program governing platform's
+// store treats root fp as EV-enabled (nil if none, or the platform has no root program).
+func EVPolicyOIDs(platform Platform, fp Fingerprint) []string {
+	program := ProgramOf(platform)
+	if program == "" {
+		return nil
+	}
+	return EVPolicies[fp][program]
+}
+
+// loadEVPolicies indexes EV policy OIDs from evpolicies.csv in fsys. Data generated before
+// they were recorded has none.
+func loadEVPolicies(fsys fs.FS) (map[Fingerprint]map[string][]string, error) {
+	index := make(map[Fingerprint]map[string][]string)
+	data, err := fs.ReadFile(fsys, "evpolicies.csv")
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := ParseEVPolicies(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		if index[p.Fingerprint] == nil {
+			index[p.Fingerprint] = make(map[string][]string)
+		}
+		index[p.Fingerprint][p.Program] = append(index[p.Fingerprint][p.Program], p.OID)
+	}
+	return index, nil
+}
+
+// ParseEVPolicies reads EV policy OIDs from an evpolicies CSV (with header).
+// CSV format: fingerprint,program,oid
+func ParseEVPolicies(reader io.Reader) ([]EVPolicy, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = 3
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var policies []EVPolicy
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		fp, err := ParseFingerprint(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", record[0], err)
+		}
+		policies = append(policies, EVPolicy{Fingerprint: fp, Program: record[1], OID: record[2]})
+	}
+	return policies, nil
+}
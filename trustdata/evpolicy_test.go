@@ -0,0 +1,50 @@
+package trustdata
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadEVPolicies(t *testing.T) {
+	t.Parallel()
+
+	hex := strings.Repeat("AB", 32)
+	fp := mustParseFingerprint(t, hex)
+	fsys := fstest.MapFS{"evpolicies.csv": {Data: []byte("fingerprint,program,oid\n" +
+		hex + ",chrome,2.23.140.1.1\n" + hex + ",microsoft,2.23.140.1.1\n" + hex + ",microsoft,1.3.6.1.4.1.34697.2.1\n")}}
+	index, err := loadEVPolicies(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := index[fp][ProgramMicrosoft]; !slices.Equal(got, []string{"2.23.140.1.1", "1.3.6.1.4.1.34697.2.1"}) {
+		t.Errorf("microsoft EV OIDs = %v", got)
+	}
+
+	// Data generated before EV policies were recorded has none
+	if index, err := loadEVPolicies(fstest.MapFS{}); err != nil || len(index) != 0 {
+		t.Errorf("loadEVPolicies(no file) = %v, %v; want empty", index, err)
+	}
+	if _, err := ParseEVPolicies(strings.NewReader("fingerprint,program,oid\nAA,chrome\n")); err == nil {
+		t.Error("ParseEVPolicies() accepted a short record")
+	}
+}
+
+// TestEVPolicyOIDs replaces package data and must not run in parallel.
+func TestEVPolicyOIDs(t *testing.T) {
+	fp := Fingerprint{0xAA}
+	saved := EVPolicies
+	t.Cleanup(func() { EVPolicies = saved })
+	EVPolicies = map[Fingerprint]map[string][]string{fp: {ProgramApple: {"2.23.140.1.1"}}}
+
+	if got := EVPolicyOIDs(PlatformMacOS, fp); !slices.Equal(got, []string{"2.23.140.1.1"}) {
+		t.Errorf("EVPolicyOIDs(macos) = %v", got)
+	}
+	if got := EVPolicyOIDs(PlatformWindows, fp); got != nil {
+		t.Errorf("EVPolicyOIDs(windows) = %v, want none", got)
+	}
+	if got := EVPolicyOIDs(PlatformAndroid, fp); got != nil {
+		t.Errorf("EVPolicyOIDs(android) = %v, want none", got)
+	}
+}
@@ -1,4 +1,4 @@
-package truststore
+package trustdata
 
 import (
 	"crypto/sha256"
@@ -100,6 +100,12 @@ func FingerprintFromCert(cert *x509.Certificate) Fingerprint {
 	return Fingerprint(sha256.Sum256(cert.Raw))
 }
 
+// SPKIFingerprint computes the SHA-256 hash of a certificate's SubjectPublicKeyInfo, which is
+// shared by re-issued and cross-signed certificates for the same key (as in HPKP pin-sha256).
+func SPKIFingerprint(cert *x509.Certificate) Fingerprint {
+	return Fingerprint(sha256.Sum256(cert.RawSubjectPublicKeyInfo))
+}
+
 // FingerprintFromBytes creates a Fingerprint from raw bytes.
 // Panics if bytes is not exactly 32 bytes.
 func FingerprintFromBytes(bytes []byte) Fingerprint {
@@ -141,3 +147,36 @@ func (f Fingerprint) Truncate(octets int) string {
 	}
 	return strings.Join(parts, ":") + "..."
 }
+
+// MarshalText implements encoding.TextMarshaler using the canonical format.
+func (f Fingerprint) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler accepting any ParseFingerprint format.
+func (f *Fingerprint) UnmarshalText(text []byte) error {
+	fp, err := ParseFingerprint(string(text))
+	if err != nil {
+		return err
+	}
+	*f = fp
+	return nil
+}
+
+// parseCanonicalFingerprint is a fast path for the canonical "AA:BB:..." format used
+// in embedded data, falling back to ParseFingerprint for anything else.
+func parseCanonicalFingerprint(s string) (Fingerprint, error) {
+	var fp Fingerprint
+	if len(s) != sha256Pairs*3-1 {
+		return ParseFingerprint(s)
+	}
+	for i := range sha256Pairs {
+		if i > 0 && s[i*3-1] != ':' {
+			return ParseFingerprint(s)
+		}
+		if _, err := hex.Decode(fp[i:i+1], []byte(s[i*3:i*3+2])); err != nil {
+			return ParseFingerprint(s)
+		}
+	}
+	return fp, nil
+}
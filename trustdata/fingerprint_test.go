@@ -1,8 +1,10 @@
-package truststore
+package trustdata
 
 import (
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"strings"
 	"testing"
 )
 
@@ -232,4 +234,62 @@ WD9f
 	if len(str) != 95 { // 32 pairs * 2 + 31 colons = 95
 		t.Errorf("FingerprintFromCert.String() length = %d, want 95", len(str))
 	}
+
+	// Matches the well-known pin-sha256 of GlobalSign Root CA - R3
+	spki := SPKIFingerprint(cert)
+	if got := base64.StdEncoding.EncodeToString(spki[:]); got != "cGuxAXyFXFkWm61cF4HPWX8S0srS9j0aSqN0k4AP+4A=" {
+		t.Errorf("SPKIFingerprint() = %s", got)
+	}
+}
+
+func TestFingerprintTextRoundTrip(t *testing.T) {
+	fp, err := ParseFingerprint(validSHA256)
+	if err != nil {
+		t.Fatalf("ParseFingerprint: %v", err)
+	}
+
+	text, err := fp.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if string(text) != validSHA256Formatted {
+		t.Errorf("MarshalText() = %q, want %q", text, validSHA256Formatted)
+	}
+
+	var got Fingerprint
+	if err := got.UnmarshalText([]byte(validSHA256)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if got != fp {
+		t.Errorf("UnmarshalText() = %s, want %s", got, fp)
+	}
+
+	if err := got.UnmarshalText([]byte("not-a-fingerprint")); err == nil {
+		t.Error("UnmarshalText should reject invalid input")
+	}
+}
+
+func TestParseCanonicalFingerprint(t *testing.T) {
+	canonical := "D7:A7:A0:FB:5D:7E:27:31:D7:71:E9:48:4E:BC:DE:F7:1D:5F:0C:3E:0A:29:48:78:2B:C8:3E:E0:EA:69:9E:F4"
+	inputs := []string{
+		canonical,
+		strings.ToLower(canonical),
+		strings.ReplaceAll(canonical, ":", "-"), // Falls back to ParseFingerprint
+		strings.ReplaceAll(canonical, ":", ""),
+	}
+
+	want, err := ParseFingerprint(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range inputs {
+		got, err := parseCanonicalFingerprint(in)
+		if err != nil || got != want {
+			t.Errorf("parseCanonicalFingerprint(%q) = %v, %v", in, got, err)
+		}
+	}
+
+	if _, err := parseCanonicalFingerprint(strings.Replace(canonical, "D7", "ZZ", 1)); err == nil {
+		t.Error("expected error for invalid hex")
+	}
 }
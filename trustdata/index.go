@@ -0,0 +1,76 @@
+package trustdata
+
+// StoreKey identifies a store by platform and version.
+type StoreKey struct {
+	Platform Platform
+	Version  string
+}
+
+// StoreIndex maps root fingerprints to the stores that include them, so membership
+// queries don't scan every store. It is immutable once built and safe for concurrent use.
+type StoreIndex struct {
+	stores []Store
+	byRoot map[Fingerprint][]int // Positions in stores, ascending
+	byKey  map[StoreKey]int      // Position of each store in stores
+	member map[rootInStore]bool
+}
+
+// rootInStore is a root included by the store at a position.
+type rootInStore struct {
+	fp    Fingerprint
+	store int
+}
+
+// Index indexes Stores. Use replaces it along with Stores.
+var Index *StoreIndex
+
+// NewStoreIndex indexes the roots of stores.
+func NewStoreIndex(stores []Store) *StoreIndex {
+	idx := &StoreIndex{
+		stores: stores,
+		byRoot: make(map[Fingerprint][]int),
+		byKey:  make(map[StoreKey]int, len(stores)),
+		member: make(map[rootInStore]bool),
+	}
+	for i, s := range stores {
+		idx.byKey[StoreKey{Platform: s.Platform, Version: s.Version}] = i
+		for _, fp := range s.Fingerprints {
+			if !idx.member[rootInStore{fp, i}] {
+				idx.member[rootInStore{fp, i}] = true
+				idx.byRoot[fp] = append(idx.byRoot[fp], i)
+			}
+		}
+	}
+	return idx
+}
+
+// Contains reports whether any indexed store includes the root with fingerprint fp.
+func (idx *StoreIndex) Contains(fp Fingerprint) bool {
+	return len(idx.byRoot[fp]) > 0
+}
+
+// Includes reports whether the store of key includes the root with fingerprint fp.
+func (idx *StoreIndex) Includes(key StoreKey, fp Fingerprint) bool {
+	i, ok := idx.byKey[key]
+	return ok && idx.member[rootInStore{fp, i}]
+}
+
+// Keys returns the platform versions whose stores include the root with fingerprint fp,
+// in index order.
+func (idx *StoreIndex) Keys(fp Fingerprint) []StoreKey {
+	var out []StoreKey
+	for _, i := range idx.byRoot[fp] {
+		out = append(out, StoreKey{Platform: idx.stores[i].Platform, Version: idx.stores[i].Version})
+	}
+	return out
+}
+
+// Trusting returns the indexed stores that include the root with fingerprint fp, in index
+// order, like Trusting over the indexed stores.
+func (idx *StoreIndex) Trusting(fp Fingerprint) []Store {
+	var out []Store
+	for _, i := range idx.byRoot[fp] {
+		out = append(out, idx.stores[i])
+	}
+	return out
+}
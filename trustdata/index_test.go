@@ -0,0 +1,63 @@
+package trustdata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStoreIndex(t *testing.T) {
+	t.Parallel()
+
+	fp1, fp2, fp3 := Fingerprint{0x01}, Fingerprint{0x02}, Fingerprint{0x03}
+	stores := []Store{
+		{Platform: PlatformAndroid, Version: "14", Fingerprints: []Fingerprint{fp1, fp2}},
+		{Platform: PlatformAndroid, Version: "10", Fingerprints: []Fingerprint{fp2}},
+		{Platform: PlatformChrome, Version: "current", Fingerprints: []Fingerprint{fp1}},
+	}
+	idx := NewStoreIndex(stores)
+
+	if !idx.Contains(fp1) || !idx.Contains(fp2) || idx.Contains(fp3) {
+		t.Error("Contains should find only roots of some store")
+	}
+	tests := []struct {
+		key  StoreKey
+		fp   Fingerprint
+		want bool
+	}{
+		{StoreKey{PlatformAndroid, "14"}, fp1, true},
+		{StoreKey{PlatformAndroid, "10"}, fp1, false},
+		{StoreKey{PlatformAndroid, "10"}, fp2, true},
+		{StoreKey{PlatformAndroid, "7"}, fp2, false},
+		{StoreKey{PlatformChrome, "current"}, fp3, false},
+	}
+	for _, tt := range tests {
+		if got := idx.Includes(tt.key, tt.fp); got != tt.want {
+			t.Errorf("Includes(%v, %s) = %v, want %v", tt.key, tt.fp.Truncate(1), got, tt.want)
+		}
+	}
+
+	want := []StoreKey{{PlatformAndroid, "14"}, {PlatformChrome, "current"}}
+	if got := idx.Keys(fp1); !slices.Equal(got, want) {
+		t.Errorf("Keys(fp1) = %v, want %v", got, want)
+	}
+	for _, fp := range []Fingerprint{fp1, fp2, fp3} {
+		got, want := idx.Trusting(fp), Trusting(stores, fp)
+		if len(got) != len(want) {
+			t.Fatalf("Trusting(%s) = %d stores, want %d", fp.Truncate(1), len(got), len(want))
+		}
+		for i := range got {
+			if got[i].Platform != want[i].Platform || got[i].Version != want[i].Version {
+				t.Errorf("Trusting(%s)[%d] = %s %s, want %s %s", fp.Truncate(1), i, got[i].Platform, got[i].Version, want[i].Platform, want[i].Version)
+			}
+		}
+	}
+
+	// The package index covers the embedded stores
+	for _, s := range Stores[:3] {
+		for _, fp := range s.Fingerprints {
+			if !Index.Includes(StoreKey{s.Platform, s.Version}, fp) {
+				t.Fatalf("Index misses %s in %s %s", fp.Truncate(4), s.Platform, s.Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package trustdata
+
+import "os"
+
+// mapFile reads a file into memory on platforms without mmap support.
+func mapFile(path string) ([]byte, error) {
+	return os.ReadFile(path) //nolint:gosec // G304: Caller-specified data file
+}
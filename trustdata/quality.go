@@ -0,0 +1,213 @@
+package trustdata
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// allPlatforms lists every platform that must be present in the trust store data.
+var allPlatforms = []Platform{
+	PlatformIOS,
+	PlatformIPadOS,
+	PlatformMacOS,
+	PlatformTVOS,
+	PlatformVisionOS,
+	PlatformWatchOS,
+	PlatformAndroid,
+	PlatformChrome,
+	PlatformWindows,
+	PlatformWinContainer,
+}
+
+// versionPattern matches valid version strings: "current" or semver-like (e.g., "18", "17.4", "12.1.3")
+var versionPattern = regexp.MustCompile(`^(current|\d+(\.\d+)*)$`)
+
+// maxReported caps the problems reported per check for problems that tend to come in bulk.
+const maxReported = 5
+
+// CheckQuality runs the sanity checks a complete dataset must pass: enough certificates,
+// stores and versions, every platform present, certificates matching their fingerprints,
+// no orphaned certificates or duplicate store entries, and sane constraint dates and
+// version strings. It returns the problems found, if any.
+func CheckQuality(stores []Store, certs *CertIndex) []string {
+	var problems []string
+	for _, check := range [][]string{
+		checkCertificateCount(certs),
+		checkCertificatesParse(certs),
+		checkAllPlatformsPresent(stores),
+		checkMinCertsPerStore(stores),
+		checkMinVersionsPerPlatform(stores),
+		checkAllCertsAreUsed(stores, certs),
+		checkNoDuplicateStoreEntries(stores),
+		checkConstraintDatesReasonable(stores),
+		checkVersionFormat(stores),
+	} {
+		problems = append(problems, check...)
+	}
+	return problems
+}
+
+func checkCertificateCount(certs *CertIndex) []string {
+	const minCerts = 500
+	if certs.Len() <= minCerts {
+		return []string{fmt.Sprintf("expected more than %d certificates, got %d", minCerts, certs.Len())}
+	}
+	return nil
+}
+
+func checkCertificatesParse(certs *CertIndex) []string {
+	var problems []string
+	for _, fp := range certs.Fingerprints() {
+		cert, err := certs.Load(fp)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("load %s: %v", fp.Truncate(4), err))
+			continue
+		}
+		if got := FingerprintFromCert(cert); got != fp {
+			problems = append(problems, fmt.Sprintf("cert %s: fingerprint mismatch, got %s", fp.Truncate(4), got.Truncate(4)))
+		}
+	}
+	return problems
+}
+
+func checkAllPlatformsPresent(stores []Store) []string {
+	presentPlatforms := make(map[Platform]bool)
+	for _, store := range stores {
+		presentPlatforms[store.Platform] = true
+	}
+
+	var problems []string
+	for _, platform := range allPlatforms {
+		if !presentPlatforms[platform] {
+			problems = append(problems, fmt.Sprintf("platform %q not found in stores", platform))
+		}
+	}
+	return problems
+}
+
+func checkMinCertsPerStore(stores []Store) []string {
+	const minCerts = 50
+	const minTrimmedCerts = 5 // Curated stores that intentionally ship few roots
+	var problems []string
+	for _, store := range stores {
+		limit := minCerts
+		if store.Platform == PlatformWinContainer {
+			limit = minTrimmedCerts
+		}
+		if len(store.Fingerprints) <= limit {
+			problems = append(problems, fmt.Sprintf("%s/%s has only %d certs, expected > %d",
+				store.Platform, store.Version, len(store.Fingerprints), limit))
+		}
+	}
+	return problems
+}
+
+func checkMinVersionsPerPlatform(stores []Store) []string {
+	const minVersions = 5
+	versionCount := make(map[Platform]int)
+	for _, store := range stores {
+		versionCount[store.Platform]++
+	}
+
+	var problems []string
+	for _, platform := range []Platform{PlatformAndroid, PlatformIOS} {
+		if versionCount[platform] <= minVersions {
+			problems = append(problems, fmt.Sprintf("%s has only %d versions, expected > %d",
+				platform, versionCount[platform], minVersions))
+		}
+	}
+	return problems
+}
+
+// checkAllCertsAreUsed reports orphaned certificates, not referenced by any store.
+func checkAllCertsAreUsed(stores []Store, certs *CertIndex) []string {
+	usedFingerprints := make(map[Fingerprint]bool)
+	for _, store := range stores {
+		for _, fp := range store.Fingerprints {
+			usedFingerprints[fp] = true
+		}
+	}
+
+	var problems []string
+	var orphanCount int
+	for _, fp := range certs.Fingerprints() {
+		if !usedFingerprints[fp] {
+			orphanCount++
+			if orphanCount <= maxReported {
+				problems = append(problems, fmt.Sprintf("orphaned certificate not used by any store: %s", fp.Truncate(4)))
+			}
+		}
+	}
+	if orphanCount > maxReported {
+		problems = append(problems, fmt.Sprintf("... and %d more orphaned certificates", orphanCount-maxReported))
+	}
+	return problems
+}
+
+// checkNoDuplicateStoreEntries reports duplicate (platform, version, fingerprint) tuples.
+func checkNoDuplicateStoreEntries(stores []Store) []string {
+	type storeKey struct {
+		platform    Platform
+		version     string
+		fingerprint Fingerprint
+	}
+
+	seen := make(map[storeKey]bool)
+	var problems []string
+	var dupCount int
+	for _, store := range stores {
+		for _, fp := range store.Fingerprints {
+			key := storeKey{store.Platform, store.Version, fp}
+			if seen[key] {
+				dupCount++
+				if dupCount <= maxReported {
+					problems = append(problems, fmt.Sprintf("duplicate entry: %s/%s/%s", store.Platform, store.Version, fp.Truncate(4)))
+				}
+			}
+			seen[key] = true
+		}
+	}
+	if dupCount > maxReported {
+		problems = append(problems, fmt.Sprintf("... and %d more duplicate entries", dupCount-maxReported))
+	}
+	return problems
+}
+
+// checkConstraintDatesReasonable reports constraint dates outside sane bounds.
+func checkConstraintDatesReasonable(stores []Store) []string {
+	minDate := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDate := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var problems []string
+	for _, store := range stores {
+		for fp, constraints := range store.Constraints {
+			for _, c := range []struct {
+				name string
+				date *time.Time
+			}{
+				{"NotBeforeMax", constraints.NotBeforeMax},
+				{"DistrustDate", constraints.DistrustDate},
+				{"SCTNotAfter", constraints.SCTNotAfter},
+			} {
+				if c.date != nil && (c.date.Before(minDate) || c.date.After(maxDate)) {
+					problems = append(problems, fmt.Sprintf("%s/%s/%s: %s %v outside reasonable range",
+						store.Platform, store.Version, fp.Truncate(4), c.name, c.date))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// checkVersionFormat reports invalid version strings.
+// Valid formats: "current" or semver-like patterns (e.g., "18", "17.4", "12.1.3")
+func checkVersionFormat(stores []Store) []string {
+	var problems []string
+	for _, store := range stores {
+		if !versionPattern.MatchString(store.Version) {
+			problems = append(problems, fmt.Sprintf("%s has invalid version format: %q", store.Platform, store.Version))
+		}
+	}
+	return problems
+}
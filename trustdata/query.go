@@ -0,0 +1,48 @@
+package trustdata
+
+import "sort"
+
+// Includes reports whether the store trusts the root with fingerprint fp.
+func (s Store) Includes(fp Fingerprint) bool {
+	for _, f := range s.Fingerprints {
+		if f == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the embedded store for a platform version.
+func Lookup(platform Platform, version string) (Store, bool) {
+	for _, s := range Stores {
+		if s.Platform == platform && s.Version == version {
+			return s, true
+		}
+	}
+	return Store{}, false
+}
+
+// Platforms returns the platforms present in the embedded stores, sorted by name.
+func Platforms() []Platform {
+	seen := make(map[Platform]bool)
+	var out []Platform
+	for _, s := range Stores {
+		if !seen[s.Platform] {
+			seen[s.Platform] = true
+			out = append(out, s.Platform)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Trusting returns the stores among stores that include the root with fingerprint fp, in input order.
+func Trusting(stores []Store, fp Fingerprint) []Store {
+	var out []Store
+	for _, s := range stores {
+		if s.Includes(fp) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package trustdata
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	store, ok := Lookup(PlatformChrome, "current")
+	if !ok || len(store.Fingerprints) == 0 {
+		t.Fatalf("Lookup(chrome, current) = %v, %v", store.Platform, ok)
+	}
+	if _, ok := Lookup(PlatformChrome, "0"); ok {
+		t.Error("Lookup(chrome, 0) found a store")
+	}
+
+	fp := store.Fingerprints[0]
+	if !store.Includes(fp) || store.Includes(Fingerprint{}) {
+		t.Errorf("Includes mismatch for %s", fp.Truncate(4))
+	}
+	trusting := Trusting(Stores, fp)
+	found := false
+	for _, s := range trusting {
+		if !s.Includes(fp) {
+			t.Errorf("Trusting returned %s %s without the root", s.Platform, s.Version)
+		}
+		found = found || (s.Platform == PlatformChrome && s.Version == "current")
+	}
+	if !found {
+		t.Error("Trusting omitted chrome current")
+	}
+
+	platforms := Platforms()
+	if len(platforms) != len(allPlatforms) {
+		t.Errorf("Platforms() = %v, want %d platforms", platforms, len(allPlatforms))
+	}
+	for i := 1; i < len(platforms); i++ {
+		if platforms[i-1] >= platforms[i] {
+			t.Errorf("Platforms() not sorted: %v", platforms)
+		}
+	}
+}
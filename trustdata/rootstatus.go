@@ -0,0 +1,113 @@
+package trustdata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// Root inclusion statuses in a root program, from CCADB's root certificate records.
+const (
+	RootIncluded         = "included"
+	RootPendingInclusion = "pending_inclusion"
+	RootPendingRemoval   = "pending_removal"
+	RootRemoved          = "removed"
+)
+
+// Root programs reporting inclusion statuses to CCADB.
+const (
+	ProgramApple     = "apple"
+	ProgramChrome    = "chrome"
+	ProgramMicrosoft = "microsoft"
+	ProgramMozilla   = "mozilla"
+)
+
+// RootStatus is the inclusion status of a root in one root program.
+type RootStatus struct {
+	Fingerprint Fingerprint
+	Program     string // One of Program* constants
+	Status      string // One of Root* constants
+}
+
+// RootStatuses indexes root inclusion statuses by fingerprint, then program. Empty if the
+// data has none, in which case no root is reported pending removal.
+var RootStatuses map[Fingerprint]map[string]string
+
+// ProgramOf returns the root program governing platform's store (empty if none reports to
+// CCADB, as for Android and custom stores).
+func ProgramOf(platform Platform) string {
+	switch platform {
+	case PlatformIOS, PlatformIPadOS, PlatformMacOS, PlatformTVOS, PlatformVisionOS, PlatformWatchOS:
+		return ProgramApple
+	case PlatformChrome:
+		return ProgramChrome
+	case PlatformWindows, PlatformWinContainer:
+		return ProgramMicrosoft
+	}
+	return ""
+}
+
+// PendingRemoval reports whether the root program governing platform has announced the
+// removal of root fp, so stores of the platform will stop trusting it.
+func PendingRemoval(platform Platform, fp Fingerprint) bool {
+	program := ProgramOf(platform)
+	return program != "" && RootStatuses[fp][program] == RootPendingRemoval
+}
+
+// loadRootStatuses indexes root inclusion statuses from rootstatus.csv in fsys. Data
+// generated before they were recorded has none.
+func loadRootStatuses(fsys fs.FS) (map[Fingerprint]map[string]string, error) {
+	index := make(map[Fingerprint]map[string]string)
+	data, err := fs.ReadFile(fsys, "rootstatus.csv")
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := ParseRootStatuses(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statuses {
+		if index[s.Fingerprint] == nil {
+			index[s.Fingerprint] = make(map[string]string)
+		}
+		index[s.Fingerprint][s.Program] = s.Status
+	}
+	return index, nil
+}
+
+// ParseRootStatuses reads root inclusion statuses from a rootstatus CSV (with header).
+// CSV format: fingerprint,program,status
+func ParseRootStatuses(reader io.Reader) ([]RootStatus, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = 3
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var statuses []RootStatus
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		fp, err := ParseFingerprint(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", record[0], err)
+		}
+		statuses = append(statuses, RootStatus{Fingerprint: fp, Program: record[1], Status: record[2]})
+	}
+	return statuses, nil
+}
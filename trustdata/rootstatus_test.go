@@ -0,0 +1,103 @@
+package trustdata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseRootStatuses(t *testing.T) {
+	t.Parallel()
+
+	fp := strings.Repeat("AB", 32)
+	csv := "fingerprint,program,status\n" +
+		fp + ",microsoft,pending_removal\n" +
+		fp + ",apple,included\n"
+	statuses, err := ParseRootStatuses(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RootStatus{Fingerprint: mustParseFingerprint(t, fp), Program: ProgramMicrosoft, Status: RootPendingRemoval}
+	if len(statuses) != 2 || statuses[0] != want {
+		t.Errorf("ParseRootStatuses() = %+v, want %+v first", statuses, want)
+	}
+
+	for _, bad := range []string{"", "fingerprint,program,status\nnot-hex,apple,included\n", "fingerprint,program,status\nAA,apple\n"} {
+		if _, err := ParseRootStatuses(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseRootStatuses(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestLoadRootStatuses(t *testing.T) {
+	t.Parallel()
+
+	hex := strings.Repeat("AB", 32)
+	fp := mustParseFingerprint(t, hex)
+	fsys := fstest.MapFS{"rootstatus.csv": {Data: []byte("fingerprint,program,status\n" + hex + ",microsoft,pending_removal\n" + hex + ",apple,included\n")}}
+	index, err := loadRootStatuses(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index[fp][ProgramMicrosoft] != RootPendingRemoval || index[fp][ProgramApple] != RootIncluded {
+		t.Errorf("loadRootStatuses() = %v", index)
+	}
+
+	// Data generated before root statuses were recorded has none
+	if index, err := loadRootStatuses(fstest.MapFS{}); err != nil || len(index) != 0 {
+		t.Errorf("loadRootStatuses(no file) = %v, %v; want empty", index, err)
+	}
+}
+
+// TestPendingRemoval replaces package data and must not run in parallel.
+func TestPendingRemoval(t *testing.T) {
+	fp := Fingerprint{0xAA, 0xBB}
+	saved := RootStatuses
+	t.Cleanup(func() { RootStatuses = saved })
+	RootStatuses = map[Fingerprint]map[string]string{fp: {ProgramMicrosoft: RootPendingRemoval, ProgramApple: RootIncluded}}
+
+	for platform, want := range map[Platform]bool{
+		PlatformWindows:      true,
+		PlatformWinContainer: true,
+		PlatformIOS:          false,
+		PlatformAndroid:      false, // No CCADB root program
+		"corp":               false,
+	} {
+		if got := PendingRemoval(platform, fp); got != want {
+			t.Errorf("PendingRemoval(%s) = %v, want %v", platform, got, want)
+		}
+	}
+	if PendingRemoval(PlatformWindows, Fingerprint{0x01}) {
+		t.Error("PendingRemoval() of an unknown root = true")
+	}
+}
+
+func TestOpenWithoutRootStatuses(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range DataFiles {
+		data, err := dataFS.ReadFile("data/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, "rootstatus.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if ds, err := Open(dir); err != nil || len(ds.RootStatuses) != 0 {
+		t.Errorf("Open without rootstatus.csv = %v; want data without root statuses", err)
+	}
+}
+
+func mustParseFingerprint(t *testing.T, s string) Fingerprint {
+	t.Helper()
+	fp, err := ParseFingerprint(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fp
+}
@@ -0,0 +1,476 @@
+package trustdata
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed data/certificates.csv data/stores.csv data/intermediates.csv data/ctlogs.csv data/changelog.json data/sources.json data/rootstatus.csv data/cainfo.csv data/evpolicies.csv data/blocked.csv data/preloaded.csv
+var dataFS embed.FS
+
+// DataFiles lists the files of a dataset directory, the same files as the embedded data.
+var DataFiles = []string{"certificates.csv", "stores.csv", "intermediates.csv", "ctlogs.csv", "changelog.json", "sources.json", "rootstatus.csv", "cainfo.csv", "evpolicies.csv", "blocked.csv", "preloaded.csv"}
+
+// Dataset is a complete set of trust store data.
+type Dataset struct {
+	Stores     []Store
+	Certs      *CertIndex         // Root certificates
+	CrossSigns *CertIndex         // Cross-signed intermediates
+	Preloaded  *CertIndex         // Intermediates clients hold without servers sending them
+	CTLogs     map[[32]byte]CTLog // CT logs by log ID
+	Changelog  []byte             // JSON changelog of data snapshots
+	Sources    SourceInfo         // When and from which upstream versions the data was generated
+
+	RootStatuses map[Fingerprint]map[string]string   // Root program inclusion statuses by fingerprint, then program
+	CAInfos      map[Fingerprint]CAInfo              // Root CA operators and certificate basics by fingerprint
+	EVPolicies   map[Fingerprint]map[string][]string // EV-enabled policy OIDs by fingerprint, then program
+}
+
+// SourceInfo records when a dataset was generated and the versions of the upstream sources
+// it was generated from (sources.json).
+type SourceInfo struct {
+	Generated time.Time         `json:"generated"`
+	Versions  map[string]string `json:"versions,omitempty"` // Upstream version by source, e.g. "chrome": "17"
+}
+
+// Sources describes the generation of the embedded data.
+var Sources SourceInfo
+
+// ChangelogData is the JSON changelog of trust store data snapshots.
+var ChangelogData []byte
+
+// Certs indexes root certificates by fingerprint.
+var Certs *CertIndex
+
+// CrossSigns indexes CCADB intermediates that cross-sign a root in any store
+// (same subject and key as the root, issued by another CA).
+var CrossSigns *CertIndex
+
+// Preloaded indexes intermediates that clients such as Firefox ship preloaded, so chains
+// missing them still verify there. Empty for data generated before they were recorded.
+var Preloaded *CertIndex
+
+// Stores contains all trust stores for all platforms and versions.
+var Stores []Store
+
+func init() {
+	sub, err := fs.Sub(dataFS, "data")
+	if err != nil {
+		panic(fmt.Sprintf("failed to open embedded data: %v", err))
+	}
+	ds, err := loadDataset(sub, func(name string) (*CertIndex, error) {
+		data, err := fs.ReadFile(sub, name)
+		if err != nil {
+			return nil, err
+		}
+		return NewCertIndex(data)
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to load embedded data: %v", err))
+	}
+	Use(ds)
+}
+
+// Open loads a dataset from a directory containing DataFiles, such as an unpacked data
+// bundle newer than the embedded data. Certificate files are memory-mapped on unix and
+// stay mapped for the process lifetime, so the files must not be rewritten in place
+// (replacing them is fine); use Read for data that may be, or that is loaded repeatedly.
+func Open(dir string) (*Dataset, error) {
+	return loadDataset(os.DirFS(dir), func(name string) (*CertIndex, error) {
+		return OpenCertIndex(filepath.Join(dir, name))
+	})
+}
+
+// Read is Open reading certificate files into memory instead of mapping them.
+func Read(dir string) (*Dataset, error) {
+	return loadDataset(os.DirFS(dir), func(name string) (*CertIndex, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: Caller-specified data directory
+		if err != nil {
+			return nil, err
+		}
+		return NewCertIndex(data)
+	})
+}
+
+// Use replaces the package-level data (Stores, Index, Certs, CrossSigns, Preloaded, CTLogs,
+// ChangelogData, Sources, RootStatuses, CAInfos and EVPolicies) with ds. It is not safe to call concurrently with lookups.
+func Use(ds *Dataset) {
+	Stores = ds.Stores
+	Index = NewStoreIndex(ds.Stores)
+	Certs = ds.Certs
+	CrossSigns = ds.CrossSigns
+	Preloaded = ds.Preloaded
+	CTLogs = ds.CTLogs
+	ChangelogData = ds.Changelog
+	Sources = ds.Sources
+	RootStatuses = ds.RootStatuses
+	CAInfos = ds.CAInfos
+	EVPolicies = ds.EVPolicies
+}
+
+// loadDataset reads DataFiles from fsys, indexing certificate CSVs with openIndex.
+func loadDataset(fsys fs.FS, openIndex func(name string) (*CertIndex, error)) (*Dataset, error) {
+	var ds Dataset
+	var err error
+
+	if ds.Certs, err = openIndex("certificates.csv"); err != nil {
+		return nil, fmt.Errorf("load certificates: %w", err)
+	}
+	if ds.CrossSigns, err = openIndex("intermediates.csv"); err != nil {
+		return nil, fmt.Errorf("load cross-signed intermediates: %w", err)
+	}
+	// Optional: data generated before preloaded intermediates were recorded has none
+	ds.Preloaded, err = openIndex("preloaded.csv")
+	if errors.Is(err, fs.ErrNotExist) {
+		ds.Preloaded, err = NewCertIndex([]byte("fingerprint,pem\n"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load preloaded intermediates: %w", err)
+	}
+	if ds.Stores, err = loadStores(fsys); err != nil {
+		return nil, fmt.Errorf("load stores: %w", err)
+	}
+	if err := loadBlocked(fsys, ds.Stores); err != nil {
+		return nil, fmt.Errorf("load blocked certificates: %w", err)
+	}
+	if ds.CTLogs, err = loadCTLogs(fsys); err != nil {
+		return nil, fmt.Errorf("load CT logs: %w", err)
+	}
+	if ds.Changelog, err = fs.ReadFile(fsys, "changelog.json"); err != nil {
+		return nil, fmt.Errorf("load changelog: %w", err)
+	}
+	if ds.Sources, err = loadSources(fsys); err != nil {
+		return nil, fmt.Errorf("load sources: %w", err)
+	}
+	if ds.RootStatuses, err = loadRootStatuses(fsys); err != nil {
+		return nil, fmt.Errorf("load root statuses: %w", err)
+	}
+	if ds.CAInfos, err = loadCAInfos(fsys); err != nil {
+		return nil, fmt.Errorf("load CA info: %w", err)
+	}
+	if ds.EVPolicies, err = loadEVPolicies(fsys); err != nil {
+		return nil, fmt.Errorf("load EV policies: %w", err)
+	}
+	return &ds, nil
+}
+
+// loadSources reads sources.json. Data generated before it was recorded has none.
+func loadSources(fsys fs.FS) (SourceInfo, error) {
+	var info SourceInfo
+	data, err := fs.ReadFile(fsys, "sources.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return info, nil
+	}
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// storeKey identifies a unique platform+version combination.
+type storeKey struct {
+	platform Platform
+	version  string
+}
+
+// storeEntry holds parsed data for a single trust store CSV record.
+type storeEntry struct {
+	fingerprint Fingerprint
+	constraints Constraints
+}
+
+// parseConstraintColumns extracts date constraints from CSV record columns 3-5.
+func parseConstraintColumns(record []string) (Constraints, error) {
+	var c Constraints
+
+	if len(record) > 3 && record[3] != "" {
+		t, err := time.Parse(time.RFC3339, record[3])
+		if err != nil {
+			return c, fmt.Errorf("parse not_before_max %s: %w", record[3], err)
+		}
+		c.NotBeforeMax = &t
+	}
+	if len(record) > 4 && record[4] != "" {
+		t, err := time.Parse(time.RFC3339, record[4])
+		if err != nil {
+			return c, fmt.Errorf("parse distrust_date %s: %w", record[4], err)
+		}
+		c.DistrustDate = &t
+	}
+	if len(record) > 5 && record[5] != "" {
+		t, err := time.Parse(time.RFC3339, record[5])
+		if err != nil {
+			return c, fmt.Errorf("parse sct_not_after %s: %w", record[5], err)
+		}
+		c.SCTNotAfter = &t
+	}
+	return c, nil
+}
+
+// storeRecords holds the parsed rows of one platform version.
+type storeRecords struct {
+	key     storeKey
+	changes bool          // Rows are changes to the previous version of the platform
+	entries []storeEntry  // Listed roots, or added and updated roots with changes
+	removed []Fingerprint // Roots removed from the previous version
+	rows    int
+}
+
+// parseStoreRecords reads CSV records and groups them by platform+version, in the order
+// versions first appear.
+func parseStoreRecords(r *csv.Reader) ([]*storeRecords, error) {
+	var order []*storeRecords
+	byKey := make(map[storeKey]*storeRecords)
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		key := storeKey{Platform(record[0]), record[1]}
+		rec := byKey[key]
+		if rec == nil {
+			rec = &storeRecords{key: key}
+			byKey[key] = rec
+			order = append(order, rec)
+		}
+
+		fpStr := record[2]
+		op := byte(0)
+		if fpStr == "" || fpStr[0] == '+' || fpStr[0] == '-' {
+			op = '='
+			if fpStr != "" {
+				op, fpStr = fpStr[0], fpStr[1:]
+			}
+		}
+		if rec.rows > 0 && rec.changes != (op != 0) {
+			return nil, fmt.Errorf("%s %s: mixes listed roots and changes", key.platform, key.version)
+		}
+		rec.changes = op != 0
+		rec.rows++
+		if op == '=' {
+			continue // Version without changes
+		}
+
+		fp, err := ParseFingerprint(fpStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse fingerprint %s: %w", fpStr, err)
+		}
+		if op == '-' {
+			rec.removed = append(rec.removed, fp)
+			continue
+		}
+
+		constraints, err := parseConstraintColumns(record)
+		if err != nil {
+			return nil, err
+		}
+		rec.entries = append(rec.entries, storeEntry{fp, constraints})
+	}
+	return order, nil
+}
+
+// buildStore converts grouped entries into a Store.
+func buildStore(key storeKey, entries []storeEntry) Store {
+	store := Store{
+		Platform:     key.platform,
+		Version:      key.version,
+		Fingerprints: make([]Fingerprint, len(entries)),
+	}
+
+	for i, e := range entries {
+		store.Fingerprints[i] = e.fingerprint
+		if !e.constraints.IsEmpty() {
+			if store.Constraints == nil {
+				store.Constraints = make(map[Fingerprint]Constraints)
+			}
+			store.Constraints[e.fingerprint] = e.constraints
+		}
+	}
+	return store
+}
+
+// applyStoreChanges builds the store of rec from the previous version of its platform.
+// A version without changes shares the roots and constraints of prev.
+func applyStoreChanges(prev Store, rec *storeRecords) (Store, error) {
+	if len(rec.entries) == 0 && len(rec.removed) == 0 {
+		return Store{Platform: prev.Platform, Version: rec.key.version, Fingerprints: prev.Fingerprints, Constraints: prev.Constraints}, nil
+	}
+
+	roots := make(map[Fingerprint]Constraints, len(prev.Fingerprints)+len(rec.entries))
+	for _, fp := range prev.Fingerprints {
+		roots[fp] = prev.Constraints[fp]
+	}
+	for _, fp := range rec.removed {
+		if _, ok := roots[fp]; !ok {
+			return Store{}, fmt.Errorf("%s %s: removed root %s is not in %s", rec.key.platform, rec.key.version, fp.Truncate(4), prev.Version)
+		}
+		delete(roots, fp)
+	}
+	for _, e := range rec.entries {
+		roots[e.fingerprint] = e.constraints
+	}
+
+	entries := make([]storeEntry, 0, len(roots))
+	for fp, c := range roots {
+		entries = append(entries, storeEntry{fp, c})
+	}
+	slices.SortFunc(entries, func(a, b storeEntry) int { return bytes.Compare(a.fingerprint[:], b.fingerprint[:]) })
+	return buildStore(rec.key, entries), nil
+}
+
+// loadStores builds trust stores from stores.csv in fsys.
+func loadStores(fsys fs.FS) ([]Store, error) {
+	f, err := fsys.Open("stores.csv")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return ParseStores(f)
+}
+
+// ParseStores builds trust stores from a stores CSV (with header), such as a previous
+// data snapshot read by the generator. Stores are returned in the order they appear.
+//
+// CSV format: platform,version,fingerprint,not_before_max,distrust_date,sct_not_after
+//
+// The first version of a platform lists its roots. A later version may instead be written
+// as changes to the version of the platform before it: "+" before a fingerprint adds the
+// root or replaces its constraints, "-" removes it, and a single row with an empty
+// fingerprint declares a version without changes.
+func ParseStores(reader io.Reader) ([]Store, error) {
+	r := csv.NewReader(reader)
+
+	// Skip header
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	records, err := parseStoreRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := make([]Store, 0, len(records))
+	last := make(map[Platform]int)          // Index of each platform's latest version in stores
+	lists := make(map[string][]Fingerprint) // Identical fingerprint lists share one slice
+	for _, rec := range records {
+		store := buildStore(rec.key, rec.entries)
+		if rec.changes {
+			i, ok := last[rec.key.platform]
+			if !ok {
+				return nil, fmt.Errorf("%s %s: changes without a previous version", rec.key.platform, rec.key.version)
+			}
+			if store, err = applyStoreChanges(stores[i], rec); err != nil {
+				return nil, err
+			}
+		}
+		if shared, ok := lists[fingerprintListKey(store.Fingerprints)]; ok {
+			store.Fingerprints = shared
+		} else {
+			lists[fingerprintListKey(store.Fingerprints)] = store.Fingerprints
+		}
+		last[rec.key.platform] = len(stores)
+		stores = append(stores, store)
+	}
+
+	return stores, nil
+}
+
+// WriteStores writes stores in the format read by ParseStores. Each platform's first store
+// lists its roots and later ones are written as changes to the previous store of the
+// platform, so stores should be ordered by version within a platform.
+func WriteStores(w io.Writer, stores []Store) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"platform", "version", "fingerprint", "not_before_max", "distrust_date", "sct_not_after"}); err != nil {
+		return err
+	}
+
+	last := make(map[Platform]Store)
+	for _, s := range stores {
+		prev, ok := last[s.Platform]
+		last[s.Platform] = s
+
+		var rows [][]string
+		for _, fp := range sortedFingerprints(s.Fingerprints) {
+			c := s.Constraints[fp]
+			switch {
+			case !ok:
+				rows = append(rows, storeRow(s, fp.String(), c))
+			case !prev.Includes(fp) || !constraintsEqual(prev.Constraints[fp], c):
+				rows = append(rows, storeRow(s, "+"+fp.String(), c))
+			}
+		}
+		if ok {
+			for _, fp := range sortedFingerprints(prev.Fingerprints) {
+				if !s.Includes(fp) {
+					rows = append(rows, storeRow(s, "-"+fp.String(), Constraints{}))
+				}
+			}
+			if len(rows) == 0 {
+				rows = append(rows, storeRow(s, "", Constraints{}))
+			}
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// storeRow formats a stores CSV row.
+func storeRow(s Store, fingerprint string, c Constraints) []string {
+	return []string{string(s.Platform), s.Version, fingerprint, formatConstraintTime(c.NotBeforeMax), formatConstraintTime(c.DistrustDate), formatConstraintTime(c.SCTNotAfter)}
+}
+
+// formatConstraintTime formats a constraint date as RFC 3339 (empty if unset).
+func formatConstraintTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// constraintsEqual reports whether a and b set the same dates.
+func constraintsEqual(a, b Constraints) bool {
+	eq := func(x, y *time.Time) bool {
+		return (x == nil) == (y == nil) && (x == nil || x.Equal(*y))
+	}
+	return eq(a.NotBeforeMax, b.NotBeforeMax) && eq(a.DistrustDate, b.DistrustDate) && eq(a.SCTNotAfter, b.SCTNotAfter)
+}
+
+// fingerprintListKey identifies a fingerprint list, in order.
+func fingerprintListKey(fps []Fingerprint) string {
+	var key strings.Builder
+	key.Grow(len(fps) * len(Fingerprint{}))
+	for _, fp := range fps {
+		key.Write(fp[:])
+	}
+	return key.String()
+}
+
+// sortedFingerprints returns a sorted copy of fps.
+func sortedFingerprints(fps []Fingerprint) []Fingerprint {
+	sorted := slices.Clone(fps)
+	slices.SortFunc(sorted, func(a, b Fingerprint) int { return bytes.Compare(a[:], b[:]) })
+	return sorted
+}
@@ -0,0 +1,161 @@
+package trustdata
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range DataFiles {
+		data, err := dataFS.ReadFile("data/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ds, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if len(ds.Stores) != len(Stores) || ds.Certs.Len() != Certs.Len() || len(ds.CTLogs) != len(CTLogs) {
+		t.Errorf("Open loaded %d stores, %d certs, %d CT logs; embedded has %d, %d, %d",
+			len(ds.Stores), ds.Certs.Len(), len(ds.CTLogs), len(Stores), Certs.Len(), len(CTLogs))
+	}
+
+	// Data generated before sources.json existed still loads, without source info
+	sources := `{"generated":"2026-10-01T00:00:00Z","versions":{"chrome":"17"}}`
+	if err := os.WriteFile(filepath.Join(dir, "sources.json"), []byte(sources), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ds, err = Open(dir); err != nil || ds.Sources.Versions["chrome"] != "17" || ds.Sources.Generated.IsZero() {
+		t.Errorf("Open with sources.json = %v; want the recorded generation", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "sources.json")); err != nil {
+		t.Fatal(err)
+	}
+	if ds, err = Open(dir); err != nil || !ds.Sources.Generated.IsZero() {
+		t.Errorf("Open without sources.json = %v; want data without source info", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "stores.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir); err == nil {
+		t.Error("Open with missing stores.csv: expected error")
+	}
+}
+
+func TestParseStoresChanges(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := Fingerprint{0x0A}, Fingerprint{0x0B}, Fingerprint{0x0C}
+	header := "platform,version,fingerprint,not_before_max,distrust_date,sct_not_after\n"
+	data := header +
+		"ios,17," + b.String() + ",,,\n" +
+		"ios,17," + a.String() + ",,,\n" +
+		"ios,18,+" + c.String() + ",,,\n" +
+		"ios,18,-" + a.String() + ",,,\n" +
+		"ios,18,+" + b.String() + ",,2026-01-01T00:00:00Z,\n" +
+		"ios,18.1,,,,\n" +
+		"android,35," + a.String() + ",,,\n"
+
+	stores, err := ParseStores(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 4 {
+		t.Fatalf("got %d stores, want 4", len(stores))
+	}
+	ios17, ios18, ios181 := stores[0], stores[1], stores[2]
+	if len(ios17.Fingerprints) != 2 || ios17.Fingerprints[0] != b || ios17.Constraints != nil {
+		t.Errorf("ios 17 = %+v, want listed roots in file order", ios17)
+	}
+	if len(ios18.Fingerprints) != 2 || ios18.Fingerprints[0] != b || ios18.Fingerprints[1] != c {
+		t.Errorf("ios 18 fingerprints = %v, want B, C", ios18.Fingerprints)
+	}
+	if d := ios18.ConstraintFor(b).DistrustDate; d == nil || d.Year() != 2026 {
+		t.Errorf("ios 18 constraint for B = %+v, want distrust date", ios18.ConstraintFor(b))
+	}
+	if ios181.Version != "18.1" || len(ios181.Fingerprints) != 2 || ios181.ConstraintFor(b).DistrustDate == nil {
+		t.Errorf("ios 18.1 = %+v, want roots of 18", ios181)
+	}
+	if stores[3].Platform != "android" || len(stores[3].Fingerprints) != 1 {
+		t.Errorf("android 35 = %+v", stores[3])
+	}
+	// Identical root lists share one slice
+	if &ios18.Fingerprints[0] != &ios181.Fingerprints[0] {
+		t.Error("unchanged version does not share the roots of the previous one")
+	}
+	shared, err := ParseStores(strings.NewReader(header +
+		"ios,17," + a.String() + ",,,\nandroid,35," + a.String() + ",,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &shared[0].Fingerprints[0] != &shared[1].Fingerprints[0] {
+		t.Error("identical root lists of different platforms are not shared")
+	}
+
+	for name, data := range map[string]string{
+		"changes without previous": header + "ios,18,+" + a.String() + ",,,\n",
+		"removes unknown root":     header + "ios,17," + a.String() + ",,,\nios,18,-" + b.String() + ",,,\n",
+		"mixed rows":               header + "ios,17," + a.String() + ",,,\nios,18,+" + b.String() + ",,,\nios,18," + c.String() + ",,,\n",
+	} {
+		if _, err := ParseStores(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestWriteStores(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := Fingerprint{0x0A}, Fingerprint{0x0B}, Fingerprint{0x0C}
+	sct := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	stores := []Store{
+		{Platform: "chrome", Version: "130", Fingerprints: []Fingerprint{b, a}},
+		{Platform: "chrome", Version: "131", Fingerprints: []Fingerprint{a, b}},
+		{Platform: "chrome", Version: "132", Fingerprints: []Fingerprint{a, c}, Constraints: map[Fingerprint]Constraints{a: {SCTNotAfter: &sct}}},
+		{Platform: "ios", Version: "18", Fingerprints: []Fingerprint{c}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStores(&buf, stores); err != nil {
+		t.Fatal(err)
+	}
+	want := "platform,version,fingerprint,not_before_max,distrust_date,sct_not_after\n" +
+		"chrome,130," + a.String() + ",,,\n" +
+		"chrome,130," + b.String() + ",,,\n" +
+		"chrome,131,,,,\n" +
+		"chrome,132,+" + a.String() + ",,,2025-07-01T00:00:00Z\n" +
+		"chrome,132,+" + c.String() + ",,,\n" +
+		"chrome,132,-" + b.String() + ",,,\n" +
+		"ios,18," + c.String() + ",,,\n"
+	if buf.String() != want {
+		t.Errorf("WriteStores() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	parsed, err := ParseStores(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range stores {
+		p := parsed[i]
+		if p.Platform != s.Platform || p.Version != s.Version || len(p.Fingerprints) != len(s.Fingerprints) {
+			t.Errorf("store %d = %s %s %v, want %s %s %v", i, p.Platform, p.Version, p.Fingerprints, s.Platform, s.Version, s.Fingerprints)
+			continue
+		}
+		for _, fp := range s.Fingerprints {
+			if !p.Includes(fp) || !constraintsEqual(p.ConstraintFor(fp), s.ConstraintFor(fp)) {
+				t.Errorf("store %d: root %s not round-tripped", i, fp.Truncate(2))
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package trustdata
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// Platform represents a supported platform.
+type Platform string
+
+const (
+	// Apple platforms
+	PlatformIOS      Platform = "ios"
+	PlatformIPadOS   Platform = "ipados"
+	PlatformMacOS    Platform = "macos"
+	PlatformTVOS     Platform = "tvos"
+	PlatformVisionOS Platform = "visionos"
+	PlatformWatchOS  Platform = "watchos"
+
+	// Other platforms
+	PlatformAndroid Platform = "android"
+	PlatformChrome  Platform = "chrome"
+	PlatformWindows Platform = "windows"
+
+	// PlatformWinContainer models Windows container images (Server Core, Nano Server),
+	// which lack automatic root updates and ship a trimmed root store.
+	PlatformWinContainer Platform = "wincontainer"
+)
+
+func (p Platform) String() string { return string(p) }
+
+// IsApple reports whether the platform is an Apple OS (shares Apple's root store and CT policy).
+func (p Platform) IsApple() bool {
+	switch p {
+	case PlatformIOS, PlatformIPadOS, PlatformMacOS, PlatformTVOS, PlatformVisionOS, PlatformWatchOS:
+		return true
+	}
+	return false
+}
+
+// Store represents a platform version's trusted root CAs.
+type Store struct {
+	Platform     Platform
+	Version      string                      // Semver string (e.g., "17.4", "18", "10")
+	Fingerprints []Fingerprint               // SHA-256 fingerprints
+	Constraints  map[Fingerprint]Constraints // Per-CA date constraints (nil if none)
+	Extra        map[Fingerprint]bool        // User-supplied roots among Fingerprints (nil for stock stores)
+	Blocked      map[Fingerprint]string      // Blocked and always ask certificates by status (nil if none)
+}
+
+// HasExtraRoots reports whether the store includes user-supplied roots recorded in Extra.
+func (s Store) HasExtraRoots() bool {
+	return len(s.Extra) > 0
+}
+
+// ConstraintFor returns constraints for a fingerprint (empty if none).
+func (s Store) ConstraintFor(fp Fingerprint) Constraints {
+	if s.Constraints == nil {
+		return Constraints{}
+	}
+	return s.Constraints[fp]
+}
+
+// Constraints holds date-based trust constraints for a CA.
+type Constraints struct {
+	NotBeforeMax *time.Time // Windows: cert.NotBefore must be <= this
+	DistrustDate *time.Time // Windows: CA distrusted after this date
+	SCTNotAfter  *time.Time // Chrome: SCT timestamp must be <= this
+}
+
+// IsEmpty returns true if no constraints are set.
+func (c Constraints) IsEmpty() bool {
+	return c.NotBeforeMax == nil && c.DistrustDate == nil && c.SCTNotAfter == nil
+}
+
+// DateFormat is the ISO 8601 date format used for displaying constraint dates.
+const DateFormat = "2006-01-02"
+
+// CertName returns a certificate's display name: subject CommonName, falling back to Organization.
+// Returns empty string if neither is set.
+func CertName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.Subject.Organization) > 0 {
+		return cert.Subject.Organization[0]
+	}
+	return ""
+}